@@ -0,0 +1,322 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sinyal kesehatan untuk alerting: run crawl yang gagal berturut-turut per
+// source, rasio query tanpa hasil 1 jam terakhir dibanding 24 jam terakhir,
+// dan umur corpus (sejak articles.json atau WAL terakhir berubah). Sinyal ini
+// diekspor di GET /metrics untuk Prometheus (aturan siap pakai di
+// alerts.rules.yml) dan dievaluasi langsung dengan ambang "alerts" di
+// config.json oleh GET /api/admin/alerts, untuk deployment tanpa Prometheus.
+// Job alert_evaluate mengevaluasi aturan yang sama setiap
+// ALERT_EVALUATE_INTERVAL dan mencatat di log saat alert mulai atau berhenti
+// firing.
+const (
+	AlertCrawlFailing    = "CrawlFailing"
+	AlertZeroResultSpike = "ZeroResultRateSpike"
+	AlertIndexStale      = "IndexStale"
+
+	ALERT_SHORT_WINDOW    = time.Hour
+	ALERT_BASELINE_WINDOW = 24 * time.Hour
+
+	ALERT_EVALUATE_INTERVAL = time.Minute
+	JobAlertEvaluate        = "alert_evaluate"
+)
+
+// Ambang alert bawaan; 0 mematikan aturannya
+type AlertRules struct {
+	// Alert kalau sebanyak ini run crawl berturut-turut gagal. Run gagal
+	// kalau tidak menghasilkan artikel atau success rate-nya di bawah
+	// crawl_min_success_rate.
+	CrawlFailureStreak  int     `json:"crawl_failure_streak"`
+	CrawlMinSuccessRate float64 `json:"crawl_min_success_rate"`
+
+	// Alert kalau rasio zero-result 1 jam terakhir minimal zero_result_rate
+	// dan minimal zero_result_spike kali rasio 24 jam, dengan minimal
+	// zero_result_min_queries query dalam 1 jam
+	ZeroResultRate       float64 `json:"zero_result_rate"`
+	ZeroResultSpike      float64 `json:"zero_result_spike"`
+	ZeroResultMinQueries int     `json:"zero_result_min_queries"`
+
+	// Alert kalau corpus tidak berubah selama ini
+	MaxIndexAgeHours float64 `json:"max_index_age_hours"`
+}
+
+func defaultAlertRules() AlertRules {
+	return AlertRules{
+		CrawlFailureStreak:   3,
+		CrawlMinSuccessRate:  0.5,
+		ZeroResultRate:       0.3,
+		ZeroResultSpike:      2,
+		ZeroResultMinQueries: 20,
+		MaxIndexAgeHours:     48,
+	}
+}
+
+func (r AlertRules) validate() error {
+	switch {
+	case r.CrawlFailureStreak < 0 || r.ZeroResultMinQueries < 0:
+		return fmt.Errorf("crawl_failure_streak and zero_result_min_queries must not be negative")
+	case r.CrawlMinSuccessRate < 0 || r.CrawlMinSuccessRate > 1:
+		return fmt.Errorf("crawl_min_success_rate must be between 0 and 1")
+	case r.ZeroResultRate < 0 || r.ZeroResultRate > 1:
+		return fmt.Errorf("zero_result_rate must be between 0 and 1")
+	case r.ZeroResultSpike < 0 || r.MaxIndexAgeHours < 0:
+		return fmt.Errorf("zero_result_spike and max_index_age_hours must not be negative")
+	}
+	return nil
+}
+
+// Run crawl yang selesai tanpa artikel atau dengan terlalu banyak request gagal
+func crawlFailed(progress CrawlProgress, rules AlertRules) bool {
+	if progress.Articles == 0 {
+		return true
+	}
+	return progress.Responses+progress.Errors > 0 && progress.SuccessRate < rules.CrawlMinSuccessRate
+}
+
+type HealthSignals struct {
+	CrawlFailureStreaks map[string]int `json:"crawl_failure_streaks"`
+
+	Queries1h         int     `json:"queries_1h"`
+	ZeroResultRate1h  float64 `json:"zero_result_rate_1h"`
+	Queries24h        int     `json:"queries_24h"`
+	ZeroResultRate24h float64 `json:"zero_result_rate_24h"`
+
+	Generation int       `json:"generation"`
+	CorpusTime time.Time `json:"corpus_updated_at"` // zero kalau articles.json belum ada
+	IndexAge   float64   `json:"index_age_seconds"`
+}
+
+// Rasio query tanpa hasil sejak since
+func zeroResultRate(entries []QueryLogEntry, since time.Time) (queries int, rate float64) {
+	zero := 0
+	for _, entry := range entries {
+		if entry.Time.Before(since) {
+			continue
+		}
+		queries++
+		if entry.Results == 0 {
+			zero++
+		}
+	}
+	if queries > 0 {
+		rate = float64(zero) / float64(queries)
+	}
+	return queries, rate
+}
+
+// Waktu corpus terakhir berubah: articles.json ditulis ulang (merge) atau
+// record WAL tertunda terbaru
+func corpusUpdatedAt() time.Time {
+	var updated time.Time
+	if info, err := os.Stat(ARTICLES_FILE); err == nil {
+		updated = info.ModTime()
+	}
+	if pending := documentLog.Pending(); len(pending) > 0 {
+		if last := pending[len(pending)-1].Time; last.After(updated) {
+			updated = last
+		}
+	}
+	return updated
+}
+
+func healthSignals(now time.Time) (HealthSignals, error) {
+	signals := HealthSignals{
+		CrawlFailureStreaks: crawlMonitor.FailureStreaks(),
+		Generation:          currentGeneration(),
+		CorpusTime:          corpusUpdatedAt(),
+	}
+	if !signals.CorpusTime.IsZero() {
+		signals.IndexAge = now.Sub(signals.CorpusTime).Seconds()
+	}
+
+	entries, err := readQueryLog(QUERY_LOG_FILE, now.Add(-ALERT_BASELINE_WINDOW))
+	if err != nil {
+		return signals, err
+	}
+	signals.Queries1h, signals.ZeroResultRate1h = zeroResultRate(entries, now.Add(-ALERT_SHORT_WINDOW))
+	signals.Queries24h, signals.ZeroResultRate24h = zeroResultRate(entries, now.Add(-ALERT_BASELINE_WINDOW))
+	return signals, nil
+}
+
+type Alert struct {
+	Name      string            `json:"name"`
+	Firing    bool              `json:"firing"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Value     float64           `json:"value"`
+	Threshold float64           `json:"threshold"`
+	Message   string            `json:"message"`
+}
+
+// Evaluasi aturan bawaan; aturan yang dimatikan tidak ikut dalam hasil
+func evaluateAlerts(signals HealthSignals, rules AlertRules) []Alert {
+	var alerts []Alert
+
+	if rules.CrawlFailureStreak > 0 {
+		sources := make([]string, 0, len(signals.CrawlFailureStreaks))
+		for source := range signals.CrawlFailureStreaks {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+		for _, source := range sources {
+			streak := signals.CrawlFailureStreaks[source]
+			alerts = append(alerts, Alert{
+				Name:      AlertCrawlFailing,
+				Firing:    streak >= rules.CrawlFailureStreak,
+				Labels:    map[string]string{"source": source},
+				Value:     float64(streak),
+				Threshold: float64(rules.CrawlFailureStreak),
+				Message:   fmt.Sprintf("%s: %d consecutive failed crawl runs", source, streak),
+			})
+		}
+	}
+
+	if rules.ZeroResultRate > 0 || rules.ZeroResultSpike > 0 {
+		threshold := rules.ZeroResultRate
+		if spike := rules.ZeroResultSpike * signals.ZeroResultRate24h; spike > threshold {
+			threshold = spike
+		}
+		alerts = append(alerts, Alert{
+			Name:      AlertZeroResultSpike,
+			Firing:    signals.Queries1h >= rules.ZeroResultMinQueries && signals.ZeroResultRate1h > 0 && signals.ZeroResultRate1h >= threshold,
+			Value:     signals.ZeroResultRate1h,
+			Threshold: threshold,
+			Message: fmt.Sprintf("%.1f%% of %d queries in the last hour had no results (24h: %.1f%% of %d)",
+				signals.ZeroResultRate1h*100, signals.Queries1h, signals.ZeroResultRate24h*100, signals.Queries24h),
+		})
+	}
+
+	if rules.MaxIndexAgeHours > 0 && !signals.CorpusTime.IsZero() {
+		hours := signals.IndexAge / 3600
+		alerts = append(alerts, Alert{
+			Name:      AlertIndexStale,
+			Firing:    hours >= rules.MaxIndexAgeHours,
+			Value:     hours,
+			Threshold: rules.MaxIndexAgeHours,
+			Message:   fmt.Sprintf("corpus last changed %.1f hours ago", hours),
+		})
+	}
+	return alerts
+}
+
+// Metrics untuk alerting, ditambahkan ke output GET /metrics
+func writeHealthMetrics(buf *bytes.Buffer, signals HealthSignals, alerts []Alert) {
+	gauge := func(name, help string) {
+		fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	}
+
+	gauge("search_crawl_failure_streak", "Consecutive crawl runs that finished without articles or with a low success rate.")
+	sources := make([]string, 0, len(signals.CrawlFailureStreaks))
+	for source := range signals.CrawlFailureStreaks {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		fmt.Fprintf(buf, "search_crawl_failure_streak{source=%q} %d\n", source, signals.CrawlFailureStreaks[source])
+	}
+
+	gauge("search_queries", "Searches in the query log over the window.")
+	fmt.Fprintf(buf, "search_queries{window=\"1h\"} %d\n", signals.Queries1h)
+	fmt.Fprintf(buf, "search_queries{window=\"24h\"} %d\n", signals.Queries24h)
+	gauge("search_zero_result_ratio", "Share of searches over the window that returned no results.")
+	fmt.Fprintf(buf, "search_zero_result_ratio{window=\"1h\"} %g\n", signals.ZeroResultRate1h)
+	fmt.Fprintf(buf, "search_zero_result_ratio{window=\"24h\"} %g\n", signals.ZeroResultRate24h)
+
+	gauge("search_index_generation", "Current index generation; changes whenever the corpus changes.")
+	fmt.Fprintf(buf, "search_index_generation %d\n", signals.Generation)
+	if !signals.CorpusTime.IsZero() {
+		gauge("search_index_age_seconds", "Seconds since articles.json or the WAL last changed.")
+		fmt.Fprintf(buf, "search_index_age_seconds %g\n", signals.IndexAge)
+	}
+
+	gauge("search_alert_firing", "1 while a built-in alert condition (config.json \"alerts\") holds.")
+	for _, alert := range alerts {
+		firing := 0
+		if alert.Firing {
+			firing = 1
+		}
+		if source, exists := alert.Labels["source"]; exists {
+			fmt.Fprintf(buf, "search_alert_firing{alert=%q,source=%q} %d\n", alert.Name, source, firing)
+		} else {
+			fmt.Fprintf(buf, "search_alert_firing{alert=%q} %d\n", alert.Name, firing)
+		}
+	}
+}
+
+// GET /api/admin/alerts[?firing=1]: status aturan alert bawaan
+func alertsHandler(c *gin.Context) {
+	signals, err := healthSignals(time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	alerts := evaluateAlerts(signals, requestConfig(c).Alerts)
+
+	firing := 0
+	list := make([]Alert, 0, len(alerts))
+	for _, alert := range alerts {
+		if alert.Firing {
+			firing++
+		} else if c.Query("firing") == "1" {
+			continue
+		}
+		list = append(list, alert)
+	}
+	c.JSON(http.StatusOK, gin.H{"firing": firing, "alerts": list, "signals": signals})
+}
+
+// Alert yang firing pada evaluasi terakhir, per nama dan label source
+var (
+	alertFiringMu sync.Mutex
+	alertFiring   = map[string]bool{}
+)
+
+func startAlertEvaluator(interval time.Duration) {
+	adminJobs.Every(JobAlertEvaluate, interval)
+}
+
+func alertEvaluateJob(ctx context.Context, job jobRun) error {
+	signals, err := healthSignals(time.Now())
+	if err != nil {
+		return err
+	}
+	alerts := evaluateAlerts(signals, currentConfig().Alerts)
+	job.SetTotal(len(alerts))
+
+	alertFiringMu.Lock()
+	defer alertFiringMu.Unlock()
+	changed := 0
+	for _, alert := range alerts {
+		key := alert.Name + "/" + alert.Labels["source"]
+		if alert.Firing == alertFiring[key] {
+			continue
+		}
+		changed++
+		if alert.Firing {
+			log.Printf("Alert %s firing: %s", alert.Name, alert.Message)
+		} else {
+			log.Printf("Alert %s resolved: %s", alert.Name, alert.Message)
+		}
+		alertFiring[key] = alert.Firing
+	}
+	job.Advance(len(alerts), changed)
+	return nil
+}
+
+func init() {
+	// Tanpa retry: evaluasi berikutnya jalan ALERT_EVALUATE_INTERVAL lagi
+	registerJobKind(JobAlertEvaluate, "", RetryPolicy{}, alertEvaluateJob)
+}
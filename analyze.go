@@ -0,0 +1,187 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Playground analyzer: GET /api/_analyze?text=...&analyzer=... menampilkan
+// token yang dihasilkan analyzer beserta posisi, offset dan filter yang
+// mengubahnya, untuk mencari tahu kenapa query tidak cocok dengan dokumen.
+//
+//	analyzer=id|en     pipeline bahasa tertentu
+//	analyzer=language  bahasa dideteksi dari teks, seperti dokumen saat index (default)
+//	analyzer=query     seperti query: semua bahasa dengan bobotnya, plus sinonim
+//
+// Dengan doc_id, tiap token juga menunjukkan apakah term itu ada di dokumen
+// tersebut di index utama.
+const (
+	AnalyzeLanguage = "language"
+	AnalyzeQuery    = "query"
+
+	ANALYZE_MAX_TEXT = 10000 // byte
+
+	// Alasan token dibuang
+	RemovedNumber   = "number"
+	RemovedStopword = "stopword"
+)
+
+type AnalyzeToken struct {
+	Token    string `json:"token"`
+	Original string `json:"original"`
+	Position int    `json:"position"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	// Filter yang mengubah token, urut: lowercase, stem
+	Filters []string `json:"filters"`
+	// Jumlah dokumen di index utama yang memuat term ini
+	DocFrequency int `json:"doc_frequency"`
+	// Hanya kalau doc_id diberikan
+	InDocument *bool `json:"in_document,omitempty"`
+	// Sinonim yang ikut ditambahkan ke query (analyzer=query)
+	Synonyms []string `json:"synonyms,omitempty"`
+}
+
+// Kata yang tidak menjadi token
+type AnalyzeRemoved struct {
+	Original string `json:"original"`
+	Position int    `json:"position"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	Reason   string `json:"reason"` // number, stopword
+}
+
+// Hasil satu pipeline bahasa
+type AnalyzeStream struct {
+	Language string           `json:"language"`
+	Weight   float64          `json:"weight"`
+	Tokens   []AnalyzeToken   `json:"tokens"`
+	Removed  []AnalyzeRemoved `json:"removed"`
+}
+
+type AnalyzeResponse struct {
+	Text     string          `json:"text"`
+	Analyzer string          `json:"analyzer"`
+	DocID    *int            `json:"doc_id,omitempty"`
+	Streams  []AnalyzeStream `json:"streams"`
+}
+
+// Jalankan analyzer langkah demi langkah dengan tahap yang sama seperti
+// AnalyzeInto, mencatat kata yang dibuang dan filter yang mengubah token
+func traceAnalyze(tp *TextProcessor, text string) AnalyzeStream {
+	stream := AnalyzeStream{Language: tp.language, Weight: 1, Tokens: []AnalyzeToken{}, Removed: []AnalyzeRemoved{}}
+	position := 0
+	for i := 0; i < len(text); {
+		if !isWordByte(text[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(text) && isWordByte(text[i]) {
+			i++
+		}
+		original := text[start:i]
+		tokens := tp.splitWords(nil, original)
+		if len(tokens) == 0 {
+			stream.Removed = append(stream.Removed, AnalyzeRemoved{Original: original, Position: position, Start: start, End: i, Reason: RemovedNumber})
+			position++
+			continue
+		}
+
+		token := AnalyzeToken{Original: original, Position: position, Start: start, End: i, Filters: []string{}}
+		tokens = tp.caseFolding(tokens)
+		if tokens[0].Term != original {
+			token.Filters = append(token.Filters, "lowercase")
+		}
+		if tokens = tp.removeStopwords(tokens); len(tokens) == 0 {
+			stream.Removed = append(stream.Removed, AnalyzeRemoved{Original: original, Position: position, Start: start, End: i, Reason: RemovedStopword})
+			position++
+			continue
+		}
+		folded := tokens[0].Term
+		tokens = tp.stemming(tokens)
+		if tokens[0].Term != folded {
+			token.Filters = append(token.Filters, "stem")
+		}
+		token.Token = tokens[0].Term
+		stream.Tokens = append(stream.Tokens, token)
+		position++
+	}
+	return stream
+}
+
+func analyzeText(text, analyzer string) []AnalyzeStream {
+	switch analyzer {
+	case LangIndonesian, LangEnglish:
+		return []AnalyzeStream{traceAnalyze(analyzerFor(analyzer), text)}
+	case AnalyzeQuery:
+		weights := queryLanguageWeights(text)
+		var streams []AnalyzeStream
+		for _, language := range []string{LangIndonesian, LangEnglish} {
+			stream := traceAnalyze(analyzerFor(language), text)
+			stream.Weight = weights[language]
+			for i := range stream.Tokens {
+				stream.Tokens[i].Synonyms = synonyms.Lookup(stream.Tokens[i].Token)
+			}
+			streams = append(streams, stream)
+		}
+		return streams
+	}
+	return []AnalyzeStream{traceAnalyze(analyzerFor(detectLanguage(text)), text)}
+}
+
+// GET /api/_analyze
+func analyzeHandler(c *gin.Context) {
+	text := c.Query("text")
+	if strings.TrimSpace(text) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "text is required"})
+		return
+	}
+	if len(text) > ANALYZE_MAX_TEXT {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "text is too long"})
+		return
+	}
+	analyzer := c.DefaultQuery("analyzer", AnalyzeLanguage)
+	switch analyzer {
+	case LangIndonesian, LangEnglish, AnalyzeLanguage, AnalyzeQuery:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "analyzer must be id, en, language or query"})
+		return
+	}
+
+	articles, idx, err := loadIndex()
+	if err != nil {
+		log.Printf("Error loading index for analyze: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "index unavailable"})
+		return
+	}
+	res := AnalyzeResponse{Text: text, Analyzer: analyzer, Streams: analyzeText(text, analyzer)}
+
+	docID := -1
+	if value := c.Query("doc_id"); value != "" {
+		docID, err = strconv.Atoi(value)
+		if err != nil || docID < 0 || docID >= len(articles) || !articles[docID].VisibleTo(clientScopes(c)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid doc_id"})
+			return
+		}
+		res.DocID = &docID
+	}
+	for s := range res.Streams {
+		for i := range res.Streams[s].Tokens {
+			token := &res.Streams[s].Tokens[i]
+			postingList, exists := idx.Index[token.Token]
+			if exists {
+				token.DocFrequency = postingList.DocFrequency
+			}
+			if docID >= 0 {
+				inDocument := exists && postingList.Postings[docID] != nil
+				token.InDocument = &inDocument
+			}
+		}
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// searchctl bench analyzer: benchmark alokasi analyzer terhadap corpus asli.
+// Memakai testing.Benchmark supaya bisa jalan dari binary biasa.
+func benchAnalyzerCommand(args []string) int {
+	fs := flag.NewFlagSet("bench analyzer", flag.ExitOnError)
+	docs := fs.Int("docs", 0, "batasi jumlah dokumen (0 = semua)")
+	fs.Parse(args)
+
+	articles, err := loadArticles()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *docs > 0 && *docs < len(articles) {
+		articles = articles[:*docs]
+	}
+
+	texts := make([]string, len(articles))
+	for i, article := range articles {
+		texts[i] = article.Title + " " + article.Content
+	}
+
+	benchmarks := []struct {
+		name string
+		fn   func()
+	}{
+		{"Analyze", func() {
+			for _, text := range texts {
+				textProcessor.Analyze(text)
+			}
+		}},
+		{"ProcessText", func() {
+			for _, text := range texts {
+				textProcessor.ProcessText(text)
+			}
+		}},
+		{"cleanContent", func() {
+			for _, article := range articles {
+				cleanContent(article.Content)
+			}
+		}},
+		{"highlightText", func() {
+			for _, article := range articles {
+				highlightText(article.Title, "harga rumah subsidi")
+			}
+		}},
+		{"buildInvertedIndex", func() {
+			docs := make([]Article, len(articles))
+			copy(docs, articles)
+			buildInvertedIndex(docs, currentConfig())
+		}},
+	}
+
+	fmt.Printf("%d documents, per corpus pass:\n", len(articles))
+	for _, bm := range benchmarks {
+		fn := bm.fn
+		result := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				fn()
+			}
+		})
+		fmt.Printf("  %-20s %12d ns/op %12d B/op %10d allocs/op\n",
+			bm.name, result.NsPerOp(), result.AllocedBytesPerOp(), result.AllocsPerOp())
+	}
+	return 0
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deteksi beda analyzer saat index dan saat query. Fingerprint analyzer
+// (stopword, kamus kata dasar, field analyzer di schema dan hasil stemming
+// sejumlah kata uji) dicatat di data yang menyimpan term hasil analisis. Index utama dan
+// arsip selalu dibangun ulang di proses ini dari articles.json/archive.json,
+// jadi selalu cocok; yang bisa basi adalah synonyms.json, yang term dan
+// sinonimnya disimpan sudah di-stem. Kalau fingerprint-nya beda, sinonim
+// lama tidak lagi cocok dengan term query dan recall turun diam-diam.
+//
+//	"analyzer_check": "warn"    // log peringatan, server tetap jalan (default)
+//	"analyzer_check": "refuse"  // server tidak mau start
+//
+// Perbaiki dengan `searchctl analyzer check -reanalyze`. Naikkan
+// ANALYZER_VERSION kalau mengubah analyzer dengan cara yang tidak tertangkap
+// kata uji.
+const (
+	ANALYZER_VERSION = 3
+
+	AnalyzerCheckWarn   = "warn"
+	AnalyzerCheckRefuse = "refuse"
+	AnalyzerCheckOff    = "off"
+)
+
+// Kata uji stemmer Indonesia dan Porter2; perubahan aturan stemming
+// mengubah hasilnya dan dengan itu fingerprint
+var analyzerProbes = []string{
+	"membangunkan", "pembangunan", "perumahan", "dijual", "kepemilikan", "berlokasi",
+	"terjangkau", "menyewakan", "penyewaan", "bersertifikat", "apartemennya", "kawasan",
+	"dikembangkan", "memperhatikan", "pengeringan", "kerusakan", "disetujui",
+	"buildings", "running", "generously", "apartments", "ownership", "renovated",
+	"mortgages", "affordable", "located", "investments",
+}
+
+// Data tersimpan yang fingerprint analyzer-nya beda dengan server
+type AnalyzerMismatch struct {
+	Artifact string `json:"artifact"`
+	Stored   string `json:"stored"`
+	Current  string `json:"current"`
+	Detail   string `json:"detail"`
+}
+
+type AnalyzerStatus struct {
+	Version     int                `json:"version"`
+	Fingerprint string             `json:"fingerprint"`
+	Mode        string             `json:"mode"`
+	Mismatches  []AnalyzerMismatch `json:"mismatches"`
+}
+
+// Fingerprint konfigurasi analyzer yang sedang dipakai
+func analyzerFingerprint(cfg *Config) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "version=%d\n", ANALYZER_VERSION)
+	fmt.Fprintf(h, "regex_clean_content=%v\n", cfg.RegexCleanContent)
+	fmt.Fprintf(h, "root_words=%d:%s\n", len(rootDictionary), rootDictionaryDigest)
+	for _, tp := range []*TextProcessor{textProcessor, englishTextProcessor} {
+		words := make([]string, 0, len(tp.stopWords))
+		for word := range tp.stopWords {
+			words = append(words, word)
+		}
+		sort.Strings(words)
+		fmt.Fprintf(h, "stopwords[%s]=%s\n", tp.language, strings.Join(words, ","))
+		fmt.Fprintf(h, "probes[%s]=%s\n", tp.language, strings.Join(tp.ProcessText(strings.Join(analyzerProbes, " ")), ","))
+	}
+	for _, field := range cfg.Schema.Fields {
+		if field.Type == FieldText && field.Indexed {
+			fmt.Fprintf(h, "field[%s]=%s\n", field.Name, field.Analyzer)
+			if len(field.Compounds) > 0 {
+				fmt.Fprintf(h, "compounds[%s]=%s\n", field.Name, strings.Join(field.Compounds, ","))
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Bandingkan fingerprint yang tersimpan dengan analyzer server
+func checkAnalyzer(cfg *Config) AnalyzerStatus {
+	status := AnalyzerStatus{
+		Version:     ANALYZER_VERSION,
+		Fingerprint: analyzerFingerprint(cfg),
+		Mode:        cfg.AnalyzerCheck,
+		Mismatches:  []AnalyzerMismatch{},
+	}
+	if stored := synonyms.analyzer(); stored != "" && stored != status.Fingerprint {
+		status.Mismatches = append(status.Mismatches, AnalyzerMismatch{
+			Artifact: SYNONYMS_FILE,
+			Stored:   stored,
+			Current:  status.Fingerprint,
+			Detail:   "synonyms were analyzed with a different analyzer; run `searchctl analyzer check -reanalyze`",
+		})
+	}
+	return status
+}
+
+// Cek saat server start; error kalau analyzer_check "refuse" dan ada yang beda
+func startupAnalyzerCheck() error {
+	cfg := currentConfig()
+	if cfg.AnalyzerCheck == AnalyzerCheckOff {
+		return nil
+	}
+	status := checkAnalyzer(cfg)
+	for _, mismatch := range status.Mismatches {
+		log.Printf("Analyzer mismatch in %s: stored %s, current %s: %s", mismatch.Artifact, mismatch.Stored, mismatch.Current, mismatch.Detail)
+	}
+	if len(status.Mismatches) > 0 && cfg.AnalyzerCheck == AnalyzerCheckRefuse {
+		return fmt.Errorf("analyzer fingerprint %s does not match %d stored artifact(s); refusing to serve", status.Fingerprint, len(status.Mismatches))
+	}
+	return nil
+}
+
+// GET /api/admin/analyzer
+func analyzerCheckHandler(c *gin.Context) {
+	status := checkAnalyzer(requestConfig(c))
+	code := http.StatusOK
+	if len(status.Mismatches) > 0 {
+		code = http.StatusConflict
+	}
+	c.JSON(code, status)
+}
+
+// searchctl analyzer check [-reanalyze]
+func analyzerCheckCommand(args []string) int {
+	fs := flag.NewFlagSet("analyzer check", flag.ContinueOnError)
+	reanalyze := fs.Bool("reanalyze", false, "analisis ulang term synonyms.json dengan analyzer sekarang")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := currentConfig()
+	status := checkAnalyzer(cfg)
+	fmt.Printf("analyzer version %d, fingerprint %s\n", status.Version, status.Fingerprint)
+	if *reanalyze {
+		changed, dropped, err := synonyms.Reanalyze()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("%s: %d entries re-analyzed, %d dropped\n", SYNONYMS_FILE, changed, dropped)
+		status = checkAnalyzer(cfg)
+	}
+	for _, mismatch := range status.Mismatches {
+		fmt.Printf("MISMATCH %s: stored %s\n  %s\n", mismatch.Artifact, mismatch.Stored, mismatch.Detail)
+	}
+	if len(status.Mismatches) > 0 {
+		return 1
+	}
+	fmt.Println("ok")
+	return 0
+}
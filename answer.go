@@ -0,0 +1,434 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Jawaban ringkas di atas hasil pencarian (GET /api/answer?q=...). Paragraf
+// terbaik dari top-k hasil dikirim ke provider LLM sebagai sumber bernomor,
+// dan jawabannya harus mengutip sumber itu ([1], [2], ...). Jawaban tanpa
+// kutipan yang valid dibuang, jadi yang tampil selalu bisa ditelusuri ke URL
+// hasil.
+//
+//	"answer": {"enabled": true, "provider": "openai", "url": "http://localhost:11434/v1/chat/completions", "model": "llama3.1"}
+//
+// Provider default "none" tidak memanggil apa pun dan tidak pernah menjawab.
+// Provider "openai" memakai API chat completions yang kompatibel OpenAI
+// (Ollama, llama.cpp server, vLLM atau OpenAI sendiri dengan api_key).
+// Provider lain cukup mengimplementasi AnswerProvider dan didaftarkan di
+// answerProviders. Halaman hasil mengambil jawaban terpisah lewat JavaScript,
+// jadi LLM yang lambat tidak menahan hasil organik.
+const (
+	ANSWER_PROVIDER_NONE   = "none"
+	ANSWER_PROVIDER_OPENAI = "openai"
+
+	ANSWER_TOP_K            = 5
+	ANSWER_MAX_TOP_K        = 10
+	ANSWER_PASSAGE_WORDS    = 120
+	ANSWER_MAX_TOKENS       = 300
+	ANSWER_TIMEOUT          = 15 * time.Second
+	ANSWER_CACHE_SIZE       = 256
+	ANSWER_CACHE_TTL        = 10 * time.Minute
+	ANSWER_MAX_QUERY_LENGTH = 200
+	ANSWER_OPENAI_URL       = "http://localhost:11434/v1/chat/completions"
+	ANSWER_TEMPERATURE      = 0.2
+)
+
+type AnswerConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Provider string `json:"provider"`
+	URL      string `json:"url"`
+	Model    string `json:"model"`
+	APIKey   string `json:"api_key"`
+	// Jumlah hasil teratas yang dijadikan sumber dan panjang paragrafnya
+	TopK         int `json:"top_k"`
+	PassageWords int `json:"passage_words"`
+	MaxTokens    int `json:"max_tokens"`
+	TimeoutSec   int `json:"timeout_sec"`
+}
+
+func defaultAnswerConfig() AnswerConfig {
+	return AnswerConfig{
+		Provider:     ANSWER_PROVIDER_NONE,
+		URL:          ANSWER_OPENAI_URL,
+		TopK:         ANSWER_TOP_K,
+		PassageWords: ANSWER_PASSAGE_WORDS,
+		MaxTokens:    ANSWER_MAX_TOKENS,
+		TimeoutSec:   int(ANSWER_TIMEOUT / time.Second),
+	}
+}
+
+// Sumber bernomor untuk LLM (N mulai dari 1)
+type AnswerPassage struct {
+	N     int    `json:"n"`
+	DocID int    `json:"doc_id"`
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Node  string `json:"node,omitempty"`
+	Text  string `json:"-"`
+}
+
+type AnswerResponse struct {
+	Query string `json:"query"`
+	// Kosong kalau provider tidak menjawab atau jawabannya tanpa kutipan
+	Answer    string          `json:"answer"`
+	Citations []AnswerPassage `json:"citations"` // sumber yang dikutip, urut kemunculan
+	Provider  string          `json:"provider"`
+}
+
+// Provider LLM. Jawaban kosong berarti tidak menjawab.
+type AnswerProvider interface {
+	Name() string
+	Answer(ctx context.Context, question string, passages []AnswerPassage) (string, error)
+}
+
+var answerProviders = map[string]func(AnswerConfig) AnswerProvider{
+	ANSWER_PROVIDER_NONE:   func(AnswerConfig) AnswerProvider { return noAnswer{} },
+	ANSWER_PROVIDER_OPENAI: newOpenAIAnswer,
+}
+
+func answerProvider(cfg AnswerConfig) (AnswerProvider, error) {
+	newProvider, exists := answerProviders[cfg.Provider]
+	if !exists {
+		return nil, fmt.Errorf("unknown answer provider %q", cfg.Provider)
+	}
+	return newProvider(cfg), nil
+}
+
+type noAnswer struct{}
+
+func (noAnswer) Name() string { return ANSWER_PROVIDER_NONE }
+
+func (noAnswer) Answer(context.Context, string, []AnswerPassage) (string, error) {
+	return "", nil
+}
+
+type openAIAnswer struct {
+	url       string
+	model     string
+	apiKey    string
+	maxTokens int
+}
+
+func newOpenAIAnswer(cfg AnswerConfig) AnswerProvider {
+	maxTokens := cfg.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = ANSWER_MAX_TOKENS
+	}
+	return &openAIAnswer{url: cfg.URL, model: cfg.Model, apiKey: cfg.APIKey, maxTokens: maxTokens}
+}
+
+func (o *openAIAnswer) Name() string { return ANSWER_PROVIDER_OPENAI }
+
+const answerSystemPrompt = `You answer questions for a property news search engine.
+Use only the numbered sources. Cite every claim with the source number in square brackets, e.g. [1] or [2][3].
+Answer in the language of the question, in at most 3 sentences.
+If the sources do not answer the question, reply with exactly: NO_ANSWER`
+
+func (o *openAIAnswer) Answer(ctx context.Context, question string, passages []AnswerPassage) (string, error) {
+	var prompt strings.Builder
+	for _, passage := range passages {
+		fmt.Fprintf(&prompt, "[%d] %s\n%s\n\n", passage.N, passage.Title, passage.Text)
+	}
+	fmt.Fprintf(&prompt, "Question: %s", question)
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"model": o.model,
+		"messages": []map[string]string{
+			{"role": "system", "content": answerSystemPrompt},
+			{"role": "user", "content": prompt.String()},
+		},
+		"temperature": ANSWER_TEMPERATURE,
+		"max_tokens":  o.maxTokens,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodPost, o.url, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("chat completions returned %s: %s", res.Status, strings.TrimSpace(string(data)))
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("invalid chat completions response: %v", err)
+	}
+	if len(result.Choices) == 0 {
+		return "", nil
+	}
+	text := strings.TrimSpace(result.Choices[0].Message.Content)
+	if strings.Contains(text, "NO_ANSWER") {
+		return "", nil
+	}
+	return text, nil
+}
+
+// Paragraf konten yang memuat term query terbanyak, dipotong ke maxWords.
+// Konten tanpa paragraf yang cocok memakai paragraf pertama.
+func bestParagraph(content, language string, queryVector map[string]float64, minWords, maxWords int) string {
+	analyzer := analyzerFor(language)
+	paragraphs := splitParagraphs(content, minWords)
+	if len(paragraphs) == 0 {
+		return ""
+	}
+	best, bestMatches := paragraphs[0], 0
+	for _, paragraph := range paragraphs {
+		matches := 0
+		for _, term := range uniqueTerms(analyzer.ProcessText(paragraph)) {
+			if queryVector[term] > 0 {
+				matches++
+			}
+		}
+		if matches > bestMatches {
+			best, bestMatches = paragraph, matches
+		}
+	}
+	words := strings.Fields(best)
+	if len(words) > maxWords {
+		words = append(words[:maxWords], "...")
+	}
+	return strings.Join(words, " ")
+}
+
+// Sumber dari top-k hasil: paragraf terbaik konten lengkap untuk hasil lokal,
+// snippet untuk hasil arsip dan instance remote
+func answerPassages(articles []Article, results []SearchResult, queryVector map[string]float64, cfg AnswerConfig, minWords int) []AnswerPassage {
+	topK := cfg.TopK
+	if topK <= 0 || topK > ANSWER_MAX_TOP_K {
+		topK = ANSWER_TOP_K
+	}
+	maxWords := cfg.PassageWords
+	if maxWords <= 0 {
+		maxWords = ANSWER_PASSAGE_WORDS
+	}
+
+	var passages []AnswerPassage
+	for _, result := range results {
+		if len(passages) == topK {
+			break
+		}
+		if result.DuplicateOf != nil {
+			continue
+		}
+		text := result.Content
+		if article, local := localArticle(articles, result); local {
+			if paragraph := bestParagraph(article.Content, article.Language, queryVector, minWords, maxWords); paragraph != "" {
+				text = paragraph
+			}
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		passages = append(passages, AnswerPassage{
+			N:     len(passages) + 1,
+			DocID: result.DocID,
+			URL:   result.URL,
+			Title: result.Title,
+			Node:  result.Node,
+			Text:  text,
+		})
+	}
+	return passages
+}
+
+// Artikel lengkap (dengan konten dari document store) untuk hasil dari index
+// lokal; hasil arsip dan instance remote hanya punya snippet
+func localArticle(articles []Article, result SearchResult) (Article, bool) {
+	if result.Node != "" || result.Archived || result.DocID >= len(articles) {
+		return Article{}, false
+	}
+	article := articles[result.DocID]
+	if article.Content == "" && hasDocStore() {
+		stored, err := storedArticle(result.DocID)
+		if err != nil {
+			log.Printf("Error reading doc %d from document store: %v", result.DocID, err)
+		}
+		article.Content = stored.Content
+	}
+	return article, true
+}
+
+var (
+	citationPattern  = regexp.MustCompile(`\[(\d+)\]`)
+	spaceBeforePunct = regexp.MustCompile(`\s+([.,;:!?])`)
+)
+
+// Sumber yang dikutip jawaban, urut kemunculan. Nomor di luar daftar sumber
+// dihapus dari jawaban.
+func answerCitations(answer string, passages []AnswerPassage) (string, []AnswerPassage) {
+	citations := []AnswerPassage{}
+	cited := make(map[int]bool)
+	answer = citationPattern.ReplaceAllStringFunc(answer, func(match string) string {
+		n, _ := strconv.Atoi(match[1 : len(match)-1])
+		if n < 1 || n > len(passages) {
+			return ""
+		}
+		if !cited[n] {
+			cited[n] = true
+			citations = append(citations, passages[n-1])
+		}
+		return match
+	})
+	return strings.TrimSpace(spaceBeforePunct.ReplaceAllString(answer, "$1")), citations
+}
+
+// Cache jawaban per (generasi corpus, query, filter); panggilan LLM mahal
+type AnswerCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedAnswer
+	order   []string
+}
+
+type cachedAnswer struct {
+	response AnswerResponse
+	expires  time.Time
+}
+
+var answerCache = &AnswerCache{entries: make(map[string]*cachedAnswer)}
+
+func answerKey(query, method string, opts SearchOptions) string {
+	return fmt.Sprintf("%s|%s|%s|%s", corpusGenerationKey(documentLog.Pending()), normalizeQuery(query), method, filterParams(opts))
+}
+
+func (ac *AnswerCache) Get(key string) (AnswerResponse, bool) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	if entry, exists := ac.entries[key]; exists && time.Now().Before(entry.expires) {
+		return entry.response, true
+	}
+	return AnswerResponse{}, false
+}
+
+func (ac *AnswerCache) Put(key string, response AnswerResponse) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	if _, exists := ac.entries[key]; !exists {
+		for len(ac.order) >= ANSWER_CACHE_SIZE {
+			delete(ac.entries, ac.order[0])
+			ac.order = ac.order[1:]
+		}
+		ac.order = append(ac.order, key)
+	}
+	ac.entries[key] = &cachedAnswer{response: response, expires: time.Now().Add(ANSWER_CACHE_TTL)}
+}
+
+// Cari, susun sumber, lalu minta jawaban ke provider
+func answerQuery(ctx context.Context, query, method string, opts SearchOptions) (AnswerResponse, error) {
+	opts = opts.withConfig()
+	cfg := opts.Config.Answer
+	provider, err := answerProvider(cfg)
+	if err != nil {
+		return AnswerResponse{}, err
+	}
+	response := AnswerResponse{Query: query, Citations: []AnswerPassage{}, Provider: provider.Name()}
+
+	articles, _, err := loadIndex()
+	if err != nil {
+		return AnswerResponse{}, err
+	}
+	results, _ := searchWithPlan(query, method, opts)
+	queryVector := buildQueryVector(query)
+	synonyms.Expand(queryVector)
+	passages := answerPassages(articles, results, queryVector, cfg, opts.Config.ParagraphMinWords)
+	if len(passages) == 0 {
+		return response, nil
+	}
+
+	answer, err := provider.Answer(ctx, query, passages)
+	if err != nil {
+		return AnswerResponse{}, err
+	}
+	answer, citations := answerCitations(answer, passages)
+	if answer == "" || len(citations) == 0 {
+		// Jawaban tanpa kutipan tidak bisa diverifikasi pengguna
+		if answer != "" {
+			log.Printf("Discarding uncited answer for %q", query)
+		}
+		return response, nil
+	}
+	response.Answer, response.Citations = answer, citations
+	return response, nil
+}
+
+// GET /api/answer?q=...&method=cosine, menerima parameter filter yang sama
+// dengan /api/search
+func answerHandler(c *gin.Context) {
+	cfg := requestConfig(c)
+	if !cfg.Answer.Enabled {
+		c.JSON(http.StatusNotFound, gin.H{"error": "answers are disabled"})
+		return
+	}
+	query := normalizeQuery(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	if len(query) > ANSWER_MAX_QUERY_LENGTH {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("q is longer than %d characters", ANSWER_MAX_QUERY_LENGTH)})
+		return
+	}
+	method := c.DefaultQuery("method", "cosine")
+	if !validSearchMethod(method) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "method must be cosine, jaccard or bm25"})
+		return
+	}
+	opts := searchOptionsFromQuery(c)
+	// Sumber jawaban selalu hasil paling relevan; query plan tidak dipakai
+	opts.Sort, opts.Explain = SortRelevance, false
+
+	key := answerKey(query, method, opts)
+	if cached, exists := answerCache.Get(key); exists {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	timeout := time.Duration(cfg.Answer.TimeoutSec) * time.Second
+	if timeout <= 0 {
+		timeout = ANSWER_TIMEOUT
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+	response, err := answerQuery(ctx, query, method, opts)
+	if err != nil {
+		log.Printf("Error answering %q: %v", query, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "answer provider failed: " + err.Error()})
+		return
+	}
+	answerCache.Put(key, response)
+	c.JSON(http.StatusOK, response)
+}
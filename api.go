@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Batas hasil per halaman di JSON API
+const API_MAX_PER_PAGE = 100
+
+// Hasil pencarian versi JSON API, dipakai oleh package client
+type SearchAPIResult struct {
+	DocID       int     `json:"doc_id"`
+	Title       string  `json:"title"`
+	URL         string  `json:"url"`
+	Snippet     string  `json:"snippet"`
+	Highlighted string  `json:"highlighted"`
+	Score       float64 `json:"score"`
+	Author      string  `json:"author,omitempty"`
+	Category    string  `json:"category,omitempty"`
+	// Text directive untuk URL (url + "#:~:" + text_fragment), lompat ke paragraf yang cocok
+	TextFragment string `json:"text_fragment,omitempty"`
+
+	Position       int       `json:"position"` // peringkat 1-based di seluruh hasil
+	Words          int       `json:"words"`
+	ReadingMinutes int       `json:"reading_minutes"`
+	Date           time.Time `json:"date,omitempty"`
+	Price          int64     `json:"price,omitempty"`
+	Archived       bool      `json:"archived,omitempty"` // dari arsip, hanya dengan include_archive=1
+	Node           string    `json:"node,omitempty"`     // instance remote asal hasil (remote_instances)
+	DocType        string    `json:"doctype,omitempty"`  // article, pdf, docx
+	Page           int       `json:"page,omitempty"`     // halaman yang paling cocok (dokumen hasil OCR)
+	// Field schema tambahan yang stored
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// Salinan artikel yang sama di source lain (sindikasi)
+	AlsoPublished []SyndicatedCopy `json:"also_published,omitempty"`
+	// Hasil yang digabung ke hasil ini; dengan collapse=false hasil yang
+	// akan menggantikan hasil ini
+	Collapsed   []CollapsedResult `json:"collapsed,omitempty"`
+	DuplicateOf *CollapsedResult  `json:"duplicate_of,omitempty"`
+	// Ditemukan lewat terjemahan query, lihat translation
+	Translated bool `json:"translated,omitempty"`
+}
+
+type SearchAPIResponse struct {
+	Query        string `json:"query"`
+	Method       string `json:"method"`
+	Page         int    `json:"page"`
+	PerPage      int    `json:"per_page"`
+	TotalPages   int    `json:"total_pages"`
+	TotalResults int    `json:"total_results"`
+	// Hasil yang digabung ke hasil lain, tidak termasuk total_results
+	Collapsed int `json:"collapsed"`
+	// []SearchAPIResult, atau hanya key yang dipilih lewat fields/include_content
+	Results interface{}     `json:"results"`
+	Facets  SearchAPIFacets `json:"facets"`
+	Plan    string          `json:"plan,omitempty"`
+	Partial bool            `json:"partial,omitempty"` // ada tier (mis. arsip) yang timeout
+	// Terjemahan query yang ikut dicari (translation.enabled)
+	Translation *QueryTranslation `json:"translation,omitempty"`
+	// Query yang dipecah/dikoreksi: sudah dicari (applied) atau hanya saran
+	Rewrite *QueryRewrite `json:"rewrite,omitempty"`
+	// Kalimat jawaban untuk query pertanyaan, hanya di halaman pertama
+	FeaturedAnswer *FeaturedAnswer `json:"featured_answer,omitempty"`
+	// Entitas di query dan filter aktif, untuk chip yang bisa dibuang
+	Structure QueryStructure `json:"structure"`
+	// Waktu pencarian di server, termasuk facet dan paginasi
+	TookMs float64 `json:"took_ms"`
+	// Generasi index yang menjawab; kirim sebagai X-Index-Generation untuk
+	// mengulang query di index yang sama
+	Generation int `json:"generation"`
+}
+
+// Key hasil yang bisa dipilih lewat fields=...
+var apiResultFields = jsonKeys(reflect.TypeOf(SearchAPIResult{}))
+
+// Facet dihitung dari semua hasil, bukan hanya halaman ini
+type SearchAPIFacets struct {
+	Source   []FacetValue `json:"source"`
+	Year     []FacetValue `json:"year"`
+	Author   []FacetValue `json:"author"`
+	Category []FacetValue `json:"category"`
+	DocType  []FacetValue `json:"doctype"`
+}
+
+// GET /api/search?q=...&method=cosine|jaccard|bm25&page=1&per_page=10, menerima
+// parameter sort dan filter yang sama dengan /search. fields=title,url,score
+// dan include_content=false memperkecil response untuk klien ringan.
+func searchAPIHandler(c *gin.Context) {
+	query := normalizeQuery(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	response, status, err := runSearchAPI(c, query)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// Jalankan query dengan parameter API dari query string; dipakai juga oleh
+// pencarian suara. Error dikembalikan bersama status HTTP-nya.
+func runSearchAPI(c *gin.Context, query string) (*SearchAPIResponse, int, error) {
+	method := c.DefaultQuery("method", "cosine")
+	if !validSearchMethod(method) {
+		return nil, http.StatusBadRequest, errors.New("method must be cosine, jaccard or bm25")
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	// per_page di atas batas dipotong ke API_MAX_PER_PAGE; nilai efektifnya
+	// dikembalikan di response
+	perPage, err := strconv.Atoi(c.DefaultQuery("per_page", strconv.Itoa(ITEMS_PER_PAGE)))
+	if err != nil || perPage < 1 {
+		return nil, http.StatusBadRequest, errors.New("per_page must be a positive number")
+	}
+	if perPage > API_MAX_PER_PAGE {
+		perPage = API_MAX_PER_PAGE
+	}
+	selected, err := resultFieldSelection(c.Query("fields"), c.Query("include_content"))
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	opts := searchOptionsFromQuery(c)
+	if _, err := pinnedGeneration(c); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if opts.Generation > 0 && !generationAvailable(opts.Generation) {
+		return nil, http.StatusGone, fmt.Errorf("index generation %d is not retained", opts.Generation)
+	}
+
+	start := time.Now()
+	allResults, plan := searchWithPlan(query, method, opts)
+	shadowQuery(query, method, opts, allResults, time.Since(start))
+
+	logQuery(QueryLogEntry{
+		Time:       start,
+		Query:      query,
+		Method:     method,
+		Results:    len(allResults),
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	})
+	pagedResults, page, totalPages := paginate(allResults, page, perPage)
+
+	response := SearchAPIResponse{
+		Query:        query,
+		Method:       method,
+		Page:         page,
+		PerPage:      perPage,
+		TotalPages:   totalPages,
+		TotalResults: len(allResults),
+		Collapsed:    collapsedCount(allResults),
+		Facets: SearchAPIFacets{
+			Source:   sourceFacet(allResults, SOURCE_FACET_SIZE),
+			Year:     yearFacet(allResults, YEAR_FACET_SIZE),
+			Author:   authorFacet(allResults, AUTHOR_FACET_SIZE),
+			Category: categoryFacet(allResults, CATEGORY_FACET_SIZE),
+			DocType:  docTypeFacet(allResults, DOCTYPE_FACET_SIZE),
+		},
+		Plan:           explainPlan(plan, opts.Explain),
+		Partial:        plan.Partial(),
+		Translation:    plan.translation(),
+		Rewrite:        plan.rewrite(),
+		FeaturedAnswer: pageFeaturedAnswer(query, allResults, page, opts),
+		Structure:      parseQueryStructure(query, method, opts),
+	}
+	results := make([]SearchAPIResult, len(pagedResults))
+	for i, result := range pagedResults {
+		results[i] = SearchAPIResult{
+			DocID:          result.DocID,
+			Title:          result.Title,
+			URL:            result.URL,
+			Snippet:        result.Content,
+			Highlighted:    string(result.HighlightedContent),
+			Score:          result.Score,
+			Author:         result.Author,
+			Category:       result.Category,
+			TextFragment:   result.TextFragment,
+			Position:       result.Position,
+			Words:          result.Words,
+			ReadingMinutes: result.ReadingMinutes,
+			Date:           result.Date,
+			Price:          result.Price,
+			Archived:       result.Archived,
+			Node:           result.Node,
+			DocType:        result.DocType,
+			Page:           result.Page,
+			Fields:         result.Fields,
+			AlsoPublished:  result.AlsoPublished,
+			Collapsed:      result.Collapsed,
+			DuplicateOf:    result.DuplicateOf,
+			Translated:     result.Translated,
+		}
+	}
+	if response.Results, err = shapeResults(results, selected); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	response.TookMs = float64(time.Since(start).Microseconds()) / 1000
+	response.Generation = opts.Generation
+	if response.Generation == 0 {
+		response.Generation = currentGeneration()
+	}
+	c.Header(INDEX_GENERATION_HEADER, strconv.Itoa(response.Generation))
+	return &response, http.StatusOK, nil
+}
+
+func collapsedCount(results []SearchResult) int {
+	count := 0
+	for _, result := range results {
+		count += len(result.Collapsed)
+	}
+	return count
+}
+
+// Key hasil yang dikirim, nil berarti semua. fields memilih key tertentu,
+// include_content=false membuang snippet dan highlighted.
+func resultFieldSelection(fields, includeContent string) (map[string]bool, error) {
+	var selected map[string]bool
+	if fields != "" {
+		selected = make(map[string]bool)
+		for _, name := range strings.Split(fields, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if !apiResultFields[name] {
+				return nil, fmt.Errorf("unknown result field %q", name)
+			}
+			selected[name] = true
+		}
+	}
+
+	if includeContent == "" {
+		return selected, nil
+	}
+	include, err := strconv.ParseBool(includeContent)
+	if err != nil {
+		return nil, fmt.Errorf("include_content must be true or false")
+	}
+	if !include {
+		if selected == nil {
+			selected = make(map[string]bool, len(apiResultFields))
+			for name := range apiResultFields {
+				selected[name] = true
+			}
+		}
+		delete(selected, "snippet")
+		delete(selected, "highlighted")
+	}
+	return selected, nil
+}
+
+// Hasil dengan key terpilih saja; omitempty tetap berlaku
+func shapeResults(results []SearchAPIResult, selected map[string]bool) (interface{}, error) {
+	if selected == nil {
+		return results, nil
+	}
+	shaped := make([]map[string]json.RawMessage, len(results))
+	for i, result := range results {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(data, &all); err != nil {
+			return nil, err
+		}
+		shaped[i] = make(map[string]json.RawMessage, len(selected))
+		for key, value := range all {
+			if selected[key] {
+				shaped[i][key] = value
+			}
+		}
+	}
+	return shaped, nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// URL hasil sesuai urutan
+func resultURLs(results []SearchAPIResult) []string {
+	urls := make([]string, len(results))
+	for i, result := range results {
+		urls[i] = result.URL
+	}
+	return urls
+}
+
+type searchAPITestResponse struct {
+	SearchAPIResponse
+	Results []SearchAPIResult `json:"results"`
+}
+
+func searchAPI(t *testing.T, target string) searchAPITestResponse {
+	t.Helper()
+	var res searchAPITestResponse
+	getJSON(t, target, &res)
+	return res
+}
+
+func TestSearchAPIRanking(t *testing.T) {
+	res := searchAPI(t, "/api/search?q=apartemen")
+
+	want := []string{
+		"https://artikel.rumah123.com/apartemen-murah-jakarta-selatan",
+		"https://propertiterkini.com/tips-hunian-vertikal-keluarga",
+	}
+	if got := resultURLs(res.Results); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("results %v, want %v", got, want)
+	}
+	if res.TotalResults != 2 || res.TotalPages != 1 || res.Page != 1 || res.PerPage != ITEMS_PER_PAGE {
+		t.Errorf("total_results %d, total_pages %d, page %d, per_page %d", res.TotalResults, res.TotalPages, res.Page, res.PerPage)
+	}
+	for i, result := range res.Results {
+		if result.Position != i+1 {
+			t.Errorf("result %d has position %d", i, result.Position)
+		}
+	}
+	if res.Results[0].Score <= res.Results[1].Score {
+		t.Errorf("scores not descending: %v, %v", res.Results[0].Score, res.Results[1].Score)
+	}
+	if !strings.HasPrefix(res.Results[0].Highlighted, "<em>Apartemen</em> murah di Jakarta Selatan") {
+		t.Errorf("highlighted %q", res.Results[0].Highlighted)
+	}
+	if strings.Contains(res.Results[0].Snippet, "<em>") {
+		t.Errorf("snippet should be plain text: %q", res.Results[0].Snippet)
+	}
+}
+
+func TestSearchAPIPagination(t *testing.T) {
+	seen := make(map[string]bool)
+	for page, want := range []int{5, 5, 2} {
+		res := searchAPI(t, "/api/search?q=subsidi&per_page=5&page="+strconv.Itoa(page+1))
+		if res.Page != page+1 || res.PerPage != 5 || res.TotalPages != 3 || res.TotalResults != 12 {
+			t.Errorf("page %d: page %d, per_page %d, total_pages %d, total_results %d", page+1, res.Page, res.PerPage, res.TotalPages, res.TotalResults)
+		}
+		if len(res.Results) != want {
+			t.Errorf("page %d has %d results, want %d", page+1, len(res.Results), want)
+		}
+		for i, result := range res.Results {
+			if seen[result.URL] {
+				t.Errorf("%s on more than one page", result.URL)
+			}
+			seen[result.URL] = true
+			if result.Position != page*5+i+1 {
+				t.Errorf("%s has position %d, want %d", result.URL, result.Position, page*5+i+1)
+			}
+		}
+	}
+	if len(seen) != 12 {
+		t.Errorf("pages cover %d results, want 12", len(seen))
+	}
+
+	// Halaman di luar jangkauan dijawab dengan halaman terakhir
+	if res := searchAPI(t, "/api/search?q=subsidi&per_page=5&page=9"); res.Page != 3 || len(res.Results) != 2 {
+		t.Errorf("page 9: page %d with %d results", res.Page, len(res.Results))
+	}
+	if res := searchAPI(t, "/api/search?q=subsidi&per_page=500"); res.PerPage != API_MAX_PER_PAGE || len(res.Results) != 12 {
+		t.Errorf("per_page 500: per_page %d with %d results", res.PerPage, len(res.Results))
+	}
+	for _, perPage := range []string{"0", "-1", "abc"} {
+		if w := get(t, "/api/search?q=subsidi&per_page="+perPage); w.Code != http.StatusBadRequest {
+			t.Errorf("per_page=%s: status %d", perPage, w.Code)
+		}
+	}
+}
+
+func TestSearchAPIFilters(t *testing.T) {
+	res := searchAPI(t, "/api/search?q=subsidi&source=propertiterkini")
+	if res.TotalResults != 4 {
+		t.Errorf("source filter: %d results, want 4", res.TotalResults)
+	}
+	for _, result := range res.Results {
+		if !strings.HasPrefix(result.URL, "https://propertiterkini.com/") {
+			t.Errorf("source filter let %s through", result.URL)
+		}
+	}
+
+	// Tanggal fixture: bulan ke-(i+1) 2022 untuk kota ke-i
+	res = searchAPI(t, "/api/search?q=subsidi&from=2022-03-01&to=2022-05-31&sort=date_desc")
+	want := []string{
+		"https://artikel.rumah123.com/rumah-subsidi-cikarang",
+		"https://artikel.rumah123.com/rumah-subsidi-tangerang",
+		"https://propertiterkini.com/rumah-subsidi-bogor",
+	}
+	if got := resultURLs(res.Results); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("date filter: results %v, want %v", got, want)
+	}
+
+	res = searchAPI(t, "/api/search?q=apartemen&author=Sinta+Dewi")
+	if got := resultURLs(res.Results); len(got) != 1 || got[0] != "https://artikel.rumah123.com/apartemen-murah-jakarta-selatan" {
+		t.Errorf("author filter: results %v", got)
+	}
+
+	// Facet dihitung dari semua hasil, bukan hanya halaman ini
+	res = searchAPI(t, "/api/search?q=subsidi&per_page=1")
+	counts := make(map[string]int)
+	for _, facet := range res.Facets.Source {
+		counts[facet.Value] = facet.Count
+	}
+	if counts["rumah123"] != 8 || counts["propertiterkini"] != 4 {
+		t.Errorf("source facet %v", res.Facets.Source)
+	}
+}
+
+func TestSearchAPIRequiresQuery(t *testing.T) {
+	if w := get(t, "/api/search?q=+"); w.Code != http.StatusBadRequest {
+		t.Errorf("status %d", w.Code)
+	}
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Retensi dokumen per source. Artikel yang lebih tua dari batas source-nya
+// dipindah dari corpus utama ke archive.json (cold index): tidak ikut
+// pencarian biasa, tapi tetap bisa dicari dengan include_archive=1.
+// Kebijakan di config.json, dalam hari, "*" untuk source lain:
+//
+//	"archive_after_days": {"*": 1825, "rumah123": 730}
+//
+// Job background menegakkan kebijakan setiap ARCHIVE_INTERVAL. Artikel
+// ditulis ke arsip dulu baru dihapus lewat WAL, jadi crash di tengah jalan
+// paling buruk membuat artikel ada di dua tempat (arsip lalu dilewati karena
+// masih ada di corpus utama), tidak pernah hilang.
+const (
+	ARCHIVE_FILE           = "archive.json"
+	ARCHIVE_INTERVAL       = time.Hour
+	ARCHIVE_DEFAULT_SOURCE = "*"
+
+	JobArchive = "archive"
+)
+
+type ArchiveRun struct {
+	Time     time.Time      `json:"time"`
+	Archived int            `json:"archived"`
+	BySource map[string]int `json:"by_source,omitempty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+var (
+	archiveMu      sync.Mutex
+	lastArchiveRun *ArchiveRun
+)
+
+// Index arsip di-cache sampai archive.json berubah (modtime/ukuran)
+var archiveIndex struct {
+	mu       sync.Mutex
+	modTime  time.Time
+	size     int64
+	articles []Article
+	idx      *InvertedIndex
+}
+
+// Batas umur artikel dari source ini; ok false kalau tidak ada kebijakan
+func archiveCutoff(policy map[string]int, source string, now time.Time) (time.Time, bool) {
+	days, exists := policy[source]
+	if !exists {
+		days, exists = policy[ARCHIVE_DEFAULT_SOURCE]
+	}
+	if !exists || days <= 0 {
+		return time.Time{}, false
+	}
+	return now.AddDate(0, 0, -days), true
+}
+
+// Artikel yang sudah melewati batas retensi. Artikel tanpa tanggal dan
+// artikel yang di-soft delete tidak pernah diarsipkan.
+func expiredArticles(articles []Article, policy map[string]int, now time.Time) []Article {
+	var expired []Article
+	for _, article := range articles {
+		if article.Date.IsZero() || article.Deleted() {
+			continue
+		}
+		cutoff, ok := archiveCutoff(policy, sourceOf(article.URL), now)
+		if ok && article.Date.Before(cutoff) {
+			expired = append(expired, article)
+		}
+	}
+	return expired
+}
+
+// Gabungkan artikel baru ke arsip; URL yang sama diganti versi terbaru
+func mergeArchive(archived, expired []Article) []Article {
+	position := make(map[string]int, len(archived))
+	for i, article := range archived {
+		position[article.URL] = i
+	}
+	for _, article := range expired {
+		if i, exists := position[article.URL]; exists {
+			archived[i] = article
+			continue
+		}
+		position[article.URL] = len(archived)
+		archived = append(archived, article)
+	}
+	return archived
+}
+
+func readArchive() ([]Article, error) {
+	archived, err := readArticlesFile(ARCHIVE_FILE)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return archived, nil
+}
+
+// Satu putaran penegakan kebijakan retensi
+func runArchival(now time.Time) (*ArchiveRun, error) {
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+
+	run := &ArchiveRun{Time: now}
+	defer func() { lastArchiveRun = run }()
+
+	cfg := currentConfig()
+	policy := cfg.ArchiveAfterDays
+	if len(policy) == 0 {
+		return run, nil
+	}
+
+	articles, err := loadArticles()
+	if err != nil {
+		run.Error = err.Error()
+		return run, err
+	}
+	articles = applyWalRecords(articles, documentLog.Pending(), cfg)
+
+	expired := expiredArticles(articles, policy, now)
+	if len(expired) == 0 {
+		return run, nil
+	}
+
+	archived, err := readArchive()
+	if err != nil {
+		run.Error = err.Error()
+		return run, err
+	}
+	if err := writeJSONFile(ARCHIVE_FILE, mergeArchive(archived, expired)); err != nil {
+		run.Error = err.Error()
+		return run, err
+	}
+
+	records := make([]WalRecord, len(expired))
+	run.BySource = make(map[string]int)
+	for i, article := range expired {
+		records[i] = WalRecord{Op: WalPurge, URL: article.URL}
+		run.BySource[sourceOf(article.URL)]++
+	}
+	if _, err := documentLog.Append(records...); err != nil {
+		run.Error = err.Error()
+		return run, err
+	}
+	run.Archived = len(expired)
+	log.Printf("Archived %d articles to %s", run.Archived, ARCHIVE_FILE)
+	return run, nil
+}
+
+func startArchiver(interval time.Duration) {
+	adminJobs.Every(JobArchive, interval)
+}
+
+func archiveJob(ctx context.Context, job jobRun) error {
+	run, err := runArchival(time.Now())
+	if run != nil {
+		job.Advance(run.Archived, run.Archived)
+	}
+	return err
+}
+
+// Index arsip, nil kalau archive.json belum ada
+func loadArchiveIndex() ([]Article, *InvertedIndex, error) {
+	info, err := os.Stat(ARCHIVE_FILE)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	archiveIndex.mu.Lock()
+	defer archiveIndex.mu.Unlock()
+	if archiveIndex.idx != nil && archiveIndex.modTime.Equal(info.ModTime()) && archiveIndex.size == info.Size() {
+		return archiveIndex.articles, archiveIndex.idx, nil
+	}
+
+	articles, err := readArticlesFile(ARCHIVE_FILE)
+	if err != nil {
+		return nil, nil, err
+	}
+	reviewQueue.ApplyEdits(articles)
+	archiveIndex.articles = articles
+	archiveIndex.idx = buildInvertedIndex(articles, currentConfig())
+	archiveIndex.modTime, archiveIndex.size = info.ModTime(), info.Size()
+	return archiveIndex.articles, archiveIndex.idx, nil
+}
+
+// Cari di arsip; artikel yang masih ada di corpus utama dilewati dan skor
+// dikurangi sesuai umur artikel (lihat archivePenalty)
+func searchArchive(hot []Article, query string, queryVector map[string]float64, method string, opts SearchOptions) ([]SearchResult, error) {
+	opts = opts.withConfig()
+	articles, idx, err := loadArchiveIndex()
+	if err != nil || idx == nil {
+		return nil, err
+	}
+
+	hotURLs := make(map[string]bool, len(hot))
+	for _, article := range hot {
+		hotURLs[article.URL] = true
+	}
+
+	now := time.Now()
+	results, _ := searchIndex(articles, idx, query, queryVector, method, opts, true)
+	kept := results[:0]
+	for _, result := range results {
+		if !hotURLs[result.URL] {
+			result.Score *= archivePenalty(opts.Config.ArchivePenaltyPerYear, result.Date, now)
+			kept = append(kept, result)
+		}
+	}
+	return kept, nil
+}
+
+// Urutkan gabungan hasil beberapa tier (arsip, instance remote).
+// DocValues.Sort tidak bisa dipakai karena docID antar index tumpang tindih;
+// tanggal dan harga diambil dari hasilnya sendiri. Skor sama: corpus utama
+// dulu, lalu index lokal sebelum instance remote.
+func sortMergedResults(results []SearchResult, sortBy string) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].Archived != results[j].Archived {
+			return !results[i].Archived
+		}
+		if results[i].Node != results[j].Node {
+			return results[i].Node < results[j].Node
+		}
+		return results[i].DocID < results[j].DocID
+	})
+
+	var value func(result SearchResult) int64
+	desc := false
+	switch sortBy {
+	case SortDateDesc, SortDateAsc:
+		value, desc = resultDate, sortBy == SortDateDesc
+	case SortPriceDesc, SortPriceAsc:
+		value, desc = resultPrice, sortBy == SortPriceDesc
+	default:
+		return
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := value(results[i]), value(results[j])
+		if a == 0 || b == 0 {
+			return a != 0
+		}
+		if desc {
+			return a > b
+		}
+		return a < b
+	})
+}
+
+func resultDate(result SearchResult) int64 {
+	if result.Date.IsZero() {
+		return 0
+	}
+	return result.Date.Unix()
+}
+
+func resultPrice(result SearchResult) int64 {
+	return result.Price
+}
+
+// GET /api/admin/archive
+func archiveStatusHandler(c *gin.Context) {
+	archived, err := readArchive()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	bySource := make(map[string]int)
+	for _, article := range archived {
+		bySource[sourceOf(article.URL)]++
+	}
+
+	archiveMu.Lock()
+	lastRun := lastArchiveRun
+	archiveMu.Unlock()
+
+	c.JSON(http.StatusOK, gin.H{
+		"policy":    requestConfig(c).ArchiveAfterDays,
+		"archived":  len(archived),
+		"by_source": bySource,
+		"last_run":  lastRun,
+	})
+}
+
+// POST /api/admin/archive, jalankan kebijakan retensi sekarang
+func runArchiveHandler(c *gin.Context) {
+	run, err := runArchival(time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	auditActor(c).Record(AuditArchiveRun, "", nil, run)
+	c.JSON(http.StatusOK, run)
+}
+
+func init() {
+	registerJobKind(JobArchive, "", maintenanceRetry, archiveJob)
+}
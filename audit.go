@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Audit log perubahan yang dilakukan lewat admin API: edit/hapus dokumen,
+// review, blocklist, sinonim, feature flag, sumber crawl, config, merge
+// index, arsip, query yang dipantau dan job admin. Setiap entry mencatat siapa (header X-Admin-Actor,
+// karena ADMIN_TOKEN dipakai bersama), kapan, dan snapshot sebelum/sesudah
+// perubahan. Perubahan dokumen dari job bulk dicatat per dokumen dengan id
+// job-nya. Format JSON lines di audit.log, hanya ditambah, dibaca lewat
+// GET /api/admin/audit.
+const (
+	AUDIT_LOG_FILE      = "audit.log"
+	ADMIN_ACTOR_HEADER  = "X-Admin-Actor"
+	AUDIT_DEFAULT_ACTOR = "admin"
+
+	AUDIT_DEFAULT_LIMIT = 100
+	AUDIT_MAX_LIMIT     = 1000
+)
+
+// Aksi yang dicatat; perubahan dokumen memakai "document.<op WAL>"
+const (
+	AuditBlocklistAdd    = "blocklist.add"
+	AuditBlocklistRemove = "blocklist.remove"
+	AuditReviewDecision  = "review." // + approved/rejected
+	AuditReviewEdit      = "review.edit"
+	AuditSynonymDecision = "synonym." // + approved/rejected
+	AuditFlagSet         = "flag.set"
+	AuditSourceSave      = "source.save"
+	AuditConfigUpdate    = "config.update"
+	AuditConfigRollback  = "config.rollback"
+	AuditIndexMerge      = "index.merge"
+	AuditReindex         = "index.reindex"
+	AuditArchiveRun      = "archive.run"
+	AuditJobStart        = "job.start"
+	AuditJobCancel       = "job.cancel"
+	AuditMonitorAdd      = "monitor.add"
+	AuditMonitorRemove   = "monitor.remove"
+	AuditDocument        = "document." // + op WAL
+)
+
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"`
+	IP     string    `json:"ip,omitempty"`
+	Action string    `json:"action"`
+	Target string    `json:"target,omitempty"`
+	// Job bulk yang melakukan perubahan ini
+	Job string `json:"job,omitempty"`
+	// Seq record WAL untuk perubahan dokumen
+	Seq    uint64          `json:"seq,omitempty"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// Pelaku perubahan, diambil dari request admin sebelum job berjalan di background
+type AuditActor struct {
+	Name string
+	IP   string
+	Job  string
+}
+
+var auditMu sync.Mutex
+
+func auditActor(c *gin.Context) AuditActor {
+	name := strings.TrimSpace(c.GetHeader(ADMIN_ACTOR_HEADER))
+	if name == "" {
+		name = AUDIT_DEFAULT_ACTOR
+	}
+	return AuditActor{Name: name, IP: c.ClientIP()}
+}
+
+// Snapshot JSON saat ini juga, supaya perubahan berikutnya tidak ikut tercatat
+func auditSnapshot(value interface{}) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Error encoding audit snapshot: %v", err)
+		return nil
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	return data
+}
+
+// Perubahan yang dilakukan job atas nama actor
+func (a AuditActor) forJob(job jobRun) AuditActor {
+	a.Job = job.id
+	return a
+}
+
+func (a AuditActor) entry(action, target string, before, after json.RawMessage) AuditEntry {
+	return AuditEntry{
+		Time:   time.Now(),
+		Actor:  a.Name,
+		IP:     a.IP,
+		Action: action,
+		Target: target,
+		Job:    a.Job,
+		Before: before,
+		After:  after,
+	}
+}
+
+// Catat satu perubahan; before sebaiknya sudah berupa auditSnapshot yang
+// diambil sebelum perubahan
+func (a AuditActor) Record(action, target string, before json.RawMessage, after interface{}) {
+	writeAudit(a.entry(action, target, before, auditSnapshot(after)))
+}
+
+// Tulis record ke WAL dan catat setiap perubahan dokumen beserta isi
+// dokumen sebelumnya
+func (a AuditActor) appendDocuments(records ...WalRecord) ([]WalRecord, error) {
+	before := currentArticles(records)
+	accepted, err := documentLog.Append(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]AuditEntry, len(accepted))
+	for i, record := range accepted {
+		var previous json.RawMessage
+		if article, exists := before[record.URL]; exists {
+			previous = auditSnapshot(article)
+		}
+		var next json.RawMessage
+		if record.Article != nil {
+			next = auditSnapshot(record.Article)
+		}
+		entries[i] = a.entry(AuditDocument+record.Op, record.URL, previous, next)
+		entries[i].Seq = record.Seq
+	}
+	writeAudit(entries...)
+	return accepted, nil
+}
+
+// Versi terbaru (articles.json + WAL tertunda) artikel yang disentuh records
+func currentArticles(records []WalRecord) map[string]Article {
+	urls := make(map[string]bool, len(records))
+	for _, record := range records {
+		if record.Article != nil {
+			urls[record.Article.URL] = true
+		} else {
+			urls[record.URL] = true
+		}
+	}
+
+	articles, err := loadArticles()
+	if err != nil {
+		return nil
+	}
+	current := make(map[string]Article, len(urls))
+	for _, article := range applyWalRecords(articles, documentLog.Pending(), currentConfig()) {
+		if urls[article.URL] {
+			current[article.URL] = article
+		}
+	}
+	return current
+}
+
+// Append entry ke audit log, error hanya dicatat supaya perubahan admin tidak gagal
+func writeAudit(entries ...AuditEntry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	var buf []byte
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			log.Printf("Error encoding audit entry %s: %v", entry.Action, err)
+			continue
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+
+	f, err := os.OpenFile(AUDIT_LOG_FILE, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Printf("Error opening audit log: %v", err)
+		return
+	}
+	defer f.Close()
+
+	if _, err := f.Write(buf); err != nil {
+		log.Printf("Error writing audit log: %v", err)
+	}
+}
+
+// Filter GET /api/admin/audit. Action yang diakhiri "." cocok sebagai prefix
+// (misalnya "document." untuk semua perubahan dokumen).
+type AuditQuery struct {
+	Actor  string
+	Action string
+	Target string
+	Job    string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+}
+
+func (q AuditQuery) matches(entry AuditEntry) bool {
+	switch {
+	case q.Actor != "" && entry.Actor != q.Actor:
+		return false
+	case q.Action != "" && !strings.HasSuffix(q.Action, ".") && entry.Action != q.Action:
+		return false
+	case strings.HasSuffix(q.Action, ".") && !strings.HasPrefix(entry.Action, q.Action):
+		return false
+	case q.Target != "" && entry.Target != q.Target:
+		return false
+	case q.Job != "" && entry.Job != q.Job:
+		return false
+	case !q.Since.IsZero() && entry.Time.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && !entry.Time.Before(q.Until):
+		return false
+	}
+	return true
+}
+
+// Entry yang cocok, terbaru dulu, paling banyak q.Limit. Baris rusak dilewati.
+// total adalah jumlah semua entry yang cocok.
+func readAuditLog(path string, q AuditQuery) (entries []AuditEntry, total int, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []AuditEntry{}, 0, nil
+		}
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	// Snapshot dokumen bisa lebih panjang dari batas baris bufio.Scanner
+	var matched []AuditEntry
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var entry AuditEntry
+			if json.Unmarshal(line, &entry) == nil && q.matches(entry) {
+				matched = append(matched, entry)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	total = len(matched)
+	entries = make([]AuditEntry, 0, q.Limit)
+	for i := len(matched) - 1; i >= 0 && len(entries) < q.Limit; i-- {
+		entries = append(entries, matched[i])
+	}
+	return entries, total, nil
+}
+
+// GET /api/admin/audit?actor=&action=&target=&job=&since=&until=&limit=,
+// since/until dalam RFC 3339
+func auditLogHandler(c *gin.Context) {
+	q := AuditQuery{
+		Actor:  c.Query("actor"),
+		Action: c.Query("action"),
+		Target: c.Query("target"),
+		Job:    c.Query("job"),
+		Limit:  AUDIT_DEFAULT_LIMIT,
+	}
+	for name, field := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		if value := c.Query(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be an RFC 3339 time"})
+				return
+			}
+			*field = t
+		}
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		if limit > AUDIT_MAX_LIMIT {
+			limit = AUDIT_MAX_LIMIT
+		}
+		q.Limit = limit
+	}
+
+	entries, total, err := readAuditLog(AUDIT_LOG_FILE, q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"entries": entries, "total": total})
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Nama penulis dinormalisasi saat index dibangun lalu disimpan sebagai
+// keyword di doc values (kolom Authors), untuk filter ?author=, facet di
+// halaman hasil dan halaman /author/<slug>. Alias dipetakan ke satu nama:
+//
+//	{"Redaksi Properti Terkini": ["redaksi", "tim redaksi propertiterkini"]}
+const (
+	AUTHOR_ALIASES_FILE = "author_aliases.json"
+	AUTHOR_FACET_SIZE   = 10
+)
+
+var (
+	// Prefix byline yang dibuang: "Oleh: ", "Penulis : ", "By ", "Ditulis oleh"
+	authorPrefixPattern = regexp.MustCompile(`(?i)^(oleh|penulis|ditulis oleh|reporter|by|written by)\s*:?(\s+|$)`)
+	// Bagian setelah pemisah biasanya editor/sumber: "Budi | Editor: Ani"
+	authorSuffixPattern = regexp.MustCompile(`\s*[|/]\s.*$|\s+-\s.*$`)
+)
+
+// alias (sudah dinormalisasi, huruf kecil) -> nama kanonik
+var authorAliases = make(map[string]string)
+
+func loadAuthorAliases(path string) (map[string]string, error) {
+	aliases := make(map[string]string)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return aliases, nil
+		}
+		return nil, err
+	}
+
+	var canonical map[string][]string
+	if err := json.Unmarshal(data, &canonical); err != nil {
+		return nil, err
+	}
+	for name, names := range canonical {
+		name = cleanAuthorName(name)
+		aliases[strings.ToLower(name)] = name
+		for _, alias := range names {
+			aliases[strings.ToLower(cleanAuthorName(alias))] = name
+		}
+	}
+	return aliases, nil
+}
+
+// Nama penulis untuk index: tanpa "Oleh:", kapitalisasi seragam, alias
+// dipetakan ke nama kanonik
+func normalizeAuthor(raw string) string {
+	name := cleanAuthorName(raw)
+	if name == "" {
+		return ""
+	}
+	if canonical, exists := authorAliases[strings.ToLower(name)]; exists {
+		return canonical
+	}
+	return name
+}
+
+func cleanAuthorName(raw string) string {
+	name := strings.Join(strings.Fields(raw), " ")
+	for {
+		stripped := authorPrefixPattern.ReplaceAllString(name, "")
+		if stripped == name {
+			break
+		}
+		name = stripped
+	}
+	name = authorSuffixPattern.ReplaceAllString(name, "")
+	name = strings.Trim(name, " ,.:;-")
+
+	// "BUDI SANTOSO" / "budi santoso" -> "Budi Santoso"
+	words := strings.Fields(name)
+	for i, word := range words {
+		words[i] = titleWord(word)
+	}
+	return strings.Join(words, " ")
+}
+
+func titleWord(word string) string {
+	lower := []rune(strings.ToLower(word))
+	if len(lower) == 0 {
+		return word
+	}
+	lower[0] = []rune(strings.ToUpper(string(lower[0])))[0]
+	return string(lower)
+}
+
+func authorSlug(name string) string {
+	return slugify(name)
+}
+
+// Penulis terbanyak di hasil pencarian (semua halaman)
+func authorFacet(results []SearchResult, size int) []FacetValue {
+	values := make([]string, len(results))
+	for i, result := range results {
+		values[i] = result.Author
+	}
+	return countFacet(values, size)
+}
+
+type AuthorArticle struct {
+	Title  string
+	URL    string
+	Date   time.Time
+	Source string
+}
+
+// GET /author/:slug: semua artikel seorang penulis, terbaru dulu
+func authorPageHandler(c *gin.Context) {
+	slug := c.Param("slug")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	articles, idx, err := loadIndex()
+	if err != nil {
+		log.Printf("Error loading articles: %v", err)
+		c.String(http.StatusInternalServerError, "Error loading articles")
+		return
+	}
+
+	dv := idx.DocValues
+	scopes := clientScopes(c)
+	var name string
+	var docs []int
+	for docID, author := range dv.Authors {
+		if author == "" || authorSlug(author) != slug || articles[docID].Deleted() || !articles[docID].VisibleTo(scopes) || blocklist.IsURLBlocked(articles[docID].URL) {
+			continue
+		}
+		name = author
+		docs = append(docs, docID)
+	}
+	if len(docs) == 0 {
+		renderError(c, http.StatusNotFound)
+		return
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool { return dv.Dates[docs[i]] > dv.Dates[docs[j]] })
+
+	totalPages := (len(docs) + ITEMS_PER_PAGE - 1) / ITEMS_PER_PAGE
+	if page < 1 {
+		page = 1
+	} else if page > totalPages {
+		page = totalPages
+	}
+	start := (page - 1) * ITEMS_PER_PAGE
+	end := start + ITEMS_PER_PAGE
+	if end > len(docs) {
+		end = len(docs)
+	}
+
+	list := make([]AuthorArticle, 0, end-start)
+	for _, docID := range docs[start:end] {
+		list = append(list, AuthorArticle{
+			Title:  articles[docID].Title,
+			URL:    articles[docID].URL,
+			Date:   articles[docID].Date,
+			Source: dv.Sources[docID],
+		})
+	}
+
+	renderHTML(c, http.StatusOK, "author.html", gin.H{
+		"author":       name,
+		"slug":         slug,
+		"articles":     list,
+		"totalResults": len(docs),
+		"currentPage":  page,
+		"totalPages":   totalPages,
+		"previousPage": page - 1,
+		"nextPage":     page + 1,
+		"now":          time.Now(),
+	})
+}
+
+func init() {
+	aliases, err := loadAuthorAliases(AUTHOR_ALIASES_FILE)
+	if err != nil {
+		log.Printf("Error loading %s: %v", AUTHOR_ALIASES_FILE, err)
+		return
+	}
+	authorAliases = aliases
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Bench mode (--bench-mode): cache dimatikan, job background yang bikin
+// noise tidak dijalankan, dan urutan hasil selalu deterministik, supaya
+// angka load test bisa dibandingkan antar rilis.
+var benchMode bool
+
+const (
+	BENCH_MAX_CORPUS_DOCS = 200000
+	BENCH_DEFAULT_QUERIES = 1000
+)
+
+// GET /api/bench/corpus?docs=N&seed=S, hanya aktif di bench mode.
+// Corpus sintetis yang sama untuk docs+seed yang sama.
+func benchCorpusHandler(c *gin.Context) {
+	docs, _ := strconv.Atoi(c.DefaultQuery("docs", "1000"))
+	seed, _ := strconv.ParseInt(c.DefaultQuery("seed", "1"), 10, 64)
+	if docs < 1 || docs > BENCH_MAX_CORPUS_DOCS {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("docs must be between 1 and %d", BENCH_MAX_CORPUS_DOCS)})
+		return
+	}
+	c.JSON(http.StatusOK, generateCorpus(docs, seed, 5))
+}
+
+// Query dari log beserta bobotnya (berapa kali muncul)
+type benchQuery struct {
+	Query  string
+	Method string
+	Count  int
+}
+
+// searchctl bench scenario --log queries.log --target URL --format k6|vegeta
+func benchScenarioCommand(args []string) int {
+	fs := flag.NewFlagSet("bench scenario", flag.ExitOnError)
+	logPath := fs.String("log", QUERY_LOG_FILE, "query log sumber")
+	target := fs.String("target", "http://localhost:8080", "base URL server")
+	format := fs.String("format", "vegeta", "vegeta (targets file) atau k6 (script)")
+	out := fs.String("out", "", "file output (default stdout)")
+	maxQueries := fs.Int("queries", BENCH_DEFAULT_QUERIES, "jumlah request di skenario")
+	days := fs.Int("days", 30, "ambil query dari N hari terakhir (0 = semua)")
+	fs.Parse(args)
+
+	var since time.Time
+	if *days > 0 {
+		since = time.Now().AddDate(0, 0, -*days)
+	}
+	entries, err := readQueryLog(*logPath, since)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	queries := weightedBenchQueries(entries, *maxQueries)
+	if len(queries) == 0 {
+		fmt.Fprintln(os.Stderr, "no queries in log")
+		return 1
+	}
+
+	var scenario string
+	switch *format {
+	case "vegeta":
+		scenario = vegetaTargets(*target, queries)
+	case "k6":
+		scenario = k6Script(*target, queries)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		return 2
+	}
+
+	if *out == "" {
+		fmt.Print(scenario)
+		return 0
+	}
+	if err := ioutil.WriteFile(*out, []byte(scenario), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Printf("wrote %d requests to %s\n", len(queries), *out)
+	return 0
+}
+
+// Kelompokkan query log lalu bagi jatah request sesuai frekuensinya.
+// Urutan deterministik: frekuensi menurun, lalu alfabetis.
+func weightedBenchQueries(entries []QueryLogEntry, total int) []benchQuery {
+	counts := make(map[string]*benchQuery)
+	for _, entry := range entries {
+		q := strings.ToLower(normalizeQuery(entry.Query))
+		if q == "" {
+			continue
+		}
+		method := entry.Method
+		if method == "" {
+			method = "cosine"
+		}
+		key := method + "\x00" + q
+		if counts[key] == nil {
+			counts[key] = &benchQuery{Query: q, Method: method}
+		}
+		counts[key].Count++
+	}
+
+	var distinct []benchQuery
+	sum := 0
+	for _, bq := range counts {
+		distinct = append(distinct, *bq)
+		sum += bq.Count
+	}
+	sort.Slice(distinct, func(i, j int) bool {
+		if distinct[i].Count != distinct[j].Count {
+			return distinct[i].Count > distinct[j].Count
+		}
+		if distinct[i].Query != distinct[j].Query {
+			return distinct[i].Query < distinct[j].Query
+		}
+		return distinct[i].Method < distinct[j].Method
+	})
+
+	// Setiap query minimal sekali selama masih ada jatah, sisanya proporsional
+	var scenario []benchQuery
+	for _, bq := range distinct {
+		if len(scenario) >= total {
+			break
+		}
+		n := bq.Count * total / sum
+		if n < 1 {
+			n = 1
+		}
+		for i := 0; i < n && len(scenario) < total; i++ {
+			scenario = append(scenario, bq)
+		}
+	}
+	return scenario
+}
+
+func benchSearchURL(target string, bq benchQuery) string {
+	values := url.Values{}
+	values.Set("q", bq.Query)
+	values.Set("method", bq.Method)
+	return strings.TrimSuffix(target, "/") + "/search?" + values.Encode()
+}
+
+func vegetaTargets(target string, queries []benchQuery) string {
+	var b strings.Builder
+	for _, bq := range queries {
+		b.WriteString("GET " + benchSearchURL(target, bq) + "\n\n")
+	}
+	return b.String()
+}
+
+func k6Script(target string, queries []benchQuery) string {
+	urls := make([]string, len(queries))
+	for i, bq := range queries {
+		urls[i] = benchSearchURL(target, bq)
+	}
+	data, _ := json.MarshalIndent(urls, "", "  ")
+
+	return `// Dibuat oleh: searchctl bench scenario --format k6
+import http from "k6/http";
+import { check } from "k6";
+import exec from "k6/execution";
+
+const urls = ` + string(data) + `;
+
+export const options = {
+  scenarios: {
+    replay: {
+      executor: "shared-iterations",
+      vus: 10,
+      iterations: urls.length,
+    },
+  },
+};
+
+export default function () {
+  // Urutan tetap supaya hasil run bisa dibandingkan
+  const res = http.get(urls[exec.scenario.iterationInTest % urls.length]);
+  check(res, { "status 200": (r) => r.status === 200 });
+}
+`
+}
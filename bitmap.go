@@ -0,0 +1,254 @@
+package main
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// Roaring bitmap sederhana untuk himpunan docID. docID dibagi per 65536
+// (16 bit atas sebagai key container); container kecil disimpan sebagai
+// array terurut, container padat sebagai bitset 65536 bit.
+const (
+	BITMAP_ARRAY_MAX = 4096 // di atas ini array diubah jadi bitset
+	bitmapWords      = 65536 / 64
+)
+
+type Bitmap struct {
+	keys       []uint16
+	containers []*bitmapContainer
+}
+
+type bitmapContainer struct {
+	array  []uint16 // dipakai kalau bitset == nil
+	bitset []uint64
+	count  int
+}
+
+func NewBitmap() *Bitmap {
+	return &Bitmap{}
+}
+
+// Bitmap dari daftar docID
+func BitmapOf(docIDs ...int) *Bitmap {
+	b := NewBitmap()
+	for _, docID := range docIDs {
+		b.Add(docID)
+	}
+	return b
+}
+
+func (b *Bitmap) find(key uint16) (int, bool) {
+	i := sort.Search(len(b.keys), func(i int) bool { return b.keys[i] >= key })
+	return i, i < len(b.keys) && b.keys[i] == key
+}
+
+func (b *Bitmap) Add(docID int) {
+	key, low := uint16(docID>>16), uint16(docID)
+	i, exists := b.find(key)
+	if !exists {
+		b.keys = append(b.keys, 0)
+		b.containers = append(b.containers, nil)
+		copy(b.keys[i+1:], b.keys[i:])
+		copy(b.containers[i+1:], b.containers[i:])
+		b.keys[i] = key
+		b.containers[i] = &bitmapContainer{}
+	}
+	b.containers[i].add(low)
+}
+
+func (b *Bitmap) Contains(docID int) bool {
+	if b == nil || docID < 0 {
+		return false
+	}
+	i, exists := b.find(uint16(docID >> 16))
+	return exists && b.containers[i].contains(uint16(docID))
+}
+
+func (b *Bitmap) Cardinality() int {
+	n := 0
+	for _, c := range b.containers {
+		n += c.count
+	}
+	return n
+}
+
+// Panggil fn untuk setiap docID secara terurut
+func (b *Bitmap) ForEach(fn func(docID int)) {
+	for i, c := range b.containers {
+		high := int(b.keys[i]) << 16
+		if c.bitset == nil {
+			for _, low := range c.array {
+				fn(high | int(low))
+			}
+			continue
+		}
+		for w, word := range c.bitset {
+			for word != 0 {
+				t := bits.TrailingZeros64(word)
+				fn(high | (w*64 + t))
+				word &= word - 1
+			}
+		}
+	}
+}
+
+func (b *Bitmap) ToArray() []int {
+	docIDs := make([]int, 0, b.Cardinality())
+	b.ForEach(func(docID int) {
+		docIDs = append(docIDs, docID)
+	})
+	return docIDs
+}
+
+// Irisan dua bitmap, hanya container dengan key yang sama yang diproses
+func (b *Bitmap) And(other *Bitmap) *Bitmap {
+	result := NewBitmap()
+	i, j := 0, 0
+	for i < len(b.keys) && j < len(other.keys) {
+		switch {
+		case b.keys[i] < other.keys[j]:
+			i++
+		case b.keys[i] > other.keys[j]:
+			j++
+		default:
+			if c := b.containers[i].and(other.containers[j]); c.count > 0 {
+				result.keys = append(result.keys, b.keys[i])
+				result.containers = append(result.containers, c)
+			}
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+func (b *Bitmap) Or(other *Bitmap) *Bitmap {
+	result := NewBitmap()
+	i, j := 0, 0
+	for i < len(b.keys) || j < len(other.keys) {
+		switch {
+		case j >= len(other.keys) || (i < len(b.keys) && b.keys[i] < other.keys[j]):
+			result.keys = append(result.keys, b.keys[i])
+			result.containers = append(result.containers, b.containers[i].clone())
+			i++
+		case i >= len(b.keys) || b.keys[i] > other.keys[j]:
+			result.keys = append(result.keys, other.keys[j])
+			result.containers = append(result.containers, other.containers[j].clone())
+			j++
+		default:
+			result.keys = append(result.keys, b.keys[i])
+			result.containers = append(result.containers, b.containers[i].or(other.containers[j]))
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+func (c *bitmapContainer) contains(low uint16) bool {
+	if c.bitset != nil {
+		return c.bitset[low>>6]&(1<<(low&63)) != 0
+	}
+	i := sort.Search(len(c.array), func(i int) bool { return c.array[i] >= low })
+	return i < len(c.array) && c.array[i] == low
+}
+
+func (c *bitmapContainer) add(low uint16) {
+	if c.bitset != nil {
+		if c.bitset[low>>6]&(1<<(low&63)) == 0 {
+			c.bitset[low>>6] |= 1 << (low & 63)
+			c.count++
+		}
+		return
+	}
+
+	i := sort.Search(len(c.array), func(i int) bool { return c.array[i] >= low })
+	if i < len(c.array) && c.array[i] == low {
+		return
+	}
+	c.array = append(c.array, 0)
+	copy(c.array[i+1:], c.array[i:])
+	c.array[i] = low
+	c.count++
+
+	if c.count > BITMAP_ARRAY_MAX {
+		c.toBitset()
+	}
+}
+
+func (c *bitmapContainer) toBitset() {
+	c.bitset = make([]uint64, bitmapWords)
+	for _, low := range c.array {
+		c.bitset[low>>6] |= 1 << (low & 63)
+	}
+	c.array = nil
+}
+
+func (c *bitmapContainer) clone() *bitmapContainer {
+	clone := &bitmapContainer{count: c.count}
+	if c.bitset != nil {
+		clone.bitset = append([]uint64(nil), c.bitset...)
+	} else {
+		clone.array = append([]uint16(nil), c.array...)
+	}
+	return clone
+}
+
+func (c *bitmapContainer) and(other *bitmapContainer) *bitmapContainer {
+	if c.bitset != nil && other.bitset != nil {
+		result := &bitmapContainer{bitset: make([]uint64, bitmapWords)}
+		for w := range result.bitset {
+			result.bitset[w] = c.bitset[w] & other.bitset[w]
+			result.count += bits.OnesCount64(result.bitset[w])
+		}
+		if result.count <= BITMAP_ARRAY_MAX {
+			result.toArray()
+		}
+		return result
+	}
+
+	// Minimal satu array: cek tiap elemen array ke container lain
+	small, large := c, other
+	if small.bitset != nil {
+		small, large = other, c
+	}
+	result := &bitmapContainer{}
+	for _, low := range small.array {
+		if large.contains(low) {
+			result.array = append(result.array, low)
+		}
+	}
+	result.count = len(result.array)
+	return result
+}
+
+func (c *bitmapContainer) or(other *bitmapContainer) *bitmapContainer {
+	result := c.clone()
+	if other.bitset != nil && result.bitset == nil {
+		result.toBitset()
+	}
+	if other.bitset != nil {
+		result.count = 0
+		for w := range result.bitset {
+			result.bitset[w] |= other.bitset[w]
+			result.count += bits.OnesCount64(result.bitset[w])
+		}
+		return result
+	}
+	for _, low := range other.array {
+		result.add(low)
+	}
+	return result
+}
+
+func (c *bitmapContainer) toArray() {
+	c.array = make([]uint16, 0, c.count)
+	for w, word := range c.bitset {
+		for word != 0 {
+			t := bits.TrailingZeros64(word)
+			c.array = append(c.array, uint16(w*64+t))
+			word &= word - 1
+		}
+	}
+	c.bitset = nil
+}
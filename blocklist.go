@@ -0,0 +1,285 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const BLOCKLIST_FILE = "blocklist.json"
+
+// Blocklist berisi dokumen yang tidak boleh tampil di hasil pencarian
+// maupun di-crawl ulang. Disimpan sebagai JSON agar bisa dibaca crawler.
+//
+// Dokumen disimpan per URL, bukan docID: docID adalah posisi di articles.json
+// dan bergeser setiap hard delete, purge, arsip atau rebuild.
+type Blocklist struct {
+	mu sync.RWMutex
+
+	URLs        []string `json:"urls"`
+	URLPatterns []string `json:"url_patterns"`
+	Domains     []string `json:"domains"`
+	// doc_ids dari format lama, diubah ke URL oleh migrateBlocklist
+	LegacyDocIDs []int `json:"doc_ids,omitempty"`
+
+	urlSet   map[string]bool
+	patterns []*regexp.Regexp
+}
+
+// BlocklistUpdate adalah payload admin API untuk menambah/menghapus entri.
+// doc_ids hanya input: diubah ke URL artikel saat request masuk (lihat
+// resolveDocIDs) dan tidak pernah disimpan.
+type BlocklistUpdate struct {
+	DocIDs      []int    `json:"doc_ids,omitempty"`
+	URLs        []string `json:"urls"`
+	URLPatterns []string `json:"url_patterns"`
+	Domains     []string `json:"domains"`
+}
+
+// Ganti doc_ids dengan URL artikelnya di corpus saat ini
+func (u *BlocklistUpdate) resolveDocIDs(articles []Article) error {
+	for _, docID := range u.DocIDs {
+		if docID < 0 || docID >= len(articles) {
+			return fmt.Errorf("doc_id %d not in corpus", docID)
+		}
+		u.URLs = append(u.URLs, articles[docID].URL)
+	}
+	u.DocIDs = nil
+	return nil
+}
+
+var blocklist = &Blocklist{}
+
+// Load blocklist from JSON file, file yang belum ada dianggap kosong
+func loadBlocklist(path string) (*Blocklist, error) {
+	bl := &Blocklist{}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return bl, bl.rebuild()
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, bl); err != nil {
+		return nil, err
+	}
+
+	return bl, bl.rebuild()
+}
+
+// Compile ulang lookup set dan regex setelah daftar berubah
+func (bl *Blocklist) rebuild() error {
+	urlSet := make(map[string]bool, len(bl.URLs))
+	for _, u := range bl.URLs {
+		urlSet[u] = true
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(bl.URLPatterns))
+	for _, p := range bl.URLPatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("invalid url pattern %q: %v", p, err)
+		}
+		patterns = append(patterns, re)
+	}
+
+	for i, d := range bl.Domains {
+		bl.Domains[i] = normalizeDomain(d)
+	}
+
+	bl.urlSet = urlSet
+	bl.patterns = patterns
+	return nil
+}
+
+// Blocklist format lama menyimpan doc_ids. Dipanggil sekali saat startup
+// setelah WAL diputar ulang: docID diubah ke URL artikel di posisi itu, lalu
+// file disimpan tanpa doc_ids.
+func migrateBlocklist() error {
+	blocklist.mu.Lock()
+	defer blocklist.mu.Unlock()
+
+	if len(blocklist.LegacyDocIDs) == 0 {
+		return nil
+	}
+	articles, err := loadArticles()
+	if err != nil {
+		return err
+	}
+	next := blocklist.snapshot()
+	converted := 0
+	for _, docID := range blocklist.LegacyDocIDs {
+		if docID < 0 || docID >= len(articles) {
+			log.Printf("Blocklist migration: dropping doc ID %d outside corpus", docID)
+			continue
+		}
+		next.URLs = appendUniqueStrings(next.URLs, articles[docID].URL)
+		converted++
+	}
+	log.Printf("Blocklist migration: %d doc IDs converted to URLs", converted)
+	return blocklist.apply(next)
+}
+
+func (bl *Blocklist) save(path string) error {
+	data, err := json.MarshalIndent(bl, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+func normalizeDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	return strings.TrimPrefix(domain, "www.")
+}
+
+// Cek apakah URL dokumen masuk blocklist
+func (bl *Blocklist) IsURLBlocked(rawURL string) bool {
+	bl.mu.RLock()
+	defer bl.mu.RUnlock()
+
+	return bl.isURLBlocked(rawURL)
+}
+
+func (bl *Blocklist) isURLBlocked(rawURL string) bool {
+	if bl.urlSet[rawURL] {
+		return true
+	}
+
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		host := normalizeDomain(u.Hostname())
+		for _, domain := range bl.Domains {
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		}
+	}
+
+	for _, re := range bl.patterns {
+		if re.MatchString(rawURL) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Tambah entri baru lalu simpan ke disk
+func (bl *Blocklist) Add(update BlocklistUpdate) error {
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+
+	next := bl.snapshot()
+	for _, u := range update.URLs {
+		next.URLs = appendUniqueStrings(next.URLs, strings.TrimSpace(u))
+	}
+	next.URLPatterns = appendUniqueStrings(next.URLPatterns, update.URLPatterns...)
+	for _, d := range update.Domains {
+		next.Domains = appendUniqueStrings(next.Domains, normalizeDomain(d))
+	}
+
+	return bl.apply(next)
+}
+
+// Hapus entri lalu simpan ke disk
+func (bl *Blocklist) Remove(update BlocklistUpdate) error {
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+
+	next := bl.snapshot()
+	urls := make([]string, len(update.URLs))
+	for i, u := range update.URLs {
+		urls[i] = strings.TrimSpace(u)
+	}
+	next.URLs = removeStrings(next.URLs, urls)
+	next.URLPatterns = removeStrings(next.URLPatterns, update.URLPatterns)
+	domains := make([]string, len(update.Domains))
+	for i, d := range update.Domains {
+		domains[i] = normalizeDomain(d)
+	}
+	next.Domains = removeStrings(next.Domains, domains)
+
+	return bl.apply(next)
+}
+
+func (bl *Blocklist) snapshot() *Blocklist {
+	return &Blocklist{
+		URLs:        append([]string(nil), bl.URLs...),
+		URLPatterns: append([]string(nil), bl.URLPatterns...),
+		Domains:     append([]string(nil), bl.Domains...),
+	}
+}
+
+// Validasi dulu di salinan, baru ganti isi blocklist kalau valid
+func (bl *Blocklist) apply(next *Blocklist) error {
+	if err := next.rebuild(); err != nil {
+		return err
+	}
+	if err := next.save(BLOCKLIST_FILE); err != nil {
+		return err
+	}
+
+	bl.URLs = next.URLs
+	bl.URLPatterns = next.URLPatterns
+	bl.Domains = next.Domains
+	bl.LegacyDocIDs = nil
+	bl.urlSet = next.urlSet
+	bl.patterns = next.patterns
+	return nil
+}
+
+func (bl *Blocklist) Entries() BlocklistUpdate {
+	bl.mu.RLock()
+	defer bl.mu.RUnlock()
+
+	return BlocklistUpdate{
+		URLs:        append([]string{}, bl.URLs...),
+		URLPatterns: append([]string{}, bl.URLPatterns...),
+		Domains:     append([]string{}, bl.Domains...),
+	}
+}
+
+func appendUniqueStrings(list []string, values ...string) []string {
+	seen := make(map[string]bool, len(list))
+	for _, v := range list {
+		seen[v] = true
+	}
+	for _, v := range values {
+		if v != "" && !seen[v] {
+			list = append(list, v)
+			seen[v] = true
+		}
+	}
+	return list
+}
+
+func removeStrings(list []string, values []string) []string {
+	drop := make(map[string]bool, len(values))
+	for _, v := range values {
+		drop[v] = true
+	}
+	kept := make([]string, 0, len(list))
+	for _, v := range list {
+		if !drop[v] {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+func init() {
+	bl, err := loadBlocklist(BLOCKLIST_FILE)
+	if err != nil {
+		log.Printf("Error loading %s: %v", BLOCKLIST_FILE, err)
+		return
+	}
+	blocklist = bl
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Laporan setiap build index (generasi baru) ditulis ke index_report.json di
+// samping articles.json: jumlah dokumen yang diindex dan dilewati beserta
+// alasannya, term baru/hilang dan dokumen yang terhapus dibanding build
+// sebelumnya, durasi build dan ukuran tiap segmen (corpus, WAL, document
+// store). GET /api/admin/index-report mengembalikan laporan terakhir.
+const (
+	INDEX_REPORT_FILE   = "index_report.json"
+	INDEX_REPORT_LISTED = 50 // contoh term/URL/dokumen per daftar
+)
+
+type SkippedDoc struct {
+	URL    string `json:"url"`
+	Reason string `json:"reason"`
+}
+
+type BuildSegment struct {
+	Name    string `json:"name"`
+	Bytes   int64  `json:"bytes"`
+	Docs    int    `json:"docs,omitempty"`
+	Records int    `json:"records,omitempty"`
+	Blocks  int    `json:"blocks,omitempty"`
+}
+
+type BuildReport struct {
+	Generation  int       `json:"generation"`
+	Time        time.Time `json:"time"`
+	BuildMs     float64   `json:"build_ms"`
+	Docs        int       `json:"docs"`
+	IndexedDocs int       `json:"indexed_docs"`
+	Terms       int       `json:"terms"`
+	Postings    int       `json:"postings"`
+	// Fingerprint analyzer yang membangun generasi ini, lihat analyzercheck.go
+	Analyzer string `json:"analyzer"`
+
+	SkippedDocs int            `json:"skipped_docs"`
+	SkipReasons map[string]int `json:"skip_reasons"`
+	Skipped     []SkippedDoc   `json:"skipped,omitempty"` // maksimal INDEX_REPORT_LISTED
+
+	// Dibanding build sebelumnya di proses ini; Baseline true kalau belum ada
+	Baseline     bool     `json:"baseline,omitempty"`
+	NewTerms     int      `json:"new_terms"`
+	NewTermList  []string `json:"new_term_sample,omitempty"`
+	RemovedTerms int      `json:"removed_terms"`
+	AddedDocs    int      `json:"added_docs"`
+	DeletedDocs  int      `json:"deleted_docs"`
+	DeletedURLs  []string `json:"deleted_urls,omitempty"`
+
+	Segments []BuildSegment `json:"segments"`
+}
+
+// Term dan URL build terakhir untuk menghitung selisih
+var lastBuild struct {
+	mu    sync.Mutex
+	terms map[string]bool
+	urls  map[string]bool
+}
+
+func newBuildReport(generation int, articles []Article, idx *InvertedIndex, buildDuration time.Duration, cfg *Config) *BuildReport {
+	report := &BuildReport{
+		Generation:  generation,
+		Time:        time.Now(),
+		BuildMs:     float64(buildDuration.Microseconds()) / 1000,
+		Docs:        len(articles),
+		IndexedDocs: idx.DocCount,
+		Terms:       len(idx.Index),
+		Analyzer:    analyzerFingerprint(cfg),
+		SkipReasons: make(map[string]int),
+	}
+
+	terms := make(map[string]bool, len(idx.Index))
+	for term, postingList := range idx.Index {
+		terms[term] = true
+		report.Postings += len(postingList.Postings)
+	}
+	urls := make(map[string]bool, len(articles))
+	for docID, article := range articles {
+		urls[article.URL] = true
+		if docID < len(idx.Allowed) && !idx.Allowed[docID] {
+			reason := reviewQueue.SkipReason(article.URL)
+			if article.Deleted() {
+				reason = "deleted"
+			}
+			report.SkippedDocs++
+			report.SkipReasons[reason]++
+			if len(report.Skipped) < INDEX_REPORT_LISTED {
+				report.Skipped = append(report.Skipped, SkippedDoc{URL: article.URL, Reason: reason})
+			}
+		}
+	}
+
+	lastBuild.mu.Lock()
+	if lastBuild.terms == nil {
+		report.Baseline = true
+	} else {
+		for term := range terms {
+			if !lastBuild.terms[term] {
+				report.NewTerms++
+				report.NewTermList = append(report.NewTermList, term)
+			}
+		}
+		report.RemovedTerms = len(lastBuild.terms) - (len(terms) - report.NewTerms)
+		for url := range urls {
+			if !lastBuild.urls[url] {
+				report.AddedDocs++
+			}
+		}
+		for url := range lastBuild.urls {
+			if !urls[url] {
+				report.DeletedDocs++
+				report.DeletedURLs = append(report.DeletedURLs, url)
+			}
+		}
+	}
+	lastBuild.terms, lastBuild.urls = terms, urls
+	lastBuild.mu.Unlock()
+
+	report.NewTermList = firstSorted(report.NewTermList, INDEX_REPORT_LISTED)
+	report.DeletedURLs = firstSorted(report.DeletedURLs, INDEX_REPORT_LISTED)
+	report.Segments = buildSegments(cfg.DocStorePath)
+	return report
+}
+
+func firstSorted(values []string, n int) []string {
+	sort.Strings(values)
+	if len(values) > n {
+		values = values[:n]
+	}
+	return values
+}
+
+// Ukuran file tiap segmen index di disk
+func buildSegments(docStorePath string) []BuildSegment {
+	segments := []BuildSegment{
+		{Name: ARTICLES_FILE, Bytes: fileSize(ARTICLES_FILE)},
+		{Name: WAL_FILE, Bytes: fileSize(WAL_FILE), Records: len(documentLog.Pending())},
+	}
+	if ds := acquireDocStore(); ds != nil {
+		segments = append(segments, BuildSegment{
+			Name:   docStorePath,
+			Bytes:  fileSize(docStorePath),
+			Docs:   ds.Count(),
+			Blocks: ds.BlockCount(),
+		})
+		ds.release()
+	}
+	return segments
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+func writeBuildReport(report *BuildReport) {
+	if err := writeJSONFile(INDEX_REPORT_FILE, report); err != nil {
+		log.Printf("Error writing %s: %v", INDEX_REPORT_FILE, err)
+	}
+}
+
+// GET /api/admin/index-report
+func buildReportHandler(c *gin.Context) {
+	data, err := ioutil.ReadFile(INDEX_REPORT_FILE)
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no index build yet"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
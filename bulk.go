@@ -0,0 +1,439 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gin-gonic/gin"
+)
+
+// Operasi bulk admin, dijalankan sebagai job di background (lihat jobs.go):
+//
+//	POST /api/admin/bulk/delete?q=...&source=...          hapus dokumen yang cocok
+//	POST /api/admin/bulk/retag?q=...  {"field": "promo", "value": "subsidi"}
+//	POST /api/admin/bulk/recrawl      {"source": "rumah123"}
+//
+// Delete dan retag memilih dokumen dengan query dan filter yang sama seperti
+// /api/search (q, op, source, lang, days, f.<field>, ...), tapi hanya di index
+// lokal: tanpa instance remote, arsip, terjemahan query dan collapse. Dengan
+// dry_run=1 respons berisi jumlah dan contoh dokumen yang cocok tanpa memulai
+// job. Dokumen dipilih saat job mulai berjalan, bukan saat request diterima.
+//
+// Delete memakai record delete biasa, jadi dokumen bisa di-restore selama
+// soft_delete_days. Retag mengisi (atau dengan value null mengosongkan) satu
+// field tambahan di schema. Recrawl mengambil ulang setiap URL sumber di
+// corpus dengan selector sumbernya dan menulis update hanya kalau hasil
+// ekstraksinya berubah. Perubahan masuk WAL per batch; job yang dibatalkan
+// tetap menyimpan batch yang sudah ditulis.
+const (
+	JobBulkDelete  = "bulk_delete"
+	JobBulkRetag   = "bulk_retag"
+	JobBulkRecrawl = "bulk_recrawl"
+
+	// Operasi bulk dijalankan satu per satu supaya tidak saling menimpa dokumen yang sama
+	JOB_LANE_DOCUMENTS = "documents"
+
+	BULK_BATCH          = 100 // record per append ke WAL
+	BULK_DRY_RUN_SAMPLE = 20
+	RECRAWL_DELAY       = time.Second // jeda antar halaman supaya sopan ke situs sumber
+)
+
+// Dokumen yang dipilih operasi bulk
+type BulkMatch struct {
+	DocID int    `json:"doc_id"`
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+type bulkRetagRequest struct {
+	Field string      `json:"field"`
+	Value interface{} `json:"value"` // null = hapus field
+}
+
+// Query dan filter dari parameter URL, seperti /api/search
+func bulkSelection(c *gin.Context) (string, SearchOptions, error) {
+	query := normalizeQuery(c.Query("q"))
+	if query == "" {
+		return "", SearchOptions{}, errors.New("q is required")
+	}
+	opts := searchOptionsFromQuery(c)
+	opts.Local = true
+	opts.IncludeArchive = false
+	opts.NoCollapse = true
+	opts.NoTranslate = true
+	opts.NoSegment = true
+	opts.NoSpell = true
+	opts.Explain = false
+	opts.Scopes = []string{ScopeAll}
+	return query, opts, nil
+}
+
+// Parameter job untuk ditampilkan di daftar job
+func bulkParams(c *gin.Context) map[string]string {
+	params := make(map[string]string)
+	for name, values := range c.Request.URL.Query() {
+		if name != "dry_run" && len(values) > 0 {
+			params[name] = values[0]
+		}
+	}
+	return params
+}
+
+// Dokumen lokal yang cocok dengan query dan filter
+func bulkMatches(query string, opts SearchOptions) []BulkMatch {
+	matches := []BulkMatch{}
+	for _, result := range searching(query, "cosine", opts) {
+		if result.Node != "" || result.Archived {
+			continue
+		}
+		matches = append(matches, BulkMatch{DocID: result.DocID, URL: result.URL, Title: result.Title})
+	}
+	return matches
+}
+
+func bulkDryRun(c *gin.Context, query string, opts SearchOptions) bool {
+	if c.Query("dry_run") != "1" {
+		return false
+	}
+	matches := bulkMatches(query, opts)
+	sample := matches
+	if len(sample) > BULK_DRY_RUN_SAMPLE {
+		sample = sample[:BULK_DRY_RUN_SAMPLE]
+	}
+	c.JSON(http.StatusOK, gin.H{"matched": len(matches), "sample": sample})
+	return true
+}
+
+// Tulis record ke WAL per BULK_BATCH, berhenti di antara batch kalau job dibatalkan
+func appendBatches(ctx context.Context, job jobRun, actor AuditActor, records []WalRecord) error {
+	for start := 0; start < len(records); start += BULK_BATCH {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := start + BULK_BATCH
+		if end > len(records) {
+			end = len(records)
+		}
+		if _, err := actor.appendDocuments(records[start:end]...); err != nil {
+			return err
+		}
+		job.Advance(end-start, end-start)
+	}
+	return nil
+}
+
+// POST /api/admin/bulk/delete
+func bulkDeleteHandler(c *gin.Context) {
+	query, opts, err := bulkSelection(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if bulkDryRun(c, query, opts) {
+		return
+	}
+
+	actor := auditActor(c)
+	job := adminJobs.Start(JobBulkDelete, bulkParams(c), func(ctx context.Context, job jobRun) error {
+		matches := bulkMatches(query, opts)
+		job.SetTotal(len(matches))
+		records := make([]WalRecord, len(matches))
+		for i, match := range matches {
+			records[i] = WalRecord{Op: WalDelete, URL: match.URL}
+		}
+		return appendBatches(ctx, job, actor.forJob(job), records)
+	})
+	actor.Record(AuditJobStart, job.ID, nil, job)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// POST /api/admin/bulk/retag
+func bulkRetagHandler(c *gin.Context) {
+	query, opts, err := bulkSelection(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req bulkRetagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	field, exists := requestConfig(c).Schema.Field(req.Field)
+	if _, builtin := builtinField(req.Field); !exists || builtin {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%q is not a custom field in the schema", req.Field)})
+		return
+	}
+	if req.Value != nil {
+		if _, err := parseFieldValue(field, req.Value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s field %v", field.Type, err)})
+			return
+		}
+	}
+	if bulkDryRun(c, query, opts) {
+		return
+	}
+
+	params := bulkParams(c)
+	params["field"] = req.Field
+	if req.Value != nil {
+		params["value"] = fmt.Sprint(req.Value)
+	}
+	actor := auditActor(c)
+	job := adminJobs.Start(JobBulkRetag, params, func(ctx context.Context, job jobRun) error {
+		matches := bulkMatches(query, opts)
+		job.SetTotal(len(matches))
+		articles, _, err := loadIndex()
+		if err != nil {
+			return err
+		}
+
+		var records []WalRecord
+		for _, match := range matches {
+			article, exists := localArticle(articles, SearchResult{DocID: match.DocID})
+			if !exists || article.URL != match.URL {
+				job.Fail(match.URL, errors.New("document changed while the job was starting"))
+				continue
+			}
+			if !setArticleField(&article, req.Field, req.Value) {
+				job.Advance(1, 0)
+				continue
+			}
+			records = append(records, WalRecord{Op: WalUpdate, Article: &article})
+		}
+		return appendBatches(ctx, job, actor.forJob(job), records)
+	})
+	actor.Record(AuditJobStart, job.ID, nil, job)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// Isi field tambahan artikel, false kalau nilainya sudah sama
+func setArticleField(article *Article, name string, value interface{}) bool {
+	current, exists := article.Fields[name]
+	if value == nil {
+		if !exists {
+			return false
+		}
+		fields := make(map[string]interface{}, len(article.Fields))
+		for key, v := range article.Fields {
+			if key != name {
+				fields[key] = v
+			}
+		}
+		article.Fields = fields
+		return true
+	}
+	if exists && reflect.DeepEqual(current, value) {
+		return false
+	}
+	fields := make(map[string]interface{}, len(article.Fields)+1)
+	for key, v := range article.Fields {
+		fields[key] = v
+	}
+	fields[name] = value
+	article.Fields = fields
+	return true
+}
+
+// POST /api/admin/bulk/recrawl
+func bulkRecrawlHandler(c *gin.Context) {
+	var req struct {
+		Source string `json:"source"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Source == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source is required"})
+		return
+	}
+	source, exists := findCrawlSource(req.Source)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("unknown source %q", req.Source)})
+		return
+	}
+
+	actor := auditActor(c)
+	job := adminJobs.Start(JobBulkRecrawl, map[string]string{"source": source.Name}, func(ctx context.Context, job jobRun) error {
+		return recrawlSource(ctx, job, actor.forJob(job), source, RECRAWL_DELAY)
+	})
+	actor.Record(AuditJobStart, job.ID, nil, job)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// URL corpus milik domain sumber
+func sourceOwnsURL(source CrawlSource, rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host, domain := normalizeDomain(u.Hostname()), normalizeDomain(source.Domain)
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+func recrawlSource(ctx context.Context, job jobRun, actor AuditActor, source CrawlSource, delay time.Duration) error {
+	articles, _, err := loadIndex()
+	if err != nil {
+		return err
+	}
+	var docIDs []int
+	for docID, article := range articles {
+		if !article.Deleted() && sourceOwnsURL(source, article.URL) {
+			docIDs = append(docIDs, docID)
+		}
+	}
+	job.SetTotal(len(docIDs))
+	cfg := currentConfig()
+
+	var records []WalRecord
+	flush := func() error {
+		if len(records) == 0 {
+			return nil
+		}
+		_, err := actor.appendDocuments(records...)
+		records = nil
+		return err
+	}
+	for i, docID := range docIDs {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(delay):
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		current, _ := localArticle(articles, SearchResult{DocID: docID})
+		if blocklist.IsURLBlocked(current.URL) {
+			job.Advance(1, 0)
+			continue
+		}
+		updated, err := recrawlArticle(source, current, cfg)
+		if err != nil {
+			job.Fail(current.URL, err)
+			continue
+		}
+		if updated == nil {
+			job.Advance(1, 0)
+			continue
+		}
+		updated.Provenance = &Provenance{
+			Method:           ProvenanceRecrawl,
+			Source:           source.Name,
+			SourceConfigHash: sourceConfigHash(source),
+			Actor:            actor.Name,
+			IngestedAt:       time.Now().UTC(),
+		}
+		records = append(records, WalRecord{Op: WalUpdate, Article: updated})
+		job.Advance(1, 1)
+		if len(records) >= BULK_BATCH {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	// Hasil yang sudah diambil tetap disimpan walaupun job dibatalkan
+	if err := flush(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// Ambil ulang satu artikel; nil kalau hasil ekstraksinya tidak berubah
+func recrawlArticle(source CrawlSource, current Article, cfg *Config) (*Article, error) {
+	doc, err := fetchDocument(current.URL)
+	if err != nil {
+		return nil, err
+	}
+	extracted, found := extractPage(doc, source)
+	if !found {
+		return nil, errors.New("no article found on page")
+	}
+	if extracted.Content == "" {
+		return nil, errors.New("no content extracted")
+	}
+
+	updated := current
+	updated.Title = extracted.Title
+	updated.Content = extracted.Content
+	updated.Extraction = extracted.Extraction
+	if !extracted.Date.IsZero() {
+		updated.Date = extracted.Date
+	}
+	if extracted.Author != "" {
+		updated.Author = extracted.Author
+	}
+	if len(extracted.Breadcrumb) > 0 {
+		updated.Breadcrumb = extracted.Breadcrumb
+	}
+	updated.Tables = extracted.Tables
+	updated.Links = extracted.Links
+	if reflect.DeepEqual(updated, current) {
+		return nil, nil
+	}
+	if errs := cfg.Validation.validateArticle(&updated, cfg.Schema); len(errs) > 0 {
+		return nil, errors.New(fieldErrorsString(errs))
+	}
+	return &updated, nil
+}
+
+// Ekstraksi halaman artikel dengan selector sumber, sama seperti crawler
+// (lihat previewCrawl) tapi dari dokumen goquery
+func extractPage(doc *goquery.Document, source CrawlSource) (Article, bool) {
+	container := doc.Find(source.articleSelector()).First()
+	if container.Length() == 0 {
+		return Article{}, false
+	}
+	field := func(selector, attr string) string {
+		// Meta tag ada di <head>, di luar container artikel
+		if strings.HasPrefix(selector, "meta") {
+			return strings.TrimSpace(doc.Find(selector).First().AttrOr(attr, ""))
+		}
+		if attr != "" {
+			return strings.TrimSpace(container.Find(selector).First().AttrOr(attr, ""))
+		}
+		return strings.TrimSpace(container.Find(selector).Text())
+	}
+	paragraphs := func(selector string) []string {
+		var parts []string
+		container.Find(selector).Each(func(_ int, s *goquery.Selection) {
+			if text := strings.TrimSpace(s.Text()); text != "" {
+				parts = append(parts, text)
+			}
+		})
+		return parts
+	}
+
+	var article Article
+	article.Title = field(source.Title, "")
+	contentParts := paragraphs(source.Content)
+	if len(contentParts) == 0 {
+		if contentParts = paragraphs("p"); len(contentParts) > 0 {
+			article.Extraction = "fallback"
+		}
+	}
+	article.Content = strings.Join(contentParts, "\n")
+	article.Tables = extractTables(container)
+	article.Links = extractLinks(container, doc.Url)
+	if source.Date != "" {
+		if date, err := source.parseDate(field(source.Date, source.DateAttr)); err == nil {
+			article.Date = date
+		}
+	}
+	if source.Author != "" {
+		article.Author = field(source.Author, source.AuthorAttr)
+	}
+	article.Breadcrumb = documentBreadcrumb(doc.Selection, source.Breadcrumb, article.Title)
+	return article, true
+}
+
+func init() {
+	for _, kind := range []string{JobBulkDelete, JobBulkRetag, JobBulkRecrawl} {
+		registerJobKind(kind, JOB_LANE_DOCUMENTS, RetryPolicy{}, nil)
+	}
+}
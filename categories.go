@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Breadcrumb tiap sumber berbeda ("Berita Properti > KPR", "Tips > Pembiayaan"),
+// jadi dipetakan ke satu taksonomi lewat categories.json:
+//
+//	{"KPR & Pembiayaan": ["kpr", "berita properti > kpr", "pembiayaan"]}
+//
+// Pola dicocokkan tanpa beda huruf besar/kecil dengan breadcrumb lengkap dulu,
+// lalu per segmen dari yang paling dalam. Breadcrumb yang tidak cocok tidak
+// punya kategori; daftarnya ada di GET /api/admin/categories supaya mapping
+// bisa dilengkapi.
+const (
+	CATEGORIES_FILE      = "categories.json"
+	CATEGORY_FACET_SIZE  = 10
+	BREADCRUMB_SEPARATOR = " > "
+)
+
+// pola breadcrumb (sudah dinormalisasi) -> nama kategori
+var categoryPatterns = make(map[string]string)
+
+// Taksonomi bawaan kalau categories.json belum ada
+func defaultCategoryMapping() map[string][]string {
+	return map[string][]string{
+		"Berita Properti":    {"berita", "berita properti", "news", "property news", "properti"},
+		"KPR & Pembiayaan":   {"kpr", "pembiayaan", "keuangan", "finansial", "mortgage", "finance"},
+		"Hunian":             {"rumah", "hunian", "perumahan", "residensial", "apartemen", "residential", "apartment"},
+		"Properti Komersial": {"komersial", "perkantoran", "ruko", "ritel", "commercial", "office"},
+		"Desain & Interior":  {"desain", "interior", "arsitektur", "dekorasi", "design", "architecture"},
+		"Hukum & Pajak":      {"hukum", "legal", "legalitas", "pajak", "sertifikat", "tax"},
+		"Investasi":          {"investasi", "investment"},
+		"Tips & Panduan":     {"tips", "panduan", "tips properti", "guide", "how to"},
+		"Infrastruktur":      {"infrastruktur", "infrastructure"},
+		"Lifestyle":          {"lifestyle", "gaya hidup"},
+	}
+}
+
+func loadCategoryMapping(path string) (map[string]string, error) {
+	mapping := defaultCategoryMapping()
+
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		mapping = nil
+		if err := json.Unmarshal(data, &mapping); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	patterns := make(map[string]string)
+	for category, names := range mapping {
+		patterns[breadcrumbKey([]string{category})] = category
+		for _, pattern := range names {
+			patterns[breadcrumbKey(strings.Split(pattern, ">"))] = category
+		}
+	}
+	return patterns, nil
+}
+
+// "Berita  Properti", " KPR" -> "berita properti > kpr"
+func breadcrumbKey(segments []string) string {
+	parts := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment = strings.Join(strings.Fields(segment), " "); segment != "" {
+			parts = append(parts, strings.ToLower(segment))
+		}
+	}
+	return strings.Join(parts, BREADCRUMB_SEPARATOR)
+}
+
+// Kategori terpadu untuk breadcrumb sumber, kosong kalau tidak ada yang cocok
+func categorize(breadcrumb []string) string {
+	if len(breadcrumb) == 0 {
+		return ""
+	}
+	if category, exists := categoryPatterns[breadcrumbKey(breadcrumb)]; exists {
+		return category
+	}
+	for i := len(breadcrumb) - 1; i >= 0; i-- {
+		if category, exists := categoryPatterns[breadcrumbKey(breadcrumb[i:i+1])]; exists {
+			return category
+		}
+	}
+	return ""
+}
+
+func categorySlug(name string) string {
+	return slugify(name)
+}
+
+// Kategori terbanyak di hasil pencarian (semua halaman)
+func categoryFacet(results []SearchResult, size int) []FacetValue {
+	values := make([]string, len(results))
+	for i, result := range results {
+		values[i] = result.Category
+	}
+	return countFacet(values, size)
+}
+
+type UnmappedBreadcrumb struct {
+	Breadcrumb string `json:"breadcrumb"`
+	Count      int    `json:"count"`
+}
+
+// GET /api/admin/categories: jumlah dokumen per kategori dan breadcrumb yang
+// belum ada di mapping
+func categoriesHandler(c *gin.Context) {
+	articles, idx, err := loadIndex()
+	if err != nil {
+		log.Printf("Error loading articles: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error loading articles"})
+		return
+	}
+
+	unmapped := make(map[string]int)
+	for docID, article := range articles {
+		if len(article.Breadcrumb) > 0 && idx.DocValues.Categories[docID] == "" {
+			unmapped[strings.Join(article.Breadcrumb, BREADCRUMB_SEPARATOR)]++
+		}
+	}
+	list := make([]UnmappedBreadcrumb, 0, len(unmapped))
+	for breadcrumb, count := range unmapped {
+		list = append(list, UnmappedBreadcrumb{Breadcrumb: breadcrumb, Count: count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Breadcrumb < list[j].Breadcrumb
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"categories": countFacet(idx.DocValues.Categories, len(idx.DocValues.Categories)),
+		"unmapped":   list,
+	})
+}
+
+func init() {
+	patterns, err := loadCategoryMapping(CATEGORIES_FILE)
+	if err != nil {
+		log.Printf("Error loading %s: %v", CATEGORIES_FILE, err)
+		return
+	}
+	categoryPatterns = patterns
+}
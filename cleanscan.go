@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Versi cleanContent tanpa regex. Hasilnya harus identik dengan
+// cleanContentRegex (cek dengan: searchctl analyzer parity), tapi teks cukup
+// dipindai sekali per kata:
+//
+//   - URL, email dan handle sosmed selalu berakhir di akhir kata (\S+), jadi
+//     cukup memotong kata, bukan menyambung ulang string
+//   - karakter selain huruf/angka ASCII menjadi pemisah (specialCharsPattern)
+//   - angka berdiri sendiri dibuang dengan aturan yang sama seperti
+//     `\s+\d+\s+`: butuh pemisah di kiri dan kanan, dan pemisah yang sudah
+//     dimakan angka sebelumnya tidak bisa dipakai lagi
+func cleanContentScan(content string) string {
+	// 1. Remove unwanted texts
+	for _, text := range unwantedTexts {
+		content = strings.ReplaceAll(content, text, "")
+	}
+
+	// 2-5. Potong URL/email/handle per kata lalu pecah jadi token alfanumerik.
+	// gaps[i] menandai ada pemisah sebelum token i.
+	var words []string
+	var gaps []bool
+	separator := false
+	for i := 0; i < len(content); {
+		if isRegexSpace(content[i]) {
+			separator = true
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(content) && !isRegexSpace(content[i]) {
+			i++
+		}
+		word := stripWordLinks(content[start:i])
+
+		for j := 0; j < len(word); {
+			if !isAlnumByte(word[j]) {
+				separator = true
+				j++
+				continue
+			}
+			tokenStart := j
+			for j < len(word) && isAlnumByte(word[j]) {
+				j++
+			}
+			words = append(words, word[tokenStart:j])
+			gaps = append(gaps, separator)
+			separator = false
+		}
+	}
+	trailing := separator
+
+	// 6. Remove standalone numbers
+	kept := words[:0]
+	prevRemoved := false
+	for i, word := range words {
+		before := gaps[i] && !prevRemoved
+		after := i < len(words)-1 || trailing
+		if before && after && isDigits(word) {
+			prevRemoved = true
+			continue
+		}
+		prevRemoved = false
+		kept = append(kept, word)
+	}
+
+	return strings.Join(kept, " ")
+}
+
+// Urutan sama dengan urlPatterns, emailPattern lalu socialPattern
+func stripWordLinks(word string) string {
+	// https?://\S+
+	for k := 0; k+4 < len(word); k++ {
+		if !strings.HasPrefix(word[k:], "http") {
+			continue
+		}
+		rest := word[k+4:]
+		if (strings.HasPrefix(rest, "s://") && len(rest) > 4) || (strings.HasPrefix(rest, "://") && len(rest) > 3) {
+			word = word[:k]
+			break
+		}
+	}
+
+	// www\.\S+
+	if k := strings.Index(word, "www."); k >= 0 && k+4 < len(word) {
+		word = word[:k]
+	}
+
+	if len(word) > 1 {
+		// \S+\.(com|net|org)\S*
+		tail := word[1:]
+		if strings.Contains(tail, ".com") || strings.Contains(tail, ".net") || strings.Contains(tail, ".org") {
+			return ""
+		}
+
+		// \S+@\S+\.\S+
+		if at := strings.IndexByte(tail, '@'); at >= 0 {
+			if dot := strings.LastIndexByte(word[:len(word)-1], '.'); dot >= at+3 {
+				return ""
+			}
+		}
+	}
+
+	// @\S+
+	if at := strings.IndexByte(word, '@'); at >= 0 && at < len(word)-1 {
+		word = word[:at]
+	}
+
+	return word
+}
+
+// \s pada regexp Go: hanya spasi ASCII, tanpa \v
+func isRegexSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
+
+func isAlnumByte(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// searchctl analyzer parity: bandingkan cleanContentScan dengan versi regex
+// untuk setiap artikel di corpus
+func analyzerParityCommand(args []string) int {
+	articles, err := loadArticles()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	mismatches := 0
+	for docID, article := range articles {
+		expected := cleanContentRegex(article.Content)
+		got := cleanContentScan(article.Content)
+		if expected == got {
+			continue
+		}
+		mismatches++
+		if mismatches <= 10 {
+			fmt.Printf("doc=%d %s\n  regex: %.200q\n  scan:  %.200q\n", docID, article.URL, expected, got)
+		}
+	}
+
+	fmt.Printf("%d documents, %d mismatches\n", len(articles), mismatches)
+	if mismatches > 0 {
+		return 1
+	}
+	return 0
+}
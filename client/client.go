@@ -0,0 +1,676 @@
+// Package client adalah SDK Go untuk JSON API search engine, supaya service
+// lain tidak perlu menulis HTTP call sendiri.
+//
+//	c := client.New("http://localhost:8080", client.WithAdminToken(os.Getenv("ADMIN_TOKEN")))
+//	res, err := c.Search(ctx, client.SearchRequest{Query: "rumah subsidi", Sort: client.SortDateDesc})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_TIMEOUT     = 30 * time.Second
+	DEFAULT_MAX_RETRIES = 3
+	DEFAULT_BACKOFF     = 200 * time.Millisecond
+)
+
+type Client struct {
+	baseURL    string
+	adminToken string
+	actor      string
+	apiKey     string
+	generation int
+	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration
+}
+
+type Option func(*Client)
+
+// Token untuk endpoint /api/admin (header X-Admin-Token)
+func WithAdminToken(token string) Option {
+	return func(c *Client) { c.adminToken = token }
+}
+
+// Nama pelaku yang dicatat di audit log untuk perubahan admin
+// (header X-Admin-Actor)
+func WithActor(name string) Option {
+	return func(c *Client) { c.actor = name }
+}
+
+// API key untuk semua request (header X-API-Key). Dokumen internal hanya
+// muncul di hasil kalau key-nya punya scope "internal".
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// Pin semua pencarian ke satu generasi index (header X-Index-Generation),
+// mis. SearchResponse.Generation dari query sebelumnya. Server menjawab 410
+// kalau generasi itu sudah dibuang sesuai generation_retention.
+func WithGeneration(generation int) Option {
+	return func(c *Client) { c.generation = generation }
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// Jumlah percobaan ulang untuk error jaringan, 429 dan 5xx (0 = tanpa retry).
+// Backoff dimulai dari backoff lalu berlipat dua setiap percobaan.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: DEFAULT_TIMEOUT},
+		maxRetries: DEFAULT_MAX_RETRIES,
+		backoff:    DEFAULT_BACKOFF,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Error dari server (status bukan 2xx)
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("search api: %d %s", e.StatusCode, e.Message)
+}
+
+func (e *APIError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Search menjalankan query lewat GET /api/search
+func (c *Client) Search(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
+	var res SearchResponse
+	if err := c.do(ctx, http.MethodGet, "/api/search?"+req.values().Encode(), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Suggest mengambil saran autocomplete untuk teks yang sedang diketik lewat
+// GET /api/suggest; limit 0 memakai default server
+func (c *Client) Suggest(ctx context.Context, prefix string, limit int) ([]Suggestion, error) {
+	values := url.Values{}
+	values.Set("q", prefix)
+	setIfPositive(values, "limit", int64(limit))
+	var res struct {
+		Suggestions []Suggestion `json:"suggestions"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/suggest?"+values.Encode(), nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Suggestions, nil
+}
+
+// Answer meminta jawaban ringkas dengan kutipan dari hasil teratas lewat
+// GET /api/answer. Answer kosong kalau server tidak bisa menjawab dari hasil.
+func (c *Client) Answer(ctx context.Context, req SearchRequest) (*AnswerResponse, error) {
+	var res AnswerResponse
+	if err := c.do(ctx, http.MethodGet, "/api/answer?"+req.values().Encode(), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Analyze menampilkan token yang dihasilkan analyzer server untuk teks lewat
+// GET /api/_analyze, untuk mencari tahu kenapa query tidak cocok dengan
+// dokumen
+func (c *Client) Analyze(ctx context.Context, req AnalyzeRequest) (*AnalyzeResponse, error) {
+	values := url.Values{}
+	values.Set("text", req.Text)
+	if req.Analyzer != "" {
+		values.Set("analyzer", req.Analyzer)
+	}
+	if req.DocID != nil {
+		values.Set("doc_id", strconv.Itoa(*req.DocID))
+	}
+	var res AnalyzeResponse
+	if err := c.do(ctx, http.MethodGet, "/api/_analyze?"+values.Encode(), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Score menghitung skor satu dokumen untuk query dengan setiap ranker lewat
+// POST /api/_score. Isi req.DocID untuk dokumen di index atau req.Document
+// untuk dokumen yang belum ada di corpus.
+func (c *Client) Score(ctx context.Context, req ScoreRequest) (*ScoreResponse, error) {
+	var res ScoreResponse
+	if err := c.do(ctx, http.MethodPost, "/api/_score", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// VoiceSearch mengirim rekaman query suara (mis. WAV, WebM atau MP3) ke
+// POST /api/voice-search. Server mentranskripsi audio lalu mencarinya dengan
+// parameter req; req.Query diabaikan. language kosong memakai default server.
+func (c *Client) VoiceSearch(ctx context.Context, audio []byte, language string, req SearchRequest) (*VoiceSearchResponse, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("audio", "query")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(audio); err != nil {
+		return nil, err
+	}
+	if language != "" {
+		form.WriteField("language", language)
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	values := req.values()
+	values.Del("q")
+	var res VoiceSearchResponse
+	if err := c.send(ctx, http.MethodPost, "/api/voice-search?"+values.Encode(), form.FormDataContentType(), body.Bytes(), &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Ingest mengirim perubahan dokumen ke write-ahead log. Perubahan dikenali
+// dari URL, jadi aman dikirim ulang saat retry.
+func (c *Client) Ingest(ctx context.Context, changes []DocumentChange) (*IngestResponse, error) {
+	var res IngestResponse
+	if err := c.do(ctx, http.MethodPost, "/api/admin/ingest", changes, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Merge memaksa WAL di-merge ke corpus dan document store dibangun ulang
+func (c *Client) Merge(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/api/admin/merge", nil, nil)
+}
+
+// DeletedDocuments mendaftar dokumen yang di-soft delete dan masih bisa
+// dikembalikan (GET /api/documents/deleted)
+func (c *Client) DeletedDocuments(ctx context.Context) ([]DeletedDocument, error) {
+	var res struct {
+		Documents []DeletedDocument `json:"documents"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/documents/deleted", nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Documents, nil
+}
+
+// RestoreDocument mengembalikan dokumen yang di-soft delete
+// (POST /api/documents/:id/restore)
+func (c *Client) RestoreDocument(ctx context.Context, docID int) error {
+	return c.do(ctx, http.MethodPost, "/api/documents/"+strconv.Itoa(docID)+"/restore", nil, nil)
+}
+
+// Documents mendaftar dokumen yang cocok dengan filter provenance
+// (GET /api/documents). total adalah jumlah semua dokumen yang cocok.
+func (c *Client) Documents(ctx context.Context, f ProvenanceFilter) (documents []DocumentSummary, total int, err error) {
+	values := f.values()
+	if f.Limit > 0 {
+		values.Set("limit", strconv.Itoa(f.Limit))
+	}
+	var res struct {
+		Documents []DocumentSummary `json:"documents"`
+		Total     int               `json:"total"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/documents?"+values.Encode(), nil, &res); err != nil {
+		return nil, 0, err
+	}
+	return res.Documents, res.Total, nil
+}
+
+// Document membaca satu dokumen lengkap beserta provenance-nya
+// (GET /api/documents/:id)
+func (c *Client) Document(ctx context.Context, docID int) (*Article, error) {
+	var res struct {
+		Article Article `json:"article"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/documents/"+strconv.Itoa(docID), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res.Article, nil
+}
+
+// BulkDeleteProvenance menghapus semua dokumen yang cocok dengan filter
+// provenance lewat job di background (POST /api/admin/bulk/delete-provenance)
+func (c *Client) BulkDeleteProvenance(ctx context.Context, f ProvenanceFilter) (*Job, error) {
+	var res Job
+	if err := c.do(ctx, http.MethodPost, "/api/admin/bulk/delete-provenance?"+f.values().Encode(), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (f ProvenanceFilter) values() url.Values {
+	values := url.Values{}
+	for name, value := range map[string]string{
+		"method": f.Method, "crawler_version": f.CrawlerVersion, "source": f.Source,
+		"source_config_hash": f.SourceConfigHash, "api_key": f.APIKey, "import_file": f.ImportFile,
+	} {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+	if !f.Before.IsZero() {
+		values.Set("before", f.Before.Format(time.RFC3339))
+	}
+	if !f.After.IsZero() {
+		values.Set("after", f.After.Format(time.RFC3339))
+	}
+	return values
+}
+
+// BulkDelete menghapus semua dokumen lokal yang cocok dengan query dan filter
+// req (seperti Search) lewat job di background (POST /api/admin/bulk/delete).
+// Pantau hasilnya dengan Job.
+func (c *Client) BulkDelete(ctx context.Context, req SearchRequest) (*Job, error) {
+	var res Job
+	if err := c.do(ctx, http.MethodPost, "/api/admin/bulk/delete?"+req.values().Encode(), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// BulkRetag mengisi field tambahan di schema untuk semua dokumen yang cocok
+// dengan req (POST /api/admin/bulk/retag); value nil mengosongkan field
+func (c *Client) BulkRetag(ctx context.Context, req SearchRequest, field string, value interface{}) (*Job, error) {
+	body := map[string]interface{}{"field": field, "value": value}
+	var res Job
+	if err := c.do(ctx, http.MethodPost, "/api/admin/bulk/retag?"+req.values().Encode(), body, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// BulkRecrawl mengambil ulang semua dokumen satu sumber crawl
+// (POST /api/admin/bulk/recrawl)
+func (c *Client) BulkRecrawl(ctx context.Context, source string) (*Job, error) {
+	var res Job
+	if err := c.do(ctx, http.MethodPost, "/api/admin/bulk/recrawl", map[string]string{"source": source}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Jobs mengembalikan job dengan jenis dan status tertentu, terbaru dulu
+// (GET /api/admin/jobs). kind dan status kosong berarti semua.
+func (c *Client) Jobs(ctx context.Context, kind, status string) ([]Job, error) {
+	values := url.Values{}
+	if kind != "" {
+		values.Set("kind", kind)
+	}
+	if status != "" {
+		values.Set("status", status)
+	}
+	path := "/api/admin/jobs"
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+	var res struct {
+		Jobs []Job `json:"jobs"`
+	}
+	if err := c.do(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Jobs, nil
+}
+
+// RunJob menjalankan job berkala sekarang, tanpa menunggu jadwalnya
+// (POST /api/admin/jobs), misalnya "archive" atau "zero_results"
+func (c *Client) RunJob(ctx context.Context, kind string, params map[string]string) (*Job, error) {
+	req := map[string]interface{}{"kind": kind}
+	if len(params) > 0 {
+		req["params"] = params
+	}
+	var res Job
+	if err := c.do(ctx, http.MethodPost, "/api/admin/jobs", req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Job membaca status dan progres satu job (GET /api/admin/jobs/:id)
+func (c *Client) Job(ctx context.Context, id string) (*Job, error) {
+	var res Job
+	if err := c.do(ctx, http.MethodGet, "/api/admin/jobs/"+url.PathEscape(id), nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CancelJob membatalkan job yang belum selesai (POST /api/admin/jobs/:id/cancel).
+// Perubahan yang sudah ditulis job tidak dibatalkan.
+func (c *Client) CancelJob(ctx context.Context, id string) (*Job, error) {
+	var res Job
+	if err := c.do(ctx, http.MethodPost, "/api/admin/jobs/"+url.PathEscape(id)+"/cancel", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// AuditLog membaca perubahan admin, terbaru dulu (GET /api/admin/audit).
+// total adalah jumlah semua entry yang cocok dengan filter.
+func (c *Client) AuditLog(ctx context.Context, q AuditQuery) (entries []AuditEntry, total int, err error) {
+	values := url.Values{}
+	for name, value := range map[string]string{"actor": q.Actor, "action": q.Action, "target": q.Target, "job": q.Job} {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+	if !q.Since.IsZero() {
+		values.Set("since", q.Since.Format(time.RFC3339))
+	}
+	if !q.Until.IsZero() {
+		values.Set("until", q.Until.Format(time.RFC3339))
+	}
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	var res struct {
+		Entries []AuditEntry `json:"entries"`
+		Total   int          `json:"total"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/admin/audit?"+values.Encode(), nil, &res); err != nil {
+		return nil, 0, err
+	}
+	return res.Entries, res.Total, nil
+}
+
+// Config membaca config yang sedang dipakai server beserta nomor versinya
+// (GET /api/admin/config)
+func (c *Client) Config(ctx context.Context) (config json.RawMessage, version int, err error) {
+	var res struct {
+		Version int             `json:"version"`
+		Config  json.RawMessage `json:"config"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/admin/config", nil, &res); err != nil {
+		return nil, 0, err
+	}
+	return res.Config, res.Version, nil
+}
+
+// UpdateConfig mengganti config.json server dan langsung menerapkannya
+// (POST /api/admin/config). Config yang tidak valid ditolak dengan 400 dan
+// config lama tetap dipakai.
+func (c *Client) UpdateConfig(ctx context.Context, config json.RawMessage) (*ConfigUpdate, error) {
+	var res ConfigUpdate
+	if err := c.send(ctx, http.MethodPost, "/api/admin/config", "application/json", config, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ConfigVersions mengembalikan riwayat versi config, terbaru dulu
+func (c *Client) ConfigVersions(ctx context.Context) ([]ConfigVersion, error) {
+	var res struct {
+		Versions []ConfigVersion `json:"versions"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/admin/config/versions", nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Versions, nil
+}
+
+// RollbackConfig menerapkan lagi config versi lama sebagai versi baru
+func (c *Client) RollbackConfig(ctx context.Context, version int) (*ConfigUpdate, error) {
+	var res ConfigUpdate
+	if err := c.do(ctx, http.MethodPost, "/api/admin/config/rollback", map[string]int{"version": version}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) IndexStats(ctx context.Context) (*IndexStats, error) {
+	var res IndexStats
+	if err := c.do(ctx, http.MethodGet, "/api/admin/index-stats", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Dashboard mengembalikan ringkasan status sistem yang juga ditampilkan di /admin/dashboard
+func (c *Client) Dashboard(ctx context.Context) (*Dashboard, error) {
+	var res Dashboard
+	if err := c.do(ctx, http.MethodGet, "/api/admin/dashboard", nil, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// MonitoredQueries mengembalikan query yang dipantau beserta riwayat jumlah hasilnya
+func (c *Client) MonitoredQueries(ctx context.Context) ([]MonitoredQuery, error) {
+	var res struct {
+		Queries []MonitoredQuery `json:"queries"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/admin/monitored", nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Queries, nil
+}
+
+// MonitorQuery menambah query yang jumlah hasilnya dihitung ulang setiap generasi index
+func (c *Client) MonitorQuery(ctx context.Context, query string) ([]MonitoredQuery, error) {
+	var res struct {
+		Queries []MonitoredQuery `json:"queries"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/admin/monitored", map[string]string{"query": query}, &res); err != nil {
+		return nil, err
+	}
+	return res.Queries, nil
+}
+
+func (c *Client) UnmonitorQuery(ctx context.Context, query string) ([]MonitoredQuery, error) {
+	var res struct {
+		Queries []MonitoredQuery `json:"queries"`
+	}
+	if err := c.do(ctx, http.MethodDelete, "/api/admin/monitored", map[string]string{"query": query}, &res); err != nil {
+		return nil, err
+	}
+	return res.Queries, nil
+}
+
+// Alerts mengevaluasi kondisi alert bawaan; firingOnly hanya mengembalikan
+// alert yang sedang aktif
+func (c *Client) Alerts(ctx context.Context, firingOnly bool) ([]Alert, error) {
+	path := "/api/admin/alerts"
+	if firingOnly {
+		path += "?firing=1"
+	}
+	var res struct {
+		Alerts []Alert `json:"alerts"`
+	}
+	if err := c.do(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Alerts, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	return c.send(ctx, method, path, "application/json", payload, out)
+}
+
+// Kirim payload yang sudah di-encode, dengan retry
+func (c *Client) send(ctx context.Context, method, path, contentType string, payload []byte, out interface{}) error {
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		err := c.attempt(ctx, method, path, contentType, payload, out)
+		if err == nil || attempt >= c.maxRetries || ctx.Err() != nil || !retryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// Endpoint yang butuh X-Admin-Token: admin API dan API dokumen
+func adminPath(path string) bool {
+	return strings.HasPrefix(path, "/api/admin/") || path == "/api/documents" || strings.HasPrefix(path, "/api/documents/") || strings.HasPrefix(path, "/api/documents?")
+}
+
+func (c *Client) attempt(ctx context.Context, method, path, contentType string, payload []byte, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.adminToken != "" && adminPath(path) {
+		req.Header.Set("X-Admin-Token", c.adminToken)
+	}
+	if c.actor != "" && adminPath(path) {
+		req.Header.Set("X-Admin-Actor", c.actor)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.generation > 0 {
+		req.Header.Set("X-Index-Generation", strconv.Itoa(c.generation))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		var errBody struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &errBody) == nil && errBody.Error != "" {
+			apiErr.Message = errBody.Error
+		}
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// Error jaringan dan status 429/5xx dicoba ulang
+func retryable(err error) bool {
+	if apiErr, ok := err.(*APIError); ok {
+		return apiErr.retryable()
+	}
+	return true
+}
+
+func (r SearchRequest) values() url.Values {
+	values := url.Values{}
+	values.Set("q", r.Query)
+	setIfNotEmpty(values, "method", r.Method)
+	setIfNotEmpty(values, "sort", r.Sort)
+	setIfNotEmpty(values, "source", r.Source)
+	setIfNotEmpty(values, "lang", r.Language)
+	setIfNotEmpty(values, "location", r.Location)
+	setIfNotEmpty(values, "author", r.Author)
+	setIfNotEmpty(values, "category", r.Category)
+	setIfNotEmpty(values, "doctype", r.DocType)
+	if r.MatchAll {
+		values.Set("op", "and")
+	}
+	if r.Explain {
+		values.Set("explain", "1")
+	}
+	if r.IncludeArchive {
+		values.Set("include_archive", "1")
+	}
+	if r.Local {
+		values.Set("local", "1")
+	}
+	if len(r.ResultFields) > 0 {
+		values.Set("fields", strings.Join(r.ResultFields, ","))
+	}
+	if r.OmitContent {
+		values.Set("include_content", "false")
+	}
+	if r.NoCollapse {
+		values.Set("collapse", "false")
+	}
+	if r.NoTranslate {
+		values.Set("translate", "false")
+	}
+	if r.NoSegment {
+		values.Set("segment", "false")
+	}
+	if r.NoSpell {
+		values.Set("spell", "false")
+	}
+	setIfPositive(values, "page", int64(r.Page))
+	setIfPositive(values, "per_page", int64(r.PerPage))
+	setIfPositive(values, "min_price", r.MinPrice)
+	setIfPositive(values, "max_price", r.MaxPrice)
+	setIfPositive(values, "min_words", int64(r.MinWords))
+	setIfPositive(values, "days", int64(r.Days))
+	setIfNotEmpty(values, "from", r.From)
+	setIfNotEmpty(values, "to", r.To)
+	for name, value := range r.Fields {
+		setIfNotEmpty(values, "f."+name, value)
+	}
+	return values
+}
+
+func setIfNotEmpty(values url.Values, key, value string) {
+	if value != "" {
+		values.Set(key, value)
+	}
+}
+
+func setIfPositive(values url.Values, key string, value int64) {
+	if value > 0 {
+		values.Set(key, strconv.FormatInt(value, 10))
+	}
+}
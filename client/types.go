@@ -0,0 +1,657 @@
+package client
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Nilai SearchRequest.Sort
+const (
+	SortRelevance = "relevance"
+	SortDateDesc  = "date_desc"
+	SortDateAsc   = "date_asc"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+)
+
+// Operasi DocumentChange.Op
+const (
+	OpAdd    = "add"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+type SearchRequest struct {
+	Query    string
+	Method   string // "cosine" (default), "jaccard" atau "bm25"
+	Page     int
+	PerPage  int // maksimal 100
+	Sort     string
+	MatchAll bool // semua term query harus ada (op=and)
+	Explain  bool // sertakan query plan di SearchResponse.Plan
+
+	IncludeArchive bool // ikut cari di arsip artikel lama
+	Local          bool // hanya index instance itu, tanpa federasi ke instance lain
+
+	// Bentuk response: hanya key Result ini yang dikirim (mis. "title", "url",
+	// "score"), field lain di Result dibiarkan kosong. OmitContent membuang
+	// snippet dan highlighted.
+	ResultFields []string
+	OmitContent  bool
+
+	// Jangan gabungkan salinan sindikasi dan URL yang sama dari instance
+	// lain; lihat Result.DuplicateOf
+	NoCollapse bool
+	// Jangan ikut cari terjemahan query (lihat SearchResponse.Translation)
+	NoTranslate bool
+	// Jangan pecah kata gabungan di query (lihat SearchResponse.Rewrite)
+	NoSegment bool
+	// Jangan koreksi ejaan query (lihat SearchResponse.Rewrite)
+	NoSpell bool
+
+	// Filter
+	Source   string // rumah123, propertiterkini, propertyandthecity
+	Language string // id atau en
+	Location string
+	Author   string // slug atau nama penulis
+	Category string // slug atau nama kategori
+	DocType  string // article, pdf atau docx
+	MinPrice int64
+	MaxPrice int64
+	MinWords int // buang artikel pendek/stub
+	Days     int // hanya artikel N hari terakhir
+	// Rentang tanggal terbit YYYY-MM-DD (WIB), To inklusif
+	From string
+	To   string
+	// Filter field schema server: nama field -> nilai, mis.
+	// {"developer": "ciputra", "luas_tanah": "100..200", "lokasi": "-6.2,106.8,5"}
+	Fields map[string]string
+}
+
+type SearchResponse struct {
+	Query        string   `json:"query"`
+	Method       string   `json:"method"`
+	Page         int      `json:"page"`
+	PerPage      int      `json:"per_page"`
+	TotalPages   int      `json:"total_pages"`
+	TotalResults int      `json:"total_results"`
+	Collapsed    int      `json:"collapsed"` // hasil yang digabung ke hasil lain, tidak termasuk TotalResults
+	Results      []Result `json:"results"`
+	Facets       Facets   `json:"facets"`
+	Plan         string   `json:"plan,omitempty"`
+	Partial      bool     `json:"partial,omitempty"` // ada tier (mis. arsip) yang timeout
+	// Terjemahan query yang ikut dicari kalau server mengaktifkan translation
+	Translation *QueryTranslation `json:"translation,omitempty"`
+	// Query yang ditulis ulang server, mis. "hargarumahsubsidi" dipecah jadi
+	// "harga rumah subsidi" atau "rumha" dikoreksi jadi "rumah". Applied
+	// false berarti hanya saran ("Maksud Anda").
+	Rewrite *QueryRewrite `json:"rewrite,omitempty"`
+	// Kalimat jawaban dari dokumen teratas untuk query berupa pertanyaan,
+	// hanya di halaman pertama hasil urut relevansi
+	FeaturedAnswer *FeaturedAnswer `json:"featured_answer,omitempty"`
+	// Entitas yang dikenali di query dan filter aktif
+	Structure QueryStructure `json:"structure"`
+	// Waktu pencarian di server dalam milidetik
+	TookMs float64 `json:"took_ms"`
+	// Generasi index yang menjawab, untuk WithGeneration
+	Generation int `json:"generation"`
+}
+
+// Jenis QueryChip.Kind
+const (
+	ChipLocation     = "location"
+	ChipPropertyType = "property_type"
+	ChipPrice        = "price"
+	ChipFilter       = "filter"
+)
+
+type QueryStructure struct {
+	Text  []string    `json:"text"` // kata query di luar entitas
+	Chips []QueryChip `json:"chips"`
+}
+
+// Satu chip, mis. {location, lokasi, Jakarta}. Entitas menunjuk posisi byte
+// di query (Start, End); filter menunjuk parameter (Param). Remove adalah
+// query string setelah chip dibuang.
+type QueryChip struct {
+	Kind   string `json:"kind"`
+	Label  string `json:"label"`
+	Value  string `json:"value"`
+	Start  int    `json:"start,omitempty"`
+	End    int    `json:"end,omitempty"`
+	Param  string `json:"param,omitempty"`
+	Remove string `json:"remove"`
+}
+
+type FeaturedAnswer struct {
+	Text         string  `json:"text"`
+	DocID        int     `json:"doc_id"`
+	URL          string  `json:"url"`
+	Title        string  `json:"title"`
+	TextFragment string  `json:"text_fragment,omitempty"` // lihat Result.FragmentURL
+	Score        float64 `json:"score"`
+}
+
+type QueryRewrite struct {
+	Kind     string `json:"kind"` // segmentation atau spelling
+	Original string `json:"original"`
+	Text     string `json:"text"`
+	Applied  bool   `json:"applied"`
+}
+
+type QueryTranslation struct {
+	Text     string `json:"text"`
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Provider string `json:"provider"`
+}
+
+// Facet dihitung dari semua hasil, bukan hanya halaman ini
+type Facets struct {
+	Source   []FacetValue `json:"source"`
+	Year     []FacetValue `json:"year"` // tahun terbit, terbaru dulu
+	Author   []FacetValue `json:"author"`
+	Category []FacetValue `json:"category"`
+	DocType  []FacetValue `json:"doctype"`
+}
+
+type FacetValue struct {
+	Value string `json:"value"`
+	Slug  string `json:"slug"` // nilai untuk SearchRequest.Source/Author/Category/DocType
+	Count int    `json:"count"`
+}
+
+type Result struct {
+	DocID       int     `json:"doc_id"`
+	Title       string  `json:"title"`
+	URL         string  `json:"url"`
+	Snippet     string  `json:"snippet"`
+	Highlighted string  `json:"highlighted"` // HTML, term query dibungkus <em>
+	Score       float64 `json:"score"`
+	Author      string  `json:"author,omitempty"`
+	Category    string  `json:"category,omitempty"`
+	// Text directive ("text=...") untuk lompat ke paragraf yang cocok, lihat FragmentURL
+	TextFragment string `json:"text_fragment,omitempty"`
+
+	Position       int       `json:"position"` // peringkat 1-based di seluruh hasil
+	Words          int       `json:"words"`
+	ReadingMinutes int       `json:"reading_minutes"`
+	Date           time.Time `json:"date,omitempty"`
+	Price          int64     `json:"price,omitempty"`    // rupiah
+	Archived       bool      `json:"archived,omitempty"` // dari arsip (IncludeArchive)
+	Node           string    `json:"node,omitempty"`     // instance asal kalau server memakai federasi
+	DocType        string    `json:"doctype,omitempty"`  // article, pdf, docx
+	Page           int       `json:"page,omitempty"`     // halaman yang paling cocok, untuk URL + "#page=N"
+	// Field schema tambahan yang stored di server
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// Salinan artikel yang sama di source lain (sindikasi)
+	AlsoPublished []SyndicatedCopy `json:"also_published,omitempty"`
+	// Hasil lain yang digabung ke hasil ini. Dengan NoCollapse hasil itu
+	// tetap dikirim dan DuplicateOf menunjuk hasil yang akan menggantikannya.
+	Collapsed   []CollapsedResult `json:"collapsed,omitempty"`
+	DuplicateOf *CollapsedResult  `json:"duplicate_of,omitempty"`
+	// Ditemukan (atau lebih cocok) lewat terjemahan query
+	Translated bool `json:"translated,omitempty"`
+}
+
+type SyndicatedCopy struct {
+	URL    string    `json:"url"`
+	Source string    `json:"source"`
+	Title  string    `json:"title"`
+	Date   time.Time `json:"date,omitempty"`
+}
+
+// Alasan penggabungan di CollapsedResult.Reason
+const (
+	CollapseSyndicated = "syndicated" // salinan sindikasi di source lain
+	CollapseSameURL    = "same_url"   // URL sama dari instance lain (federasi)
+)
+
+type CollapsedResult struct {
+	DocID  int    `json:"doc_id"`
+	URL    string `json:"url"`
+	Node   string `json:"node,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// URL hasil dengan text fragment (#:~:text=...). Browser yang tidak
+// mendukung text fragment mengabaikannya dan membuka halaman dari atas.
+func (r Result) FragmentURL() string {
+	if r.TextFragment == "" {
+		return r.URL
+	}
+	if strings.Contains(r.URL, "#") {
+		return r.URL + ":~:" + r.TextFragment
+	}
+	return r.URL + "#:~:" + r.TextFragment
+}
+
+type Article struct {
+	Title      string    `json:"title"`
+	Content    string    `json:"content"`
+	URL        string    `json:"url"`
+	Date       time.Time `json:"date,omitempty"`
+	Author     string    `json:"author,omitempty"`
+	Language   string    `json:"language,omitempty"`
+	Breadcrumb []string  `json:"breadcrumb,omitempty"`
+	DocType    string    `json:"doctype,omitempty"` // pdf/docx, kosong untuk halaman web
+	// Nilai field tambahan yang dideklarasikan di schema index server
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// VisibilityPublic (default) atau VisibilityInternal
+	Visibility string `json:"visibility,omitempty"`
+	// Diisi server saat ingest; klien crawler boleh mengirim CrawlerVersion,
+	// Source dan SourceConfigHash
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// Asal dokumen. Method crawler, ingest, upload, recrawl, atau unknown untuk
+// dokumen dari sebelum provenance dicatat. APIKey adalah nama key, bukan key-nya.
+type Provenance struct {
+	Method           string    `json:"method"`
+	CrawlerVersion   string    `json:"crawler_version,omitempty"`
+	Source           string    `json:"source,omitempty"`
+	SourceConfigHash string    `json:"source_config_hash,omitempty"`
+	APIKey           string    `json:"api_key,omitempty"`
+	Actor            string    `json:"actor,omitempty"`
+	ImportFile       string    `json:"import_file,omitempty"`
+	IngestedAt       time.Time `json:"ingested_at"`
+}
+
+// Filter Documents dan BulkDeleteProvenance; field kosong tidak difilter
+type ProvenanceFilter struct {
+	Method           string
+	CrawlerVersion   string
+	Source           string
+	SourceConfigHash string
+	APIKey           string
+	ImportFile       string
+	Before           time.Time // ditulis sebelum waktu ini
+	After            time.Time // ditulis sesudah waktu ini
+	Limit            int       // hanya Documents, default 100
+}
+
+// Dokumen di daftar Documents
+type DocumentSummary struct {
+	DocID      int        `json:"doc_id"`
+	URL        string     `json:"url"`
+	Title      string     `json:"title"`
+	Deleted    bool       `json:"deleted,omitempty"`
+	Provenance Provenance `json:"provenance"`
+}
+
+// Visibility dokumen; dokumen internal hanya dicari klien WithAPIKey yang
+// key-nya punya scope "internal"
+const (
+	VisibilityPublic   = "public"
+	VisibilityInternal = "internal"
+)
+
+// Perubahan dokumen untuk Ingest. Add/update butuh Article, delete cukup URL.
+type DocumentChange struct {
+	Op      string   `json:"op"`
+	URL     string   `json:"url,omitempty"`
+	Article *Article `json:"article,omitempty"`
+}
+
+type IngestResponse struct {
+	Accepted []uint64      `json:"accepted"` // sequence number WAL
+	Rejected []RecordError `json:"rejected"` // record yang gagal validasi, sisanya tetap diterima
+	Pending  int           `json:"pending"`
+}
+
+type RecordError struct {
+	Index  int          `json:"index"` // posisi di batch Ingest
+	Op     string       `json:"op,omitempty"`
+	URL    string       `json:"url,omitempty"`
+	Errors []FieldError `json:"errors"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"` // required, max_length, url, doctype, op
+	Message string `json:"message"`
+}
+
+type IndexStats struct {
+	Docs             int     `json:"docs"`
+	IndexedDocs      int     `json:"indexed_docs"`
+	Terms            int     `json:"terms"`
+	Postings         int     `json:"postings"`
+	Tokens           int     `json:"tokens"`
+	AvgDocLength     float64 `json:"avg_doc_length"`
+	AvgTitleLength   float64 `json:"avg_title_length"`
+	AvgContentLength float64 `json:"avg_content_length"`
+	InternedTerms    int     `json:"interned_terms"`
+	Paragraphs       int     `json:"paragraphs,omitempty"` // 0 kalau paragraph_index nonaktif
+}
+
+// Saran autocomplete; Sources berisi "queries" (query populer) dan/atau
+// "corpus" (kata judul artikel)
+type Suggestion struct {
+	Text    string   `json:"text"`
+	Score   float64  `json:"score"`
+	Sources []string `json:"sources"`
+}
+
+// Hasil VoiceSearch: teks yang dikenali dan hasil pencariannya
+type VoiceSearchResponse struct {
+	Transcription Transcription `json:"transcription"`
+	SearchResponse
+}
+
+type Transcription struct {
+	Text     string  `json:"text"`
+	Language string  `json:"language,omitempty"`
+	Duration float64 `json:"duration,omitempty"` // detik audio
+	Provider string  `json:"provider"`
+}
+
+// Jawaban dari Client.Answer. Setiap [n] di Answer merujuk Citation dengan N
+// yang sama.
+type AnswerResponse struct {
+	Query     string     `json:"query"`
+	Answer    string     `json:"answer"`
+	Citations []Citation `json:"citations"`
+	Provider  string     `json:"provider"`
+}
+
+type Citation struct {
+	N     int    `json:"n"`
+	DocID int    `json:"doc_id"`
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Node  string `json:"node,omitempty"`
+}
+
+// Analyzer untuk Client.Analyze
+const (
+	AnalyzerIndonesian = "id"
+	AnalyzerEnglish    = "en"
+	AnalyzerLanguage   = "language" // bahasa dideteksi dari teks (default)
+	AnalyzerQuery      = "query"    // seperti query: semua bahasa plus sinonim
+)
+
+type AnalyzeRequest struct {
+	Text     string
+	Analyzer string
+	// Kalau di-set, tiap token menunjukkan apakah term ada di dokumen ini
+	DocID *int
+}
+
+type AnalyzeResponse struct {
+	Text     string          `json:"text"`
+	Analyzer string          `json:"analyzer"`
+	DocID    *int            `json:"doc_id,omitempty"`
+	Streams  []AnalyzeStream `json:"streams"`
+}
+
+// Hasil satu pipeline bahasa
+type AnalyzeStream struct {
+	Language string           `json:"language"`
+	Weight   float64          `json:"weight"`
+	Tokens   []AnalyzeToken   `json:"tokens"`
+	Removed  []AnalyzeRemoved `json:"removed"`
+}
+
+type AnalyzeToken struct {
+	Token        string   `json:"token"`
+	Original     string   `json:"original"`
+	Position     int      `json:"position"`
+	Start        int      `json:"start"`
+	End          int      `json:"end"`
+	Filters      []string `json:"filters"` // lowercase, stem
+	DocFrequency int      `json:"doc_frequency"`
+	InDocument   *bool    `json:"in_document,omitempty"`
+	Synonyms     []string `json:"synonyms,omitempty"`
+}
+
+// Kata yang dibuang analyzer; Reason "number" atau "stopword"
+type AnalyzeRemoved struct {
+	Original string `json:"original"`
+	Position int    `json:"position"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	Reason   string `json:"reason"`
+}
+
+type ScoreRequest struct {
+	Query    string   `json:"query"`
+	DocID    *int     `json:"doc_id,omitempty"`
+	Document *Article `json:"document,omitempty"`
+}
+
+// Hasil Client.Score: skor dokumen per ranker berdampingan
+type ScoreResponse struct {
+	Query       string             `json:"query"`
+	QueryVector map[string]float64 `json:"query_vector"`
+	DocID       *int               `json:"doc_id,omitempty"`
+	Title       string             `json:"title"`
+	Language    string             `json:"language"`
+	Source      string             `json:"source"`
+	Length      DocLength          `json:"length"`
+	Indexed     bool               `json:"indexed"`
+	Matched     bool               `json:"matched"`
+	// Phrase dalam tanda kutip di query; PhraseMatch kalau dokumen memuat semuanya persis
+	Phrases     []string          `json:"phrases,omitempty"`
+	PhraseMatch bool              `json:"phrase_match,omitempty"`
+	Rankers     []RankerBreakdown `json:"rankers"`
+	Messages    []string          `json:"messages,omitempty"`
+}
+
+type DocLength struct {
+	Title   int `json:"title"`
+	Content int `json:"content"`
+}
+
+type RankerBreakdown struct {
+	Ranker         string          `json:"ranker"` // cosine, jaccard, bm25
+	Score          float64         `json:"score"`
+	Document       RankerScore     `json:"document"`
+	Paragraph      *ParagraphScore `json:"paragraph,omitempty"`
+	AuthorityBoost float64         `json:"authority_boost"`
+}
+
+type RankerScore struct {
+	Score        float64     `json:"score"`
+	Terms        []ScoreTerm `json:"terms"`
+	DocNorm      float64     `json:"doc_norm,omitempty"`
+	Intersection int         `json:"intersection,omitempty"`
+	Union        int         `json:"union,omitempty"`
+	DocLength    int         `json:"doc_length,omitempty"`
+	AvgDocLength float64     `json:"avg_doc_length,omitempty"`
+}
+
+type ParagraphScore struct {
+	Ordinal int `json:"ordinal"`
+	RankerScore
+}
+
+type ScoreTerm struct {
+	Term         string  `json:"term"`
+	QueryWeight  float64 `json:"query_weight"`
+	Frequency    int     `json:"frequency"`
+	DocFrequency int     `json:"doc_frequency"`
+	IDF          float64 `json:"idf"`
+	Contribution float64 `json:"contribution"`
+}
+
+// Dokumen yang di-soft delete; bisa dikembalikan sampai PurgeAt
+type DeletedDocument struct {
+	DocID     int       `json:"doc_id"`
+	URL       string    `json:"url"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// Job di background (operasi bulk atau job berkala); Status queued,
+// running, done, failed atau canceled
+type Job struct {
+	ID        string            `json:"id"`
+	Kind      string            `json:"kind"` // bulk_delete, bulk_retag, bulk_recrawl, archive, reindex, ...
+	Status    string            `json:"status"`
+	Params    map[string]string `json:"params,omitempty"`
+	Total     int               `json:"total"`
+	Processed int               `json:"processed"`
+	Changed   int               `json:"changed"`
+	Failed    int               `json:"failed"`
+	Progress  float64           `json:"progress"`
+	Errors    []string          `json:"errors,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	// Percobaan yang sudah dimulai; job gagal dicoba lagi sampai MaxAttempts
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	NextRunAt   *time.Time `json:"next_run_at,omitempty"` // kapan retry berikutnya
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// Satu perubahan admin di audit log. Before dan After adalah snapshot JSON
+// objek yang diubah, kosong kalau tidak ada (misalnya dokumen baru).
+type AuditEntry struct {
+	Time   time.Time       `json:"time"`
+	Actor  string          `json:"actor"`
+	IP     string          `json:"ip,omitempty"`
+	Action string          `json:"action"`
+	Target string          `json:"target,omitempty"`
+	Job    string          `json:"job,omitempty"`
+	Seq    uint64          `json:"seq,omitempty"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// Filter audit log; Action yang diakhiri "." cocok sebagai prefix
+type AuditQuery struct {
+	Actor  string
+	Action string
+	Target string
+	Job    string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+}
+
+// Satu versi config.json di riwayat server; Source startup, file, api atau rollback
+type ConfigVersion struct {
+	Version    int             `json:"version"`
+	Time       time.Time       `json:"time"`
+	Source     string          `json:"source"`
+	Actor      string          `json:"actor,omitempty"`
+	RollbackOf int             `json:"rollback_of,omitempty"`
+	Config     json.RawMessage `json:"config"`
+}
+
+// Hasil UpdateConfig dan RollbackConfig
+type ConfigUpdate struct {
+	Version int  `json:"version"`
+	Changed bool `json:"changed"`
+	// Setting yang baru berlaku setelah server di-restart
+	RestartRequired []string `json:"restart_required,omitempty"`
+}
+
+// Ringkasan status sistem dari GET /api/admin/dashboard
+type Dashboard struct {
+	Time    time.Time         `json:"time"`
+	Index   *DashboardIndex   `json:"index"` // nil kalau index belum pernah dibangun
+	Sources []DashboardSource `json:"sources"`
+	Crawls  []DashboardCrawl  `json:"crawls"`
+	Queries DashboardQueries  `json:"queries"`
+	// Jumlah terbaru query yang dipantau
+	Monitored []MonitorUpdate `json:"monitored"`
+
+	ResultCache DashboardCache `json:"result_cache"`
+	QueryCache  DashboardCache `json:"query_cache"`
+	FilterCache DashboardCache `json:"filter_cache"`
+}
+
+type DashboardIndex struct {
+	Generation     int       `json:"generation"`
+	BuiltAt        time.Time `json:"built_at"`
+	BuildMs        float64   `json:"build_ms"`
+	Docs           int       `json:"docs"`
+	IndexedDocs    int       `json:"indexed_docs"`
+	SkippedDocs    int       `json:"skipped_docs"`
+	Terms          int       `json:"terms"`
+	PendingRecords int       `json:"pending_records"`
+}
+
+type DashboardSource struct {
+	Source   string `json:"source"`
+	Articles int    `json:"articles"`
+}
+
+// Snapshot progress crawl terakhir per source
+type DashboardCrawl struct {
+	Source      string    `json:"source"`
+	Status      string    `json:"status"`
+	StartedAt   time.Time `json:"started_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Articles    int64     `json:"articles"`
+	Errors      int64     `json:"errors"`
+	StoppedBy   string    `json:"stopped_by,omitempty"`
+	SuccessRate float64   `json:"success_rate"`
+}
+
+// Query 24 jam terakhir: Hourly terlama dulu, Slow terlambat dulu
+type DashboardQueries struct {
+	Total   int   `json:"total"`
+	Hourly  []int `json:"hourly"`
+	Latency struct {
+		P50  float64 `json:"p50_ms"`
+		P90  float64 `json:"p90_ms"`
+		P95  float64 `json:"p95_ms"`
+		P99  float64 `json:"p99_ms"`
+		Max  float64 `json:"max_ms"`
+		Mean float64 `json:"mean_ms"`
+	} `json:"latency"`
+	Slow []SlowQuery `json:"slow"`
+}
+
+type SlowQuery struct {
+	Time       time.Time `json:"time"`
+	Query      string    `json:"query"`
+	Method     string    `json:"method"`
+	Results    int       `json:"results"`
+	DurationMs float64   `json:"duration_ms"`
+}
+
+type DashboardCache struct {
+	Hits    int     `json:"hits"`
+	Misses  int     `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// Query yang dipantau beserta jumlah hasilnya per generasi index, terlama dulu
+type MonitoredQuery struct {
+	Query  string         `json:"query"`
+	Points []MonitorPoint `json:"points"`
+}
+
+type MonitorPoint struct {
+	Generation int       `json:"generation"`
+	Time       time.Time `json:"time"`
+	Total      int       `json:"total"`
+}
+
+// Jumlah terbaru satu query yang dipantau; Delta dibanding generasi
+// sebelumnya, Growth dibanding titik pertama di riwayat
+type MonitorUpdate struct {
+	Query      string    `json:"query"`
+	Generation int       `json:"generation"`
+	Time       time.Time `json:"time"`
+	Total      int       `json:"total"`
+	Delta      int       `json:"delta"`
+	Growth     int       `json:"growth"`
+}
+
+// Kondisi alert bawaan dari GET /api/admin/alerts
+type Alert struct {
+	Name      string            `json:"name"` // CrawlFailing, ZeroResultRateSpike, IndexStale
+	Firing    bool              `json:"firing"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Value     float64           `json:"value"`
+	Threshold float64           `json:"threshold"`
+	Message   string            `json:"message"`
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Aturan kata majemuk per field text schema, supaya bentuk gabungan dan
+// bentuk terpisah sama-sama cocok dengan query:
+//
+//   - hyphen: KPR-FLPP -> kpr, flpp, kprflpp
+//   - slash: Rp5jt/bulan -> rp5jt, bulan, rp5jtbulan
+//   - alnum: huruf yang disambung angka dipecah, rumah2 -> rumah2, rumah dan
+//     Rp5jt -> rp5jt, rp, 5jt; m2 dan km2 tidak dipecah
+//   - units: simbol satuan dinormalisasi, ㎡ dan m² -> m2, ㎥ dan m³ -> m3
+//
+// Aturan dipilih per field di schema:
+//
+//	{"name": "content", "type": "text", "compounds": ["hyphen", "slash", "alnum", "units"]}
+//
+// Posisi kata tidak berubah: bentuk gabungan dan pecahan alnum ditumpuk di
+// posisi kata pertamanya, simbol satuan yang berdiri sendiri di posisi kata
+// sebelumnya. Phrase query dengan bentuk yang tertulis di dokumen tetap cocok.
+// Query tidak dipecah: "kprflpp" cocok lewat bentuk gabungan, "kpr flpp" dan
+// "KPR-FLPP" lewat bentuk terpisah.
+const (
+	CompoundHyphen = "hyphen"
+	CompoundSlash  = "slash"
+	CompoundAlnum  = "alnum"
+	CompoundUnits  = "units"
+)
+
+type CompoundRules struct {
+	Hyphen bool
+	Slash  bool
+	Alnum  bool
+	Units  bool
+}
+
+// Simbol satuan dan bentuk normalnya. Simbol satu karakter boleh berdiri
+// sendiri; pangkat hanya setelah huruf (m², km³).
+var unitSymbols = []struct{ symbol, term string }{
+	{"㎡", "m2"},
+	{"㎥", "m3"},
+	{"㎢", "km2"},
+	{"㎞", "km"},
+}
+
+var unitPowers = []struct{ symbol, term string }{
+	{"²", "2"},
+	{"³", "3"},
+}
+
+func compileCompoundRules(names []string) (CompoundRules, error) {
+	var rules CompoundRules
+	for _, name := range names {
+		switch name {
+		case CompoundHyphen:
+			rules.Hyphen = true
+		case CompoundSlash:
+			rules.Slash = true
+		case CompoundAlnum:
+			rules.Alnum = true
+		case CompoundUnits:
+			rules.Units = true
+		default:
+			return rules, fmt.Errorf("unknown compound rule %q (%s, %s, %s or %s)", name, CompoundHyphen, CompoundSlash, CompoundAlnum, CompoundUnits)
+		}
+	}
+	return rules, nil
+}
+
+// Aturan field; sudah divalidasi IndexSchema.compile
+func (f SchemaField) compoundRules() CompoundRules {
+	rules, _ := compileCompoundRules(f.Compounds)
+	return rules
+}
+
+// Kata di teks sebelum dipecah atau digabung
+type compoundWord struct {
+	start, end int
+	term       string
+	position   int
+	numeric    bool
+}
+
+// Sama dengan AnalyzeInto, ditambah aturan kata majemuk field
+func (tp *TextProcessor) AnalyzeCompounds(buf []Token, text string, rules CompoundRules) []Token {
+	if rules == (CompoundRules{}) {
+		return tp.AnalyzeInto(buf, text)
+	}
+	tokens := splitCompounds(buf[:0], text, rules)
+	tokens = tp.caseFolding(tokens)
+	tokens = tp.removeRepeats(tokens)
+	tokens = tp.removeStopwords(tokens)
+	return tp.stemming(tokens)
+}
+
+// Pengganti splitWords: kata dan posisinya sama, ditambah bentuk gabungan,
+// pecahan alnum dan satuan yang dinormalisasi
+func splitCompounds(tokens []Token, text string, rules CompoundRules) []Token {
+	var words []compoundWord
+	position := 0
+
+	for i := 0; i < len(text); {
+		if rules.Units && !isWordByte(text[i]) {
+			if symbol, term, ok := unitAt(text, i, unitSymbols); ok {
+				// Tidak menghabiskan posisi, supaya posisi kata lain sama
+				// dengan analyzer tanpa aturan ini
+				unitPosition := position - 1
+				if unitPosition < 0 {
+					unitPosition = 0
+				}
+				words = append(words, compoundWord{start: i, end: i + len(symbol), term: term, position: unitPosition})
+				i += len(symbol)
+				continue
+			}
+		}
+		if !isWordByte(text[i]) {
+			i++
+			continue
+		}
+
+		start := i
+		numeric := true
+		for i < len(text) && isWordByte(text[i]) {
+			if text[i] < '0' || text[i] > '9' {
+				numeric = false
+			}
+			i++
+		}
+		term := text[start:i]
+		if rules.Units {
+			// 100㎡ -> 100m2, m² -> m2
+			if symbol, unit, ok := unitAt(text, i, unitSymbols); ok {
+				term, numeric = term+unit, false
+				i += len(symbol)
+			} else if symbol, power, ok := unitAt(text, i, unitPowers); ok && isLetterByte(text[i-1]) {
+				term = term + power
+				i += len(symbol)
+			}
+		}
+		words = append(words, compoundWord{start: start, end: i, term: term, position: position, numeric: numeric})
+		position++
+	}
+
+	for g := 0; g < len(words); {
+		h := g
+		for h+1 < len(words) && joinsCompound(text, words[h], words[h+1], rules) {
+			h++
+		}
+		if h > g {
+			if joined, ok := joinCompound(words[g : h+1]); ok {
+				tokens = append(tokens, Token{Term: joined, Position: words[g].position, Start: words[g].start, End: words[h].end})
+			}
+		}
+		for _, word := range words[g : h+1] {
+			if word.numeric {
+				continue
+			}
+			tokens = append(tokens, Token{Term: word.term, Position: word.position, Start: word.start, End: word.end})
+			if rules.Alnum {
+				tokens = appendAlnumParts(tokens, word)
+			}
+		}
+		g = h + 1
+	}
+
+	return tokens
+}
+
+// Dua kata hanya dipisah satu tanda hubung atau garis miring
+func joinsCompound(text string, a, b compoundWord, rules CompoundRules) bool {
+	if b.start != a.end+1 {
+		return false
+	}
+	switch text[a.end] {
+	case '-':
+		return rules.Hyphen
+	case '/':
+		return rules.Slash
+	}
+	return false
+}
+
+// Bentuk gabungan; tidak ada kalau semua bagiannya angka (2020-2021,
+// 12/05/2023) atau sama (m2/㎡)
+func joinCompound(words []compoundWord) (string, bool) {
+	numeric, same := true, true
+	var b strings.Builder
+	for _, word := range words {
+		numeric = numeric && word.numeric
+		same = same && strings.EqualFold(word.term, words[0].term)
+		b.WriteString(word.term)
+	}
+	if numeric || same {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// Pecah kata di batas huruf-angka, ditumpuk di posisi kata. Batas dipakai
+// kalau hurufnya minimal dua dan angkanya diikuti huruf (rp|5jt) atau
+// hurufnya minimal tiga (rumah|2, covid|19), supaya satuan seperti m2 dan
+// km2 tetap utuh. Bagian yang hanya angka dibuang seperti angka lain.
+func appendAlnumParts(tokens []Token, word compoundWord) []Token {
+	term := word.term
+	var cuts []int
+	letters := 0
+	for i := 0; i < len(term); i++ {
+		if isLetterByte(term[i]) {
+			letters++
+			continue
+		}
+		if term[i] >= '0' && term[i] <= '9' && letters >= 2 {
+			j := i
+			for j < len(term) && term[j] >= '0' && term[j] <= '9' {
+				j++
+			}
+			if letters >= 3 || (j < len(term) && isLetterByte(term[j])) {
+				cuts = append(cuts, i)
+			}
+		}
+		letters = 0
+	}
+	if len(cuts) == 0 {
+		return tokens
+	}
+
+	// Satuan yang dinormalisasi membuat term lebih panjang dari teksnya
+	offset := func(i int) int {
+		if word.start+i > word.end {
+			return word.end
+		}
+		return word.start + i
+	}
+	cuts = append(cuts, len(term))
+	from := 0
+	for _, cut := range cuts {
+		if part := term[from:cut]; !isNumericTerm(part) {
+			tokens = append(tokens, Token{Term: part, Position: word.position, Start: offset(from), End: offset(cut)})
+		}
+		from = cut
+	}
+	return tokens
+}
+
+func unitAt(text string, i int, units []struct{ symbol, term string }) (string, string, bool) {
+	for _, unit := range units {
+		if strings.HasPrefix(text[i:], unit.symbol) {
+			return unit.symbol, unit.term, true
+		}
+	}
+	return "", "", false
+}
+
+func isLetterByte(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNumericTerm(term string) bool {
+	for i := 0; i < len(term); i++ {
+		if term[i] < '0' || term[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+const CONFIG_FILE = "config.json"
+
+// Config berisi pengaturan engine yang bisa diubah tanpa compile ulang
+type Config struct {
+	// Simpan byte offset tiap term di posting list supaya snippet dan
+	// highlight tidak perlu menganalisis ulang dokumen saat query
+	// (index lebih besar, query lebih cepat)
+	StoreOffsets bool `json:"store_offsets"`
+
+	// Path document store terkompresi. Kalau di-set, konten artikel dilepas
+	// dari memori setelah diindex dan diambil dari document store saat
+	// membuat snippet.
+	DocStorePath string `json:"doc_store_path"`
+
+	// Term yang muncul di minimal fraksi dokumen ini disimpan juga sebagai
+	// roaring bitmap untuk irisan cepat (0 = nonaktif)
+	DenseTermRatio float64 `json:"dense_term_ratio"`
+
+	// Kebijakan merge write-ahead log ke articles.json: merge begitu jumlah
+	// record tertunda mencapai batas atau interval terlewati
+	WalFlushRecords         int `json:"wal_flush_records"`
+	WalFlushIntervalSeconds int `json:"wal_flush_interval_seconds"`
+
+	// Pakai implementasi regex lama untuk cleanContent (kompatibilitas);
+	// default scanner tanpa regex yang jauh lebih cepat
+	RegexCleanContent bool `json:"regex_clean_content"`
+
+	// Index setiap paragraf juga sebagai sub-dokumen; skor artikel diambil
+	// dari paragraf terbaiknya dan snippet dari paragraf itu. Paragraf yang
+	// lebih pendek dari paragraph_min_words kata digabung dengan berikutnya.
+	ParagraphIndex    bool `json:"paragraph_index"`
+	ParagraphMinWords int  `json:"paragraph_min_words"`
+
+	// Cache halaman hasil yang sudah dirender; TTL 0 mematikan cache
+	ResultCacheTTLSeconds int `json:"result_cache_ttl_seconds"`
+	ResultCacheSize       int `json:"result_cache_size"`
+
+	// Cache LRU hasil yang sudah diranking per query; TTL 0 mematikan cache,
+	// lihat querycache.go
+	QueryCacheTTLSeconds int `json:"query_cache_ttl_seconds"`
+	QueryCacheSize       int `json:"query_cache_size"`
+
+	// Pencarian yang lebih lama dari ini mendapat halaman timeout (0 = tanpa batas)
+	SearchTimeoutSeconds int `json:"search_timeout_seconds"`
+
+	// Feature flag ranker kandidat untuk shadow traffic (kosong = shadow mati)
+	ShadowFeatures []string `json:"shadow_features"`
+
+	// Retensi per source dalam hari ("*" = source lain); artikel yang lebih
+	// tua dipindah ke arsip, lihat archive.go. Kosong = tidak ada arsip.
+	ArchiveAfterDays map[string]int `json:"archive_after_days"`
+
+	// Timeout per tier federasi dalam milidetik (hot, archive; 0 = tanpa
+	// batas) dan penalti skor hasil arsip per tahun umur artikel
+	TierTimeoutsMs        map[string]int `json:"tier_timeouts_ms"`
+	ArchivePenaltyPerYear float64        `json:"archive_penalty_per_year"`
+
+	// Instance lain yang ikut dicari untuk setiap query, lihat remote.go
+	RemoteInstances []RemoteInstance `json:"remote_instances"`
+
+	// Pengaruh skor kualitas source ke ranking (0 = mati), lihat sourcequality.go
+	AuthorityWeight float64 `json:"authority_weight"`
+
+	// Perintah eksternal pengganti extractor bawaan per doctype (pdf, docx),
+	// lihat documents.go
+	DocumentExtractors map[string][]string `json:"document_extractors"`
+
+	// OCR untuk PDF hasil scan, lihat ocr.go
+	OCR OCRConfig `json:"ocr"`
+
+	// Aturan validasi dokumen saat ingestion, lihat validation.go
+	Validation ValidationRules `json:"validation"`
+
+	// Field dokumen dan cara mengindexnya, lihat schema.go
+	Schema IndexSchema `json:"schema"`
+
+	// Saran autocomplete: half-life popularitas query dan bobotnya terhadap
+	// kosakata judul, lihat suggest.go
+	SuggestHalfLifeDays     float64 `json:"suggest_half_life_days"`
+	SuggestPopularityWeight float64 `json:"suggest_popularity_weight"`
+
+	// Pencarian suara lewat provider speech-to-text, lihat voice.go
+	Voice VoiceConfig `json:"voice"`
+
+	// Terjemahan query untuk pencarian lintas bahasa, lihat translation.go
+	Translation TranslationConfig `json:"translation"`
+
+	// Jawaban LLM dari hasil teratas, lihat answer.go
+	Answer AnswerConfig `json:"answer"`
+
+	// Jawaban unggulan leksikal untuk query pertanyaan, lihat qa.go
+	FeaturedAnswers bool `json:"featured_answers"`
+
+	// Reaksi kalau data tersimpan dianalisis dengan analyzer lain: warn,
+	// refuse atau off, lihat analyzercheck.go
+	AnalyzerCheck string `json:"analyzer_check"`
+
+	// Berapa hari dokumen yang dihapus masih bisa dikembalikan sebelum
+	// dibuang permanen (0 = delete langsung permanen), lihat softdelete.go
+	SoftDeleteDays int `json:"soft_delete_days"`
+
+	// Parameter ranking method=bm25, lihat scoring.go
+	BM25 BM25Params `json:"bm25"`
+
+	// API key klien beserta scope visibility dokumen yang boleh dilihat,
+	// lihat visibility.go
+	APIKeys []APIKey `json:"api_keys"`
+
+	// Stage lookup eksternal untuk dokumen baru, lihat enrichment.go
+	Enrichment []EnrichmentStage `json:"enrichment"`
+
+	// Ambang alert bawaan GET /api/admin/alerts, lihat alerts.go
+	Alerts AlertRules `json:"alerts"`
+
+	// Pemecahan kata gabungan di query: rewrite, suggest atau off, lihat
+	// segmentation.go
+	QuerySegmentation string `json:"query_segmentation"`
+	// Koreksi ejaan query: suggest, rewrite atau off, lihat spelling.go
+	SpellingCorrection string `json:"spelling_correction"`
+
+	// Generasi index lama yang disimpan untuk klien yang mem-pin generasi,
+	// lihat generations.go
+	GenerationRetention GenerationRetention `json:"generation_retention"`
+
+	// Bangun ulang index di background setiap interval ini walaupun corpus
+	// tidak berubah (0 = hanya saat corpus berubah), lihat indexer.go
+	IndexRebuildIntervalSeconds int `json:"index_rebuild_interval_seconds"`
+
+	// Worker build index paralel (0 = jumlah CPU, 1 = tanpa paralel), lihat
+	// parallelindex.go
+	IndexWorkers int `json:"index_workers"`
+
+	// Kenaikan skor untuk term query yang berdekatan, lihat proximity.go
+	Proximity ProximityConfig `json:"proximity"`
+
+	// Pengaruh authority link graph ke ranking (0 = mati), lihat linkgraph.go
+	LinkAuthorityWeight float64 `json:"link_authority_weight"`
+}
+
+// Config aktif. Config tidak pernah diubah di tempat: load awal, reload dan
+// rollback memasang pointer baru lewat setConfig. Pembaca mengambil satu
+// snapshot dengan currentConfig (atau requestConfig untuk request HTTP) lalu
+// memakainya sampai selesai, supaya satu request atau satu pass indexer
+// tidak melihat campuran config lama dan baru.
+var activeConfig atomic.Value // *Config
+
+func currentConfig() *Config {
+	return activeConfig.Load().(*Config)
+}
+
+func setConfig(cfg *Config) {
+	activeConfig.Store(cfg)
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		StoreOffsets:            false,
+		DenseTermRatio:          0.05,
+		WalFlushRecords:         WAL_FLUSH_RECORDS,
+		WalFlushIntervalSeconds: int(WAL_FLUSH_INTERVAL / time.Second),
+		ParagraphMinWords:       PARAGRAPH_MIN_WORDS,
+		ResultCacheTTLSeconds:   60,
+		ResultCacheSize:         256,
+		QueryCacheTTLSeconds:    300,
+		QueryCacheSize:          1024,
+		SearchTimeoutSeconds:    10,
+		ArchivePenaltyPerYear:   ARCHIVE_PENALTY_PER_YEAR,
+		AuthorityWeight:         AUTHORITY_WEIGHT,
+		OCR:                     defaultOCRConfig(),
+		Validation:              defaultValidationRules(),
+		Schema:                  defaultIndexSchema(),
+		SuggestHalfLifeDays:     SUGGEST_HALF_LIFE_DAYS,
+		SuggestPopularityWeight: SUGGEST_POPULARITY_WEIGHT,
+		Voice:                   defaultVoiceConfig(),
+		Translation:             defaultTranslationConfig(),
+		Answer:                  defaultAnswerConfig(),
+		FeaturedAnswers:         true,
+		AnalyzerCheck:           AnalyzerCheckWarn,
+		SoftDeleteDays:          SOFT_DELETE_DAYS,
+		BM25:                    BM25Params{K1: BM25_K1, B: BM25_B},
+		Alerts:                  defaultAlertRules(),
+		QuerySegmentation:       SegmentRewrite,
+		SpellingCorrection:      SpellingSuggest,
+		Proximity:               defaultProximityConfig(),
+		LinkAuthorityWeight:     LINK_AUTHORITY_WEIGHT,
+	}
+}
+
+// Load config dari JSON file, field yang tidak ada memakai nilai default
+func loadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return defaultConfig(), nil
+		}
+		return nil, err
+	}
+	return parseConfig(data, false)
+}
+
+// Parse dan validasi config. strict menolak key yang tidak dikenal, dipakai
+// saat reload supaya salah ketik tidak diam-diam diabaikan.
+func parseConfig(data []byte, strict bool) (*Config, error) {
+	cfg := defaultConfig()
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.Schema.compile(); err != nil {
+		return nil, fmt.Errorf("schema: %v", err)
+	}
+	if err := cfg.BM25.validate(); err != nil {
+		return nil, fmt.Errorf("bm25: %v", err)
+	}
+	if err := validateAPIKeys(cfg.APIKeys); err != nil {
+		return nil, fmt.Errorf("api_keys: %v", err)
+	}
+	if err := validateEnrichmentStages(cfg.Enrichment); err != nil {
+		return nil, fmt.Errorf("enrichment: %v", err)
+	}
+	if err := cfg.Alerts.validate(); err != nil {
+		return nil, fmt.Errorf("alerts: %v", err)
+	}
+	if err := validateQuerySegmentation(cfg.QuerySegmentation); err != nil {
+		return nil, fmt.Errorf("query_segmentation: %v", err)
+	}
+	if err := validateSpellingCorrection(cfg.SpellingCorrection); err != nil {
+		return nil, fmt.Errorf("spelling_correction: %v", err)
+	}
+	if err := cfg.Proximity.validate(); err != nil {
+		return nil, fmt.Errorf("proximity: %v", err)
+	}
+	if cfg.IndexRebuildIntervalSeconds < 0 {
+		return nil, fmt.Errorf("index_rebuild_interval_seconds: must not be negative")
+	}
+	if cfg.IndexWorkers < 0 {
+		return nil, fmt.Errorf("index_workers: must not be negative")
+	}
+	if cfg.LinkAuthorityWeight < 0 {
+		return nil, fmt.Errorf("link_authority_weight: must not be negative")
+	}
+
+	return cfg, nil
+}
+
+func init() {
+	setConfig(defaultConfig())
+
+	cfg, err := loadConfig(CONFIG_FILE)
+	if err != nil {
+		log.Printf("Error loading %s: %v", CONFIG_FILE, err)
+		return
+	}
+	setConfig(cfg)
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Hot reload config.json. Server memeriksa file setiap CONFIG_WATCH_INTERVAL;
+// config baru juga bisa dikirim lewat POST /api/admin/config (ditulis ke
+// config.json). Config baru divalidasi dulu dengan aturan yang sama seperti
+// saat startup ditambah penolakan key yang tidak dikenal; kalau gagal, config
+// lama tetap dipakai. Setiap config yang diterapkan menjadi versi baru di
+// config_history.json dan bisa dikembalikan lewat
+// POST /api/admin/config/rollback.
+//
+// Config diganti utuh lewat setConfig (config.go), tidak pernah diubah di
+// tempat. Setiap request HTTP memegang satu snapshot (pinConfig/requestConfig)
+// dan setiap pass indexer satu snapshot currentConfig, jadi pekerjaan yang
+// sedang berjalan tetap melihat config yang konsisten sampai selesai.
+// Index dibangun ulang dari config setiap load, jadi perubahan schema, bm25,
+// paragraph_index dan sejenisnya langsung berlaku. doc_store_path hanya
+// dibaca saat startup.
+const (
+	CONFIG_HISTORY_FILE   = "config_history.json"
+	CONFIG_HISTORY_SIZE   = 20
+	CONFIG_WATCH_INTERVAL = 5 * time.Second
+
+	configContextKey = "config"
+)
+
+// Asal versi config
+const (
+	ConfigSourceStartup  = "startup"
+	ConfigSourceFile     = "file"
+	ConfigSourceAPI      = "api"
+	ConfigSourceRollback = "rollback"
+)
+
+type ConfigVersion struct {
+	Version int       `json:"version"`
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source"`
+	Actor   string    `json:"actor,omitempty"`
+	// Versi yang dikembalikan, untuk source rollback
+	RollbackOf int `json:"rollback_of,omitempty"`
+	// Isi config.json versi ini
+	Config json.RawMessage `json:"config"`
+}
+
+type ConfigHistory struct {
+	mu       sync.Mutex
+	path     string
+	Versions []ConfigVersion `json:"versions"`
+	// Stat config.json yang terakhir dilihat watcher atau ditulis lewat API
+	seenModTime time.Time
+	seenSize    int64
+}
+
+var configHistory *ConfigHistory
+
+func loadConfigHistory(path string) (*ConfigHistory, error) {
+	history := &ConfigHistory{path: path}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// Dipanggil dengan mu terkunci
+func (h *ConfigHistory) save() error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(h.path, data, 0644)
+}
+
+func (h *ConfigHistory) latest() *ConfigVersion {
+	if len(h.Versions) == 0 {
+		return nil
+	}
+	return &h.Versions[len(h.Versions)-1]
+}
+
+func (h *ConfigHistory) Current() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if latest := h.latest(); latest != nil {
+		return latest.Version
+	}
+	return 0
+}
+
+// Semua versi, terbaru dulu
+func (h *ConfigHistory) List() []ConfigVersion {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	list := make([]ConfigVersion, 0, len(h.Versions))
+	for i := len(h.Versions) - 1; i >= 0; i-- {
+		list = append(list, h.Versions[i])
+	}
+	return list
+}
+
+func (h *ConfigHistory) Get(version int) (ConfigVersion, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, v := range h.Versions {
+		if v.Version == version {
+			return v, true
+		}
+	}
+	return ConfigVersion{}, false
+}
+
+// Hasil menerapkan config baru
+type ConfigUpdate struct {
+	Version int  `json:"version"`
+	Changed bool `json:"changed"` // false kalau isinya sama dengan versi sekarang
+	// Setting yang berubah tapi baru berlaku setelah restart
+	RestartRequired []string `json:"restart_required,omitempty"`
+}
+
+// Setting yang hanya dibaca saat startup dan berbeda di next
+func restartRequired(current, next *Config) []string {
+	var fields []string
+	if current.DocStorePath != next.DocStorePath {
+		fields = append(fields, "doc_store_path")
+	}
+	return fields
+}
+
+// Validasi lalu pakai config baru dan catat sebagai versi baru. writeFile
+// menulis data ke config.json (perubahan lewat API) supaya tetap berlaku
+// setelah restart.
+func (h *ConfigHistory) Apply(data []byte, source string, rollbackOf int, actor AuditActor, writeFile bool) (ConfigUpdate, error) {
+	cfg, err := parseConfig(data, true)
+	if err != nil {
+		return ConfigUpdate{}, err
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return ConfigUpdate{}, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	latest := h.latest()
+	if latest != nil && bytes.Equal(latest.Config, compact.Bytes()) {
+		return ConfigUpdate{Version: latest.Version}, nil
+	}
+	if writeFile {
+		if err := writeConfigFile(CONFIG_FILE, data); err != nil {
+			return ConfigUpdate{}, err
+		}
+		h.markSeen()
+	}
+
+	previous := currentConfig()
+	setConfig(cfg)
+	purgeResultCaches()
+	// Schema dan analyzer ikut menentukan isi index
+	indexer.Invalidate()
+
+	version := ConfigVersion{
+		Version:    1,
+		Time:       time.Now(),
+		Source:     source,
+		Actor:      actor.Name,
+		RollbackOf: rollbackOf,
+		Config:     compact.Bytes(),
+	}
+	var before json.RawMessage
+	if latest != nil {
+		version.Version = latest.Version + 1
+		before = latest.Config
+	}
+	h.Versions = append(h.Versions, version)
+	if len(h.Versions) > CONFIG_HISTORY_SIZE {
+		h.Versions = h.Versions[len(h.Versions)-CONFIG_HISTORY_SIZE:]
+	}
+	if err := h.save(); err != nil {
+		log.Printf("Error saving config history: %v", err)
+	}
+
+	action := AuditConfigUpdate
+	if source == ConfigSourceRollback {
+		action = AuditConfigRollback
+	}
+	actor.Record(action, fmt.Sprintf("version %d", version.Version), before, version.Config)
+
+	update := ConfigUpdate{Version: version.Version, Changed: true, RestartRequired: restartRequired(previous, cfg)}
+	log.Printf("Config version %d applied (%s)", version.Version, source)
+	if len(update.RestartRequired) > 0 {
+		log.Printf("Config version %d: %v take effect after a restart", version.Version, update.RestartRequired)
+	}
+	return update, nil
+}
+
+// Tulis lewat file sementara supaya watcher tidak membaca file setengah jadi
+func writeConfigFile(path string, data []byte) error {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, data, "", "  "); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, append(indented.Bytes(), '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Catat stat config.json sekarang supaya watcher tidak menerapkannya lagi;
+// dipanggil dengan mu terkunci
+func (h *ConfigHistory) markSeen() {
+	if info, err := os.Stat(CONFIG_FILE); err == nil {
+		h.seenModTime, h.seenSize = info.ModTime(), info.Size()
+	}
+}
+
+// config.json berubah sejak terakhir dilihat
+func (h *ConfigHistory) fileChanged() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	info, err := os.Stat(CONFIG_FILE)
+	if err != nil {
+		return false
+	}
+	if info.ModTime().Equal(h.seenModTime) && info.Size() == h.seenSize {
+		return false
+	}
+	h.seenModTime, h.seenSize = info.ModTime(), info.Size()
+	return true
+}
+
+func (h *ConfigHistory) reloadFile(source string) {
+	data, err := ioutil.ReadFile(CONFIG_FILE)
+	if err != nil {
+		log.Printf("Error reading %s: %v", CONFIG_FILE, err)
+		return
+	}
+	if _, err := h.Apply(data, source, 0, AuditActor{Name: source}, false); err != nil {
+		log.Printf("Config reload rejected, keeping version %d: %v", h.Current(), err)
+	}
+}
+
+// Catat config.json saat startup sebagai versi (kalau berbeda dari versi
+// terakhir) lalu periksa perubahan file secara berkala
+func startConfigWatcher(interval time.Duration) {
+	if configHistory == nil {
+		return
+	}
+	if configHistory.fileChanged() {
+		configHistory.reloadFile(ConfigSourceStartup)
+	}
+	go func() {
+		for {
+			time.Sleep(interval)
+			if configHistory.fileChanged() {
+				configHistory.reloadFile(ConfigSourceFile)
+			}
+		}
+	}()
+}
+
+// Middleware: snapshot config untuk seluruh request, diambil sebelum handler
+// lain (termasuk apiKeyAuth) membaca config
+func pinConfig() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(configContextKey, currentConfig())
+		c.Next()
+	}
+}
+
+// Snapshot config request ini; currentConfig kalau request tidak lewat pinConfig
+func requestConfig(c *gin.Context) *Config {
+	if value, exists := c.Get(configContextKey); exists {
+		return value.(*Config)
+	}
+	return currentConfig()
+}
+
+// GET /api/admin/config
+func getConfigHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"version": configHistory.Current(), "config": requestConfig(c)})
+}
+
+// POST /api/admin/config, body: isi config.json baru. ?dry_run=1 hanya validasi.
+func updateConfigHandler(c *gin.Context) {
+	data, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if c.Query("dry_run") == "1" {
+		cfg, err := parseConfig(data, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"valid": true, "restart_required": restartRequired(requestConfig(c), cfg)})
+		return
+	}
+
+	update, err := configHistory.Apply(data, ConfigSourceAPI, 0, auditActor(c), true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, update)
+}
+
+// GET /api/admin/config/versions
+func configVersionsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"current": configHistory.Current(), "versions": configHistory.List()})
+}
+
+// POST /api/admin/config/rollback, body: {"version": 3}. Config versi itu
+// diterapkan lagi sebagai versi baru.
+func rollbackConfigHandler(c *gin.Context) {
+	var req struct {
+		Version int `json:"version" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	version, exists := configHistory.Get(req.Version)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("config version %d not found", req.Version)})
+		return
+	}
+
+	update, err := configHistory.Apply(version.Config, ConfigSourceRollback, version.Version, auditActor(c), true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, update)
+}
+
+func init() {
+	history, err := loadConfigHistory(CONFIG_HISTORY_FILE)
+	if err != nil {
+		log.Printf("Error loading %s: %v", CONFIG_HISTORY_FILE, err)
+		history = &ConfigHistory{path: CONFIG_HISTORY_FILE}
+	}
+	configHistory = history
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const CONTENT_FILTERS_FILE = "content_filters.json"
+
+// Aksi yang diambil saat filter cocok
+const (
+	FilterActionFlag    = "flag"    // tetap diindex, masuk review queue
+	FilterActionExclude = "exclude" // tidak diindex sampai di-approve admin
+)
+
+// ContentFilter mendeteksi spam (judi, dewasa) yang sering ikut ter-scrape
+// dari kolom komentar
+type ContentFilter struct {
+	Name     string   `json:"name"`
+	Keywords []string `json:"keywords"`
+	Patterns []string `json:"patterns"`
+	Action   string   `json:"action"`
+
+	compiled []*regexp.Regexp
+}
+
+// Hasil pengecekan satu dokumen
+type FilterMatch struct {
+	Filter  string   `json:"filter"`
+	Action  string   `json:"action"`
+	Matches []string `json:"matches"`
+}
+
+var contentFilters []*ContentFilter
+
+func defaultContentFilters() []*ContentFilter {
+	return []*ContentFilter{
+		{
+			Name: "gambling",
+			Keywords: []string{
+				"judi online", "slot gacor", "situs slot", "togel", "maxwin",
+				"rtp live", "bandar bola", "casino online", "deposit pulsa",
+			},
+			Patterns: []string{`(?i)\bslot\s*\d{2,}\b`, `(?i)\bsbobet\b`},
+			Action:   FilterActionExclude,
+		},
+		{
+			Name:     "adult",
+			Keywords: []string{"bokep", "porn", "video mesum", "situs dewasa"},
+			Action:   FilterActionExclude,
+		},
+	}
+}
+
+// Load filters from JSON file, fallback ke default kalau file belum ada
+func loadContentFilters(path string) ([]*ContentFilter, error) {
+	var filters []*ContentFilter
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		filters = defaultContentFilters()
+	} else if err := json.Unmarshal(data, &filters); err != nil {
+		return nil, err
+	}
+
+	for _, f := range filters {
+		if err := f.compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	return filters, nil
+}
+
+func (f *ContentFilter) compile() error {
+	if f.Action == "" {
+		f.Action = FilterActionFlag
+	}
+	if f.Action != FilterActionFlag && f.Action != FilterActionExclude {
+		return fmt.Errorf("filter %q: unknown action %q", f.Name, f.Action)
+	}
+
+	f.compiled = make([]*regexp.Regexp, 0, len(f.Patterns))
+	for _, p := range f.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("filter %q: invalid pattern %q: %v", f.Name, p, err)
+		}
+		f.compiled = append(f.compiled, re)
+	}
+	return nil
+}
+
+// Cari semua keyword/pattern yang cocok di teks
+func (f *ContentFilter) match(text string) []string {
+	var matches []string
+	lower := strings.ToLower(text)
+
+	for _, keyword := range f.Keywords {
+		if strings.Contains(lower, strings.ToLower(keyword)) {
+			matches = append(matches, keyword)
+		}
+	}
+	for _, re := range f.compiled {
+		if m := re.FindString(text); m != "" {
+			matches = append(matches, m)
+		}
+	}
+
+	return matches
+}
+
+// Cek artikel terhadap semua filter
+func checkContent(article Article) []FilterMatch {
+	var results []FilterMatch
+	text := article.Title + " " + article.Content
+
+	for _, f := range contentFilters {
+		if matches := f.match(text); len(matches) > 0 {
+			results = append(results, FilterMatch{
+				Filter:  f.Name,
+				Action:  f.Action,
+				Matches: matches,
+			})
+		}
+	}
+
+	return results
+}
+
+func hasExcludeMatch(matches []FilterMatch) bool {
+	for _, m := range matches {
+		if m.Action == FilterActionExclude {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	filters, err := loadContentFilters(CONTENT_FILTERS_FILE)
+	if err != nil {
+		log.Printf("Error loading %s: %v", CONTENT_FILTERS_FILE, err)
+		filters = defaultContentFilters()
+		for _, f := range filters {
+			f.compile()
+		}
+	}
+	contentFilters = filters
+}
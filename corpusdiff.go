@@ -0,0 +1,293 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Diff corpus antar crawl. Setiap crawler memindahkan output lamanya ke
+// <output>.previous.json sebelum menulis hasil baru, jadi perbandingan dua
+// crawl terakhir selalu tersedia: artikel baru, artikel yang hilang dan
+// artikel yang berubah (field apa saja dan berapa kata konten yang
+// bertambah/berkurang), per source.
+const CORPUS_DIFF_LISTED = 100 // artikel per daftar di laporan
+
+type ArticleRef struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+type ChangedArticle struct {
+	ArticleRef
+	Fields       []string `json:"fields"` // title, content, date, author, breadcrumb
+	WordsAdded   int      `json:"words_added"`
+	WordsRemoved int      `json:"words_removed"`
+}
+
+type SourceDiff struct {
+	Source    string           `json:"source"`
+	Before    int              `json:"before"`
+	After     int              `json:"after"`
+	Added     int              `json:"added"`
+	Removed   int              `json:"removed"`
+	Changed   int              `json:"changed"`
+	Unchanged int              `json:"unchanged"`
+	AddedList []ArticleRef     `json:"added_articles,omitempty"`
+	Removals  []ArticleRef     `json:"removed_articles,omitempty"`
+	Changes   []ChangedArticle `json:"changed_articles,omitempty"`
+}
+
+// "articles.json" -> "articles.previous.json"
+func previousCrawlPath(path string) string {
+	return strings.TrimSuffix(path, ".json") + ".previous.json"
+}
+
+func readArticlesFile(path string) ([]Article, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var articles []Article
+	if err := json.Unmarshal(data, &articles); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return articles, nil
+}
+
+// Bandingkan dua snapshot, dikelompokkan per source (dari host URL)
+func diffCorpora(before, after []Article) []SourceDiff {
+	diffs := make(map[string]*SourceDiff)
+	diffFor := func(url string) *SourceDiff {
+		source := sourceOf(url)
+		if diffs[source] == nil {
+			diffs[source] = &SourceDiff{Source: source}
+		}
+		return diffs[source]
+	}
+
+	old := make(map[string]Article, len(before))
+	for _, article := range before {
+		old[article.URL] = article
+		diffFor(article.URL).Before++
+	}
+
+	seen := make(map[string]bool, len(after))
+	for _, article := range after {
+		if seen[article.URL] {
+			continue
+		}
+		seen[article.URL] = true
+		diff := diffFor(article.URL)
+		diff.After++
+
+		previous, exists := old[article.URL]
+		if !exists {
+			diff.Added++
+			if len(diff.AddedList) < CORPUS_DIFF_LISTED {
+				diff.AddedList = append(diff.AddedList, ArticleRef{URL: article.URL, Title: article.Title})
+			}
+			continue
+		}
+		change := compareArticles(previous, article)
+		if len(change.Fields) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		diff.Changed++
+		if len(diff.Changes) < CORPUS_DIFF_LISTED {
+			diff.Changes = append(diff.Changes, change)
+		}
+	}
+
+	for _, article := range before {
+		if seen[article.URL] {
+			continue
+		}
+		seen[article.URL] = true
+		diff := diffFor(article.URL)
+		diff.Removed++
+		if len(diff.Removals) < CORPUS_DIFF_LISTED {
+			diff.Removals = append(diff.Removals, ArticleRef{URL: article.URL, Title: article.Title})
+		}
+	}
+
+	list := make([]SourceDiff, 0, len(diffs))
+	for _, diff := range diffs {
+		list = append(list, *diff)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Source < list[j].Source })
+	return list
+}
+
+func compareArticles(before, after Article) ChangedArticle {
+	change := ChangedArticle{ArticleRef: ArticleRef{URL: after.URL, Title: after.Title}}
+	if before.Title != after.Title {
+		change.Fields = append(change.Fields, "title")
+	}
+	if before.Content != after.Content {
+		change.Fields = append(change.Fields, "content")
+		change.WordsAdded, change.WordsRemoved = wordDelta(before.Content, after.Content)
+	}
+	if !before.Date.Equal(after.Date) {
+		change.Fields = append(change.Fields, "date")
+	}
+	if before.Author != after.Author {
+		change.Fields = append(change.Fields, "author")
+	}
+	if strings.Join(before.Breadcrumb, BREADCRUMB_SEPARATOR) != strings.Join(after.Breadcrumb, BREADCRUMB_SEPARATOR) {
+		change.Fields = append(change.Fields, "breadcrumb")
+	}
+	return change
+}
+
+// Selisih kata sebagai multiset: kata yang bertambah dan berkurang
+func wordDelta(before, after string) (int, int) {
+	counts := make(map[string]int)
+	for _, word := range strings.Fields(before) {
+		counts[word]--
+	}
+	for _, word := range strings.Fields(after) {
+		counts[word]++
+	}
+
+	added, removed := 0, 0
+	for _, count := range counts {
+		if count > 0 {
+			added += count
+		} else {
+			removed -= count
+		}
+	}
+	return added, removed
+}
+
+// Diff dua crawl terakhir sebuah crawler
+func diffLastCrawls(source string) (*SourceDiff, error) {
+	// Output relatif ke root repo, sama dengan Output di crawl.completed
+	crawlSource, exists := findCrawlSource(source)
+	if !exists {
+		return nil, fmt.Errorf("unknown source %q", source)
+	}
+	path := crawlSource.outputPath()
+	after, err := readArticlesFile(path)
+	if err != nil {
+		return nil, err
+	}
+	before, err := readArticlesFile(previousCrawlPath(path))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// Output crawler hanya berisi satu source; source dari URL bisa berbeda
+	// (mis. host lain), jadi semuanya digabung di bawah nama crawler
+	total := SourceDiff{Source: source}
+	for _, diff := range diffCorpora(before, after) {
+		total.Before += diff.Before
+		total.After += diff.After
+		total.Added += diff.Added
+		total.Removed += diff.Removed
+		total.Changed += diff.Changed
+		total.Unchanged += diff.Unchanged
+		total.AddedList = append(total.AddedList, diff.AddedList...)
+		total.Removals = append(total.Removals, diff.Removals...)
+		total.Changes = append(total.Changes, diff.Changes...)
+	}
+	return &total, nil
+}
+
+// GET /api/admin/crawl/diff[?source=rumah123]
+func crawlDiffHandler(c *gin.Context) {
+	sources := crawlSourceNames()
+	if source := c.Query("source"); source != "" {
+		sources = []string{source}
+	}
+
+	diffs := make([]*SourceDiff, 0, len(sources))
+	for _, source := range sources {
+		diff, err := diffLastCrawls(source)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		diffs = append(diffs, diff)
+	}
+	c.JSON(http.StatusOK, gin.H{"sources": diffs})
+}
+
+// searchctl corpus diff [--source name] [--json] [<before.json> <after.json>]
+func corpusDiffCommand(args []string) int {
+	fs := flag.NewFlagSet("corpus diff", flag.ExitOnError)
+	source := fs.String("source", "", "hanya source ini")
+	asJSON := fs.Bool("json", false, "cetak laporan sebagai JSON")
+	fs.Parse(args)
+
+	var diffs []SourceDiff
+	switch fs.NArg() {
+	case 0:
+		// Dua crawl terakhir setiap crawler
+		for _, name := range crawlSourceNames() {
+			if *source != "" && name != *source {
+				continue
+			}
+			diff, err := diffLastCrawls(name)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			diffs = append(diffs, *diff)
+		}
+	case 2:
+		before, err := readArticlesFile(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		after, err := readArticlesFile(fs.Arg(1))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, diff := range diffCorpora(before, after) {
+			if *source == "" || diff.Source == *source {
+				diffs = append(diffs, diff)
+			}
+		}
+	default:
+		fmt.Fprintln(os.Stderr, "usage: searchctl corpus diff [--source name] [--json] [<before.json> <after.json>]")
+		return 2
+	}
+
+	if *asJSON {
+		data, _ := json.MarshalIndent(diffs, "", "  ")
+		fmt.Println(string(data))
+		return 0
+	}
+	for _, diff := range diffs {
+		fmt.Printf("%s: %d -> %d articles, %d added, %d removed, %d changed, %d unchanged\n",
+			diff.Source, diff.Before, diff.After, diff.Added, diff.Removed, diff.Changed, diff.Unchanged)
+		for _, article := range diff.AddedList {
+			fmt.Printf("  + %s\n", article.URL)
+		}
+		for _, article := range diff.Removals {
+			fmt.Printf("  - %s\n", article.URL)
+		}
+		for _, change := range diff.Changes {
+			fmt.Printf("  ~ %s (%s, +%d/-%d words)\n", change.URL, strings.Join(change.Fields, ", "), change.WordsAdded, change.WordsRemoved)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Vocabulary dasar corpus sintetis, diurutkan kira-kira dari yang paling
+// sering muncul. Frekuensi kata mengikuti distribusi Zipf sehingga
+// document frequency mirip corpus asli (beberapa term sangat umum, ekor panjang).
+var syntheticVocabulary = strings.Fields(`
+rumah properti harga kawasan hunian proyek pengembang pembeli kpr bank
+apartemen tanah lokasi investasi pasar unit cicilan bunga subsidi developer
+pembangunan akses jalan tol stasiun kota perumahan milenial konsumen penjualan
+kredit uang muka dp pemerintah program pajak sertifikat legalitas fasilitas
+tipe luas bangunan kamar tidur mandi carport taman keluarga lingkungan aman
+nyaman strategis dekat pusat perbelanjaan sekolah rumah_sakit transportasi
+lrt mrt krl bandara infrastruktur kenaikan penurunan permintaan pasokan tren
+tahun kuartal data survei riset indeks nilai sewa penyewa kos ruko gudang
+kavling cluster townhouse villa kondominium komersial residensial industri
+suku_bunga tenor angsuran bulanan penghasilan gaji pekerja formal informal
+tapera flpp btn bri bni mandiri syariah akad notaris balik_nama bphtb ppn
+pbb imb pbg zonasi tata_ruang banjir hijau green building ramah energi
+desain interior minimalis modern renovasi material semen bata baja atap
+keramik cat listrik air pdam internet keamanan satpam cctv gerbang one_gate
+promo diskon cashback gratis biaya booking fee serah_terima kunci inden ready
+stock pameran expo marketing agen broker listing iklan digital online platform
+`)
+
+// Setelah vocabulary dasar habis, ekor distribusi diisi kata buatan dari suku kata
+const SYNTHETIC_VOCABULARY_SIZE = 20000
+
+var syntheticSyllables = strings.Fields("ba be bi bu da di du ga gi gu ka ke ki ku la li lu ma me mi mu na ni nu pa pe pi pu ra ri ru sa se si su ta te ti tu wa ya")
+
+var syntheticTitleTemplates = []string{
+	"Harga Rumah di {loc} Naik {pct} Persen, Ini Penyebabnya",
+	"{count} Perumahan Subsidi Terbaru di {loc} Tahun {year}",
+	"Tips Memilih KPR untuk Milenial yang Tinggal di {loc}",
+	"Proyek Apartemen Baru di {loc} Ditawarkan Mulai Rp{price} Juta",
+	"Kawasan {loc} Jadi Incaran Investor Properti di {year}",
+	"Akses Tol Baru Dorong Permintaan Hunian di {loc}",
+	"Program Tapera dan FLPP: Apa Bedanya untuk Pembeli di {loc}?",
+	"Pasar Sewa Properti {loc} Tumbuh {pct} Persen",
+}
+
+var syntheticAuthors = []string{
+	"Redaksi", "Andi Pratama", "Siti Rahmawati", "Budi Santoso", "Dewi Lestari",
+	"Rizky Hidayat", "Nurul Aini", "Agus Setiawan",
+}
+
+var syntheticSources = []string{
+	"https://artikel.rumah123.com/",
+	"https://propertiterkini.com/",
+	"https://propertyandthecity.com/",
+}
+
+// searchctl corpus generate --docs N [--out file] [--seed S]
+func corpusGenerateCommand(args []string) int {
+	fs := flag.NewFlagSet("corpus generate", flag.ExitOnError)
+	docs := fs.Int("docs", 10000, "jumlah dokumen")
+	out := fs.String("out", "articles.synthetic.json", "file output")
+	seed := fs.Int64("seed", 1, "seed random (output deterministik untuk seed yang sama)")
+	years := fs.Int("years", 5, "rentang tanggal artikel ke belakang (tahun)")
+	fs.Parse(args)
+
+	start := time.Now()
+	articles := generateCorpus(*docs, *seed, *years)
+
+	f, err := os.Create(*out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(articles); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Printf("generated %d documents into %s in %s\n", len(articles), *out, time.Since(start).Round(time.Millisecond))
+	return 0
+}
+
+func generateCorpus(n int, seed int64, years int) []Article {
+	r := rand.New(rand.NewSource(seed))
+	vocabulary := buildSyntheticVocabulary(SYNTHETIC_VOCABULARY_SIZE)
+	zipf := rand.NewZipf(r, 1.1, 1, uint64(len(vocabulary)-1))
+	end := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	span := int64(years) * 365 * 24 * int64(time.Hour/time.Second)
+
+	articles := make([]Article, n)
+	for i := range articles {
+		location := knownLocations[r.Intn(len(knownLocations))]
+		source := syntheticSources[i%len(syntheticSources)]
+		title := syntheticTitle(r, location)
+
+		articles[i] = Article{
+			Title:   title,
+			Content: syntheticContent(r, zipf, vocabulary, location),
+			URL:     fmt.Sprintf("%ssynthetic/%d-%s", source, i, slugify(title)),
+			Date:    end.Add(-time.Duration(r.Int63n(span)) * time.Second).Truncate(24 * time.Hour),
+			Author:  syntheticAuthors[r.Intn(len(syntheticAuthors))],
+		}
+	}
+	return articles
+}
+
+func buildSyntheticVocabulary(size int) []string {
+	vocabulary := append([]string(nil), syntheticVocabulary...)
+	n := len(syntheticSyllables)
+	for i := 0; len(vocabulary) < size; i++ {
+		// 3 suku kata per kata, urutan deterministik
+		vocabulary = append(vocabulary, syntheticSyllables[i%n]+syntheticSyllables[(i/n)%n]+syntheticSyllables[(i/n/n)%n])
+	}
+	return vocabulary
+}
+
+func syntheticTitle(r *rand.Rand, location string) string {
+	template := syntheticTitleTemplates[r.Intn(len(syntheticTitleTemplates))]
+	return strings.NewReplacer(
+		"{loc}", location,
+		"{pct}", strconv.Itoa(2+r.Intn(30)),
+		"{count}", strconv.Itoa(3+r.Intn(12)),
+		"{year}", strconv.Itoa(2020+r.Intn(6)),
+		"{price}", strconv.Itoa(150+r.Intn(2000)),
+	).Replace(template)
+}
+
+// Panjang konten log-normal: kebanyakan artikel sedang, sedikit yang sangat panjang
+func syntheticContent(r *rand.Rand, zipf *rand.Zipf, vocabulary []string, location string) string {
+	paragraphs := 2 + int(math.Exp(r.NormFloat64()*0.6+1.5))
+	var b strings.Builder
+	for p := 0; p < paragraphs; p++ {
+		if p > 0 {
+			b.WriteString("\n")
+		}
+		sentences := 2 + r.Intn(4)
+		for s := 0; s < sentences; s++ {
+			words := 8 + r.Intn(14)
+			for w := 0; w < words; w++ {
+				word := strings.Replace(vocabulary[zipf.Uint64()], "_", " ", 1)
+				if w == 0 {
+					word = strings.ToUpper(word[:1]) + word[1:]
+				} else {
+					b.WriteString(" ")
+				}
+				b.WriteString(word)
+			}
+			// Sesekali sebut lokasi dan harga supaya doc values ikut terisi
+			switch r.Intn(6) {
+			case 0:
+				b.WriteString(" di " + location)
+			case 1:
+				fmt.Fprintf(&b, " dengan harga Rp%d juta", 150+r.Intn(2000))
+			}
+			b.WriteString(". ")
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(title) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
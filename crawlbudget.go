@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// Budget crawl per sumber supaya crawl terjadwal selalu berhenti dalam batas
+// yang bisa diprediksi. File dibagi dengan crawler (lihat */budget.go):
+//
+//	{"default": {"max_runtime": "30m"},
+//	 "sources": {"rumah123": {"max_pages": 500, "max_articles": 200, "stop_after_seen": 50}}}
+//
+// Field sumber yang kosong mewarisi default; 0 = tanpa batas.
+const CRAWL_BUDGETS_FILE = "crawl_budgets.json"
+
+type CrawlBudget struct {
+	MaxPages    int    `json:"max_pages,omitempty"`
+	MaxArticles int    `json:"max_articles,omitempty"`
+	MaxRuntime  string `json:"max_runtime,omitempty"` // durasi Go, mis. "30m"
+	// Berhenti setelah N artikel berturut-turut yang sudah ada di hasil crawl sebelumnya
+	StopAfterSeen int `json:"stop_after_seen,omitempty"`
+}
+
+type CrawlBudgets struct {
+	Default CrawlBudget            `json:"default"`
+	Sources map[string]CrawlBudget `json:"sources"`
+}
+
+func loadCrawlBudgets(path string) (*CrawlBudgets, error) {
+	budgets := &CrawlBudgets{}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return budgets, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, budgets); err != nil {
+		return nil, err
+	}
+	for name, budget := range budgets.Sources {
+		if _, err := budget.runtime(); err != nil {
+			return nil, fmt.Errorf("source %s: %v", name, err)
+		}
+	}
+	if _, err := budgets.Default.runtime(); err != nil {
+		return nil, fmt.Errorf("default: %v", err)
+	}
+	return budgets, nil
+}
+
+func (b *CrawlBudgets) For(source string) CrawlBudget {
+	budget := b.Default
+	override, exists := b.Sources[source]
+	if !exists {
+		return budget
+	}
+	if override.MaxPages != 0 {
+		budget.MaxPages = override.MaxPages
+	}
+	if override.MaxArticles != 0 {
+		budget.MaxArticles = override.MaxArticles
+	}
+	if override.MaxRuntime != "" {
+		budget.MaxRuntime = override.MaxRuntime
+	}
+	if override.StopAfterSeen != 0 {
+		budget.StopAfterSeen = override.StopAfterSeen
+	}
+	return budget
+}
+
+func (b CrawlBudget) runtime() (time.Duration, error) {
+	if b.MaxRuntime == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(b.MaxRuntime)
+	if err != nil {
+		return 0, fmt.Errorf("invalid max_runtime %q", b.MaxRuntime)
+	}
+	return d, nil
+}
+
+// Alasan crawl berhenti sebelum semua link habis
+const (
+	StopMaxPages    = "max_pages"
+	StopMaxArticles = "max_articles"
+	StopMaxRuntime  = "max_runtime"
+	StopSeen        = "stop_after_seen"
+)
+
+// Penghitung budget selama satu crawl. Callback colly bisa jalan paralel,
+// jadi semua akses lewat mutex.
+type budgetTracker struct {
+	budget   CrawlBudget
+	deadline time.Time
+	seen     map[string]bool // URL artikel dari crawl sebelumnya
+
+	mu        sync.Mutex
+	pages     int
+	articles  int
+	seenRun   int
+	stoppedBy string
+}
+
+func newBudgetTracker(budget CrawlBudget, seen map[string]bool) *budgetTracker {
+	t := &budgetTracker{budget: budget, seen: seen}
+	if d, _ := budget.runtime(); d > 0 {
+		t.deadline = time.Now().Add(d)
+	}
+	return t
+}
+
+// Dipanggil sebelum setiap request; false = request dibatalkan
+func (t *budgetTracker) allowPage() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stoppedBy != "" {
+		return false
+	}
+	if !t.deadline.IsZero() && time.Now().After(t.deadline) {
+		t.stop(StopMaxRuntime)
+		return false
+	}
+	if t.budget.MaxPages > 0 && t.pages >= t.budget.MaxPages {
+		t.stop(StopMaxPages)
+		return false
+	}
+	t.pages++
+	return true
+}
+
+// Dipanggil untuk setiap artikel yang berhasil diekstrak; false = budget
+// artikel sudah habis dan artikel dibuang
+func (t *budgetTracker) allowArticle(url string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.budget.MaxArticles > 0 && t.articles >= t.budget.MaxArticles {
+		return false
+	}
+	t.articles++
+	if t.budget.MaxArticles > 0 && t.articles >= t.budget.MaxArticles {
+		t.stop(StopMaxArticles)
+	}
+
+	if t.seen[url] {
+		t.seenRun++
+		if t.budget.StopAfterSeen > 0 && t.seenRun >= t.budget.StopAfterSeen {
+			t.stop(StopSeen)
+		}
+	} else {
+		t.seenRun = 0
+	}
+	return true
+}
+
+// Alasan pertama yang menghentikan crawl dipertahankan
+func (t *budgetTracker) stop(reason string) {
+	if t.stoppedBy == "" {
+		t.stoppedBy = reason
+	}
+}
+
+func (t *budgetTracker) StoppedBy() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stoppedBy
+}
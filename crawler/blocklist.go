@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Blocklist dibagi dengan search engine (lihat ../blocklist.json)
+const blocklistFile = "blocklist.json"
+
+type Blocklist struct {
+	URLs        []string `json:"urls"`
+	URLPatterns []string `json:"url_patterns"`
+	Domains     []string `json:"domains"`
+
+	patterns []*regexp.Regexp
+}
+
+func loadBlocklist(path string) *Blocklist {
+	bl := &Blocklist{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return bl
+	}
+	if err := json.Unmarshal(data, bl); err != nil {
+		log.Printf("[warn] Invalid blocklist %s: %s", path, err)
+		return bl
+	}
+
+	for _, p := range bl.URLPatterns {
+		if re, err := regexp.Compile(p); err == nil {
+			bl.patterns = append(bl.patterns, re)
+		}
+	}
+
+	return bl
+}
+
+func (bl *Blocklist) IsBlocked(rawURL string) bool {
+	for _, u := range bl.URLs {
+		if u == rawURL {
+			return true
+		}
+	}
+
+	if u, err := url.Parse(rawURL); err == nil {
+		host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+		for _, domain := range bl.Domains {
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		}
+	}
+
+	for _, re := range bl.patterns {
+		if re.MatchString(rawURL) {
+			return true
+		}
+	}
+
+	return false
+}
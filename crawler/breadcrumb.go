@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+)
+
+// Ekstraksi breadcrumb dibagi dengan search engine (salinan ../breadcrumb.go,
+// harus tetap sama): JSON-LD BreadcrumbList dulu, lalu selector CSS sumber.
+// Pemetaan ke kategori terpadu dilakukan search engine saat index dibangun.
+
+var breadcrumbHomeNames = map[string]bool{
+	"home": true, "beranda": true, "homepage": true, "halaman utama": true,
+}
+
+// Pemisah yang ikut terambil kalau selector menangkap elemen di antara link
+var breadcrumbSeparators = map[string]bool{
+	">": true, "»": true, "›": true, "/": true, "|": true,
+}
+
+func extractBreadcrumb(e *colly.HTMLElement, selector, title string) []string {
+	// Breadcrumb biasanya di luar container artikel
+	root := e.DOM.ParentsFiltered("html")
+
+	var trail []string
+	root.Find(`script[type="application/ld+json"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		trail = jsonLDBreadcrumb([]byte(s.Text()))
+		return len(trail) == 0
+	})
+	if len(trail) == 0 && selector != "" {
+		root.Find(selector).Each(func(_ int, s *goquery.Selection) {
+			trail = append(trail, s.Text())
+		})
+	}
+	return cleanBreadcrumb(trail, title)
+}
+
+// Nama item BreadcrumbList dari satu blok JSON-LD (objek, array, atau @graph)
+func jsonLDBreadcrumb(data []byte) []string {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+	list := findBreadcrumbList(doc)
+	if list == nil {
+		return nil
+	}
+
+	elements, _ := list["itemListElement"].([]interface{})
+	type crumb struct {
+		position float64
+		name     string
+	}
+	crumbs := make([]crumb, 0, len(elements))
+	for i, element := range elements {
+		item, ok := element.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		position, ok := item["position"].(float64)
+		if !ok {
+			position = float64(i + 1)
+		}
+		name, _ := item["name"].(string)
+		if name == "" {
+			// Yoast lama: {"item": {"@id": ..., "name": ...}}
+			if inner, ok := item["item"].(map[string]interface{}); ok {
+				name, _ = inner["name"].(string)
+			}
+		}
+		crumbs = append(crumbs, crumb{position, name})
+	}
+	sort.SliceStable(crumbs, func(i, j int) bool { return crumbs[i].position < crumbs[j].position })
+
+	trail := make([]string, len(crumbs))
+	for i, c := range crumbs {
+		trail[i] = c.name
+	}
+	return trail
+}
+
+func findBreadcrumbList(node interface{}) map[string]interface{} {
+	switch v := node.(type) {
+	case []interface{}:
+		for _, child := range v {
+			if list := findBreadcrumbList(child); list != nil {
+				return list
+			}
+		}
+	case map[string]interface{}:
+		if hasJSONLDType(v["@type"], "BreadcrumbList") {
+			return v
+		}
+		if graph, ok := v["@graph"]; ok {
+			return findBreadcrumbList(graph)
+		}
+	}
+	return nil
+}
+
+// @type bisa string atau array string
+func hasJSONLDType(value interface{}, want string) bool {
+	switch v := value.(type) {
+	case string:
+		return v == want
+	case []interface{}:
+		for _, t := range v {
+			if s, ok := t.(string); ok && s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func cleanBreadcrumb(trail []string, title string) []string {
+	var segments []string
+	for _, segment := range trail {
+		segment = strings.Join(strings.Fields(segment), " ")
+		if segment == "" || breadcrumbSeparators[segment] {
+			continue
+		}
+		if len(segments) > 0 && strings.EqualFold(segments[len(segments)-1], segment) {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+
+	if len(segments) > 0 && breadcrumbHomeNames[strings.ToLower(segments[0])] {
+		segments = segments[1:]
+	}
+	if len(segments) > 0 && title != "" && strings.EqualFold(segments[len(segments)-1], strings.TrimSpace(title)) {
+		segments = segments[:len(segments)-1]
+	}
+	return segments
+}
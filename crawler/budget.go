@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Budget crawl dibagi dengan search engine (lihat ../crawl_budgets.json dan ../crawlbudget.go)
+const budgetsFile = "crawl_budgets.json"
+
+type CrawlBudget struct {
+	MaxPages      int    `json:"max_pages,omitempty"`
+	MaxArticles   int    `json:"max_articles,omitempty"`
+	MaxRuntime    string `json:"max_runtime,omitempty"`
+	StopAfterSeen int    `json:"stop_after_seen,omitempty"`
+}
+
+// Budget sumber ini; field yang kosong mewarisi "default", 0 = tanpa batas
+func loadBudget(path, source string) CrawlBudget {
+	var config struct {
+		Default CrawlBudget            `json:"default"`
+		Sources map[string]CrawlBudget `json:"sources"`
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return CrawlBudget{}
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		log.Printf("[warn] Invalid crawl budgets %s: %s", path, err)
+		return CrawlBudget{}
+	}
+
+	budget := config.Default
+	if override, exists := config.Sources[source]; exists {
+		if override.MaxPages != 0 {
+			budget.MaxPages = override.MaxPages
+		}
+		if override.MaxArticles != 0 {
+			budget.MaxArticles = override.MaxArticles
+		}
+		if override.MaxRuntime != "" {
+			budget.MaxRuntime = override.MaxRuntime
+		}
+		if override.StopAfterSeen != 0 {
+			budget.StopAfterSeen = override.StopAfterSeen
+		}
+	}
+	return budget
+}
+
+// Penghitung budget selama crawl (callback colly jalan paralel)
+type budgetTracker struct {
+	budget   CrawlBudget
+	deadline time.Time
+	seen     map[string]bool
+
+	mu        sync.Mutex
+	pages     int
+	articles  int
+	seenRun   int
+	stoppedBy string
+}
+
+func newBudgetTracker(budget CrawlBudget, seen map[string]bool) *budgetTracker {
+	t := &budgetTracker{budget: budget, seen: seen}
+	if budget.MaxRuntime != "" {
+		d, err := time.ParseDuration(budget.MaxRuntime)
+		if err != nil {
+			log.Printf("[warn] Invalid max_runtime %q, ignored", budget.MaxRuntime)
+		} else if d > 0 {
+			t.deadline = time.Now().Add(d)
+		}
+	}
+	return t
+}
+
+// false = request dibatalkan karena budget habis
+func (t *budgetTracker) allowPage() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stoppedBy != "" {
+		return false
+	}
+	if !t.deadline.IsZero() && time.Now().After(t.deadline) {
+		t.stop("max_runtime")
+		return false
+	}
+	if t.budget.MaxPages > 0 && t.pages >= t.budget.MaxPages {
+		t.stop("max_pages")
+		return false
+	}
+	t.pages++
+	return true
+}
+
+// false = budget artikel sudah habis, artikel dibuang
+func (t *budgetTracker) allowArticle(url string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.budget.MaxArticles > 0 && t.articles >= t.budget.MaxArticles {
+		return false
+	}
+	t.articles++
+	if t.budget.MaxArticles > 0 && t.articles >= t.budget.MaxArticles {
+		t.stop("max_articles")
+	}
+
+	if t.seen[url] {
+		t.seenRun++
+		if t.budget.StopAfterSeen > 0 && t.seenRun >= t.budget.StopAfterSeen {
+			t.stop("stop_after_seen")
+		}
+	} else {
+		t.seenRun = 0
+	}
+	return true
+}
+
+func (t *budgetTracker) stop(reason string) {
+	if t.stoppedBy == "" {
+		t.stoppedBy = reason
+		log.Printf("[budget] Stopping crawl: %s reached", reason)
+	}
+}
+
+func (t *budgetTracker) StoppedBy() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stoppedBy
+}
+
+// Hasil crawl sebelumnya: URL-nya dipakai untuk stop_after_seen
+func loadPreviousArticles(path string) []Article {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var articles []Article
+	if err := json.Unmarshal(data, &articles); err != nil {
+		return nil
+	}
+	return articles
+}
+
+func articleURLs(articles []Article) map[string]bool {
+	urls := make(map[string]bool, len(articles))
+	for _, article := range articles {
+		urls[article.URL] = true
+	}
+	return urls
+}
+
+// Output lama dipindah ke <output>.previous.json sebelum ditimpa, untuk
+// searchctl corpus diff (lihat ../corpusdiff.go)
+func keepPreviousCrawl(path string) {
+	previous := strings.TrimSuffix(path, ".json") + ".previous.json"
+	if err := os.Rename(path, previous); err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to keep previous crawl output: %v", err)
+	}
+}
+
+// Crawl yang dihentikan budget hanya sebagian, jadi artikel lama yang tidak
+// ter-crawl ulang dipertahankan supaya corpus tidak menyusut
+func keepPreviousArticles(articles, previous []Article) []Article {
+	crawled := articleURLs(articles)
+	for _, article := range previous {
+		if !crawled[article.URL] {
+			articles = append(articles, article)
+		}
+	}
+	return articles
+}
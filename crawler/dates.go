@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Parser tanggal dibagi dengan search engine (salinan ../dates.go, harus
+// tetap sama): "January 2, 2006", bulan Indonesia, ISO 8601 di meta tag.
+// Hasil selalu UTC; tanggal tanpa zona waktu dianggap WIB.
+
+var defaultDateLocation = time.FixedZone("WIB", 7*60*60)
+
+// Zona waktu Indonesia yang tidak dikenal time.Parse
+var indonesianZones = map[string]*time.Location{
+	"WIB":  defaultDateLocation,
+	"WITA": time.FixedZone("WITA", 8*60*60),
+	"WIT":  time.FixedZone("WIT", 9*60*60),
+	"UTC":  time.UTC,
+	"GMT":  time.UTC,
+}
+
+// Nama bulan (Indonesia dan Inggris, lengkap dan singkatan) ke nama Inggris lengkap
+var monthNames = map[string]string{
+	"januari": "January", "january": "January", "jan": "January",
+	"februari": "February", "pebruari": "February", "february": "February", "feb": "February",
+	"maret": "March", "march": "March", "mar": "March",
+	"april": "April", "apr": "April",
+	"mei": "May", "may": "May",
+	"juni": "June", "june": "June", "jun": "June",
+	"juli": "July", "july": "July", "jul": "July",
+	"agustus": "August", "august": "August", "agu": "August", "agt": "August", "ags": "August", "aug": "August",
+	"september": "September", "sep": "September", "sept": "September",
+	"oktober": "October", "october": "October", "okt": "October", "oct": "October",
+	"november": "November", "nov": "November", "nop": "November",
+	"desember": "December", "december": "December", "des": "December", "dec": "December",
+}
+
+// Kata yang dibuang sebelum parsing: nama hari dan kata penghubung waktu
+var dateNoiseWords = map[string]bool{
+	"senin": true, "selasa": true, "rabu": true, "kamis": true, "jumat": true, "jum'at": true, "sabtu": true, "minggu": true,
+	"monday": true, "tuesday": true, "wednesday": true, "thursday": true, "friday": true, "saturday": true, "sunday": true,
+	"mon": true, "tue": true, "wed": true, "thu": true, "fri": true, "sat": true, "sun": true,
+	"pukul": true, "jam": true, "at": true, "diterbitkan": true, "published": true, "-": true,
+}
+
+// Format mesin (meta tag, atribut datetime), dicoba apa adanya
+var isoDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// Format teks setelah dinormalisasi (bulan dalam bahasa Inggris, tanpa koma,
+// jam dengan titik dua). Tanggal angka selalu hari/bulan/tahun.
+var textDateLayouts = []string{
+	"2 January 2006 15:04:05",
+	"2 January 2006 15:04",
+	"2 January 2006",
+	"January 2 2006 15:04:05",
+	"January 2 2006 15:04",
+	"January 2 2006 3:04 PM",
+	"January 2 2006",
+	"2/1/2006 15:04:05",
+	"2/1/2006 15:04",
+	"2/1/2006",
+	"2-1-2006 15:04",
+	"2-1-2006",
+}
+
+var (
+	dateSeparatorPattern = regexp.MustCompile(`[,|]+`)
+	dotTimePattern       = regexp.MustCompile(`^(\d{1,2})\.(\d{2})$`)
+)
+
+var errUnknownDateFormat = errors.New("unrecognized date format")
+
+// Parse tanggal artikel dalam format apa pun yang dikenal. loc dipakai untuk
+// tanggal tanpa zona waktu (nil = WIB). Hasil selalu UTC.
+func parseArticleDate(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, errUnknownDateFormat
+	}
+	if loc == nil {
+		loc = defaultDateLocation
+	}
+
+	for _, layout := range isoDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	text, zone := normalizeDateText(value)
+	if zone != nil {
+		loc = zone
+	}
+	for _, layout := range textDateLayouts {
+		if t, err := time.ParseInLocation(layout, text, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errUnknownDateFormat
+}
+
+// Ubah teks tanggal ke bentuk yang dikenali textDateLayouts dan ambil zona
+// waktunya (WIB/WITA/WIT) kalau ada
+func normalizeDateText(value string) (string, *time.Location) {
+	var zone *time.Location
+	fields := strings.Fields(dateSeparatorPattern.ReplaceAllString(value, " "))
+	words := fields[:0]
+
+	for _, field := range fields {
+		lower := strings.ToLower(strings.TrimSuffix(field, "."))
+		if dateNoiseWords[lower] {
+			continue
+		}
+		if loc, ok := indonesianZones[strings.ToUpper(lower)]; ok {
+			zone = loc
+			continue
+		}
+		if month, ok := monthNames[lower]; ok {
+			words = append(words, month)
+			continue
+		}
+		if lower == "am" || lower == "pm" {
+			words = append(words, strings.ToUpper(lower))
+			continue
+		}
+		// Jam gaya Indonesia: 10.30 -> 10:30
+		words = append(words, dotTimePattern.ReplaceAllString(field, "$1:$2"))
+	}
+
+	return strings.Join(words, " "), zone
+}
+
+// Zona waktu sumber: kosong = WIB, singkatan Indonesia, atau nama IANA
+func dateLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return defaultDateLocation, nil
+	}
+	if loc, ok := indonesianZones[strings.ToUpper(name)]; ok {
+		return loc, nil
+	}
+	return time.LoadLocation(name)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Ekstraksi link dibagi dengan search engine (salinan ../linkgraph.go, harus
+// tetap sama): link keluar artikel menjadi edge link graph yang dipakai untuk
+// authority saat ranking.
+const LINK_MAX_PER_DOC = 200
+
+// Link keluar di container artikel: absolut, tanpa fragment, unik, hanya
+// http(s). Link nofollow/sponsored bukan rekomendasi dan dilewati.
+func extractLinks(container *goquery.Selection, base *url.URL) []string {
+	seen := make(map[string]bool)
+	var links []string
+	container.Find("a[href]").EachWithBreak(func(_ int, a *goquery.Selection) bool {
+		rel := strings.ToLower(a.AttrOr("rel", ""))
+		if strings.Contains(rel, "nofollow") || strings.Contains(rel, "sponsored") {
+			return true
+		}
+		ref, err := url.Parse(strings.TrimSpace(a.AttrOr("href", "")))
+		if err != nil {
+			return true
+		}
+		if base != nil {
+			ref = base.ResolveReference(ref)
+		}
+		if (ref.Scheme != "http" && ref.Scheme != "https") || ref.Host == "" {
+			return true
+		}
+		ref.Fragment, ref.RawFragment = "", ""
+		if link := ref.String(); !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+		return len(links) < LINK_MAX_PER_DOC
+	})
+	return links
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// Satu crawler untuk semua sumber. Selector, domain, kedalaman, rate limit
+// dan file output setiap sumber ada di crawlers.json (sumber bawaan) atau
+// sources.json (hasil onboarding), jadi sumber baru tidak perlu kode Go.
+// Dijalankan dari root repo:
+//
+//	go run ./crawler                 # semua sumber di crawlers.json, berurutan
+//	go run ./crawler rumah123        # sumber tertentu, termasuk dari sources.json
+//	go run ./crawler -list
+
+// Article represents the structure of our scraped data
+type Article struct {
+	Title   string    `json:"title"`
+	Content string    `json:"content"`
+	URL     string    `json:"url"`
+	Date    time.Time `json:"date"`
+	Author  string    `json:"author,omitempty"`
+	// "fallback" kalau konten diambil dari selector generik
+	Extraction string `json:"extraction,omitempty"`
+	// "Berita Properti", "KPR": kategori di situs, dipetakan saat index dibangun
+	Breadcrumb []string `json:"breadcrumb,omitempty"`
+	// Tabel di halaman artikel per baris, lihat tables.go
+	Tables []ArticleTable `json:"tables,omitempty"`
+	// Link keluar di container artikel untuk link graph, lihat links.go
+	Links []string `json:"links,omitempty"`
+	// Versi crawler dan konfigurasi sumber yang menghasilkan artikel ini;
+	// server melengkapinya saat ingest (lihat ../provenance.go)
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// Naikkan setiap kali cara crawler mengekstrak artikel berubah, supaya
+// dokumen dari versi lama bisa dicari dan dihapus per provenance
+const crawlerVersion = "4"
+
+type Provenance struct {
+	Method           string `json:"method"`
+	CrawlerVersion   string `json:"crawler_version"`
+	Source           string `json:"source"`
+	SourceConfigHash string `json:"source_config_hash"`
+}
+
+func main() {
+	configFile := flag.String("config", crawlersFile, "file sumber bawaan")
+	list := flag.Bool("list", false, "tampilkan sumber yang tersedia")
+	flag.Parse()
+
+	builtin, err := loadSources(*configFile)
+	if err != nil {
+		log.Fatal("Failed to load sources: ", err)
+	}
+	saved, err := loadSources(sourcesFile)
+	if err != nil {
+		log.Fatal("Failed to load sources: ", err)
+	}
+	available := make(map[string]CrawlSource)
+	for _, source := range append(saved, builtin...) {
+		available[source.Name] = source
+	}
+
+	if *list {
+		for _, source := range append(builtin, saved...) {
+			fmt.Printf("%-20s %-30s -> %s\n", source.Name, source.StartURL, source.outputPath())
+		}
+		return
+	}
+
+	// Tanpa argumen: semua sumber bawaan
+	selected := builtin
+	if flag.NArg() > 0 {
+		selected = nil
+		for _, name := range flag.Args() {
+			source, exists := available[name]
+			if !exists {
+				log.Fatalf("Unknown source %q, see go run ./crawler -list", name)
+			}
+			selected = append(selected, source)
+		}
+	}
+
+	failed := 0
+	for _, source := range selected {
+		if err := crawl(source); err != nil {
+			log.Printf("Crawl of %s failed: %v", source.Name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func crawl(source CrawlSource) error {
+	output := source.outputPath()
+	delay, err := source.crawlDelay()
+	if err != nil {
+		return err
+	}
+
+	// Initialize collector
+	c := colly.NewCollector(
+		colly.AllowedDomains(source.domains()...),
+		colly.MaxDepth(source.MaxDepth),
+		colly.Async(true),
+	)
+
+	// Create a slice to store all articles
+	var articles []Article
+
+	provenance := &Provenance{
+		Method:           "crawler",
+		CrawlerVersion:   crawlerVersion,
+		Source:           source.Name,
+		SourceConfigHash: source.configHash(),
+	}
+
+	// Metrics dan progress crawl untuk dashboard admin (callback colly jalan paralel)
+	progress := newProgressReporter(source.Name)
+
+	// Load blocklist so blocked domains/URLs are never fetched
+	blocklist := loadBlocklist(blocklistFile)
+
+	// Budget crawl (max halaman/artikel/durasi, berhenti setelah N artikel lama)
+	previous := loadPreviousArticles(output)
+	budget := newBudgetTracker(loadBudget(budgetsFile, source.Name), articleURLs(previous))
+
+	// Riwayat URL untuk crawl inkremental (CRAWL_FULL=1 untuk crawl ulang semua)
+	history := loadVisitHistory(filepath.Join(filepath.Dir(output), visitedFile))
+
+	// Set up rate limiting
+	c.Limit(&colly.LimitRule{
+		DomainGlob:  "*",
+		RandomDelay: delay,
+		Parallelism: source.crawlParallelism(),
+	})
+
+	// Find and visit all links within the source's domains
+	c.OnHTML("a[href]", func(e *colly.HTMLElement) {
+		link := e.Request.AbsoluteURL(e.Attr("href"))
+		if source.follows(link) {
+			progress.Link(link)
+			e.Request.Visit(link)
+		}
+	})
+
+	// Extract article data
+	c.OnHTML(source.articleSelector(), func(e *colly.HTMLElement) {
+		article := Article{URL: e.Request.URL.String(), Provenance: provenance}
+
+		article.Title = extractField(e, source.Title, "")
+
+		// Extract and concatenate content from all matching tags
+		var contentParts []string
+		e.ForEach(source.Content, func(_ int, el *colly.HTMLElement) {
+			if text := strings.TrimSpace(el.Text); text != "" {
+				contentParts = append(contentParts, text)
+			}
+		})
+		// Fallback to every p tag when the site layout changed
+		if len(contentParts) == 0 {
+			e.ForEach("p", func(_ int, el *colly.HTMLElement) {
+				if text := strings.TrimSpace(el.Text); text != "" {
+					contentParts = append(contentParts, text)
+				}
+			})
+			article.Extraction = "fallback"
+		}
+		article.Content = strings.Join(contentParts, "\n")
+		article.Tables = extractTables(e.DOM)
+		article.Links = extractLinks(e.DOM, e.Request.URL)
+
+		if source.Date != "" {
+			if date, err := source.parseDate(extractField(e, source.Date, source.DateAttr)); err == nil {
+				article.Date = date
+			}
+		}
+		if source.Author != "" {
+			article.Author = extractField(e, source.Author, source.AuthorAttr)
+		}
+
+		// Extract category breadcrumb (JSON-LD, fallback to the source's breadcrumb selector)
+		article.Breadcrumb = extractBreadcrumb(e, source.Breadcrumb, article.Title)
+
+		if article.Title != "" && article.Content != "" && budget.allowArticle(article.URL) {
+			progress.Article(article.URL, article.Title)
+			history.Article(article)
+			articles = append(articles, article)
+		}
+	})
+
+	// Handle errors
+	c.OnError(func(r *colly.Response, err error) {
+		if r.StatusCode == http.StatusNotModified {
+			progress.Response(r.Request.URL.String())
+			history.NotModified(r.Request.URL.String())
+			return
+		}
+		progress.Error(r.Request.URL.String(), err)
+	})
+
+	c.OnResponse(func(r *colly.Response) {
+		progress.Response(r.Request.URL.String())
+		history.Response(r)
+	})
+
+	// Before making a request
+	c.OnRequest(func(r *colly.Request) {
+		if blocklist.IsBlocked(r.URL.String()) {
+			progress.Blocked(r.URL.String())
+			r.Abort()
+			return
+		}
+		// Artikel yang baru di-crawl tidak diambil lagi dan tidak memakai budget
+		if history.Skip(r) {
+			r.Abort()
+			return
+		}
+		if !budget.allowPage() {
+			r.Abort()
+			return
+		}
+		progress.Request(r.URL.String())
+	})
+
+	// Start scraping
+	log.Printf("Starting crawl of %s", source.Name)
+	startTime := time.Now()
+	if err := c.Visit(source.StartURL); err != nil {
+		progress.Finish("")
+		return fmt.Errorf("failed to start scraping: %w", err)
+	}
+
+	// Wait for all scraping jobs to complete
+	c.Wait()
+
+	stoppedBy := budget.StoppedBy()
+	crawled := articles
+	if !history.Full() {
+		// Crawl inkremental hanya mengambil halaman baru atau yang sudah lama,
+		// jadi hasilnya digabung ke output sebelumnya
+		articles = keepPreviousArticles(articles, previous)
+		log.Printf("[incremental] %s, kept %d articles from the previous crawl", history.Summary(), len(articles)-len(crawled))
+	} else if stoppedBy != "" {
+		articles = keepPreviousArticles(articles, previous)
+		log.Printf("[budget] Stopped by %s, kept %d articles from the previous crawl", stoppedBy, len(articles)-len(crawled))
+	}
+
+	// Save results to JSON file
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return err
+	}
+	keepPreviousCrawl(output)
+	data, err := json.MarshalIndent(articles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode articles to JSON: %w", err)
+	}
+	if err := os.WriteFile(output, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	if err := history.Save(); err != nil {
+		log.Printf("Failed to save visit history: %v", err)
+	}
+	history.Ingest(source.Name, crawled)
+
+	final := progress.Finish(stoppedBy)
+	duration := time.Since(startTime)
+	log.Printf("Crawl completed in %s: %d articles saved to %s", duration, len(articles), output)
+
+	fallback := 0
+	for _, article := range articles {
+		if article.Extraction == "fallback" {
+			fallback++
+		}
+	}
+	notifyCrawlCompleted(CrawlStats{
+		Source:          source.Name,
+		Output:          output,
+		Articles:        len(articles),
+		Fallback:        fallback,
+		Visited:         final.Requests,
+		Blocked:         final.Blocked,
+		Errors:          final.Errors,
+		DurationSeconds: duration.Seconds(),
+		StoppedBy:       stoppedBy,
+	})
+	return nil
+}
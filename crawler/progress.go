@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress crawl untuk metrics Prometheus dan dashboard admin (lihat
+// ../crawlprogress.go). Snapshot dikirim ke search engine setiap
+// progressInterval kalau ADMIN_TOKEN di-set; alamat server dari
+// SEARCH_ENGINE_URL (default http://localhost:8080).
+const (
+	progressInterval = 2 * time.Second
+	progressRecent   = 20 // event terakhir yang ikut di snapshot
+)
+
+type ProgressEvent struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"` // article, skip, error
+	URL     string    `json:"url,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+type CrawlProgress struct {
+	Source     string    `json:"source"`
+	Status     string    `json:"status"` // running, completed
+	StartedAt  time.Time `json:"started_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Requests   int64     `json:"requests"`
+	Responses  int64     `json:"responses"`
+	Errors     int64     `json:"errors"`
+	Blocked    int64     `json:"blocked"`
+	Articles   int64     `json:"articles"`
+	QueueDepth int64     `json:"queue_depth"` // request yang sudah dijadwalkan tapi belum selesai
+	StoppedBy  string    `json:"stopped_by,omitempty"`
+
+	Recent []ProgressEvent `json:"recent"`
+}
+
+type progressReporter struct {
+	endpoint string
+	token    string
+	client   *http.Client
+
+	mu       sync.Mutex
+	progress CrawlProgress
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newProgressReporter(source string) *progressReporter {
+	p := &progressReporter{
+		endpoint: searchEngineURL() + "/api/admin/crawl/progress",
+		token:    os.Getenv("ADMIN_TOKEN"),
+		client:   &http.Client{Timeout: 5 * time.Second},
+		progress: CrawlProgress{Source: source, Status: "running", StartedAt: time.Now()},
+		done:     make(chan struct{}),
+	}
+
+	if p.token != "" {
+		p.wg.Add(1)
+		go p.loop()
+	}
+	return p
+}
+
+// Alamat search engine dari SEARCH_ENGINE_URL, tanpa "/" di akhir
+func searchEngineURL() string {
+	server := os.Getenv("SEARCH_ENGINE_URL")
+	if server == "" {
+		server = "http://localhost:8080"
+	}
+	return strings.TrimSuffix(server, "/")
+}
+
+func (p *progressReporter) loop() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			p.push(p.Snapshot())
+		}
+	}
+}
+
+// Kunjungan tidak masuk daftar event terakhir supaya artikel dan error tidak tenggelam
+func (p *progressReporter) Request(url string) {
+	log.Printf("[visit] %s", url)
+	p.update(func(pr *CrawlProgress) {
+		pr.Requests++
+		pr.QueueDepth++
+	})
+}
+
+func (p *progressReporter) Response(url string) {
+	p.update(func(pr *CrawlProgress) {
+		pr.Responses++
+		pr.QueueDepth--
+	})
+}
+
+func (p *progressReporter) Error(url string, err error) {
+	p.record(ProgressEvent{Kind: "error", URL: url, Message: err.Error()}, func(pr *CrawlProgress) {
+		pr.Errors++
+		pr.QueueDepth--
+	})
+}
+
+func (p *progressReporter) Blocked(url string) {
+	p.record(ProgressEvent{Kind: "skip", URL: url, Message: "blocklisted"}, func(pr *CrawlProgress) {
+		pr.Blocked++
+	})
+}
+
+func (p *progressReporter) Article(url, title string) {
+	p.record(ProgressEvent{Kind: "article", URL: url, Message: title}, func(pr *CrawlProgress) {
+		pr.Articles++
+	})
+}
+
+func (p *progressReporter) Link(url string) {
+	log.Printf("[link] %s", url)
+}
+
+func (p *progressReporter) update(apply func(*CrawlProgress)) {
+	p.mu.Lock()
+	apply(&p.progress)
+	p.mu.Unlock()
+}
+
+// Catat event ke log dan ke daftar event terakhir
+func (p *progressReporter) record(event ProgressEvent, apply func(*CrawlProgress)) {
+	event.Time = time.Now()
+	if event.Message != "" {
+		log.Printf("[%s] %s %s", event.Kind, event.URL, event.Message)
+	} else {
+		log.Printf("[%s] %s", event.Kind, event.URL)
+	}
+
+	p.mu.Lock()
+	apply(&p.progress)
+	p.progress.Recent = append(p.progress.Recent, event)
+	if len(p.progress.Recent) > progressRecent {
+		p.progress.Recent = p.progress.Recent[len(p.progress.Recent)-progressRecent:]
+	}
+	p.mu.Unlock()
+}
+
+func (p *progressReporter) Snapshot() CrawlProgress {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snapshot := p.progress
+	snapshot.UpdatedAt = time.Now()
+	snapshot.Recent = append([]ProgressEvent(nil), p.progress.Recent...)
+	return snapshot
+}
+
+// Hentikan pengiriman berkala lalu kirim snapshot terakhir
+func (p *progressReporter) Finish(stoppedBy string) CrawlProgress {
+	close(p.done)
+	p.wg.Wait()
+
+	p.update(func(pr *CrawlProgress) {
+		pr.Status = "completed"
+		pr.StoppedBy = stoppedBy
+		pr.QueueDepth = 0
+	})
+	snapshot := p.Snapshot()
+	if p.token != "" {
+		p.push(snapshot)
+	}
+	return snapshot
+}
+
+// Server yang sedang mati tidak menghentikan crawl, cukup dicatat
+func (p *progressReporter) push(snapshot CrawlProgress) {
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Admin-Token", p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		log.Printf("[warn] progress report failed: %v", err)
+		return
+	}
+	resp.Body.Close()
+}
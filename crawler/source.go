@@ -0,0 +1,173 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// Konfigurasi sumber dibagi dengan search engine (lihat ../crawlers.json dan
+// ../crawlpreview.go, struct harus tetap sama). Sumber hasil onboarding ada
+// di ../sources.json dengan format yang sama.
+const (
+	crawlersFile = "crawlers.json"
+	sourcesFile  = "sources.json"
+
+	defaultDelay       = 2 * time.Second
+	defaultParallelism = 2
+)
+
+type CrawlSource struct {
+	Name           string   `json:"name"`
+	StartURL       string   `json:"start_url"`
+	Domain         string   `json:"domain"`
+	AllowedDomains []string `json:"allowed_domains,omitempty"` // domain lain yang link-nya ikut diikuti
+	MaxDepth       int      `json:"max_depth"`
+	Article        string   `json:"article,omitempty"` // container artikel, default "article"
+	Title          string   `json:"title"`
+	Content        string   `json:"content"`
+	Date           string   `json:"date,omitempty"`        // kosong = sumber tidak punya tanggal
+	DateAttr       string   `json:"date_attr,omitempty"`   // baca atribut (mis. "content" di meta tag), bukan teks
+	DateLayout     string   `json:"date_layout,omitempty"` // opsional, kosong = parseArticleDate
+	Timezone       string   `json:"timezone,omitempty"`    // untuk tanggal tanpa zona waktu, default WIB
+	Author         string   `json:"author,omitempty"`
+	AuthorAttr     string   `json:"author_attr,omitempty"`
+	Breadcrumb     string   `json:"breadcrumb,omitempty"` // fallback kalau halaman tidak punya JSON-LD BreadcrumbList
+
+	Output      string `json:"output,omitempty"`      // relatif ke root repo, default <name>/articles.json
+	Delay       string `json:"delay,omitempty"`       // jeda acak antar request, default 2s
+	Parallelism int    `json:"parallelism,omitempty"` // request bersamaan per domain, default 2
+}
+
+// Daftar sumber di file, urutan file dipertahankan. File yang tidak ada
+// dianggap kosong.
+func loadSources(path string) ([]CrawlSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var sources []CrawlSource
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, source := range sources {
+		if err := source.validate(); err != nil {
+			return nil, fmt.Errorf("%s: source %q: %w", path, source.Name, err)
+		}
+	}
+	return sources, nil
+}
+
+func (s CrawlSource) validate() error {
+	if s.Name == "" {
+		return errors.New("name is required")
+	}
+	if u, err := url.Parse(s.StartURL); err != nil || u.Host == "" {
+		return errors.New("start_url must be an absolute URL")
+	}
+	if s.Domain == "" || s.Title == "" || s.Content == "" {
+		return errors.New("domain, title and content selectors are required")
+	}
+	if _, err := dateLocation(s.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", s.Timezone)
+	}
+	if delay, err := s.crawlDelay(); err != nil || delay < 0 {
+		return fmt.Errorf("invalid delay %q", s.Delay)
+	}
+	return nil
+}
+
+// Hash konfigurasi sumber, sama dengan sourceConfigHash di ../provenance.go
+func (s CrawlSource) configHash() string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+func (s CrawlSource) articleSelector() string {
+	if s.Article == "" {
+		return "article"
+	}
+	return s.Article
+}
+
+func (s CrawlSource) domains() []string {
+	return append([]string{s.Domain}, s.AllowedDomains...)
+}
+
+// Link diikuti kalau host-nya salah satu domain sumber
+func (s CrawlSource) follows(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	for _, domain := range s.domains() {
+		if strings.EqualFold(u.Hostname(), domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s CrawlSource) outputPath() string {
+	if s.Output == "" {
+		return filepath.Join(s.Name, "articles.json")
+	}
+	return s.Output
+}
+
+func (s CrawlSource) crawlDelay() (time.Duration, error) {
+	if s.Delay == "" {
+		return defaultDelay, nil
+	}
+	return time.ParseDuration(s.Delay)
+}
+
+func (s CrawlSource) crawlParallelism() int {
+	if s.Parallelism <= 0 {
+		return defaultParallelism
+	}
+	return s.Parallelism
+}
+
+// Tanggal dari halaman sumber dalam UTC. Layout sumber dicoba dulu, lalu
+// semua format yang dikenal parseArticleDate.
+func (s CrawlSource) parseDate(value string) (time.Time, error) {
+	loc, err := dateLocation(s.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if s.DateLayout != "" {
+		if t, err := time.ParseInLocation(s.DateLayout, strings.TrimSpace(value), loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return parseArticleDate(value, loc)
+}
+
+// Teks elemen, atau nilai atributnya kalau attr di-set
+func extractField(e *colly.HTMLElement, selector, attr string) string {
+	// Meta tag ada di <head>, di luar container artikel, jadi dicari dari root dokumen
+	if strings.HasPrefix(selector, "meta") {
+		return strings.TrimSpace(e.DOM.ParentsFiltered("html").Find(selector).First().AttrOr(attr, ""))
+	}
+	if attr != "" {
+		return strings.TrimSpace(e.ChildAttr(selector, attr))
+	}
+	return strings.TrimSpace(e.ChildText(selector))
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Ekstraksi tabel dibagi dengan search engine (salinan ../tables.go, harus
+// tetap sama): tabel data di container artikel disimpan per baris, teksnya
+// diindex dan tabel utamanya ditampilkan di halaman dokumen.
+const (
+	TABLE_MAX_ROWS       = 200
+	TABLE_MAX_CELL_BYTES = 500
+)
+
+type ArticleTable struct {
+	Caption string     `json:"caption,omitempty"`
+	Header  []string   `json:"header,omitempty"`
+	Rows    [][]string `json:"rows"`
+}
+
+// Tabel data di container artikel. Tabel layout (berisi tabel lain) dan
+// tabel satu kolom dilewati.
+func extractTables(container *goquery.Selection) []ArticleTable {
+	var tables []ArticleTable
+	container.Find("table").Each(func(_ int, s *goquery.Selection) {
+		if table, ok := tableOf(s); ok {
+			tables = append(tables, table)
+		}
+	})
+	return tables
+}
+
+func tableOf(s *goquery.Selection) (ArticleTable, bool) {
+	if s.Find("table").Length() > 0 {
+		return ArticleTable{}, false
+	}
+	table := ArticleTable{Caption: cellText(s.ChildrenFiltered("caption"))}
+	columns := 0
+	s.Find("tr").EachWithBreak(func(i int, tr *goquery.Selection) bool {
+		var cells []string
+		headerRow := true
+		tr.ChildrenFiltered("th, td").Each(func(_ int, cell *goquery.Selection) {
+			cells = append(cells, cellText(cell))
+			headerRow = headerRow && goquery.NodeName(cell) == "th"
+		})
+		if strings.Join(cells, "") == "" {
+			return true
+		}
+		if len(cells) > columns {
+			columns = len(cells)
+		}
+		// Baris th pertama (atau baris di thead) jadi header
+		if table.Header == nil && len(table.Rows) == 0 && (headerRow || tr.ParentsFiltered("thead").Length() > 0) {
+			table.Header = cells
+			return true
+		}
+		table.Rows = append(table.Rows, cells)
+		return len(table.Rows) < TABLE_MAX_ROWS
+	})
+	return table, columns >= 2 && len(table.Rows) > 0
+}
+
+// Teks sel dengan whitespace dirapikan
+func cellText(s *goquery.Selection) string {
+	text := strings.Join(strings.Fields(s.Text()), " ")
+	if len(text) <= TABLE_MAX_CELL_BYTES {
+		return text
+	}
+	end := TABLE_MAX_CELL_BYTES
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end]
+}
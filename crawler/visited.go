@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// Crawl inkremental. visitedFile menyimpan waktu crawl terakhir, hash konten
+// artikel dan validator HTTP (ETag/Last-Modified) per URL. Pada run
+// berikutnya halaman artikel yang di-crawl kurang dari revisitAfter lalu
+// tidak diambil, halaman artikel yang lebih lama diminta dengan
+// If-None-Match/If-Modified-Since (304 = tidak berubah), dan halaman lain
+// (beranda, kategori) selalu diambil karena link artikel baru muncul di
+// sana. Artikel yang hash-nya sama dengan crawl sebelumnya tidak dihitung
+// berubah. CRAWL_FULL=1 mengabaikan riwayat dan meng-crawl ulang semuanya.
+const (
+	visitedFile     = "visited.json"
+	revisitAfter    = 7 * 24 * time.Hour
+	ingestBatchSize = 100
+)
+
+type VisitRecord struct {
+	LastCrawled time.Time `json:"last_crawled"`
+	// sha256 judul dan konten; kosong untuk halaman yang bukan artikel
+	Hash         string `json:"hash,omitempty"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// Riwayat kunjungan selama crawl (callback colly jalan paralel)
+type visitHistory struct {
+	path string
+	full bool
+
+	mu        sync.Mutex
+	visits    map[string]VisitRecord
+	added     map[string]bool // artikel baru di run ini
+	updated   map[string]bool // artikel yang kontennya berubah
+	skipped   int
+	unchanged int
+}
+
+func loadVisitHistory(path string) *visitHistory {
+	h := &visitHistory{
+		path:    path,
+		full:    os.Getenv("CRAWL_FULL") == "1",
+		visits:  make(map[string]VisitRecord),
+		added:   make(map[string]bool),
+		updated: make(map[string]bool),
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h
+	}
+	if err := json.Unmarshal(data, &h.visits); err != nil {
+		log.Printf("[warn] Invalid visit history %s, crawling everything: %s", path, err)
+		h.visits = make(map[string]VisitRecord)
+	}
+	return h
+}
+
+// CRAWL_FULL=1: output ditulis ulang dari hasil crawl ini saja
+func (h *visitHistory) Full() bool {
+	return h.full
+}
+
+// Siapkan request: true kalau halaman artikel masih baru di-crawl dan tidak
+// perlu diambil, selain itu pasang header conditional request
+func (h *visitHistory) Skip(r *colly.Request) bool {
+	if h.full {
+		return false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	visit, exists := h.visits[r.URL.String()]
+	if !exists || visit.Hash == "" {
+		return false
+	}
+	if time.Since(visit.LastCrawled) < revisitAfter {
+		h.skipped++
+		return true
+	}
+	if visit.ETag != "" {
+		r.Headers.Set("If-None-Match", visit.ETag)
+	}
+	if visit.LastModified != "" {
+		r.Headers.Set("If-Modified-Since", visit.LastModified)
+	}
+	return false
+}
+
+// Catat halaman yang berhasil diambil beserta validator HTTP-nya
+func (h *visitHistory) Response(r *colly.Response) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	url := r.Request.URL.String()
+	visit := h.visits[url]
+	visit.LastCrawled = time.Now()
+	if r.Headers != nil {
+		visit.ETag = r.Headers.Get("ETag")
+		visit.LastModified = r.Headers.Get("Last-Modified")
+	}
+	h.visits[url] = visit
+}
+
+// Server menjawab 304: artikel tidak berubah sejak crawl terakhir
+func (h *visitHistory) NotModified(url string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	visit := h.visits[url]
+	visit.LastCrawled = time.Now()
+	h.visits[url] = visit
+	h.unchanged++
+}
+
+// Catat hash artikel; false kalau kontennya sama dengan crawl sebelumnya
+func (h *visitHistory) Article(article Article) bool {
+	sum := sha256.Sum256([]byte(article.Title + "\n" + article.Content))
+	hash := hex.EncodeToString(sum[:])
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	visit := h.visits[article.URL]
+	previous := visit.Hash
+	visit.Hash = hash
+	visit.LastCrawled = time.Now()
+	h.visits[article.URL] = visit
+
+	switch {
+	case previous == "":
+		h.added[article.URL] = true
+	case previous != hash:
+		h.updated[article.URL] = true
+	default:
+		h.unchanged++
+		return false
+	}
+	return true
+}
+
+func (h *visitHistory) Save() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	data, err := json.MarshalIndent(h.visits, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(h.path, data, 0644)
+}
+
+func (h *visitHistory) Summary() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return fmt.Sprintf("%d new, %d changed, %d unchanged, %d skipped (crawled in the last %.0f days)",
+		len(h.added), len(h.updated), h.unchanged, h.skipped, revisitAfter.Hours()/24)
+}
+
+// Artikel baru dan berubah dikirim ke POST /api/admin/ingest supaya masuk
+// index tanpa menunggu articles.json diganti. Hanya kalau ADMIN_TOKEN di-set.
+func (h *visitHistory) Ingest(source string, articles []Article) {
+	token := os.Getenv("ADMIN_TOKEN")
+	if token == "" {
+		return
+	}
+
+	type ingestRecord struct {
+		Op      string   `json:"op"`
+		URL     string   `json:"url"`
+		Article *Article `json:"article"`
+	}
+	h.mu.Lock()
+	var records []ingestRecord
+	for i := range articles {
+		url := articles[i].URL
+		switch {
+		case h.added[url]:
+			records = append(records, ingestRecord{Op: "add", URL: url, Article: &articles[i]})
+		case h.updated[url]:
+			records = append(records, ingestRecord{Op: "update", URL: url, Article: &articles[i]})
+		}
+	}
+	h.mu.Unlock()
+
+	endpoint := searchEngineURL() + "/api/admin/ingest"
+	client := &http.Client{Timeout: 30 * time.Second}
+	for start := 0; start < len(records); start += ingestBatchSize {
+		end := start + ingestBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		body, err := json.Marshal(records[start:end])
+		if err != nil {
+			return
+		}
+		req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Admin-Token", token)
+		req.Header.Set("X-Admin-Actor", "crawler:"+source)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			log.Printf("[warn] Ingest failed, the articles are still in the output file: %v", err)
+			return
+		}
+		resp.Body.Close()
+		log.Printf("[ingest] %d records -> %d", end-start, resp.StatusCode)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Webhook dibagi dengan search engine (lihat ../webhooks.json dan ../webhooks.go)
+const webhooksFile = "webhooks.json"
+
+type Webhook struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+// Statistik crawl yang dikirim bersama event crawl.completed
+type CrawlStats struct {
+	Source          string  `json:"source"`
+	Output          string  `json:"output"`
+	Articles        int     `json:"articles"`
+	Fallback        int     `json:"fallback_extractions"`
+	Visited         int64   `json:"pages_visited"`
+	Blocked         int64   `json:"blocked"`
+	Errors          int64   `json:"errors"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	StoppedBy       string  `json:"stopped_by,omitempty"` // budget yang menghentikan crawl
+}
+
+// Kirim event crawl.completed ke webhook yang berlangganan. Dijalankan
+// sinkron karena proses crawler langsung selesai setelahnya.
+func notifyCrawlCompleted(stats CrawlStats) {
+	data, err := os.ReadFile(webhooksFile)
+	if err != nil {
+		return
+	}
+	var config struct {
+		Webhooks []Webhook `json:"webhooks"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		log.Printf("[warn] Invalid webhooks %s: %s", webhooksFile, err)
+		return
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"event": "crawl.completed",
+		"time":  time.Now(),
+		"data":  stats,
+	})
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	for _, hook := range config.Webhooks {
+		if !subscribes(hook, "crawl.completed") {
+			continue
+		}
+		req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+		if err != nil {
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Webhook-Event", "crawl.completed")
+		if hook.Secret != "" {
+			mac := hmac.New(sha256.New, []byte(hook.Secret))
+			mac.Write(body)
+			req.Header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			log.Printf("[warn] Webhook %s failed: %s", hook.URL, err)
+			continue
+		}
+		resp.Body.Close()
+		log.Printf("[webhook] %s -> %d", hook.URL, resp.StatusCode)
+	}
+}
+
+func subscribes(hook Webhook, event string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	for _, e := range hook.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
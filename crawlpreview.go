@@ -0,0 +1,371 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// Sumber bawaan, dibaca oleh search engine dan oleh crawler (./crawler);
+// sumber baru dari onboarding disimpan di sources.json (lihat sources.go).
+const CRAWLERS_FILE = "crawlers.json"
+
+const (
+	DEFAULT_CRAWL_DELAY       = 2 * time.Second
+	DEFAULT_CRAWL_PARALLELISM = 2
+)
+
+// Konfigurasi crawl satu sumber. Struct yang sama ada di crawler/source.go
+// dan harus tetap sama.
+type CrawlSource struct {
+	Name           string   `json:"name"`
+	StartURL       string   `json:"start_url"`
+	Domain         string   `json:"domain"`
+	AllowedDomains []string `json:"allowed_domains,omitempty"` // domain lain yang link-nya ikut diikuti
+	MaxDepth       int      `json:"max_depth"`
+	Article        string   `json:"article,omitempty"` // container artikel, default "article"
+	Title          string   `json:"title"`
+	Content        string   `json:"content"`
+	Date           string   `json:"date,omitempty"`        // kosong = sumber tidak punya tanggal
+	DateAttr       string   `json:"date_attr,omitempty"`   // baca atribut (mis. "content" di meta tag), bukan teks
+	DateLayout     string   `json:"date_layout,omitempty"` // opsional, kosong = parseArticleDate
+	Timezone       string   `json:"timezone,omitempty"`    // untuk tanggal tanpa zona waktu, default WIB
+	Author         string   `json:"author,omitempty"`
+	AuthorAttr     string   `json:"author_attr,omitempty"`
+	Breadcrumb     string   `json:"breadcrumb,omitempty"` // fallback kalau halaman tidak punya JSON-LD BreadcrumbList
+
+	Output      string `json:"output,omitempty"`      // relatif ke root repo, default <name>/articles.json
+	Delay       string `json:"delay,omitempty"`       // jeda acak antar request, default 2s
+	Parallelism int    `json:"parallelism,omitempty"` // request bersamaan per domain, default 2
+}
+
+func (s CrawlSource) articleSelector() string {
+	if s.Article == "" {
+		return "article"
+	}
+	return s.Article
+}
+
+func (s CrawlSource) domains() []string {
+	return append([]string{s.Domain}, s.AllowedDomains...)
+}
+
+// Link diikuti kalau host-nya salah satu domain sumber
+func (s CrawlSource) follows(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	for _, domain := range s.domains() {
+		if strings.EqualFold(u.Hostname(), domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s CrawlSource) outputPath() string {
+	if s.Output == "" {
+		return filepath.Join(s.Name, "articles.json")
+	}
+	return s.Output
+}
+
+func (s CrawlSource) crawlDelay() (time.Duration, error) {
+	if s.Delay == "" {
+		return DEFAULT_CRAWL_DELAY, nil
+	}
+	return time.ParseDuration(s.Delay)
+}
+
+func (s CrawlSource) crawlParallelism() int {
+	if s.Parallelism <= 0 {
+		return DEFAULT_CRAWL_PARALLELISM
+	}
+	return s.Parallelism
+}
+
+// Tanggal dari halaman sumber dalam UTC. Layout sumber dicoba dulu, lalu
+// semua format yang dikenal parseArticleDate.
+func (s CrawlSource) parseDate(value string) (time.Time, error) {
+	loc, err := dateLocation(s.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if s.DateLayout != "" {
+		if t, err := time.ParseInLocation(s.DateLayout, strings.TrimSpace(value), loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return parseArticleDate(value, loc)
+}
+
+// Teks elemen, atau nilai atributnya kalau attr di-set
+func extractField(e *colly.HTMLElement, selector, attr string) string {
+	// Meta tag ada di <head>, di luar container artikel, jadi dicari dari root dokumen
+	if strings.HasPrefix(selector, "meta") {
+		return strings.TrimSpace(e.DOM.ParentsFiltered("html").Find(selector).First().AttrOr(attr, ""))
+	}
+	if attr != "" {
+		return strings.TrimSpace(e.ChildAttr(selector, attr))
+	}
+	return strings.TrimSpace(e.ChildText(selector))
+}
+
+var crawlSources = map[string]CrawlSource{}
+
+func loadCrawlSources(path string) (map[string]CrawlSource, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var list []CrawlSource
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	sources := make(map[string]CrawlSource, len(list))
+	for _, source := range list {
+		sources[source.Name] = source
+	}
+	return sources, nil
+}
+
+// Berapa kali setiap selector menemukan isi di halaman artikel
+type selectorHits struct {
+	Pages    int
+	Title    int
+	Content  int
+	Fallback int
+	Date     int
+	Author   int
+	Category int
+	Tables   int
+	Links    int
+}
+
+// searchctl crawl <source> --dry-run [--limit 20]: ambil beberapa halaman,
+// cetak hasil ekstraksi tanpa menulis apa pun, lalu laporkan hit rate selector
+func crawlCommand(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintf(os.Stderr, "usage: searchctl crawl <source> --dry-run [--limit 20]\nsources: %s\n", strings.Join(crawlSourceNames(), ", "))
+		return 2
+	}
+	name := args[0]
+	source, exists := findCrawlSource(name)
+	if !exists {
+		fmt.Fprintf(os.Stderr, "unknown source %q (sources: %s)\n", name, strings.Join(crawlSourceNames(), ", "))
+		return 2
+	}
+
+	fs := flag.NewFlagSet("crawl", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "preview ekstraksi tanpa menulis file")
+	limit := fs.Int("limit", 20, "jumlah halaman maksimum yang di-fetch (dibatasi juga oleh max_pages di crawl_budgets.json)")
+	contentChars := fs.Int("content-chars", 300, "panjang potongan konten yang dicetak")
+	fs.Parse(args[1:])
+
+	if !*dryRun {
+		fmt.Fprintf(os.Stderr, "only --dry-run is supported here; run the full crawl with: go run ./crawler %s\n", name)
+		return 2
+	}
+
+	budgets, err := loadCrawlBudgets(CRAWL_BUDGETS_FILE)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error loading %s: %v\n", CRAWL_BUDGETS_FILE, err)
+		return 1
+	}
+	budget := budgets.For(name)
+	if budget.MaxPages == 0 || *limit < budget.MaxPages {
+		budget.MaxPages = *limit
+	}
+	tracker := newBudgetTracker(budget, nil)
+
+	hits, visited, failed := previewCrawl(source, tracker, *contentChars)
+
+	fmt.Printf("\npages visited=%d article pages=%d failed=%d\n", visited, hits.Pages, failed)
+	if stoppedBy := tracker.StoppedBy(); stoppedBy != "" {
+		fmt.Printf("stopped by budget: %s\n", stoppedBy)
+	}
+	if hits.Pages == 0 {
+		fmt.Println("no article pages found, check the article selector and start URL")
+		return 1
+	}
+	printHitRate("title", source.Title, hits.Title, hits.Pages)
+	printHitRate("content", source.Content, hits.Content, hits.Pages)
+	printHitRate("content fallback", "p", hits.Fallback, hits.Pages)
+	if source.Date != "" {
+		printHitRate("date", source.Date, hits.Date, hits.Pages)
+	}
+	if source.Author != "" {
+		printHitRate("author", source.Author, hits.Author, hits.Pages)
+	}
+	printHitRate("category", "breadcrumb", hits.Category, hits.Pages)
+	printHitRate("tables", "table", hits.Tables, hits.Pages)
+	printHitRate("links", "a[href]", hits.Links, hits.Pages)
+	return 0
+}
+
+func previewCrawl(source CrawlSource, tracker *budgetTracker, contentChars int) (selectorHits, int, int) {
+	var hits selectorHits
+	visited, failed := 0, 0
+
+	// Sinkron supaya batas halaman tepat dan output tidak bercampur
+	c := colly.NewCollector(
+		colly.AllowedDomains(source.domains()...),
+		colly.MaxDepth(source.MaxDepth),
+	)
+	c.SetRequestTimeout(30 * time.Second)
+
+	c.OnRequest(func(r *colly.Request) {
+		if blocklist.IsURLBlocked(r.URL.String()) || !tracker.allowPage() {
+			r.Abort()
+			return
+		}
+		visited++
+	})
+
+	c.OnError(func(r *colly.Response, err error) {
+		failed++
+		fmt.Printf("[ERROR] %s: %v\n", r.Request.URL, err)
+	})
+
+	c.OnHTML("a[href]", func(e *colly.HTMLElement) {
+		link := e.Request.AbsoluteURL(e.Attr("href"))
+		if source.follows(link) {
+			e.Request.Visit(link)
+		}
+	})
+
+	c.OnHTML(source.articleSelector(), func(e *colly.HTMLElement) {
+		article := Article{URL: e.Request.URL.String()}
+		if !tracker.allowArticle(article.URL) {
+			return
+		}
+		hits.Pages++
+
+		article.Title = extractField(e, source.Title, "")
+		if article.Title != "" {
+			hits.Title++
+		}
+
+		var contentParts []string
+		e.ForEach(source.Content, func(_ int, el *colly.HTMLElement) {
+			if text := strings.TrimSpace(el.Text); text != "" {
+				contentParts = append(contentParts, text)
+			}
+		})
+		if len(contentParts) > 0 {
+			hits.Content++
+		} else {
+			e.ForEach("p", func(_ int, el *colly.HTMLElement) {
+				if text := strings.TrimSpace(el.Text); text != "" {
+					contentParts = append(contentParts, text)
+				}
+			})
+			if len(contentParts) > 0 {
+				hits.Fallback++
+				article.Extraction = "fallback"
+			}
+		}
+		article.Content = strings.Join(contentParts, "\n")
+
+		if source.Date != "" {
+			if date, err := source.parseDate(extractField(e, source.Date, source.DateAttr)); err == nil {
+				article.Date = date
+				hits.Date++
+			}
+		}
+		if source.Author != "" {
+			if article.Author = extractField(e, source.Author, source.AuthorAttr); article.Author != "" {
+				hits.Author++
+			}
+		}
+		article.Breadcrumb = extractBreadcrumb(e, source.Breadcrumb, article.Title)
+		if categorize(article.Breadcrumb) != "" {
+			hits.Category++
+		}
+		if article.Tables = extractTables(e.DOM); len(article.Tables) > 0 {
+			hits.Tables++
+		}
+		if article.Links = extractLinks(e.DOM, e.Request.URL); len(article.Links) > 0 {
+			hits.Links++
+		}
+
+		printPreview(article, contentChars)
+	})
+
+	if err := c.Visit(source.StartURL); err != nil {
+		fmt.Printf("[ERROR] %s: %v\n", source.StartURL, err)
+		failed++
+	}
+	c.Wait()
+
+	return hits, visited, failed
+}
+
+func printPreview(article Article, contentChars int) {
+	fmt.Printf("\n%s\n", article.URL)
+	fmt.Printf("  title:   %q\n", article.Title)
+	if !article.Date.IsZero() {
+		fmt.Printf("  date:    %s\n", article.Date.Format("2006-01-02"))
+	}
+	if article.Author != "" {
+		fmt.Printf("  author:  %s\n", article.Author)
+	}
+	if len(article.Breadcrumb) > 0 {
+		category := categorize(article.Breadcrumb)
+		if category == "" {
+			category = "(unmapped)"
+		}
+		fmt.Printf("  category: %s -> %s\n", strings.Join(article.Breadcrumb, BREADCRUMB_SEPARATOR), category)
+	}
+	if article.Extraction != "" {
+		fmt.Printf("  extraction: %s\n", article.Extraction)
+	}
+
+	content := article.Content
+	if len(content) > contentChars {
+		content = content[:contentChars] + "..."
+	}
+	fmt.Printf("  content (%d chars): %q\n", len(article.Content), content)
+	for _, table := range article.Tables {
+		fmt.Printf("  table:   %q header=%q rows=%d\n", table.Caption, table.Header, len(table.Rows))
+	}
+	if len(article.Links) > 0 {
+		fmt.Printf("  links:   %d outbound\n", len(article.Links))
+	}
+}
+
+func printHitRate(name, selector string, hits, pages int) {
+	fmt.Printf("  %-17s %-28s %3d/%d (%.0f%%)\n", name, selector, hits, pages, 100*float64(hits)/float64(pages))
+}
+
+func crawlSourceNames() []string {
+	names := make([]string, 0, len(crawlSources))
+	for name := range crawlSources {
+		names = append(names, name)
+	}
+	for _, source := range savedSources.List() {
+		names = append(names, source.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	sources, err := loadCrawlSources(CRAWLERS_FILE)
+	if err != nil {
+		log.Printf("Error loading %s: %v", CRAWLERS_FILE, err)
+		return
+	}
+	crawlSources = sources
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Progress crawler yang sedang/terakhir berjalan. Crawler (proses terpisah,
+// lihat */progress.go) mengirim snapshot ke POST /api/admin/crawl/progress;
+// server menyimpannya untuk metrics Prometheus (/metrics), stream SSE dan
+// dashboard /admin/crawl.
+const CRAWL_STREAM_HEARTBEAT = 15 * time.Second
+
+type ProgressEvent struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"` // article, skip, error
+	URL     string    `json:"url,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+type CrawlProgress struct {
+	Source     string    `json:"source"`
+	Status     string    `json:"status"` // running, completed
+	StartedAt  time.Time `json:"started_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Requests   int64     `json:"requests"`
+	Responses  int64     `json:"responses"`
+	Errors     int64     `json:"errors"`
+	Blocked    int64     `json:"blocked"`
+	Articles   int64     `json:"articles"`
+	QueueDepth int64     `json:"queue_depth"`
+	StoppedBy  string    `json:"stopped_by,omitempty"`
+
+	// Dihitung server dari counter di atas
+	RequestsPerSec float64 `json:"requests_per_sec"`
+	SuccessRate    float64 `json:"success_rate"`
+
+	Recent []ProgressEvent `json:"recent"`
+}
+
+type CrawlMonitor struct {
+	mu          sync.RWMutex
+	crawls      map[string]CrawlProgress
+	subscribers map[chan CrawlProgress]struct{}
+	// Run gagal berturut-turut per source, lihat crawlFailed di alerts.go
+	failures map[string]int
+}
+
+var crawlMonitor = &CrawlMonitor{
+	crawls:      make(map[string]CrawlProgress),
+	subscribers: make(map[chan CrawlProgress]struct{}),
+	failures:    make(map[string]int),
+}
+
+func (m *CrawlMonitor) Update(progress CrawlProgress) {
+	if progress.UpdatedAt.IsZero() {
+		progress.UpdatedAt = time.Now()
+	}
+	if elapsed := progress.UpdatedAt.Sub(progress.StartedAt).Seconds(); elapsed > 0 {
+		progress.RequestsPerSec = float64(progress.Requests) / elapsed
+	}
+	if done := progress.Responses + progress.Errors; done > 0 {
+		progress.SuccessRate = float64(progress.Responses) / float64(done)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Snapshot completed dihitung sekali per run
+	previous, exists := m.crawls[progress.Source]
+	finished := exists && previous.Status == "completed" && previous.StartedAt.Equal(progress.StartedAt)
+	if progress.Status == "completed" && !finished {
+		if crawlFailed(progress, currentConfig().Alerts) {
+			m.failures[progress.Source]++
+		} else {
+			m.failures[progress.Source] = 0
+		}
+	}
+	m.crawls[progress.Source] = progress
+	for ch := range m.subscribers {
+		// Subscriber yang lambat melewatkan snapshot; yang berikutnya menyusul
+		select {
+		case ch <- progress:
+		default:
+		}
+	}
+}
+
+func (m *CrawlMonitor) List() []CrawlProgress {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := make([]CrawlProgress, 0, len(m.crawls))
+	for _, progress := range m.crawls {
+		list = append(list, progress)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Source < list[j].Source })
+	return list
+}
+
+// Jumlah run gagal berturut-turut per source yang pernah melapor
+func (m *CrawlMonitor) FailureStreaks() map[string]int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	streaks := make(map[string]int, len(m.crawls))
+	for source := range m.crawls {
+		streaks[source] = m.failures[source]
+	}
+	return streaks
+}
+
+func (m *CrawlMonitor) Subscribe() (<-chan CrawlProgress, func()) {
+	ch := make(chan CrawlProgress, 16)
+	m.mu.Lock()
+	m.subscribers[ch] = struct{}{}
+	m.mu.Unlock()
+
+	return ch, func() {
+		m.mu.Lock()
+		delete(m.subscribers, ch)
+		m.mu.Unlock()
+	}
+}
+
+// POST /api/admin/crawl/progress: snapshot dari crawler
+func crawlProgressReportHandler(c *gin.Context) {
+	var progress CrawlProgress
+	if err := c.ShouldBindJSON(&progress); err != nil || progress.Source == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid progress report"})
+		return
+	}
+	crawlMonitor.Update(progress)
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// GET /api/admin/crawl/progress
+func crawlProgressHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, crawlMonitor.List())
+}
+
+// GET /api/admin/crawl/stream: Server-Sent Events, satu event "progress" per
+// snapshot. Snapshot terakhir setiap sumber dikirim dulu saat terhubung.
+func crawlStreamHandler(c *gin.Context) {
+	updates, unsubscribe := crawlMonitor.Subscribe()
+	defer unsubscribe()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	for _, progress := range crawlMonitor.List() {
+		c.SSEvent("progress", progress)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(CRAWL_STREAM_HEARTBEAT)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case progress := <-updates:
+			c.SSEvent("progress", progress)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// GET /metrics: metrics crawler dan sinyal alerting (alerts.go) dalam format
+// teks Prometheus
+func metricsHandler(c *gin.Context) {
+	var buf bytes.Buffer
+	crawls := crawlMonitor.List()
+
+	metric := func(name, kind, help string, value func(CrawlProgress) float64) {
+		fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for _, progress := range crawls {
+			fmt.Fprintf(&buf, "%s{source=%q} %g\n", name, progress.Source, value(progress))
+		}
+	}
+
+	metric("crawler_requests_total", "counter", "Requests sent by the current or last crawl run.",
+		func(p CrawlProgress) float64 { return float64(p.Requests) })
+	metric("crawler_responses_total", "counter", "Successful responses in the current or last crawl run.",
+		func(p CrawlProgress) float64 { return float64(p.Responses) })
+	metric("crawler_errors_total", "counter", "Failed requests in the current or last crawl run.",
+		func(p CrawlProgress) float64 { return float64(p.Errors) })
+	metric("crawler_blocked_total", "counter", "URLs skipped because they are blocklisted.",
+		func(p CrawlProgress) float64 { return float64(p.Blocked) })
+	metric("crawler_articles_total", "counter", "Articles extracted in the current or last crawl run.",
+		func(p CrawlProgress) float64 { return float64(p.Articles) })
+	metric("crawler_queue_depth", "gauge", "Requests scheduled but not finished yet.",
+		func(p CrawlProgress) float64 { return float64(p.QueueDepth) })
+	metric("crawler_requests_per_second", "gauge", "Average request rate of the current or last crawl run.",
+		func(p CrawlProgress) float64 { return p.RequestsPerSec })
+	metric("crawler_success_ratio", "gauge", "Share of finished requests that succeeded.",
+		func(p CrawlProgress) float64 { return p.SuccessRate })
+	metric("crawler_running", "gauge", "1 while the crawler is running.",
+		func(p CrawlProgress) float64 {
+			if p.Status == "running" {
+				return 1
+			}
+			return 0
+		})
+	metric("crawler_last_report_timestamp_seconds", "gauge", "Unix time of the last progress report.",
+		func(p CrawlProgress) float64 { return float64(p.UpdatedAt.Unix()) })
+
+	signals, err := healthSignals(time.Now())
+	if err != nil {
+		log.Printf("Error computing health metrics: %v", err)
+	}
+	writeHealthMetrics(&buf, signals, evaluateAlerts(signals, requestConfig(c).Alerts))
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", buf.Bytes())
+}
+
+// GET /admin/crawl: dashboard progress crawl (update lewat SSE)
+func crawlDashboardHandler(c *gin.Context) {
+	renderHTML(c, http.StatusOK, "admin_crawl.html", gin.H{
+		"crawls":  crawlMonitor.List(),
+		"quality": sourceQualities(),
+		"token":   c.Query("token"),
+	})
+}
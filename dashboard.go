@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Dashboard admin /admin/dashboard: ringkasan status sistem dari sumber yang
+// sama dengan API statistik, yaitu laporan build index terakhir, jumlah
+// dokumen per source, status crawl terakhir, volume query per jam (sparkline),
+// hit rate cache, query paling lambat dari query log dan jumlah hasil query
+// yang dipantau (diperbarui lewat SSE, lihat monitor.go). Datanya juga
+// tersedia sebagai JSON di GET /api/admin/dashboard.
+const (
+	DASHBOARD_VOLUME_HOURS = 24
+	DASHBOARD_SLOW_QUERIES = 10
+
+	DASHBOARD_SPARKLINE_WIDTH  = 240
+	DASHBOARD_SPARKLINE_HEIGHT = 40
+)
+
+type DashboardIndex struct {
+	Generation  int       `json:"generation"`
+	BuiltAt     time.Time `json:"built_at"`
+	BuildMs     float64   `json:"build_ms"`
+	Docs        int       `json:"docs"`
+	IndexedDocs int       `json:"indexed_docs"`
+	SkippedDocs int       `json:"skipped_docs"`
+	Terms       int       `json:"terms"`
+	// Record WAL yang belum di-merge ke articles.json
+	PendingRecords int `json:"pending_records"`
+}
+
+type DashboardSource struct {
+	Source   string `json:"source"`
+	Articles int    `json:"articles"`
+}
+
+type DashboardQueries struct {
+	Total int `json:"total"` // dalam DASHBOARD_VOLUME_HOURS jam terakhir
+	// Jumlah query per jam, terlama dulu; elemen terakhir jam berjalan
+	Hourly  []int         `json:"hourly"`
+	Latency ReplayLatency `json:"latency"`
+	// Query paling lambat, terlambat dulu
+	Slow []QueryLogEntry `json:"slow"`
+}
+
+type DashboardCache struct {
+	Hits    int     `json:"hits"`
+	Misses  int     `json:"misses"`
+	HitRate float64 `json:"hit_rate"` // 0-1, 0 kalau belum ada lookup
+}
+
+type AdminDashboard struct {
+	Time    time.Time         `json:"time"`
+	Index   *DashboardIndex   `json:"index"` // nil kalau belum ada build
+	Sources []DashboardSource `json:"sources"`
+	Crawls  []CrawlProgress   `json:"crawls"`
+	Queries DashboardQueries  `json:"queries"`
+	// Jumlah terbaru query yang dipantau
+	Monitored []MonitorUpdate `json:"monitored"`
+
+	ResultCache DashboardCache `json:"result_cache"`
+	QueryCache  DashboardCache `json:"query_cache"`
+	FilterCache DashboardCache `json:"filter_cache"`
+}
+
+func cacheHitRate(hits, misses int) DashboardCache {
+	stats := DashboardCache{Hits: hits, Misses: misses}
+	if hits+misses > 0 {
+		stats.HitRate = float64(hits) / float64(hits+misses)
+	}
+	return stats
+}
+
+// Ringkasan index_report.json terakhir; nil kalau index belum pernah dibangun
+func dashboardIndex() (*DashboardIndex, error) {
+	data, err := ioutil.ReadFile(INDEX_REPORT_FILE)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var report BuildReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	return &DashboardIndex{
+		Generation:     report.Generation,
+		BuiltAt:        report.Time,
+		BuildMs:        report.BuildMs,
+		Docs:           report.Docs,
+		IndexedDocs:    report.IndexedDocs,
+		SkippedDocs:    report.SkippedDocs,
+		Terms:          report.Terms,
+		PendingRecords: documentLog.Stats().PendingRecords,
+	}, nil
+}
+
+// Volume per jam, latency dan query terlambat dari entry query log sejak
+// now dikurangi hours jam
+func dashboardQueries(entries []QueryLogEntry, now time.Time, hours int) DashboardQueries {
+	queries := DashboardQueries{Hourly: make([]int, hours), Slow: []QueryLogEntry{}}
+	start := now.Truncate(time.Hour).Add(-time.Duration(hours-1) * time.Hour)
+
+	var recent []QueryLogEntry
+	latencies := make([]float64, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Time.Before(start) || entry.Time.After(now) {
+			continue
+		}
+		queries.Hourly[int(entry.Time.Sub(start)/time.Hour)]++
+		latencies = append(latencies, entry.DurationMs)
+		recent = append(recent, entry)
+	}
+	queries.Total = len(recent)
+	queries.Latency = latencySummary(latencies)
+
+	sort.SliceStable(recent, func(i, j int) bool { return recent[i].DurationMs > recent[j].DurationMs })
+	if len(recent) > DASHBOARD_SLOW_QUERIES {
+		recent = recent[:DASHBOARD_SLOW_QUERIES]
+	}
+	queries.Slow = append(queries.Slow, recent...)
+	return queries
+}
+
+func adminDashboard(cfg *Config) (AdminDashboard, error) {
+	now := time.Now()
+	dashboard := AdminDashboard{Time: now, Crawls: crawlMonitor.List(), Monitored: queryMonitor.Latest()}
+
+	index, err := dashboardIndex()
+	if err != nil {
+		return dashboard, err
+	}
+	dashboard.Index = index
+
+	qualities := sourceQualities()
+	dashboard.Sources = make([]DashboardSource, len(qualities))
+	for i, quality := range qualities {
+		dashboard.Sources[i] = DashboardSource{Source: quality.Source, Articles: quality.Articles}
+	}
+	sort.Slice(dashboard.Sources, func(i, j int) bool {
+		if dashboard.Sources[i].Articles != dashboard.Sources[j].Articles {
+			return dashboard.Sources[i].Articles > dashboard.Sources[j].Articles
+		}
+		return dashboard.Sources[i].Source < dashboard.Sources[j].Source
+	})
+
+	since := now.Truncate(time.Hour).Add(-(DASHBOARD_VOLUME_HOURS - 1) * time.Hour)
+	entries, err := readQueryLog(QUERY_LOG_FILE, since)
+	if err != nil {
+		return dashboard, err
+	}
+	dashboard.Queries = dashboardQueries(entries, now, DASHBOARD_VOLUME_HOURS)
+
+	resultStats := resultPageCache.Stats(cfg)
+	dashboard.ResultCache = cacheHitRate(resultStats.Hits, resultStats.Misses)
+	queryStats := queryResultCache.Stats(cfg)
+	dashboard.QueryCache = cacheHitRate(queryStats.Hits, queryStats.Misses)
+	filterStats := filterCache.Stats()
+	dashboard.FilterCache = cacheHitRate(filterStats.Hits, filterStats.Misses)
+	return dashboard, nil
+}
+
+// Titik polyline SVG untuk sparkline, nilai tertinggi di atas
+func sparklinePoints(values []int, width, height int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	max := 1
+	for _, value := range values {
+		if value > max {
+			max = value
+		}
+	}
+	step := 0.0
+	if len(values) > 1 {
+		step = float64(width) / float64(len(values)-1)
+	}
+	points := make([]string, len(values))
+	for i, value := range values {
+		y := float64(height) - float64(value)*float64(height)/float64(max)
+		points[i] = fmt.Sprintf("%.1f,%.1f", float64(i)*step, y)
+	}
+	return strings.Join(points, " ")
+}
+
+// GET /api/admin/dashboard
+func dashboardHandler(c *gin.Context) {
+	dashboard, err := adminDashboard(requestConfig(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dashboard)
+}
+
+// GET /admin/dashboard
+func dashboardPageHandler(c *gin.Context) {
+	dashboard, err := adminDashboard(requestConfig(c))
+	if err != nil {
+		log.Printf("Error building admin dashboard: %v", err)
+	}
+	renderHTML(c, http.StatusOK, "admin_dashboard.html", gin.H{
+		"dashboard": dashboard,
+		"error":     err,
+		"sparkline": sparklinePoints(dashboard.Queries.Hourly, DASHBOARD_SPARKLINE_WIDTH, DASHBOARD_SPARKLINE_HEIGHT),
+		"width":     DASHBOARD_SPARKLINE_WIDTH,
+		"height":    DASHBOARD_SPARKLINE_HEIGHT,
+		"token":     c.Query("token"),
+	})
+}
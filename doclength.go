@@ -0,0 +1,75 @@
+package main
+
+// Panjang dokumen dalam jumlah token yang diindex (setelah stopword dibuang),
+// per field. Dipakai untuk normalisasi panjang dokumen (BM25, pivoted
+// normalization) tanpa menganalisis ulang dokumen.
+type DocLength struct {
+	Title   int `json:"title"`
+	Content int `json:"content"`
+}
+
+func (l DocLength) Tokens() int {
+	return l.Title + l.Content
+}
+
+// Hitung panjang per field dari token hasil analisis title + " " + content
+func docLengthOf(tokens []Token, titleLen int) DocLength {
+	var length DocLength
+	for _, tok := range tokens {
+		if tok.Start < titleLen {
+			length.Title++
+		} else {
+			length.Content++
+		}
+	}
+	return length
+}
+
+// Rata-rata panjang dokumen (token) di antara dokumen yang diindex
+func (idx *InvertedIndex) AvgDocLength() float64 {
+	if idx.DocCount == 0 {
+		return 0
+	}
+	return float64(idx.TotalTokens) / float64(idx.DocCount)
+}
+
+type IndexStats struct {
+	Docs             int     `json:"docs"`
+	IndexedDocs      int     `json:"indexed_docs"`
+	Terms            int     `json:"terms"`
+	Postings         int     `json:"postings"`
+	Tokens           int     `json:"tokens"`
+	AvgDocLength     float64 `json:"avg_doc_length"`
+	AvgTitleLength   float64 `json:"avg_title_length"`
+	AvgContentLength float64 `json:"avg_content_length"`
+	InternedTerms    int     `json:"interned_terms"`
+	Paragraphs       int     `json:"paragraphs,omitempty"` // sub-dokumen paragraf, kalau paragraph_index aktif
+}
+
+func (idx *InvertedIndex) Stats() IndexStats {
+	stats := IndexStats{
+		Docs:          len(idx.DocLengths),
+		IndexedDocs:   idx.DocCount,
+		Terms:         len(idx.Index),
+		Tokens:        idx.TotalTokens,
+		AvgDocLength:  idx.AvgDocLength(),
+		InternedTerms: termTable.Len(),
+	}
+	for _, postingList := range idx.Index {
+		stats.Postings += len(postingList.Postings)
+	}
+	if idx.Paragraphs != nil {
+		stats.Paragraphs = len(idx.Paragraphs.Paragraphs)
+	}
+
+	if idx.DocCount > 0 {
+		var title, content int
+		for _, length := range idx.DocLengths {
+			title += length.Title
+			content += length.Content
+		}
+		stats.AvgTitleLength = float64(title) / float64(idx.DocCount)
+		stats.AvgContentLength = float64(content) / float64(idx.DocCount)
+	}
+	return stats
+}
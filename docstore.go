@@ -0,0 +1,382 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Document store: artikel disimpan terkompresi zstd per block berisi
+// DOCSTORE_BLOCK_DOCS dokumen, dengan tabel offset di akhir file supaya
+// dokumen bisa diambil langsung berdasarkan docID tanpa membaca seluruh file.
+//
+// Layout file:
+//
+//	magic | block 0 | block 1 | ... | block table | footer
+//	block table: per block uint64 offset + uint32 length + uint32 crc32
+//	footer:      uint32 blockDocs, uint32 docCount, uint32 blockCount, uint64 tableOffset, magic
+const (
+	DOCSTORE_MAGIC       = "QDS2"
+	DOCSTORE_BLOCK_DOCS  = 16
+	DOCSTORE_CACHE_SIZE  = 64
+	docStoreFooterSize   = 4 + 4 + 4 + 8 + 4
+	docStoreTableEntSize = 8 + 4 + 4
+)
+
+type docStoreBlock struct {
+	offset   uint64
+	length   uint32
+	checksum uint32 // crc32 data terkompresi
+}
+
+type DocStore struct {
+	file      *os.File
+	blockDocs int
+	docCount  int
+	blocks    []docStoreBlock
+	decoder   *zstd.Decoder
+
+	mu    sync.Mutex
+	cache map[int][]Article // block terakhir yang sudah didekompresi
+	order []int
+
+	refs int32 // pembaca aktif + 1 selama store masih dipakai, lihat acquireDocStore
+}
+
+// Document store aktif, nil kalau doc_store_path kosong. Diganti oleh
+// setDocStore setelah flush; pembaca memegang referensi lewat
+// acquireDocStore/release, jadi store lama baru ditutup setelah pembaca
+// terakhirnya selesai.
+var docStores struct {
+	mu      sync.RWMutex
+	current *DocStore
+}
+
+// Store aktif dengan satu referensi tambahan, nil kalau tidak ada. Panggil
+// release setelah selesai membaca.
+func acquireDocStore() *DocStore {
+	docStores.mu.RLock()
+	defer docStores.mu.RUnlock()
+
+	ds := docStores.current
+	if ds != nil {
+		atomic.AddInt32(&ds.refs, 1)
+	}
+	return ds
+}
+
+func (ds *DocStore) release() {
+	if atomic.AddInt32(&ds.refs, -1) == 0 {
+		if err := ds.Close(); err != nil {
+			log.Printf("Error closing document store: %v", err)
+		}
+	}
+}
+
+// Pasang store baru; store lama ditutup begitu tidak ada pembaca lagi
+func setDocStore(ds *DocStore) {
+	if ds != nil {
+		atomic.StoreInt32(&ds.refs, 1)
+	}
+	docStores.mu.Lock()
+	old := docStores.current
+	docStores.current = ds
+	docStores.mu.Unlock()
+
+	if old != nil {
+		old.release()
+	}
+}
+
+func hasDocStore() bool {
+	docStores.mu.RLock()
+	defer docStores.mu.RUnlock()
+	return docStores.current != nil
+}
+
+// Tulis semua artikel ke document store baru
+func writeDocStore(path string, articles []Article) error {
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+	if err != nil {
+		return err
+	}
+	defer encoder.Close()
+
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	offset := uint64(len(DOCSTORE_MAGIC))
+	if _, err := f.Write([]byte(DOCSTORE_MAGIC)); err != nil {
+		f.Close()
+		return err
+	}
+
+	var blocks []docStoreBlock
+	for start := 0; start < len(articles); start += DOCSTORE_BLOCK_DOCS {
+		end := start + DOCSTORE_BLOCK_DOCS
+		if end > len(articles) {
+			end = len(articles)
+		}
+
+		raw, err := json.Marshal(articles[start:end])
+		if err != nil {
+			f.Close()
+			return err
+		}
+		compressed := encoder.EncodeAll(raw, nil)
+		if _, err := f.Write(compressed); err != nil {
+			f.Close()
+			return err
+		}
+
+		blocks = append(blocks, docStoreBlock{
+			offset:   offset,
+			length:   uint32(len(compressed)),
+			checksum: crc32.ChecksumIEEE(compressed),
+		})
+		offset += uint64(len(compressed))
+	}
+
+	var table bytes.Buffer
+	for _, b := range blocks {
+		binary.Write(&table, binary.LittleEndian, b.offset)
+		binary.Write(&table, binary.LittleEndian, b.length)
+		binary.Write(&table, binary.LittleEndian, b.checksum)
+	}
+	binary.Write(&table, binary.LittleEndian, uint32(DOCSTORE_BLOCK_DOCS))
+	binary.Write(&table, binary.LittleEndian, uint32(len(articles)))
+	binary.Write(&table, binary.LittleEndian, uint32(len(blocks)))
+	binary.Write(&table, binary.LittleEndian, offset)
+	table.WriteString(DOCSTORE_MAGIC)
+
+	if _, err := f.Write(table.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// Buka document store dan baca tabel block-nya
+func openDocStore(path string) (*DocStore, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ds, err := readDocStoreTable(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	ds.decoder = decoder
+
+	return ds, nil
+}
+
+func readDocStoreTable(f *os.File) (*DocStore, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < int64(len(DOCSTORE_MAGIC)+docStoreFooterSize) {
+		return nil, errors.New("document store too small")
+	}
+
+	footer := make([]byte, docStoreFooterSize)
+	if _, err := f.ReadAt(footer, info.Size()-docStoreFooterSize); err != nil {
+		return nil, err
+	}
+	if string(footer[20:]) != DOCSTORE_MAGIC {
+		return nil, errors.New("invalid document store footer")
+	}
+
+	blockDocs := int(binary.LittleEndian.Uint32(footer[0:]))
+	docCount := int(binary.LittleEndian.Uint32(footer[4:]))
+	blockCount := int(binary.LittleEndian.Uint32(footer[8:]))
+	tableOffset := binary.LittleEndian.Uint64(footer[12:])
+
+	// Footer dari file terpotong atau rusak tidak boleh dipercaya begitu saja:
+	// ukuran tabel dan setiap block harus cocok dengan ukuran file
+	if blockDocs <= 0 {
+		return nil, fmt.Errorf("invalid docs per block %d", blockDocs)
+	}
+	if blockCount != (docCount+blockDocs-1)/blockDocs {
+		return nil, fmt.Errorf("block count %d does not match %d docs", blockCount, docCount)
+	}
+	dataEnd := uint64(info.Size() - docStoreFooterSize)
+	if tableOffset < uint64(len(DOCSTORE_MAGIC)) || tableOffset > dataEnd ||
+		(dataEnd-tableOffset)/docStoreTableEntSize != uint64(blockCount) || (dataEnd-tableOffset)%docStoreTableEntSize != 0 {
+		return nil, errors.New("block table does not fit the file")
+	}
+
+	table := make([]byte, blockCount*docStoreTableEntSize)
+	if _, err := f.ReadAt(table, int64(tableOffset)); err != nil {
+		return nil, err
+	}
+
+	blocks := make([]docStoreBlock, blockCount)
+	for i := range blocks {
+		entry := table[i*docStoreTableEntSize:]
+		blocks[i] = docStoreBlock{
+			offset:   binary.LittleEndian.Uint64(entry[0:]),
+			length:   binary.LittleEndian.Uint32(entry[8:]),
+			checksum: binary.LittleEndian.Uint32(entry[12:]),
+		}
+		if b := blocks[i]; b.offset < uint64(len(DOCSTORE_MAGIC)) || b.offset > tableOffset || uint64(b.length) > tableOffset-b.offset {
+			return nil, fmt.Errorf("block %d outside data section", i)
+		}
+	}
+
+	return &DocStore{
+		file:      f,
+		blockDocs: blockDocs,
+		docCount:  docCount,
+		blocks:    blocks,
+		cache:     make(map[int][]Article),
+	}, nil
+}
+
+func (ds *DocStore) Count() int {
+	return ds.docCount
+}
+
+func (ds *DocStore) BlockCount() int {
+	return len(ds.blocks)
+}
+
+// Ambil satu dokumen berdasarkan docID
+func (ds *DocStore) Get(docID int) (Article, error) {
+	if docID < 0 || docID >= ds.docCount {
+		return Article{}, fmt.Errorf("doc %d out of range", docID)
+	}
+
+	n := docID / ds.blockDocs
+	docs, err := ds.block(n)
+	if err != nil {
+		return Article{}, err
+	}
+	if docID%ds.blockDocs >= len(docs) {
+		return Article{}, fmt.Errorf("block %d has %d docs, doc %d missing", n, len(docs), docID)
+	}
+	return docs[docID%ds.blockDocs], nil
+}
+
+// Dekompresi block, dengan cache kecil untuk block yang sering diakses
+func (ds *DocStore) block(n int) ([]Article, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if docs, exists := ds.cache[n]; exists && !benchMode {
+		return docs, nil
+	}
+	if n < 0 || n >= len(ds.blocks) {
+		return nil, fmt.Errorf("block %d out of range", n)
+	}
+
+	b := ds.blocks[n]
+	compressed := make([]byte, b.length)
+	if _, err := ds.file.ReadAt(compressed, int64(b.offset)); err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(compressed) != b.checksum {
+		return nil, fmt.Errorf("block %d checksum mismatch", n)
+	}
+	raw, err := ds.decoder.DecodeAll(compressed, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []Article
+	if err := json.Unmarshal(raw, &docs); err != nil {
+		return nil, err
+	}
+
+	if len(ds.order) >= DOCSTORE_CACHE_SIZE {
+		delete(ds.cache, ds.order[0])
+		ds.order = ds.order[1:]
+	}
+	ds.cache[n] = docs
+	ds.order = append(ds.order, n)
+
+	return docs, nil
+}
+
+func (ds *DocStore) Close() error {
+	ds.decoder.Close()
+	return ds.file.Close()
+}
+
+// Bangun ulang document store kalau belum ada atau lebih lama dari corpus JSON
+func ensureDocStore(path string) (*DocStore, error) {
+	storeInfo, storeErr := os.Stat(path)
+	corpusInfo, err := os.Stat(ARTICLES_FILE)
+	if err != nil {
+		return nil, err
+	}
+
+	if storeErr != nil || storeInfo.ModTime().Before(corpusInfo.ModTime()) {
+		if err := rebuildDocStore(path); err != nil {
+			return nil, err
+		}
+		return openDocStore(path)
+	}
+
+	ds, err := openDocStore(path)
+	if err != nil {
+		// Format lama atau file rusak: bangun ulang dari corpus
+		log.Printf("Rebuilding document store %s: %v", path, err)
+		if err := rebuildDocStore(path); err != nil {
+			return nil, err
+		}
+		return openDocStore(path)
+	}
+	return ds, nil
+}
+
+func rebuildDocStore(path string) error {
+	articles, err := loadArticles()
+	if err != nil {
+		return err
+	}
+	return writeDocStore(path, articles)
+}
+
+// Ambil dokumen lengkap dari document store, dengan koreksi admin diterapkan
+func storedArticle(docID int) (Article, error) {
+	ds := acquireDocStore()
+	if ds == nil {
+		return Article{}, errors.New("no document store")
+	}
+	article, err := ds.Get(docID)
+	ds.release()
+	if err != nil {
+		return Article{}, err
+	}
+
+	docs := []Article{article}
+	reviewQueue.ApplyEdits(docs)
+	return docs[0], nil
+}
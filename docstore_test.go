@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// Corpus fixture diulang sampai beberapa block document store
+func docStoreCorpus(n int) []Article {
+	corpus := fixtureCorpus()
+	articles := make([]Article, n)
+	for i := range articles {
+		articles[i] = corpus[i%len(corpus)]
+		articles[i].URL += "-" + strconv.Itoa(i)
+	}
+	return articles
+}
+
+func writeTestDocStore(t *testing.T, name string, articles []Article) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := writeDocStore(path, articles); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDocStoreRoundTrip(t *testing.T) {
+	articles := docStoreCorpus(3*DOCSTORE_BLOCK_DOCS + 5)
+	ds, err := openDocStore(writeTestDocStore(t, "docs.qds", articles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ds.Close()
+
+	if ds.Count() != len(articles) || ds.BlockCount() != 4 {
+		t.Fatalf("%d docs in %d blocks", ds.Count(), ds.BlockCount())
+	}
+	for docID, want := range articles {
+		got, err := ds.Get(docID)
+		if err != nil {
+			t.Fatalf("doc %d: %v", docID, err)
+		}
+		if got.URL != want.URL || got.Content != want.Content {
+			t.Fatalf("doc %d is %s", docID, got.URL)
+		}
+	}
+	if _, err := ds.Get(len(articles)); err == nil {
+		t.Error("doc past the end should fail")
+	}
+}
+
+// File terpotong atau footer/tabel rusak harus ditolak saat dibuka, bukan
+// membuat panic atau alokasi raksasa saat dibaca
+func TestDocStoreRejectsCorruptFiles(t *testing.T) {
+	path := writeTestDocStore(t, "docs.qds", docStoreCorpus(2*DOCSTORE_BLOCK_DOCS+1))
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	footer := len(data) - docStoreFooterSize
+	tableOffset := int(binary.LittleEndian.Uint64(data[footer+12:]))
+
+	cases := map[string]func([]byte) []byte{
+		"truncated footer": func(b []byte) []byte { return b[:len(b)-5] },
+		"truncated to magic": func(b []byte) []byte {
+			return b[:len(DOCSTORE_MAGIC)]
+		},
+		"bad magic": func(b []byte) []byte {
+			copy(b[len(b)-4:], "XXXX")
+			return b
+		},
+		"zero docs per block": func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[footer:], 0)
+			return b
+		},
+		"block count": func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[footer+8:], 1000)
+			return b
+		},
+		"doc count": func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[footer+4:], 1000)
+			binary.LittleEndian.PutUint32(b[footer+8:], uint32((1000+DOCSTORE_BLOCK_DOCS-1)/DOCSTORE_BLOCK_DOCS))
+			return b
+		},
+		"table offset past the file": func(b []byte) []byte {
+			binary.LittleEndian.PutUint64(b[footer+12:], uint64(len(b)))
+			return b
+		},
+		"block outside data": func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[tableOffset+8:], 1<<31)
+			return b
+		},
+		"data section cut": func(b []byte) []byte {
+			return append(b[:len(DOCSTORE_MAGIC)], b[len(DOCSTORE_MAGIC)+10:]...)
+		},
+	}
+	for name, corrupt := range cases {
+		corrupted := corrupt(append([]byte(nil), data...))
+		corruptPath := filepath.Join(t.TempDir(), "corrupt.qds")
+		if err := ioutil.WriteFile(corruptPath, corrupted, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if ds, err := openDocStore(corruptPath); err == nil {
+			ds.Close()
+			t.Errorf("%s: opened without error", name)
+		}
+	}
+
+	// Block rusak dengan tabel utuh baru ketahuan saat dibaca, lewat checksum
+	corrupted := append([]byte(nil), data...)
+	corrupted[len(DOCSTORE_MAGIC)+1] ^= 0xff
+	corruptPath := filepath.Join(t.TempDir(), "corrupt.qds")
+	if err := ioutil.WriteFile(corruptPath, corrupted, 0644); err != nil {
+		t.Fatal(err)
+	}
+	ds, err := openDocStore(corruptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ds.Close()
+	if _, err := ds.Get(0); err == nil {
+		t.Error("corrupt block read without error")
+	}
+	if _, err := ds.Get(DOCSTORE_BLOCK_DOCS); err != nil {
+		t.Errorf("intact block: %v", err)
+	}
+}
+
+// Pembaca yang memegang store lama tetap bisa membaca setelah store diganti;
+// store lama baru ditutup saat pembaca terakhirnya selesai
+func TestDocStoreSwapWaitsForReaders(t *testing.T) {
+	docStores.mu.RLock()
+	prev := docStores.current
+	docStores.mu.RUnlock()
+	t.Cleanup(func() {
+		setDocStore(nil)
+		docStores.mu.Lock()
+		docStores.current = prev
+		docStores.mu.Unlock()
+	})
+
+	articles := docStoreCorpus(3 * DOCSTORE_BLOCK_DOCS)
+	old, err := openDocStore(writeTestDocStore(t, "old.qds", articles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	setDocStore(old)
+	reader := acquireDocStore()
+	if reader != old {
+		t.Fatal("acquired a different store")
+	}
+
+	articles[0].Title = "Judul Baru"
+	next, err := openDocStore(writeTestDocStore(t, "new.qds", articles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	setDocStore(next)
+
+	// Block yang belum pernah dibaca: harus dibaca dari file store lama
+	if article, err := reader.Get(DOCSTORE_BLOCK_DOCS); err != nil || article.URL != articles[DOCSTORE_BLOCK_DOCS].URL {
+		t.Fatalf("old store after swap: %s, %v", article.URL, err)
+	}
+	reader.release()
+	if _, err := reader.Get(2 * DOCSTORE_BLOCK_DOCS); err == nil {
+		t.Error("old store still open after its last reader released it")
+	}
+
+	current := acquireDocStore()
+	defer current.release()
+	if article, err := current.Get(0); err != nil || article.Title != "Judul Baru" {
+		t.Errorf("new store: %q, %v", article.Title, err)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Kelola dokumen satu per satu lewat doc ID tanpa restart server:
+//
+//	POST   /api/documents      body: artikel JSON (unggahan PDF/DOCX tetap multipart)
+//	PUT    /api/documents/:id  body: artikel JSON, url boleh dikosongkan
+//	DELETE /api/documents/:id
+//
+// Perubahan ditulis ke WAL seperti ingest, lalu langsung diterapkan ke index
+// yang aktif (incremental.go) sebelum response dikirim, jadi pencarian
+// berikutnya sudah melihatnya. Berbeda dengan ingest yang menjawab 202 dan
+// membiarkan indexer menyusul.
+
+// POST /api/documents: artikel JSON, atau unggahan dokumen (documents.go)
+func postDocumentHandler(c *gin.Context) {
+	if c.ContentType() == gin.MIMEJSON {
+		createDocumentHandler(c)
+		return
+	}
+	uploadDocumentHandler(c)
+}
+
+func createDocumentHandler(c *gin.Context) {
+	var article Article
+	if err := c.ShouldBindJSON(&article); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	articles, _, err := loadIndex()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if docID := documentByURL(articles, article.URL); docID >= 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "document with this url already exists", "doc_id": docID})
+		return
+	}
+	article.Provenance = requestProvenance(c, ProvenanceIngest, article.Provenance)
+	writeDocument(c, http.StatusCreated, WalRecord{Op: WalAdd, Article: &article})
+}
+
+// PUT /api/documents/:id: ganti seluruh isi dokumen; URL tidak bisa
+// diubah karena URL adalah identitas dokumen
+func updateDocumentHandler(c *gin.Context) {
+	docID, current, ok := documentParam(c)
+	if !ok {
+		return
+	}
+	var article Article
+	if err := c.ShouldBindJSON(&article); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if article.URL == "" {
+		article.URL = current.URL
+	}
+	if article.URL != current.URL {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url cannot be changed; delete the document and add it again", "doc_id": docID})
+		return
+	}
+	article.Provenance = requestProvenance(c, ProvenanceIngest, article.Provenance)
+	writeDocument(c, http.StatusOK, WalRecord{Op: WalUpdate, Article: &article})
+}
+
+// DELETE /api/documents/:id: soft delete kalau soft_delete_days > 0
+func deleteDocumentHandler(c *gin.Context) {
+	_, current, ok := documentParam(c)
+	if !ok {
+		return
+	}
+	writeDocument(c, http.StatusOK, WalRecord{Op: WalDelete, URL: current.URL})
+}
+
+// Dokumen dari :id; false kalau response error sudah dikirim
+func documentParam(c *gin.Context) (int, Article, bool) {
+	docID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid document id"})
+		return 0, Article{}, false
+	}
+	articles, _, err := loadIndex()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return 0, Article{}, false
+	}
+	if docID < 0 || docID >= len(articles) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "document not found"})
+		return 0, Article{}, false
+	}
+	if articles[docID].Deleted() {
+		c.JSON(http.StatusConflict, gin.H{"error": "document is deleted; restore it first", "doc_id": docID})
+		return 0, Article{}, false
+	}
+	return docID, articles[docID], true
+}
+
+// Validasi, tulis ke WAL, lalu terapkan ke index sebelum menjawab
+func writeDocument(c *gin.Context, status int, record WalRecord) {
+	if record.Article != nil {
+		record.Article.Date = record.Article.Date.UTC()
+	}
+	cfg := requestConfig(c)
+	valid, rejected := cfg.Validation.validateBatch([]WalRecord{record}, cfg.Schema)
+	if len(valid) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fieldErrorsString(rejected[0].Errors), "errors": rejected[0].Errors})
+		return
+	}
+
+	accepted, err := auditActor(c).appendDocuments(valid...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	state, err := indexer.rebuild(IndexTriggerDocument, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "accepted": accepted[0].Seq})
+		return
+	}
+	if record.Article != nil {
+		scheduleEnrichment()
+	}
+
+	response := gin.H{"accepted": accepted[0].Seq, "url": accepted[0].URL}
+	// Dokumen yang di-hard delete sudah tidak punya doc ID
+	if docID := documentByURL(state.Articles, accepted[0].URL); docID >= 0 {
+		response["doc_id"] = docID
+		response["deleted"] = state.Articles[docID].Deleted()
+		response["indexed"] = docID < len(state.Index.Allowed) && state.Index.Allowed[docID]
+	}
+	c.JSON(status, response)
+}
+
+func documentByURL(articles []Article, url string) int {
+	for docID, article := range articles {
+		if article.URL == url {
+			return docID
+		}
+	}
+	return -1
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /document/:id: halaman dokumen (templates/document.html) dengan judul,
+// penulis, tanggal, paragraf konten dan tabel utamanya (tables.go). Dokumen
+// yang dihapus, di blocklist atau tidak boleh dilihat klien dijawab 404.
+func documentPageHandler(c *gin.Context) {
+	docID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		renderError(c, http.StatusNotFound)
+		return
+	}
+	articles, _, err := loadIndex()
+	if err != nil {
+		log.Printf("Error loading articles: %v", err)
+		renderError(c, http.StatusInternalServerError)
+		return
+	}
+	if docID < 0 || docID >= len(articles) {
+		renderError(c, http.StatusNotFound)
+		return
+	}
+	article, _ := localArticle(articles, SearchResult{DocID: docID})
+	if article.Deleted() || !article.VisibleTo(clientScopes(c)) || blocklist.IsURLBlocked(article.URL) {
+		renderError(c, http.StatusNotFound)
+		return
+	}
+
+	var paragraphs []string
+	for _, line := range strings.Split(article.Content, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			paragraphs = append(paragraphs, line)
+		}
+	}
+	data := gin.H{
+		"Title":      article.Title,
+		"URL":        article.URL,
+		"Source":     sourceOf(article.URL),
+		"Paragraphs": paragraphs,
+		"Tables":     keyTables(article.Tables),
+	}
+	if !article.Date.IsZero() {
+		data["Meta"] = gin.H{"PublishDate": formatDate(article.Date)}
+	}
+	if article.Author != "" {
+		data["Author"] = gin.H{"Name": article.Author, "Initial": strings.ToUpper(string([]rune(article.Author)[:1]))}
+	}
+	renderHTML(c, http.StatusOK, "document.html", data)
+}
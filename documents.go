@@ -0,0 +1,786 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Dokumen non-HTML (peraturan perumahan, brosur developer) diunggah sebagai
+// PDF/DOCX lewat POST /api/documents. Teksnya diambil oleh extractor
+// per doctype lalu masuk WAL seperti artikel biasa, dengan Article.DocType
+// untuk filter ?doctype= dan facet di halaman hasil.
+//
+// Extractor bawaan hanya memakai standard library: PDF dengan content
+// stream FlateDecode dan font biasa, DOCX dari word/document.xml. Untuk PDF
+// yang lebih rumit extractor bisa diganti perintah eksternal di config.json,
+// yang menerima file di stdin dan menulis teks ke stdout:
+//
+//	"document_extractors": {"pdf": ["pdftotext", "-layout", "-", "-"]}
+const (
+	DOCTYPE_ARTICLE = "article" // halaman web hasil crawl (DocType kosong)
+	DOCTYPE_PDF     = "pdf"
+	DOCTYPE_DOCX    = "docx"
+
+	DOCTYPE_FACET_SIZE  = 5
+	EXTRACTOR_TIMEOUT   = 30 * time.Second
+	DOCUMENT_TITLE_SIZE = 120 // judul dari baris pertama dipotong sepanjang ini
+)
+
+// Hasil extractor; field selain Text opsional (metadata dokumen)
+type ExtractedDocument struct {
+	Title  string
+	Author string
+	Date   time.Time
+	Text   string // paragraf dipisah "\n" seperti konten hasil crawl
+	Pages  []DocumentPage
+}
+
+type TextExtractor func(data []byte) (*ExtractedDocument, error)
+
+// Extractor bawaan per doctype; document_extractors di config didahulukan
+var documentExtractors = map[string]TextExtractor{
+	DOCTYPE_PDF:  extractPDF,
+	DOCTYPE_DOCX: extractDOCX,
+}
+
+var errNoText = errors.New("no text found in document")
+
+// Doctype artikel; artikel lama tanpa DocType adalah halaman web
+func docTypeOf(article Article) string {
+	if article.DocType == "" {
+		return DOCTYPE_ARTICLE
+	}
+	return article.DocType
+}
+
+// Label doctype untuk badge dan facet
+func docTypeLabel(doctype string) string {
+	switch doctype {
+	case DOCTYPE_ARTICLE:
+		return "Artikel"
+	case DOCTYPE_PDF:
+		return "PDF"
+	case DOCTYPE_DOCX:
+		return "DOCX"
+	}
+	return strings.ToUpper(doctype)
+}
+
+// Doctype terbanyak di hasil pencarian (semua halaman)
+func docTypeFacet(results []SearchResult, size int) []FacetValue {
+	values := make([]string, len(results))
+	for i, result := range results {
+		values[i] = result.DocType
+	}
+	return countFacet(values, size)
+}
+
+// Doctype file dari isinya, fallback ke ekstensi nama file
+func detectDocType(filename string, data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return DOCTYPE_PDF
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")) && bytes.Contains(data, []byte("word/document.xml")):
+		return DOCTYPE_DOCX
+	}
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+}
+
+// Opsi per dokumen yang diunggah
+type ExtractOptions struct {
+	ForceOCR    bool   // OCR walaupun extractor menemukan teks
+	OCRLanguage string // kosong = ocr.language di config
+}
+
+// Ekstraksi teks, lalu OCR untuk PDF yang hampir tanpa teks (lihat ocr.go)
+func extractDocument(doctype string, data []byte, opts ExtractOptions, cfg *Config) (*ExtractedDocument, error) {
+	doc, err := extractText(doctype, data, cfg.DocumentExtractors)
+	if err != nil && err != errNoText {
+		return nil, err
+	}
+	if doctype != DOCTYPE_PDF || !(opts.ForceOCR || cfg.OCR.Enabled && needsOCR(doc)) {
+		return doc, err
+	}
+
+	pages, ocrErr := ocrPDF(data, opts.OCRLanguage, cfg.OCR)
+	if ocrErr != nil {
+		if err == nil {
+			log.Printf("OCR failed, keeping extracted text: %v", ocrErr)
+			return doc, nil
+		}
+		return nil, fmt.Errorf("ocr: %v", ocrErr)
+	}
+	if doc == nil {
+		doc = &ExtractedDocument{}
+	}
+	doc.Pages = pages
+	doc.Text = normalizeParagraphs(pagesText(pages))
+	if doc.Text == "" {
+		return nil, errors.New("no page passed the OCR confidence threshold")
+	}
+	return doc, nil
+}
+
+func extractText(doctype string, data []byte, extractors map[string][]string) (*ExtractedDocument, error) {
+	if command := extractors[doctype]; len(command) > 0 {
+		return runExternalExtractor(command, data)
+	}
+	extractor, exists := documentExtractors[doctype]
+	if !exists {
+		return nil, fmt.Errorf("unsupported document type %q", doctype)
+	}
+	doc, err := extractor(data)
+	if err != nil {
+		return nil, err
+	}
+	doc.Text = normalizeParagraphs(doc.Text)
+	if doc.Text == "" {
+		// Metadata tetap dikembalikan untuk dokumen yang akan di-OCR
+		return doc, errNoText
+	}
+	return doc, nil
+}
+
+// Perintah eksternal: file di stdin, teks di stdout
+func runExternalExtractor(command []string, data []byte) (*ExtractedDocument, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), EXTRACTOR_TIMEOUT)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %v: %s", command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	text := normalizeParagraphs(stdout.String())
+	if text == "" {
+		return nil, errNoText
+	}
+	return &ExtractedDocument{Text: text}, nil
+}
+
+// Rapikan spasi per paragraf dan buang paragraf kosong
+func normalizeParagraphs(text string) string {
+	var paragraphs []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			paragraphs = append(paragraphs, line)
+		}
+	}
+	return strings.Join(paragraphs, "\n")
+}
+
+// Artikel dari dokumen yang diunggah. Judul/penulis/tanggal dari form
+// didahulukan, lalu metadata dokumen, lalu baris pertama teks.
+func documentArticle(doctype string, doc *ExtractedDocument, rawURL, title, author string, date time.Time) Article {
+	if title == "" {
+		title = doc.Title
+	}
+	if title == "" {
+		title = strings.SplitN(doc.Text, "\n", 2)[0]
+		if runes := []rune(title); len(runes) > DOCUMENT_TITLE_SIZE {
+			title = strings.TrimSpace(string(runes[:DOCUMENT_TITLE_SIZE])) + "…"
+		}
+	}
+	if author == "" {
+		author = doc.Author
+	}
+	if date.IsZero() {
+		date = doc.Date
+	}
+	article := Article{
+		Title:   strings.TrimSpace(title),
+		Content: doc.Text,
+		URL:     rawURL,
+		Date:    date.UTC(),
+		Author:  strings.TrimSpace(author),
+		DocType: doctype,
+		Pages:   doc.Pages,
+	}
+	if len(doc.Pages) > 0 && doc.Pages[0].OCR {
+		article.Extraction = "ocr"
+	}
+	return article
+}
+
+// DOCX: paragraf <w:p> dari word/document.xml, metadata dari docProps/core.xml
+func extractDOCX(data []byte) (*ExtractedDocument, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &ExtractedDocument{}
+	found := false
+	for _, file := range archive.File {
+		switch file.Name {
+		case "word/document.xml":
+			found = true
+			content, err := readZipFile(file)
+			if err != nil {
+				return nil, err
+			}
+			if doc.Text, err = docxText(content); err != nil {
+				return nil, err
+			}
+		case "docProps/core.xml":
+			content, err := readZipFile(file)
+			if err != nil {
+				return nil, err
+			}
+			docxProperties(content, doc)
+		}
+	}
+	if !found {
+		return nil, errors.New("word/document.xml not found")
+	}
+	return doc, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+func docxText(content []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	var text, paragraph strings.Builder
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab", "br":
+				paragraph.WriteByte(' ')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteString(paragraph.String())
+				text.WriteByte('\n')
+				paragraph.Reset()
+			}
+		case xml.CharData:
+			if inText {
+				paragraph.Write(t)
+			}
+		}
+	}
+	text.WriteString(paragraph.String())
+	return text.String(), nil
+}
+
+func docxProperties(content []byte, doc *ExtractedDocument) {
+	var props struct {
+		Title   string `xml:"title"`
+		Creator string `xml:"creator"`
+		Created string `xml:"created"`
+	}
+	if err := xml.Unmarshal(content, &props); err != nil {
+		return
+	}
+	doc.Title = strings.TrimSpace(props.Title)
+	doc.Author = strings.TrimSpace(props.Creator)
+	if created, err := time.Parse(time.RFC3339, strings.TrimSpace(props.Created)); err == nil {
+		doc.Date = created
+	}
+}
+
+var (
+	pdfStreamPattern = regexp.MustCompile(`>>\s*stream\r?\n`)
+	pdfLengthPattern = regexp.MustCompile(`/Length\s+(\d+)(\s+\d+\s+R)?`)
+	pdfTitlePattern  = regexp.MustCompile(`/Title\s*(\((?:\\.|[^\\)])*\)|<[0-9A-Fa-f\s]*>)`)
+	pdfAuthorPattern = regexp.MustCompile(`/Author\s*(\((?:\\.|[^\\)])*\)|<[0-9A-Fa-f\s]*>)`)
+	pdfDatePattern   = regexp.MustCompile(`/CreationDate\s*\(D:(\d{8,14})`)
+)
+
+// PDF: operator teks (Tj, TJ, ', ") dari setiap content stream, satu baris
+// per posisi baris baru. Tidak memakai font ToUnicode, jadi PDF dengan font
+// CID/subset (mis. hasil scan atau Word dengan font tertanam) sebaiknya
+// memakai extractor eksternal.
+func extractPDF(data []byte) (*ExtractedDocument, error) {
+	if bytes.Contains(data, []byte("/Encrypt")) {
+		return nil, errors.New("encrypted PDF is not supported by the built-in extractor")
+	}
+
+	doc := &ExtractedDocument{}
+	var text strings.Builder
+	for _, stream := range pdfStreams(data) {
+		if !pdfContentStream(stream.Dict) {
+			continue
+		}
+		content := stream.Data
+		if strings.Contains(stream.Dict, "/FlateDecode") {
+			reader, err := zlib.NewReader(bytes.NewReader(content))
+			if err != nil {
+				continue
+			}
+			// Stream yang terpotong tetap dipakai sejauh yang terbaca
+			content, _ = ioutil.ReadAll(reader)
+			reader.Close()
+		} else if strings.Contains(stream.Dict, "/Filter") {
+			continue
+		}
+		text.WriteString(pdfContentText(content))
+	}
+	doc.Text = text.String()
+
+	if m := pdfTitlePattern.FindSubmatch(data); m != nil {
+		doc.Title = pdfMetaString(m[1])
+	}
+	if m := pdfAuthorPattern.FindSubmatch(data); m != nil {
+		doc.Author = pdfMetaString(m[1])
+	}
+	if m := pdfDatePattern.FindSubmatch(data); m != nil {
+		digits := string(m[1])
+		if date, err := time.ParseInLocation("20060102150405"[:len(digits)], digits, defaultDateLocation); err == nil {
+			doc.Date = date.UTC()
+		}
+	}
+	return doc, nil
+}
+
+type pdfStream struct {
+	Dict string // isi dictionary sebelum kata stream
+	Data []byte // belum di-decode
+}
+
+// Semua stream dalam file, berurutan. Panjang diambil dari /Length kalau
+// langsung berupa angka, kalau tidak sampai endstream.
+func pdfStreams(data []byte) []pdfStream {
+	var streams []pdfStream
+	for _, m := range pdfStreamPattern.FindAllIndex(data, -1) {
+		// Dictionary stream: dari "obj" terakhir sebelum kata stream
+		dict := string(data[bytes.LastIndex(data[:m[0]], []byte("obj"))+1 : m[0]])
+		start := m[1]
+		if length := pdfLengthPattern.FindStringSubmatch(dict); length != nil && length[2] == "" {
+			if n, err := strconv.Atoi(length[1]); err == nil && start+n <= len(data) {
+				streams = append(streams, pdfStream{dict, data[start : start+n]})
+				continue
+			}
+		}
+		end := bytes.Index(data[start:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		// Satu EOL sebelum endstream bukan bagian data
+		stream := data[start : start+end]
+		if bytes.HasSuffix(stream, []byte("\r\n")) {
+			stream = stream[:len(stream)-2]
+		} else if bytes.HasSuffix(stream, []byte("\n")) || bytes.HasSuffix(stream, []byte("\r")) {
+			stream = stream[:len(stream)-1]
+		}
+		streams = append(streams, pdfStream{dict, stream})
+	}
+	return streams
+}
+
+// Stream yang mungkin berisi operator teks: bukan gambar, font, xref atau object stream
+func pdfContentStream(dict string) bool {
+	for _, marker := range []string{"/Image", "/FontFile", "/Length1", "/XRef", "/ObjStm", "/Metadata", "/DCTDecode", "/JPXDecode"} {
+		if strings.Contains(dict, marker) {
+			return false
+		}
+	}
+	return true
+}
+
+func pdfMetaString(raw []byte) string {
+	if raw[0] == '<' {
+		return pdfTextString(pdfHexString(raw[1 : len(raw)-1]))
+	}
+	value, _ := pdfLiteralString(raw, 0)
+	return pdfTextString(value)
+}
+
+// Tokenizer content stream yang hanya peduli string dan operator teks
+func pdfContentText(stream []byte) string {
+	var text strings.Builder
+	var operands [][]byte // string operand terakhir (untuk Tj, ', ")
+	var numbers []float64 // angka operand terakhir (untuk Td/TD dan kerning TJ)
+	var array [][]byte    // isi array TJ
+	inArray := false
+	lineY := math.NaN() // posisi y dari Tm terakhir
+
+	newline := func() {
+		if text.Len() > 0 && !strings.HasSuffix(text.String(), "\n") {
+			text.WriteByte('\n')
+		}
+	}
+	write := func(value []byte) {
+		text.WriteString(pdfTextString(value))
+	}
+
+	for i := 0; i < len(stream); {
+		c := stream[i]
+		switch {
+		case c == '(':
+			value, next := pdfLiteralString(stream, i)
+			if inArray {
+				array = append(array, value)
+			} else {
+				operands = append(operands, value)
+			}
+			i = next
+		case c == '<' && i+1 < len(stream) && stream[i+1] == '<':
+			i += 2 // dictionary inline (mis. properti marked content)
+		case c == '<':
+			end := bytes.IndexByte(stream[i:], '>')
+			if end < 0 {
+				return text.String()
+			}
+			value := pdfHexString(stream[i+1 : i+end])
+			if inArray {
+				array = append(array, value)
+			} else {
+				operands = append(operands, value)
+			}
+			i += end + 1
+		case c == '[':
+			inArray, array = true, nil
+			i++
+		case c == ']':
+			inArray = false
+			i++
+		case c == '%':
+			for i < len(stream) && stream[i] != '\n' && stream[i] != '\r' {
+				i++
+			}
+		case c == '-' || c == '.' || c == '+' || (c >= '0' && c <= '9'):
+			start := i
+			for i < len(stream) && (stream[i] == '-' || stream[i] == '.' || stream[i] == '+' || (stream[i] >= '0' && stream[i] <= '9')) {
+				i++
+			}
+			number, _ := strconv.ParseFloat(string(stream[start:i]), 64)
+			if inArray {
+				// Kerning besar di dalam TJ biasanya spasi antar kata
+				if number < -200 {
+					array = append(array, []byte(" "))
+				}
+			} else {
+				numbers = append(numbers, number)
+			}
+		case unicode.IsLetter(rune(c)) || c == '\'' || c == '"' || c == '*':
+			start := i
+			for i < len(stream) && (unicode.IsLetter(rune(stream[i])) || stream[i] == '\'' || stream[i] == '"' || stream[i] == '*') {
+				i++
+			}
+			switch string(stream[start:i]) {
+			case "Tj":
+				for _, value := range operands {
+					write(value)
+				}
+			case "TJ":
+				for _, value := range array {
+					write(value)
+				}
+				array = nil
+			case "'", "\"":
+				newline()
+				for _, value := range operands {
+					write(value)
+				}
+			case "T*":
+				newline()
+			case "Td", "TD":
+				if len(numbers) >= 2 && numbers[len(numbers)-1] != 0 {
+					newline()
+				} else if text.Len() > 0 {
+					text.WriteByte(' ')
+				}
+			case "Tm":
+				// Sebagian generator memakai Tm untuk setiap potongan teks;
+				// baris baru hanya kalau posisi y berubah
+				if len(numbers) >= 6 && numbers[5] == lineY {
+					text.WriteByte(' ')
+				} else {
+					newline()
+				}
+				if len(numbers) >= 6 {
+					lineY = numbers[5]
+				}
+			}
+			operands, numbers = nil, nil
+		default:
+			i++
+		}
+	}
+	newline()
+	return text.String()
+}
+
+// String literal PDF mulai dari stream[start] == '(', dengan kurung
+// bersarang dan escape; mengembalikan isi dan posisi setelah ')'
+func pdfLiteralString(stream []byte, start int) ([]byte, int) {
+	var value []byte
+	depth := 0
+	for i := start; i < len(stream); i++ {
+		c := stream[i]
+		switch c {
+		case '(':
+			depth++
+			if depth == 1 {
+				continue
+			}
+		case ')':
+			depth--
+			if depth == 0 {
+				return value, i + 1
+			}
+		case '\\':
+			i++
+			if i >= len(stream) {
+				return value, i
+			}
+			switch e := stream[i]; e {
+			case 'n':
+				value = append(value, '\n')
+			case 'r':
+				value = append(value, '\r')
+			case 't':
+				value = append(value, '\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// Sambungan baris
+			default:
+				if e >= '0' && e <= '7' {
+					octal := 0
+					for j := 0; j < 3 && i < len(stream) && stream[i] >= '0' && stream[i] <= '7'; j++ {
+						octal = octal*8 + int(stream[i]-'0')
+						i++
+					}
+					i--
+					value = append(value, byte(octal))
+				} else {
+					value = append(value, e)
+				}
+			}
+			continue
+		}
+		value = append(value, c)
+	}
+	return value, len(stream)
+}
+
+func pdfHexString(hex []byte) []byte {
+	var digits []byte
+	for _, c := range hex {
+		if !unicode.IsSpace(rune(c)) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	value := make([]byte, len(digits)/2)
+	for i := range value {
+		b, err := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		if err != nil {
+			return nil
+		}
+		value[i] = byte(b)
+	}
+	return value
+}
+
+// Byte string PDF ke teks: UTF-16BE dengan BOM, selain itu Latin-1
+// (mendekati PDFDocEncoding/WinAnsi). Karakter kontrol dibuang, glyph ID
+// dari font CID biasanya jatuh di sini dan ikut terbuang.
+func pdfTextString(value []byte) string {
+	var runes []rune
+	if len(value) >= 2 && value[0] == 0xfe && value[1] == 0xff {
+		units := make([]uint16, 0, len(value)/2)
+		for i := 2; i+1 < len(value); i += 2 {
+			units = append(units, uint16(value[i])<<8|uint16(value[i+1]))
+		}
+		runes = utf16.Decode(units)
+	} else {
+		runes = make([]rune, len(value))
+		for i, b := range value {
+			runes[i] = rune(b)
+		}
+	}
+
+	var text strings.Builder
+	for _, r := range runes {
+		if r == '\t' || r == '\n' || r == '\r' {
+			text.WriteByte(' ')
+		} else if unicode.IsPrint(r) {
+			text.WriteRune(r)
+		}
+	}
+	return text.String()
+}
+
+// POST /api/documents (multipart): file, url, opsional title, author, date,
+// ocr=1 (paksa OCR) dan ocr_lang. url adalah alamat publik dokumen, dipakai
+// sebagai identitas seperti artikel.
+func uploadDocumentHandler(c *gin.Context) {
+	cfg := requestConfig(c)
+	rawURL := strings.TrimSpace(c.PostForm("url"))
+	if rawURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url is required"})
+		return
+	}
+	if err := validateURL(rawURL); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url " + err.Message})
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if limit := cfg.Validation.MaxUploadBytes; limit > 0 && header.Size > limit {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("file is %d bytes, limit is %d", header.Size, limit)})
+		return
+	}
+	var date time.Time
+	if value := c.PostForm("date"); value != "" {
+		if date, err = parseArticleDate(value, nil); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date"})
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := ioutil.ReadAll(file)
+	file.Close()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	doctype := detectDocType(header.Filename, data)
+	if allowed := cfg.Validation.AllowedDocTypes; len(allowed) > 0 && !containsString(allowed, doctype) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fmt.Sprintf("document type %q is not allowed", doctype), "doctype": doctype})
+		return
+	}
+	doc, err := extractDocument(doctype, data, ExtractOptions{
+		ForceOCR:    c.PostForm("ocr") == "1",
+		OCRLanguage: c.PostForm("ocr_lang"),
+	}, cfg)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "doctype": doctype})
+		return
+	}
+	article := documentArticle(doctype, doc, rawURL, c.PostForm("title"), c.PostForm("author"), date)
+	article.Provenance = requestProvenance(c, ProvenanceUpload, nil)
+	article.Provenance.ImportFile = header.Filename
+	if errs := cfg.Validation.validateArticle(&article, cfg.Schema); len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": fieldErrorsString(errs), "errors": errs})
+		return
+	}
+
+	accepted, err := auditActor(c).appendDocuments(WalRecord{Op: WalUpdate, Article: &article})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	scheduleEnrichment()
+	skipped := []int{}
+	for _, page := range article.Pages {
+		if page.Skipped {
+			skipped = append(skipped, page.Number)
+		}
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"accepted":      accepted[0].Seq,
+		"doctype":       doctype,
+		"title":         article.Title,
+		"words":         countWords(article.Content),
+		"pages":         len(article.Pages),
+		"ocr":           article.Extraction == "ocr",
+		"skipped_pages": skipped,
+		"pending":       len(documentLog.Pending()),
+	})
+}
+
+// searchctl extract <file>: cek hasil extractor sebelum mengunggah
+func extractCommand(args []string) int {
+	fs := flag.NewFlagSet("extract", flag.ExitOnError)
+	doctype := fs.String("doctype", "", "pdf atau docx (default dari isi file)")
+	ocr := fs.Bool("ocr", false, "paksa OCR (PDF)")
+	ocrLang := fs.String("ocr-lang", "", "bahasa tesseract (default ocr.language)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: searchctl extract [--doctype pdf|docx] [--ocr] [--ocr-lang ind] <file>")
+		return 2
+	}
+
+	data, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *doctype == "" {
+		*doctype = detectDocType(fs.Arg(0), data)
+	}
+	doc, err := extractDocument(*doctype, data, ExtractOptions{ForceOCR: *ocr, OCRLanguage: *ocrLang}, currentConfig())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	article := documentArticle(*doctype, doc, "", "", "", time.Time{})
+	fmt.Printf("doctype: %s\ntitle:   %s\n", *doctype, article.Title)
+	if article.Author != "" {
+		fmt.Printf("author:  %s\n", article.Author)
+	}
+	if !article.Date.IsZero() {
+		fmt.Printf("date:    %s\n", article.Date.Format("2006-01-02"))
+	}
+	fmt.Printf("words:   %d\n", countWords(article.Content))
+	for _, page := range article.Pages {
+		status := ""
+		if page.Skipped {
+			status = ", skipped"
+		}
+		fmt.Printf("page %d: %d words, confidence %.1f%s\n", page.Number, countWords(page.Text), page.Confidence, status)
+	}
+	fmt.Printf("\n%s\n", article.Content)
+	return 0
+}
@@ -0,0 +1,484 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DocValues menyimpan field untuk sort/filter dalam array kolom berindeks
+// docID, sehingga sort dan filter tidak perlu membuka dokumen lengkap.
+type DocValues struct {
+	Dates          []int64 // unix seconds, 0 = tanpa tanggal
+	Sources        []string
+	Languages      []string
+	Locations      []string
+	Prices         []int64  // rupiah, 0 = tanpa harga
+	Authors        []string // nama penulis yang sudah dinormalisasi, lihat authors.go
+	Categories     []string // kategori terpadu dari breadcrumb, lihat categories.go
+	DocTypes       []string // article, pdf, docx, lihat documents.go
+	Words          []int    // jumlah kata konten
+	ReadingMinutes []int    // estimasi waktu baca dalam menit, dari Words
+	Visibilities   []string // public/internal, lihat visibility.go
+	// Ada dokumen yang bukan public; kalau tidak, filter visibility dilewati
+	Restricted bool
+
+	// Field schema tambahan per nama field, lihat schema.go
+	Keywords map[string][]string
+	Numbers  map[string][]float64 // numeric dan date (unix seconds), NaN = kosong
+	Points   map[string][]GeoPoint
+
+	// Berubah setiap isi kolom berubah, dipakai sebagai kunci cache filter
+	Generation uint64
+}
+
+// Filter pencarian yang dievaluasi langsung di doc values
+type SearchFilters struct {
+	Source   string
+	Language string
+	Location string
+	MinPrice int64
+	MaxPrice int64
+	Since    time.Time
+	// Rentang tanggal terbit (from/to), To inklusif sampai akhir harinya;
+	// dokumen tanpa tanggal tidak lolos
+	From     time.Time
+	To       time.Time
+	Author   string // slug atau nama penulis
+	Category string // slug atau nama kategori
+	DocType  string // article, pdf, docx
+	MinWords int    // buang artikel pendek/stub
+	// Filter field schema tambahan (f.<nama>=nilai), lihat schema.go
+	Fields map[string]string
+}
+
+type SearchOptions struct {
+	Sort     string // relevance, date_desc, date_asc, price_asc, price_desc
+	Filters  SearchFilters
+	MatchAll bool // semua term harus ada (op=and)
+	Explain  bool // tampilkan query plan (explain=1)
+
+	// Ikut cari di arsip (include_archive=1), lihat archive.go
+	IncludeArchive bool
+	// Jangan teruskan ke remote_instances (local=1), dipakai untuk query
+	// yang datang dari instance lain, lihat remote.go
+	Local bool
+	// Jangan gabungkan salinan sindikasi dan URL yang sama (collapse=false)
+	NoCollapse bool
+	// Jangan ikut cari terjemahan query (translate=false), lihat translation.go
+	NoTranslate bool
+	// Jangan pecah kata gabungan di query (segment=false), lihat segmentation.go
+	NoSegment bool
+	// Jangan koreksi ejaan query (spell=false), lihat spelling.go
+	NoSpell bool
+
+	// Feature flag yang aktif untuk klien ini (lihat featureflags.go)
+	Features []string
+	// Visibility dokumen yang boleh dilihat klien ini, dari API key-nya
+	// (lihat visibility.go); kosong = hanya public
+	Scopes []string
+	// Generasi index yang di-pin klien (X-Index-Generation), 0 = terbaru;
+	// lihat generations.go
+	Generation int
+
+	// Snapshot config untuk request ini (requestConfig); nil diisi
+	// currentConfig sekali di awal pencarian, lihat withConfig
+	Config *Config
+}
+
+// Pastikan seluruh jalur pencarian memakai satu snapshot config
+func (o SearchOptions) withConfig() SearchOptions {
+	if o.Config == nil {
+		o.Config = currentConfig()
+	}
+	return o
+}
+
+func (o SearchOptions) Feature(name string) bool {
+	for _, feature := range o.Features {
+		if feature == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Kecepatan baca rata-rata untuk estimasi waktu baca
+const READING_WORDS_PER_MINUTE = 200
+
+// Nilai sort yang valid
+const (
+	SortRelevance = "relevance"
+	SortDateDesc  = "date_desc"
+	SortDateAsc   = "date_asc"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+)
+
+var (
+	pricePattern = regexp.MustCompile(`(?i)\bRp\.?\s?(\d{1,3}(?:[.,]\d{3})*(?:[.,]\d+)?)\s*(triliun|miliar|milyar|juta|jt|ribu|rb)?\b`)
+
+	priceMultipliers = map[string]float64{
+		"triliun": 1e12, "miliar": 1e9, "milyar": 1e9,
+		"juta": 1e6, "jt": 1e6, "ribu": 1e3, "rb": 1e3,
+	}
+
+	// Lokasi yang dikenali, urutan panjang dulu supaya "Tangerang Selatan"
+	// menang atas "Tangerang"
+	knownLocations = []string{
+		"Tangerang Selatan", "Jakarta Selatan", "Jakarta Barat", "Jakarta Timur",
+		"Jakarta Utara", "Jakarta Pusat", "Yogyakarta", "Tangerang", "Jakarta",
+		"Bekasi", "Bogor", "Depok", "Bandung", "Surabaya", "Semarang", "Medan",
+		"Makassar", "Denpasar", "Bali", "Malang", "Solo", "Karawang", "Cikarang",
+		"Serpong", "Bintaro", "Cibubur", "Sidoarjo", "Batam", "Palembang",
+		"Balikpapan", "Samarinda", "Pekanbaru", "Lampung", "Karanganyar", "IKN",
+	}
+
+	locationPattern = buildLocationPattern()
+
+	sourceNames = map[string]string{
+		"artikel.rumah123.com":   "rumah123",
+		"propertiterkini.com":    "propertiterkini",
+		"propertyandthecity.com": "propertyandthecity",
+	}
+)
+
+// Bangun doc values untuk semua dokumen (termasuk yang tidak diindex,
+// supaya docID tetap sejajar)
+func buildDocValues(articles []Article, schema IndexSchema) *DocValues {
+	dv := &DocValues{
+		Dates:          make([]int64, len(articles)),
+		Sources:        make([]string, len(articles)),
+		Languages:      make([]string, len(articles)),
+		Locations:      make([]string, len(articles)),
+		Prices:         make([]int64, len(articles)),
+		Authors:        make([]string, len(articles)),
+		Categories:     make([]string, len(articles)),
+		DocTypes:       make([]string, len(articles)),
+		Words:          make([]int, len(articles)),
+		ReadingMinutes: make([]int, len(articles)),
+		Visibilities:   make([]string, len(articles)),
+	}
+
+	for i, article := range articles {
+		if !article.Date.IsZero() {
+			dv.Dates[i] = article.Date.Unix()
+		}
+		dv.Sources[i] = sourceOf(article.URL)
+		dv.Languages[i] = article.Language
+		dv.Locations[i] = extractLocation(article.Title, article.Content)
+		dv.Prices[i] = extractPrice(article.Title + " " + article.Content)
+		dv.Authors[i] = normalizeAuthor(article.Author)
+		dv.Categories[i] = categorize(article.Breadcrumb)
+		dv.DocTypes[i] = docTypeOf(article)
+		dv.Words[i] = countWords(article.Content)
+		dv.ReadingMinutes[i] = readingMinutes(dv.Words[i])
+		dv.Visibilities[i] = article.visibility()
+		if dv.Visibilities[i] != VisibilityPublic {
+			dv.Restricted = true
+		}
+	}
+	schema.buildFieldValues(dv, articles)
+	dv.Generation = dv.fingerprint()
+
+	return dv
+}
+
+// Salinan doc values untuk n dokumen dengan baris docIDs diganti nilai dari
+// articles (articles[i] untuk docIDs[i]). Kolom lama tidak diubah karena
+// masih dipakai index sebelumnya, lihat incremental.go.
+func (dv *DocValues) withRows(n int, docIDs []int, articles []Article, schema IndexSchema) *DocValues {
+	rows := buildDocValues(articles, schema)
+	next := &DocValues{
+		Dates:          spliceInt64s(dv.Dates, n, docIDs, rows.Dates),
+		Sources:        spliceStrings(dv.Sources, n, docIDs, rows.Sources),
+		Languages:      spliceStrings(dv.Languages, n, docIDs, rows.Languages),
+		Locations:      spliceStrings(dv.Locations, n, docIDs, rows.Locations),
+		Prices:         spliceInt64s(dv.Prices, n, docIDs, rows.Prices),
+		Authors:        spliceStrings(dv.Authors, n, docIDs, rows.Authors),
+		Categories:     spliceStrings(dv.Categories, n, docIDs, rows.Categories),
+		DocTypes:       spliceStrings(dv.DocTypes, n, docIDs, rows.DocTypes),
+		Words:          spliceInts(dv.Words, n, docIDs, rows.Words),
+		ReadingMinutes: spliceInts(dv.ReadingMinutes, n, docIDs, rows.ReadingMinutes),
+		Visibilities:   spliceStrings(dv.Visibilities, n, docIDs, rows.Visibilities),
+		Restricted:     dv.Restricted || rows.Restricted,
+	}
+
+	// Field schema; kolom field yang baru dipetakan kosong untuk dokumen lain
+	for name, column := range rows.Keywords {
+		if next.Keywords == nil {
+			next.Keywords = make(map[string][]string)
+		}
+		next.Keywords[name] = spliceStrings(dv.Keywords[name], n, docIDs, column)
+	}
+	for name, column := range rows.Numbers {
+		if next.Numbers == nil {
+			next.Numbers = make(map[string][]float64)
+		}
+		merged := make([]float64, n)
+		for i := copy(merged, dv.Numbers[name]); i < n; i++ {
+			merged[i] = math.NaN()
+		}
+		for i, docID := range docIDs {
+			merged[docID] = column[i]
+		}
+		next.Numbers[name] = merged
+	}
+	for name, column := range rows.Points {
+		if next.Points == nil {
+			next.Points = make(map[string][]GeoPoint)
+		}
+		merged := make([]GeoPoint, n)
+		for i := copy(merged, dv.Points[name]); i < n; i++ {
+			merged[i] = GeoPoint{Lat: math.NaN(), Lon: math.NaN()}
+		}
+		for i, docID := range docIDs {
+			merged[docID] = column[i]
+		}
+		next.Points[name] = merged
+	}
+	next.Generation = next.fingerprint()
+	return next
+}
+
+func spliceStrings(column []string, n int, docIDs []int, rows []string) []string {
+	merged := make([]string, n)
+	copy(merged, column)
+	for i, docID := range docIDs {
+		merged[docID] = rows[i]
+	}
+	return merged
+}
+
+func spliceInt64s(column []int64, n int, docIDs []int, rows []int64) []int64 {
+	merged := make([]int64, n)
+	copy(merged, column)
+	for i, docID := range docIDs {
+		merged[docID] = rows[i]
+	}
+	return merged
+}
+
+func spliceInts(column []int, n int, docIDs []int, rows []int) []int {
+	merged := make([]int, n)
+	copy(merged, column)
+	for i, docID := range docIDs {
+		merged[docID] = rows[i]
+	}
+	return merged
+}
+
+func buildLocationPattern() *regexp.Regexp {
+	alternatives := make([]string, len(knownLocations))
+	for i, location := range knownLocations {
+		alternatives[i] = regexp.QuoteMeta(location)
+	}
+	return regexp.MustCompile(`(?i)\b(` + strings.Join(alternatives, "|") + `)\b`)
+}
+
+// Nama source dari host URL
+func sourceOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	host := normalizeDomain(u.Hostname())
+	if name, exists := sourceNames[host]; exists {
+		return name
+	}
+	return host
+}
+
+// Jumlah kata (deretan karakter non-spasi), tanpa membuat slice seperti strings.Fields
+func countWords(text string) int {
+	words, inWord := 0, false
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			inWord = false
+		} else if !inWord {
+			inWord = true
+			words++
+		}
+	}
+	return words
+}
+
+// Estimasi waktu baca dalam menit (dibulatkan), minimal 1
+func readingMinutes(words int) int {
+	minutes := (words + READING_WORDS_PER_MINUTE/2) / READING_WORDS_PER_MINUTE
+	if minutes < 1 {
+		return 1
+	}
+	return minutes
+}
+
+// Ambil harga pertama yang disebut (Rp 350.000.000, Rp 1,5 miliar, Rp500 juta)
+func extractPrice(text string) int64 {
+	m := pricePattern.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+
+	number, unit := m[1], strings.ToLower(m[2])
+	if unit == "" {
+		// Tanpa satuan: titik/koma adalah pemisah ribuan
+		number = strings.NewReplacer(".", "", ",", "").Replace(number)
+		value, err := strconv.ParseInt(number, 10, 64)
+		if err != nil {
+			return 0
+		}
+		return value
+	}
+
+	// Dengan satuan: koma adalah desimal (1,5 miliar)
+	number = strings.ReplaceAll(number, ",", ".")
+	if strings.Count(number, ".") > 1 {
+		return 0
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0
+	}
+	return int64(value * priceMultipliers[unit])
+}
+
+// Lokasi yang paling sering disebut, sebutan di judul dihitung lebih berat
+func extractLocation(title, content string) string {
+	counts := make(map[string]int)
+	for _, m := range locationPattern.FindAllString(title, -1) {
+		counts[strings.ToLower(m)] += 3
+	}
+	for _, m := range locationPattern.FindAllString(content, -1) {
+		counts[strings.ToLower(m)]++
+	}
+
+	best, bestCount := "", 0
+	for _, location := range knownLocations {
+		if n := counts[strings.ToLower(location)]; n > bestCount {
+			best, bestCount = location, n
+		}
+	}
+	return best
+}
+
+// Cek dokumen terhadap filter tanpa membuka dokumennya
+func (dv *DocValues) Match(docID int, f SearchFilters, schema IndexSchema) bool {
+	for _, clause := range f.clauses(schema) {
+		if !clause.match(dv, docID) {
+			return false
+		}
+	}
+	return true
+}
+
+// Satu klausa filter dan predikatnya terhadap doc values
+type filterClause struct {
+	key   string
+	match func(dv *DocValues, docID int) bool
+}
+
+func (f SearchFilters) clauses(schema IndexSchema) []filterClause {
+	var clauses []filterClause
+	if f.Source != "" {
+		clauses = append(clauses, filterClause{"source=" + f.Source, func(dv *DocValues, docID int) bool {
+			return dv.Sources[docID] == f.Source
+		}})
+	}
+	if f.Language != "" {
+		clauses = append(clauses, filterClause{"lang=" + f.Language, func(dv *DocValues, docID int) bool {
+			return dv.Languages[docID] == f.Language
+		}})
+	}
+	if f.Location != "" {
+		clauses = append(clauses, filterClause{"location=" + strings.ToLower(f.Location), func(dv *DocValues, docID int) bool {
+			return strings.EqualFold(dv.Locations[docID], f.Location)
+		}})
+	}
+	if f.MinPrice > 0 {
+		clauses = append(clauses, filterClause{fmt.Sprintf("min_price=%d", f.MinPrice), func(dv *DocValues, docID int) bool {
+			return dv.Prices[docID] >= f.MinPrice
+		}})
+	}
+	if f.MaxPrice > 0 {
+		clauses = append(clauses, filterClause{fmt.Sprintf("max_price=%d", f.MaxPrice), func(dv *DocValues, docID int) bool {
+			return dv.Prices[docID] != 0 && dv.Prices[docID] <= f.MaxPrice
+		}})
+	}
+	if f.Author != "" {
+		slug := authorSlug(f.Author)
+		clauses = append(clauses, filterClause{"author=" + slug, func(dv *DocValues, docID int) bool {
+			return dv.Authors[docID] != "" && authorSlug(dv.Authors[docID]) == slug
+		}})
+	}
+	if f.Category != "" {
+		slug := categorySlug(f.Category)
+		clauses = append(clauses, filterClause{"category=" + slug, func(dv *DocValues, docID int) bool {
+			return dv.Categories[docID] != "" && categorySlug(dv.Categories[docID]) == slug
+		}})
+	}
+	if f.DocType != "" {
+		clauses = append(clauses, filterClause{"doctype=" + f.DocType, func(dv *DocValues, docID int) bool {
+			return dv.DocTypes[docID] == f.DocType
+		}})
+	}
+	if f.MinWords > 0 {
+		clauses = append(clauses, filterClause{fmt.Sprintf("min_words=%d", f.MinWords), func(dv *DocValues, docID int) bool {
+			return dv.Words[docID] >= f.MinWords
+		}})
+	}
+	if !f.Since.IsZero() {
+		since := f.Since.Unix()
+		clauses = append(clauses, filterClause{fmt.Sprintf("since=%d", since), func(dv *DocValues, docID int) bool {
+			return dv.Dates[docID] >= since
+		}})
+	}
+	if !f.From.IsZero() {
+		from := f.From.Unix()
+		clauses = append(clauses, filterClause{fmt.Sprintf("from=%d", from), func(dv *DocValues, docID int) bool {
+			return dv.Dates[docID] >= from
+		}})
+	}
+	if !f.To.IsZero() {
+		until := f.To.AddDate(0, 0, 1).Unix()
+		clauses = append(clauses, filterClause{fmt.Sprintf("to=%d", until), func(dv *DocValues, docID int) bool {
+			return dv.Dates[docID] != 0 && dv.Dates[docID] < until
+		}})
+	}
+	return append(clauses, schema.fieldClauses(f.Fields)...)
+}
+
+// Urutkan hasil berdasarkan doc values; dokumen tanpa nilai selalu di akhir
+func (dv *DocValues) Sort(results []SearchResult, sortBy string) {
+	var column []int64
+	desc := false
+
+	switch sortBy {
+	case SortDateDesc:
+		column, desc = dv.Dates, true
+	case SortDateAsc:
+		column = dv.Dates
+	case SortPriceDesc:
+		column, desc = dv.Prices, true
+	case SortPriceAsc:
+		column = dv.Prices
+	default:
+		return
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := column[results[i].DocID], column[results[j].DocID]
+		if a == 0 || b == 0 {
+			return a != 0
+		}
+		if desc {
+			return a > b
+		}
+		return a < b
+	})
+}
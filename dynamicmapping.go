@@ -0,0 +1,326 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Pemetaan otomatis field yang tidak ada di schema index. Crawler versi baru
+// bisa menambah metadata (mis. "harga_per_meter" atau "tipe_unit") sebelum
+// schema di config.json diperbarui; field seperti ini tidak dibuang diam-diam.
+//
+//	"schema": {"dynamic": "map"}
+//
+// Mode strict (default) menolak record ingestion dengan field tidak dikenal.
+// Mode map memetakan field menurut tipe nilai pertama yang terlihat dan
+// menyimpannya di dynamic_fields.json; nilai berikutnya harus bertipe sama.
+// Mode ignore menyimpan field di corpus tanpa mengindexnya.
+//
+// Key JSON artikel di luar field Article (mis. "tipe_unit" di tingkat atas)
+// dipindah ke Article.Fields saat dibaca, jadi ikut aturan yang sama.
+const (
+	DynamicStrict = "strict"
+	DynamicMap    = "map"
+	DynamicIgnore = "ignore"
+
+	DYNAMIC_FIELDS_FILE = "dynamic_fields.json"
+
+	// String yang lebih panjang dari ini dipetakan sebagai text, bukan keyword
+	DYNAMIC_KEYWORD_MAX_LENGTH = 256
+	DYNAMIC_KEYWORD_MAX_WORDS  = 5
+)
+
+type DynamicMappings struct {
+	mu     sync.RWMutex
+	path   string
+	fields []SchemaField
+}
+
+var dynamicMappings = &DynamicMappings{path: DYNAMIC_FIELDS_FILE}
+
+// Key JSON field Article, key lain masuk ke Article.Fields
+var articleJSONKeys = jsonKeys(reflect.TypeOf(Article{}))
+
+func loadDynamicMappings(path string) (*DynamicMappings, error) {
+	dm := &DynamicMappings{path: path}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return dm, nil
+		}
+		return nil, err
+	}
+
+	var file struct {
+		Fields []SchemaField `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	dm.fields = file.Fields
+	return dm, nil
+}
+
+func (dm *DynamicMappings) Field(name string) (SchemaField, bool) {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	for _, field := range dm.fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return SchemaField{}, false
+}
+
+func (dm *DynamicMappings) List() []SchemaField {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	return append([]SchemaField(nil), dm.fields...)
+}
+
+// Daftarkan field baru lalu simpan; field yang sudah terpetakan tidak diubah
+func (dm *DynamicMappings) add(fields []SchemaField) error {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	added := false
+	for _, field := range fields {
+		exists := false
+		for _, mapped := range dm.fields {
+			if mapped.Name == field.Name {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			dm.fields = append(dm.fields, field)
+			added = true
+		}
+	}
+	if !added {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(map[string][]SchemaField{"fields": dm.fields}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dm.path, data, 0644)
+}
+
+// Tipe field dari nilai JSON-nya: angka jadi numeric, tanggal jadi date,
+// {"lat", "lon"} jadi geo, string pendek jadi keyword dan sisanya text.
+// Boolean, array dan object lain tidak bisa dipetakan.
+func inferFieldType(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case float64:
+		return FieldNumeric, true
+	case string:
+		if _, err := parseFieldValue(SchemaField{Type: FieldDate}, v); err == nil {
+			return FieldDate, true
+		}
+		if len(v) <= DYNAMIC_KEYWORD_MAX_LENGTH && !strings.ContainsAny(v, "\n") && len(strings.Fields(v)) <= DYNAMIC_KEYWORD_MAX_WORDS {
+			return FieldKeyword, true
+		}
+		return FieldText, true
+	case map[string]interface{}:
+		if _, err := parseFieldValue(SchemaField{Type: FieldGeo}, v); err == nil {
+			return FieldGeo, true
+		}
+	}
+	return "", false
+}
+
+func dynamicField(name, fieldType string) SchemaField {
+	field := SchemaField{Name: name, Type: fieldType, Indexed: true, Stored: true}
+	if fieldType == FieldText {
+		field.Analyzer = AnalyzerLanguage
+	}
+	return field
+}
+
+// Nama tipe JSON untuk pesan error
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	}
+	return "null"
+}
+
+// Field tidak dikenal saat validasi, sesuai mode dynamic
+func (s IndexSchema) unknownField(name string, value interface{}) *FieldError {
+	switch s.Dynamic {
+	case DynamicIgnore:
+		return nil
+	case DynamicMap:
+		if _, ok := inferFieldType(value); ok || value == nil {
+			return nil
+		}
+		return &FieldError{Field: name, Rule: RuleUnknownField,
+			Message: fmt.Sprintf("cannot be mapped automatically from a %s value", jsonKind(value))}
+	}
+	return &FieldError{Field: name, Rule: RuleUnknownField, Message: "is not declared in the index schema"}
+}
+
+// Field yang akan dipetakan otomatis harus bertipe sama di semua record
+// satu batch; seen mencatat tipe dari record valid sebelumnya
+func (s IndexSchema) checkDynamic(article *Article, seen map[string]string) []FieldError {
+	if s.Dynamic != DynamicMap {
+		return nil
+	}
+	var errs []FieldError
+	inferred := make(map[string]string)
+	for _, name := range sortedFieldNames(article.Fields) {
+		if _, exists := s.Field(name); exists {
+			continue
+		}
+		fieldType, ok := inferFieldType(article.Fields[name])
+		if !ok {
+			continue
+		}
+		if previous, exists := seen[name]; exists && !compatibleTypes(previous, fieldType) {
+			errs = append(errs, FieldError{Field: name, Rule: RuleType,
+				Message: fmt.Sprintf("is a %s value, earlier records in this batch map it as %s", fieldType, previous)})
+			continue
+		}
+		inferred[name] = fieldType
+	}
+	if len(errs) == 0 {
+		for name, fieldType := range inferred {
+			if _, exists := seen[name]; !exists {
+				seen[name] = fieldType
+			}
+		}
+	}
+	return errs
+}
+
+// String keyword dan text saling cocok; nilai pertama tetap menentukan tipe
+func compatibleTypes(a, b string) bool {
+	if a == b {
+		return true
+	}
+	return (a == FieldKeyword || a == FieldText) && (b == FieldKeyword || b == FieldText)
+}
+
+// Petakan field tidak dikenal di corpus (mode map). Dokumen diurutkan per
+// docID, jadi nilai pertama yang terlihat menentukan tipe.
+func (s IndexSchema) mapDynamic(articles []Article) {
+	if s.Dynamic != DynamicMap {
+		return
+	}
+	var fields []SchemaField
+	seen := make(map[string]bool)
+	for _, article := range articles {
+		for _, name := range sortedFieldNames(article.Fields) {
+			if seen[name] {
+				continue
+			}
+			if _, builtin := builtinField(name); builtin {
+				continue
+			}
+			if _, exists := s.Field(name); exists {
+				continue
+			}
+			if fieldType, ok := inferFieldType(article.Fields[name]); ok {
+				seen[name] = true
+				fields = append(fields, dynamicField(name, fieldType))
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+	if err := dynamicMappings.add(fields); err != nil {
+		log.Printf("Error saving %s: %v", dynamicMappings.path, err)
+	}
+	for _, field := range fields {
+		log.Printf("Mapped new field %q as %s", field.Name, field.Type)
+	}
+}
+
+func sortedFieldNames(fields map[string]interface{}) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Key JSON dari tag struct
+func jsonKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		if name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// Key JSON yang bukan field Article disimpan di Fields supaya metadata dari
+// crawler versi baru tidak hilang saat corpus dibaca atau di-ingest
+func (a *Article) UnmarshalJSON(data []byte) error {
+	type plain Article
+	var article plain
+	if err := json.Unmarshal(data, &article); err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for key, value := range raw {
+		if articleJSONKeys[key] {
+			continue
+		}
+		if _, exists := article.Fields[key]; exists {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal(value, &v); err != nil {
+			return err
+		}
+		if article.Fields == nil {
+			article.Fields = make(map[string]interface{})
+		}
+		article.Fields[key] = v
+	}
+
+	*a = Article(article)
+	return nil
+}
+
+func init() {
+	dm, err := loadDynamicMappings(DYNAMIC_FIELDS_FILE)
+	if err != nil {
+		log.Printf("Error loading %s: %v", DYNAMIC_FIELDS_FILE, err)
+		return
+	}
+	dynamicMappings = dm
+}
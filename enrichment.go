@@ -0,0 +1,471 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Enrichment: dokumen baru diperkaya lewat lookup eksternal, misalnya
+// geocoding lokasi atau data developer dari registri perusahaan. Setiap stage
+// di config.json memakai satu provider:
+//
+//	"enrichment": [
+//	  {"name": "geo", "provider": "nominatim", "rate_per_minute": 60},
+//	  {"name": "company", "provider": "http", "url": "http://registry.local/lookup", "rate_per_minute": 30}
+//	]
+//
+// Hasilnya disimpan di Article.Enrichment per nama stage beserta asalnya
+// (provider, endpoint, versi stage, waktu lookup), jadi ikut tersimpan di
+// articles.json lewat WAL seperti perubahan dokumen lain. Job enrich
+// berjalan setelah ingest dan secara berkala, lalu memperkaya dokumen yang
+// belum punya hasil untuk stage yang aktif atau hasilnya dari versi stage
+// lain. Panggilan ke satu provider dibatasi rate_per_minute, dibagi semua
+// stage yang memakai provider itu. Lookup yang gagal dicatat di job dan
+// dicoba lagi di run berikutnya. Provider lain cukup mengimplementasi
+// Enricher dan didaftarkan di enrichmentProviders.
+const (
+	JobEnrich = "enrich"
+
+	ENRICHMENT_NOMINATIM = "nominatim"
+	ENRICHMENT_HTTP      = "http"
+
+	NOMINATIM_URL          = "https://nominatim.openstreetmap.org/search"
+	ENRICH_RATE_PER_MINUTE = 60
+	ENRICH_TIMEOUT         = 10 * time.Second
+	ENRICH_INTERVAL        = 15 * time.Minute
+	ENRICH_BATCH           = 50 // dokumen per append ke WAL
+)
+
+type EnrichmentStage struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	URL      string `json:"url"`
+	APIKey   string `json:"api_key"`
+	// Batas panggilan per menit ke provider ini (0 = ENRICH_RATE_PER_MINUTE)
+	RatePerMinute int `json:"rate_per_minute"`
+	// Naikkan untuk memperkaya ulang semua dokumen dengan stage ini
+	Version int `json:"version"`
+}
+
+// Hasil satu stage untuk satu dokumen
+type Enrichment struct {
+	Provider  string                 `json:"provider"`
+	Source    string                 `json:"source"` // endpoint yang dipanggil
+	Version   int                    `json:"version"`
+	Data      map[string]interface{} `json:"data,omitempty"` // kosong = tidak ada yang bisa dicari
+	FetchedAt time.Time              `json:"fetched_at"`
+}
+
+// Provider lookup eksternal. errNothingToEnrich berarti dokumen tidak punya
+// input untuk provider ini (mis. tanpa lokasi); hasilnya tetap dicatat
+// supaya tidak dicoba lagi.
+type Enricher interface {
+	Name() string
+	Source() string
+	Enrich(ctx context.Context, article Article) (map[string]interface{}, error)
+}
+
+var errNothingToEnrich = errors.New("nothing to enrich")
+
+var enrichmentProviders = map[string]func(EnrichmentStage) Enricher{
+	ENRICHMENT_NOMINATIM: newNominatim,
+	ENRICHMENT_HTTP:      newHTTPEnricher,
+}
+
+func enricher(stage EnrichmentStage) (Enricher, error) {
+	newProvider, exists := enrichmentProviders[stage.Provider]
+	if !exists {
+		return nil, fmt.Errorf("unknown enrichment provider %q", stage.Provider)
+	}
+	return newProvider(stage), nil
+}
+
+// Nama stage unik dan provider terdaftar
+func validateEnrichmentStages(stages []EnrichmentStage) error {
+	seen := make(map[string]bool, len(stages))
+	for i, stage := range stages {
+		if stage.Name == "" {
+			return fmt.Errorf("stage %d has no name", i)
+		}
+		if seen[stage.Name] {
+			return fmt.Errorf("duplicate stage %q", stage.Name)
+		}
+		seen[stage.Name] = true
+		if _, exists := enrichmentProviders[stage.Provider]; !exists {
+			return fmt.Errorf("stage %q: unknown provider %q", stage.Name, stage.Provider)
+		}
+		if stage.Provider == ENRICHMENT_HTTP && stage.URL == "" {
+			return fmt.Errorf("stage %q: url is required", stage.Name)
+		}
+		if stage.RatePerMinute < 0 {
+			return fmt.Errorf("stage %q: rate_per_minute must not be negative", stage.Name)
+		}
+	}
+	return nil
+}
+
+// Geocoding lokasi artikel lewat Nominatim (OpenStreetMap). Kebijakan
+// pemakaian Nominatim publik maksimal satu request per detik.
+type nominatim struct {
+	url string
+}
+
+func newNominatim(stage EnrichmentStage) Enricher {
+	if stage.URL == "" {
+		return &nominatim{url: NOMINATIM_URL}
+	}
+	return &nominatim{url: stage.URL}
+}
+
+func (n *nominatim) Name() string   { return ENRICHMENT_NOMINATIM }
+func (n *nominatim) Source() string { return n.url }
+
+func (n *nominatim) Enrich(ctx context.Context, article Article) (map[string]interface{}, error) {
+	location := extractLocation(article.Title, article.Content)
+	if location == "" {
+		return nil, errNothingToEnrich
+	}
+
+	values := url.Values{}
+	values.Set("q", location+", Indonesia")
+	values.Set("format", "json")
+	values.Set("limit", "1")
+	values.Set("countrycodes", "id")
+	req, err := http.NewRequest(http.MethodGet, n.url+"?"+values.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "search-engine2 enrichment")
+	data, err := enrichmentRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var places []struct {
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+		DisplayName string `json:"display_name"`
+		OSMType     string `json:"osm_type"`
+		OSMID       int64  `json:"osm_id"`
+	}
+	if err := json.Unmarshal(data, &places); err != nil {
+		return nil, fmt.Errorf("invalid nominatim response: %v", err)
+	}
+	if len(places) == 0 {
+		return map[string]interface{}{"location": location}, nil
+	}
+	return map[string]interface{}{
+		"location":     location,
+		"lat":          places[0].Lat,
+		"lon":          places[0].Lon,
+		"display_name": places[0].DisplayName,
+		"osm_id":       fmt.Sprintf("%s/%d", places[0].OSMType, places[0].OSMID),
+	}, nil
+}
+
+// Endpoint HTTP sendiri (registri perusahaan, CRM, ...): POST JSON dokumen,
+// response berupa objek JSON yang disimpan apa adanya; 204 = tidak ada data
+type httpEnricher struct {
+	url    string
+	apiKey string
+}
+
+func newHTTPEnricher(stage EnrichmentStage) Enricher {
+	return &httpEnricher{url: stage.URL, apiKey: stage.APIKey}
+}
+
+func (h *httpEnricher) Name() string   { return ENRICHMENT_HTTP }
+func (h *httpEnricher) Source() string { return h.url }
+
+func (h *httpEnricher) Enrich(ctx context.Context, article Article) (map[string]interface{}, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"url":    article.URL,
+		"title":  article.Title,
+		"author": article.Author,
+		"fields": article.Fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, h.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+h.apiKey)
+	}
+	data, err := enrichmentRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, errNothingToEnrich
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid response from %s: %v", h.url, err)
+	}
+	return result, nil
+}
+
+// Body response 2xx, selain itu error
+func enrichmentRequest(ctx context.Context, req *http.Request) ([]byte, error) {
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("%s returned %s: %s", req.URL.Host, res.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// Jeda minimum antar panggilan ke satu provider
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+	calls    int
+	waited   time.Duration
+}
+
+var enrichmentLimiters = struct {
+	mu       sync.Mutex
+	limiters map[string]*rateLimiter
+}{limiters: make(map[string]*rateLimiter)}
+
+// Limiter provider; kalau beberapa stage memakai provider yang sama, rate
+// terkecil yang berlaku
+func providerLimiter(stage EnrichmentStage) *rateLimiter {
+	rate := stage.RatePerMinute
+	if rate == 0 {
+		rate = ENRICH_RATE_PER_MINUTE
+	}
+	interval := time.Minute / time.Duration(rate)
+
+	enrichmentLimiters.mu.Lock()
+	defer enrichmentLimiters.mu.Unlock()
+	limiter, exists := enrichmentLimiters.limiters[stage.Provider]
+	if !exists {
+		limiter = &rateLimiter{}
+		enrichmentLimiters.limiters[stage.Provider] = limiter
+	}
+	limiter.mu.Lock()
+	if interval > limiter.interval {
+		limiter.interval = interval
+	}
+	limiter.mu.Unlock()
+	return limiter
+}
+
+// Tunggu giliran; error kalau ctx dibatalkan selama menunggu
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.calls++
+	l.waited += at.Sub(now)
+	l.mu.Unlock()
+
+	select {
+	case <-time.After(time.Until(at)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stage yang belum dijalankan (atau dengan versi lain) untuk article
+func pendingStages(article Article, stages []EnrichmentStage) []EnrichmentStage {
+	var pending []EnrichmentStage
+	for _, stage := range stages {
+		if result, exists := article.Enrichment[stage.Name]; !exists || result.Version != stage.Version {
+			pending = append(pending, stage)
+		}
+	}
+	return pending
+}
+
+// Jalankan stage yang tertunda untuk satu dokumen. changed false kalau
+// tidak ada stage yang berhasil; error stage pertama yang gagal dikembalikan
+// setelah stage lain tetap dicoba.
+func enrichArticle(ctx context.Context, article Article, stages []EnrichmentStage) (Article, bool, error) {
+	var firstErr error
+	changed := false
+	for _, stage := range stages {
+		provider, err := enricher(stage)
+		if err != nil {
+			return article, changed, err
+		}
+		if err := providerLimiter(stage).Wait(ctx); err != nil {
+			return article, changed, err
+		}
+
+		lookupCtx, cancel := context.WithTimeout(ctx, ENRICH_TIMEOUT)
+		data, err := provider.Enrich(lookupCtx, article)
+		cancel()
+		if err != nil && err != errNothingToEnrich {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %v", stage.Name, err)
+			}
+			continue
+		}
+
+		// Map baru supaya salinan artikel lain tidak ikut berubah
+		enrichment := make(map[string]*Enrichment, len(article.Enrichment)+1)
+		for name, result := range article.Enrichment {
+			enrichment[name] = result
+		}
+		enrichment[stage.Name] = &Enrichment{
+			Provider:  provider.Name(),
+			Source:    provider.Source(),
+			Version:   stage.Version,
+			Data:      data,
+			FetchedAt: time.Now().UTC(),
+		}
+		article.Enrichment = enrichment
+		changed = true
+	}
+	return article, changed, firstErr
+}
+
+// Perkaya semua dokumen yang masih punya stage tertunda. Hasil ditulis ke
+// WAL per ENRICH_BATCH dokumen, jadi job yang dibatalkan tetap menyimpan
+// hasil yang sudah didapat.
+func enrichJob(ctx context.Context, job jobRun) error {
+	cfg := currentConfig()
+	stages := cfg.Enrichment
+	if len(stages) == 0 {
+		return nil
+	}
+	articles, err := loadArticles()
+	if err != nil {
+		return err
+	}
+
+	var todo []Article
+	for _, article := range applyWalRecords(articles, documentLog.Pending(), cfg) {
+		if !article.Deleted() && len(pendingStages(article, stages)) > 0 {
+			todo = append(todo, article)
+		}
+	}
+	job.SetTotal(len(todo))
+
+	actor := AuditActor{Name: JobEnrich}.forJob(job)
+	var records []WalRecord
+	flush := func() error {
+		if len(records) == 0 {
+			return nil
+		}
+		_, err := actor.appendDocuments(records...)
+		records = records[:0]
+		return err
+	}
+
+	for _, article := range todo {
+		if ctx.Err() != nil {
+			break
+		}
+		enriched, changed, err := enrichArticle(ctx, article, pendingStages(article, stages))
+		if changed {
+			records = append(records, WalRecord{Op: WalUpdate, Article: &enriched})
+		}
+		if err != nil && err != ctx.Err() {
+			job.Fail(article.URL, err)
+		} else if err == nil {
+			job.Advance(1, 1)
+		}
+		if len(records) >= ENRICH_BATCH {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// Jalankan job enrich kalau ada stage aktif dan job sebelumnya sudah selesai
+func scheduleEnrichment() {
+	if len(currentConfig().Enrichment) == 0 || benchMode || adminJobs.pending(JobEnrich) {
+		return
+	}
+	if _, err := adminJobs.Enqueue(JobEnrich, nil); err != nil {
+		log.Printf("Error scheduling enrichment: %v", err)
+	}
+}
+
+func startEnricher(interval time.Duration) {
+	adminJobs.Every(JobEnrich, interval)
+}
+
+type EnrichmentStageStatus struct {
+	EnrichmentStage
+	Enriched int `json:"enriched"`
+	Pending  int `json:"pending"`
+	// Panggilan ke provider stage ini sejak start dan total jeda rate limit
+	ProviderCalls    int     `json:"provider_calls"`
+	ProviderWaitedMs float64 `json:"provider_waited_ms"`
+}
+
+// GET /api/admin/enrichment: stage aktif dan jumlah dokumen yang sudah/belum diperkaya
+func enrichmentStatusHandler(c *gin.Context) {
+	cfg := requestConfig(c)
+	articles, err := loadArticles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	articles = applyWalRecords(articles, documentLog.Pending(), cfg)
+
+	stages := make([]EnrichmentStageStatus, len(cfg.Enrichment))
+	for i, stage := range cfg.Enrichment {
+		stage.APIKey = ""
+		stages[i].EnrichmentStage = stage
+		for _, article := range articles {
+			if article.Deleted() {
+				continue
+			}
+			if len(pendingStages(article, []EnrichmentStage{stage})) == 0 {
+				stages[i].Enriched++
+			} else {
+				stages[i].Pending++
+			}
+		}
+		limiter := providerLimiter(stage)
+		limiter.mu.Lock()
+		stages[i].ProviderCalls = limiter.calls
+		stages[i].ProviderWaitedMs = float64(limiter.waited.Microseconds()) / 1000
+		limiter.mu.Unlock()
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"stages": stages,
+		"jobs":   adminJobs.List(JobEnrich, ""),
+	})
+}
+
+func init() {
+	registerJobKind(JobEnrich, "", maintenanceRetry, enrichJob)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Template dirender ke buffer dulu, jadi error eksekusi (misalnya field yang
+// hilang setelah refactor) tidak menghasilkan halaman setengah jadi atau
+// layar putih dari Gin. Error dicatat ke log dan user mendapat 500.html.
+// Route yang tidak ada mendapat 404.html, panic mendapat 500.html dan
+// pencarian yang melewati search_timeout_seconds mendapat timeout.html.
+const TEMPLATES_GLOB = "templates/*"
+
+var htmlTemplates *template.Template
+
+// Halaman darurat kalau 500.html sendiri gagal dirender
+const FALLBACK_ERROR_PAGE = `<!doctype html><html><head><meta charset="UTF-8"><title>Error</title></head>` +
+	`<body><p>Terjadi kesalahan. <a href="/">Kembali ke pencarian</a></p></body></html>`
+
+func loadTemplates(pattern string) *template.Template {
+	return template.Must(template.New("").Funcs(templateFunctions()).ParseGlob(pattern))
+}
+
+// Render template ke buffer lalu kirim. Mengembalikan HTML yang terkirim,
+// nil kalau template gagal dan halaman error yang dikirim.
+func renderHTML(c *gin.Context, status int, name string, data interface{}) []byte {
+	var buf bytes.Buffer
+	if err := htmlTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		log.Printf("Error rendering %s for %s: %v", name, c.Request.URL, err)
+		renderError(c, http.StatusInternalServerError)
+		return nil
+	}
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+	return buf.Bytes()
+}
+
+// Halaman error untuk status; API tetap mendapat JSON
+func renderError(c *gin.Context, status int) {
+	if strings.HasPrefix(c.Request.URL.Path, "/api/") {
+		c.AbortWithStatusJSON(status, gin.H{"error": strings.ToLower(http.StatusText(status))})
+		return
+	}
+
+	name := "500.html"
+	switch status {
+	case http.StatusNotFound:
+		name = "404.html"
+	case http.StatusServiceUnavailable:
+		name = "timeout.html"
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplates.ExecuteTemplate(&buf, name, gin.H{"path": c.Request.URL.RequestURI()}); err != nil {
+		log.Printf("Error rendering %s: %v", name, err)
+		buf.Reset()
+		buf.WriteString(FALLBACK_ERROR_PAGE)
+	}
+	c.Data(status, "text/html; charset=utf-8", buf.Bytes())
+	c.Abort()
+}
+
+func notFoundHandler(c *gin.Context) {
+	renderError(c, http.StatusNotFound)
+}
+
+// Pengganti gin.Recovery: panic dicatat lalu user mendapat 500.html
+func recoveryHandler() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
+		log.Printf("Panic serving %s: %v", c.Request.URL, recovered)
+		renderError(c, http.StatusInternalServerError)
+	})
+}
+
+// Jalankan pencarian dengan batas waktu; false kalau belum selesai saat
+// batas lewat (goroutine-nya tetap jalan sampai selesai di background)
+func searchWithTimeout(query, method string, opts SearchOptions) ([]SearchResult, *QueryPlan, bool) {
+	opts = opts.withConfig()
+	timeout := opts.Config.SearchTimeoutSeconds
+	if timeout <= 0 {
+		results, plan := searchWithPlan(query, method, opts)
+		return results, plan, true
+	}
+
+	type outcome struct {
+		results []SearchResult
+		plan    *QueryPlan
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		results, plan := searchWithPlan(query, method, opts)
+		done <- outcome{results, plan}
+	}()
+
+	select {
+	case out := <-done:
+		return out.results, out.plan, true
+	case <-time.After(time.Duration(timeout) * time.Second):
+		log.Printf("Search timed out after %ds: %q", timeout, query)
+		return nil, nil, false
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"html/template"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SOURCE_FACET_SIZE = 10
+	YEAR_FACET_SIZE   = 10
+
+	// Format parameter from/to (tanggal terbit, ?from=2024-01-01&to=2024-06-30)
+	FILTER_DATE_LAYOUT = "2006-01-02"
+)
+
+// Satu nilai facet di halaman hasil dan API; Slug dipakai sebagai nilai filter
+type FacetValue struct {
+	Value string `json:"value"`
+	Slug  string `json:"slug"`
+	Count int    `json:"count"`
+}
+
+// Hitung nilai yang paling sering muncul, nilai kosong diabaikan
+func countFacet(values []string, size int) []FacetValue {
+	counts := make(map[string]int)
+	for _, value := range values {
+		if value != "" {
+			counts[value]++
+		}
+	}
+
+	facet := make([]FacetValue, 0, len(counts))
+	for value, count := range counts {
+		facet = append(facet, FacetValue{Value: value, Slug: slugify(value), Count: count})
+	}
+	sort.Slice(facet, func(i, j int) bool {
+		if facet[i].Count != facet[j].Count {
+			return facet[i].Count > facet[j].Count
+		}
+		return facet[i].Value < facet[j].Value
+	})
+	if len(facet) > size {
+		facet = facet[:size]
+	}
+	return facet
+}
+
+// Sumber terbanyak di hasil pencarian; Slug adalah nilai parameter source
+func sourceFacet(results []SearchResult, size int) []FacetValue {
+	values := make([]string, len(results))
+	for i, result := range results {
+		values[i] = sourceOf(result.URL)
+	}
+	facet := countFacet(values, size)
+	for i := range facet {
+		facet[i].Slug = facet[i].Value
+	}
+	return facet
+}
+
+// Tahun terbit di hasil pencarian, terbaru dulu; hasil tanpa tanggal diabaikan
+func yearFacet(results []SearchResult, size int) []FacetValue {
+	values := make([]string, len(results))
+	for i, result := range results {
+		if !result.Date.IsZero() {
+			values[i] = strconv.Itoa(result.Date.Year())
+		}
+	}
+	facet := countFacet(values, len(values))
+	sort.Slice(facet, func(i, j int) bool { return facet[i].Value > facet[j].Value })
+	if len(facet) > size {
+		facet = facet[:size]
+	}
+	return facet
+}
+
+// Nilai input tanggal from/to; kosong kalau filter tidak dipakai
+func formatFilterDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(FILTER_DATE_LAYOUT)
+}
+
+// Parameter pencarian saat ini tanpa filter-filter tertentu, dipakai link
+// facet ("Semua" dan nilai lain) dan form rentang tanggal
+func facetParams(query, method string, opts SearchOptions, without ...string) url.Values {
+	values, _ := url.ParseQuery(strings.TrimPrefix(string(filterParams(opts)), "&"))
+	for _, key := range without {
+		values.Del(key)
+	}
+	values.Set("q", query)
+	if method != "" {
+		values.Set("method", method)
+	}
+	return values
+}
+
+// URL /search per facet, tanpa filter facet itu sendiri; template menambahkan
+// nilai facet-nya (&source=..., &from=...&to=...)
+func facetLinks(query, method string, opts SearchOptions) map[string]template.URL {
+	links := make(map[string]template.URL)
+	for facet, without := range map[string][]string{
+		"source":   {"source"},
+		"author":   {"author"},
+		"category": {"category"},
+		"doctype":  {"doctype"},
+		"date":     {"from", "to", "days"},
+	} {
+		links[facet] = template.URL("/search?" + facetParams(query, method, opts, without...).Encode())
+	}
+	return links
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Feature flag untuk kode ranking baru yang di-ship dalam keadaan mati.
+// Flag dibaca dari feature_flags.json dan bisa diubah saat runtime lewat
+// POST /api/admin/flags (tersimpan lagi ke file):
+//
+//	{"wand_retrieval": {"enabled": true, "rollout": 10, "environments": ["staging"]}}
+//
+// Flag aktif untuk sebuah request kalau enabled, environment server
+// (SEARCH_ENV, default development) ada di environments (kosong = semua), dan
+// bucket klien (hash flag + IP) di bawah persentase rollout. Bucket stabil,
+// jadi klien yang sama selalu mendapat varian yang sama.
+const (
+	FEATURE_FLAGS_FILE  = "feature_flags.json"
+	DEFAULT_ENVIRONMENT = "development"
+)
+
+// Flag yang dikenal; flag lain di file tetap bisa di-toggle tapi tidak
+// dibaca kode mana pun
+const (
+	FlagSemanticSearch = "semantic_search"
+	FlagNewStemmer     = "new_stemmer"
+	FlagWANDRetrieval  = "wand_retrieval"
+)
+
+var knownFeatureFlags = map[string]string{
+	FlagSemanticSearch: "ranking dengan embedding di samping TF-IDF",
+	FlagNewStemmer:     "stemmer pengganti untuk bahasa Indonesia",
+	FlagWANDRetrieval:  "retrieval WAND dengan early termination",
+}
+
+type FeatureFlag struct {
+	Enabled      bool     `json:"enabled"`
+	Rollout      int      `json:"rollout"` // persen klien, 0-100
+	Environments []string `json:"environments,omitempty"`
+	Description  string   `json:"description,omitempty"`
+}
+
+type FeatureFlagStore struct {
+	mu    sync.RWMutex
+	path  string
+	env   string
+	flags map[string]*FeatureFlag
+}
+
+var featureFlags *FeatureFlagStore
+
+// Store berisi flag yang dikenal, semuanya mati
+func newFeatureFlagStore(path, env string) *FeatureFlagStore {
+	store := &FeatureFlagStore{path: path, env: env, flags: make(map[string]*FeatureFlag)}
+	for name, description := range knownFeatureFlags {
+		store.flags[name] = &FeatureFlag{Description: description}
+	}
+	return store
+}
+
+func loadFeatureFlags(path, env string) (*FeatureFlagStore, error) {
+	store := newFeatureFlagStore(path, env)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, err
+	}
+
+	var flags map[string]*FeatureFlag
+	if err := json.Unmarshal(data, &flags); err != nil {
+		return nil, err
+	}
+	for name, flag := range flags {
+		if flag.Description == "" {
+			flag.Description = knownFeatureFlags[name]
+		}
+		store.flags[name] = flag
+	}
+	return store, nil
+}
+
+func (s *FeatureFlagStore) save() error {
+	data, err := json.MarshalIndent(s.flags, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.path, data, 0644)
+}
+
+// Apakah flag aktif untuk klien dengan key ini
+func (s *FeatureFlagStore) Enabled(name, key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	flag, exists := s.flags[name]
+	if !exists || !flag.Enabled {
+		return false
+	}
+	if len(flag.Environments) > 0 {
+		found := false
+		for _, env := range flag.Environments {
+			if env == s.env {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return rolloutBucket(name, key) < flag.Rollout
+}
+
+// Bucket 0-99 per flag, supaya rollout 10% dua flag tidak mengenai klien yang sama
+func rolloutBucket(name, key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + key))
+	return int(h.Sum32() % 100)
+}
+
+// Nama flag yang aktif untuk key, terurut (ikut masuk key cache halaman hasil)
+func (s *FeatureFlagStore) Active(key string) []string {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.flags))
+	for name := range s.flags {
+		names = append(names, name)
+	}
+	s.mu.RUnlock()
+
+	var active []string
+	for _, name := range names {
+		if s.Enabled(name, key) {
+			active = append(active, name)
+		}
+	}
+	sort.Strings(active)
+	return active
+}
+
+func (s *FeatureFlagStore) Set(name string, flag FeatureFlag) (*FeatureFlag, error) {
+	if flag.Rollout < 0 || flag.Rollout > 100 {
+		return nil, fmt.Errorf("rollout must be between 0 and 100")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if flag.Description == "" {
+		if current, exists := s.flags[name]; exists {
+			flag.Description = current.Description
+		}
+	}
+	s.flags[name] = &flag
+	if err := s.save(); err != nil {
+		log.Printf("Error saving feature flags: %v", err)
+	}
+	return &flag, nil
+}
+
+func (s *FeatureFlagStore) List() map[string]FeatureFlag {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make(map[string]FeatureFlag, len(s.flags))
+	for name, flag := range s.flags {
+		list[name] = *flag
+	}
+	return list
+}
+
+// GET /api/admin/flags
+func listFeatureFlagsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"environment": featureFlags.env,
+		"flags":       featureFlags.List(),
+	})
+}
+
+// POST /api/admin/flags, body: {"name": "...", "enabled": true, "rollout": 10, "environments": [...]}
+func setFeatureFlagHandler(c *gin.Context) {
+	var req struct {
+		Name string `json:"name" binding:"required"`
+		FeatureFlag
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var before json.RawMessage
+	if current, exists := featureFlags.List()[req.Name]; exists {
+		before = auditSnapshot(current)
+	}
+	flag, err := featureFlags.Set(req.Name, req.FeatureFlag)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	purgeResultCaches()
+	auditActor(c).Record(AuditFlagSet, req.Name, before, flag)
+	c.JSON(http.StatusOK, gin.H{"name": req.Name, "flag": flag})
+}
+
+func init() {
+	env := os.Getenv("SEARCH_ENV")
+	if env == "" {
+		env = DEFAULT_ENVIRONMENT
+	}
+	store, err := loadFeatureFlags(FEATURE_FLAGS_FILE, env)
+	if err != nil {
+		log.Printf("Error loading %s: %v", FEATURE_FLAGS_FILE, err)
+		store = newFeatureFlagStore(FEATURE_FLAGS_FILE, env)
+	}
+	featureFlags = store
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// Federasi query ke beberapa index (tier). Setiap tier dijalankan paralel
+// dengan timeout sendiri dari tier_timeouts_ms; tier yang terlambat
+// ditinggalkan (hasilnya dibuang) dan tercatat di query plan, tier lain
+// tetap dikembalikan. Tier tanpa timeout hanya dibatasi
+// search_timeout_seconds.
+//
+//	"tier_timeouts_ms": {"hot": 0, "archive": 2000}
+const (
+	TIER_HOT     = "hot"
+	TIER_ARCHIVE = "archive"
+
+	ARCHIVE_TIER_TIMEOUT     = 2 * time.Second
+	ARCHIVE_PENALTY_PER_YEAR = 0.05
+)
+
+type SearchTier struct {
+	Name    string
+	Node    string        // instance asal hasil, "" untuk index lokal (lihat remote.go)
+	Timeout time.Duration // 0 = tanpa batas sendiri
+	Weight  float64       // pengali skor ternormalisasi node, 0 = 1
+	Search  func() ([]SearchResult, *QueryPlan, error)
+}
+
+type TierStatus struct {
+	Name     string
+	Results  int
+	Duration time.Duration
+	TimedOut bool
+	Error    string
+}
+
+// Timeout tier dari config, fallback ke default
+func tierTimeout(cfg *Config, name string, fallback time.Duration) time.Duration {
+	if ms, exists := cfg.TierTimeoutsMs[name]; exists {
+		return time.Duration(ms) * time.Millisecond
+	}
+	return fallback
+}
+
+// Jalankan semua tier bersamaan. Hasil dikembalikan per tier dalam urutan
+// tiers (nil untuk tier yang timeout atau gagal), plus plan tier pertama
+// dengan status setiap tier.
+func federate(tiers []SearchTier) ([][]SearchResult, *QueryPlan) {
+	type outcome struct {
+		results  []SearchResult
+		plan     *QueryPlan
+		err      error
+		duration time.Duration
+	}
+
+	start := time.Now()
+	done := make([]chan outcome, len(tiers))
+	for i, tier := range tiers {
+		// Buffer 1 supaya goroutine tier yang ditinggalkan tetap bisa selesai
+		done[i] = make(chan outcome, 1)
+		go func(search func() ([]SearchResult, *QueryPlan, error), done chan<- outcome) {
+			results, plan, err := search()
+			done <- outcome{results, plan, err, time.Since(start)}
+		}(tier.Search, done[i])
+	}
+
+	results := make([][]SearchResult, len(tiers))
+	statuses := make([]TierStatus, len(tiers))
+	var plan *QueryPlan
+	for i, tier := range tiers {
+		statuses[i].Name = tier.Name
+
+		var deadline <-chan time.Time
+		if tier.Timeout > 0 {
+			// Semua tier mulai bersamaan, jadi sisa waktunya dihitung dari start
+			deadline = time.After(tier.Timeout - time.Since(start))
+		}
+		select {
+		case out := <-done[i]:
+			if out.err != nil {
+				statuses[i].Error = out.err.Error()
+				log.Printf("Search tier %s failed: %v", tier.Name, out.err)
+			}
+			results[i] = out.results
+			statuses[i].Results = len(out.results)
+			statuses[i].Duration = out.duration
+			if i == 0 {
+				plan = out.plan
+			}
+		case <-deadline:
+			statuses[i].TimedOut = true
+			statuses[i].Duration = tier.Timeout
+			log.Printf("Search tier %s timed out after %v", tier.Name, tier.Timeout)
+		}
+	}
+
+	if plan == nil {
+		plan = &QueryPlan{Mode: "or"}
+	}
+	plan.Tiers = statuses
+	return results, plan
+}
+
+// Penalti ringan untuk hasil arsip, makin besar untuk artikel yang makin
+// tua: skor dibagi 1 + archive_penalty_per_year * umur dalam tahun
+func archivePenalty(perYear float64, date time.Time, now time.Time) float64 {
+	if date.IsZero() || perYear <= 0 {
+		return 1
+	}
+	years := now.Sub(date).Hours() / (24 * 365)
+	return 1 / (1 + perYear*math.Max(years, 0))
+}
+
+// Apakah ada tier yang timeout atau gagal (hasil tidak lengkap)
+func (p *QueryPlan) Partial() bool {
+	if p == nil {
+		return false
+	}
+	for _, tier := range p.Tiers {
+		if tier.TimedOut || tier.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *QueryPlan) tiersString() string {
+	if len(p.Tiers) < 2 {
+		return ""
+	}
+	parts := make([]string, len(p.Tiers))
+	for i, tier := range p.Tiers {
+		if tier.TimedOut {
+			parts[i] = fmt.Sprintf("%s timeout after %v", tier.Name, tier.Duration)
+			continue
+		}
+		if tier.Error != "" {
+			parts[i] = fmt.Sprintf("%s error: %s", tier.Name, tier.Error)
+			continue
+		}
+		parts[i] = fmt.Sprintf("%s %d results in %v", tier.Name, tier.Results, tier.Duration.Round(time.Microsecond))
+	}
+	return "tiers: " + strings.Join(parts, ", ") + "\n"
+}
+
+// Gabungkan hasil semua tier. Kalau ada instance remote, skor setiap node
+// dinormalisasi (dibagi skor tertinggi node itu) lalu dikali bobot instance,
+// karena skor antar index tidak sebanding: IDF dihitung dari corpus
+// masing-masing. URL yang muncul di beberapa node diambil skor tertingginya, kecuali collapse
+// false: semua tetap ada dan yang skornya lebih rendah ditandai DuplicateOf.
+func mergeTiers(tiers []SearchTier, tierResults [][]SearchResult, collapse bool) []SearchResult {
+	var results []SearchResult
+	federated := false
+	for _, tier := range tiers {
+		federated = federated || tier.Node != ""
+	}
+	if !federated {
+		for _, tier := range tierResults {
+			results = append(results, tier...)
+		}
+		return results
+	}
+
+	maxScore := make(map[string]float64)
+	for i, tier := range tiers {
+		for _, result := range tierResults[i] {
+			if result.Score > maxScore[tier.Node] {
+				maxScore[tier.Node] = result.Score
+			}
+		}
+	}
+
+	position := make(map[string]int)
+	for i, tier := range tiers {
+		weight := tier.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		for _, result := range tierResults[i] {
+			if max := maxScore[tier.Node]; max > 0 {
+				result.Score = result.Score / max * weight
+			}
+			if j, exists := position[result.URL]; exists {
+				if !collapse {
+					results = append(results, result)
+					continue
+				}
+				if result.Score > results[j].Score {
+					result.Collapsed = append(append(result.Collapsed, results[j].Collapsed...), collapsedFrom(results[j], CollapseSameURL))
+					results[j] = result
+				} else {
+					results[j].Collapsed = append(results[j].Collapsed, collapsedFrom(result, CollapseSameURL))
+				}
+				continue
+			}
+			position[result.URL] = len(results)
+			results = append(results, result)
+		}
+	}
+	if !collapse {
+		markSameURL(results)
+	}
+	return results
+}
+
+// Tandai hasil dengan URL yang sama: yang skornya tertinggi tetap, sisanya
+// DuplicateOf hasil itu
+func markSameURL(results []SearchResult) {
+	best := make(map[string]int)
+	for i, result := range results {
+		if j, exists := best[result.URL]; !exists || result.Score > results[j].Score {
+			best[result.URL] = i
+		}
+	}
+	for i, result := range results {
+		if j := best[result.URL]; j != i {
+			duplicateOf := collapsedFrom(results[j], CollapseSameURL)
+			results[i].DuplicateOf = &duplicateOf
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+)
+
+// Jumlah bitmap filter yang disimpan di cache
+const FILTER_CACHE_SIZE = 128
+
+// Cache bitmap per klausa filter (source=rumah123, lang=id, since=...),
+// dikunci dengan generasi doc values supaya otomatis basi saat corpus berubah.
+// Klausa di-cache terpisah lalu diiris, jadi kombinasi filter baru tetap
+// memakai bitmap klausa yang sudah ada.
+type FilterCache struct {
+	mu      sync.Mutex
+	entries map[string]*Bitmap
+	order   []string
+	hits    int
+	misses  int
+}
+
+var filterCache = &FilterCache{entries: make(map[string]*Bitmap)}
+
+// Fingerprint isi kolom doc values, dipakai sebagai generasi index
+func (dv *DocValues) fingerprint() uint64 {
+	h := fnv.New64a()
+	for i := range dv.Dates {
+		fmt.Fprintf(h, "%d|%s|%s|%s|%d|%s|%s|%s|%d|%s\n", dv.Dates[i], dv.Sources[i], dv.Languages[i], dv.Locations[i], dv.Prices[i], dv.Authors[i], dv.Categories[i], dv.DocTypes[i], dv.Words[i], dv.Visibilities[i])
+	}
+	// Kolom field schema, urut per nama supaya fingerprint stabil
+	for _, name := range dv.fieldNames() {
+		fmt.Fprintf(h, "%s=%q%v%v\n", name, dv.Keywords[name], dv.Numbers[name], dv.Points[name])
+	}
+	return h.Sum64()
+}
+
+// Bitmap dokumen yang lolos semua filter. nil berarti tanpa filter.
+func (fc *FilterCache) Bitmap(dv *DocValues, f SearchFilters, schema IndexSchema) *Bitmap {
+	var result *Bitmap
+	for _, clause := range f.clauses(schema) {
+		b := fc.clause(dv, clause)
+		if result == nil {
+			result = b
+		} else {
+			result = result.And(b)
+		}
+	}
+	return result
+}
+
+func (fc *FilterCache) clause(dv *DocValues, clause filterClause) *Bitmap {
+	key := fmt.Sprintf("%x|%s", dv.Generation, clause.key)
+
+	fc.mu.Lock()
+	if b, exists := fc.entries[key]; exists && !benchMode {
+		fc.hits++
+		fc.mu.Unlock()
+		return b
+	}
+	fc.misses++
+	fc.mu.Unlock()
+
+	b := NewBitmap()
+	for docID := range dv.Dates {
+		if clause.match(dv, docID) {
+			b.Add(docID)
+		}
+	}
+
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	if _, exists := fc.entries[key]; !exists {
+		if len(fc.order) >= FILTER_CACHE_SIZE {
+			delete(fc.entries, fc.order[0])
+			fc.order = fc.order[1:]
+		}
+		fc.entries[key] = b
+		fc.order = append(fc.order, key)
+	}
+
+	return b
+}
+
+type FilterCacheStats struct {
+	Entries int `json:"entries"`
+	Hits    int `json:"hits"`
+	Misses  int `json:"misses"`
+}
+
+func (fc *FilterCache) Stats() FilterCacheStats {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	return FilterCacheStats{Entries: len(fc.entries), Hits: fc.hits, Misses: fc.misses}
+}
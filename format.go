@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format tampilan locale Indonesia untuk template: titik sebagai pemisah
+// ribuan ("1.250"), "Rp 1,2 miliar" untuk harga, "2 Januari 2025" dan
+// "3 hari lalu". Handler mengirim nilai mentah (int, time.Time, waktu
+// sekarang sebagai "now"); format hanya terjadi di template.
+
+var indonesianMonthNames = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// 1234567 -> "1.234.567"
+func formatNumber(n int) string {
+	return groupThousands(strconv.FormatInt(int64(n), 10))
+}
+
+func groupThousands(digits string) string {
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(digit)
+	}
+	return sign + b.String()
+}
+
+// Harga rupiah: "Rp 850 juta", "Rp 1,25 miliar"; di bawah sejuta ditulis lengkap
+func formatRupiah(amount int64) string {
+	units := []struct {
+		value int64
+		name  string
+	}{
+		{1000000000000, "triliun"},
+		{1000000000, "miliar"},
+		{1000000, "juta"},
+	}
+	for _, unit := range units {
+		if amount >= unit.value {
+			value := strconv.FormatFloat(float64(amount)/float64(unit.value), 'f', 2, 64)
+			value = strings.TrimRight(strings.TrimRight(value, "0"), ".")
+			return "Rp " + strings.Replace(value, ".", ",", 1) + " " + unit.name
+		}
+	}
+	return "Rp " + groupThousands(strconv.FormatInt(amount, 10))
+}
+
+// "2 Januari 2025" dalam WIB (tanggal disimpan UTC)
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	t = t.In(defaultDateLocation)
+	return fmt.Sprintf("%d %s %d", t.Day(), indonesianMonthNames[t.Month()-1], t.Year())
+}
+
+// "baru saja", "5 menit lalu", "3 hari lalu", "2 bulan lalu"; tanggal di
+// masa depan ditulis lengkap
+func relativeTime(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	elapsed := now.Sub(t)
+	switch {
+	case elapsed < 0:
+		return formatDate(t)
+	case elapsed < time.Minute:
+		return "baru saja"
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%d menit lalu", int(elapsed/time.Minute))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%d jam lalu", int(elapsed/time.Hour))
+	}
+
+	days := int(elapsed / (24 * time.Hour))
+	switch {
+	case days == 1:
+		return "kemarin"
+	case days < 7:
+		return fmt.Sprintf("%d hari lalu", days)
+	case days < 30:
+		return fmt.Sprintf("%d minggu lalu", days/7)
+	case days < 365:
+		return fmt.Sprintf("%d bulan lalu", days/30)
+	}
+	return fmt.Sprintf("%d tahun lalu", days/365)
+}
+
+// Fraksi 0-1 sebagai persen: 0.125 -> "12,5%"
+func formatPercent(fraction float64) string {
+	return strings.Replace(fmt.Sprintf("%.1f%%", fraction*100), ".", ",", 1)
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Pin generasi index per klien API, untuk eksperimen yang bisa diulang.
+// Setiap generasi index baru, corpus yang diindex disimpan ke
+// generations/<n>.json. Klien yang mengirim header X-Index-Generation: <n>
+// mendapat hasil dari index generasi itu walaupun corpus sudah berubah;
+// /api/search selalu mengembalikan generasi yang dipakai di field generation.
+// Berapa generasi lama yang disimpan diatur generation_retention:
+//
+//	{"generation_retention": {"count": 5, "max_age_hours": 168}}
+//
+// count 0 (default) berarti tidak ada yang disimpan dan pin hanya berlaku
+// untuk generasi yang sedang aktif. Generasi yang sudah dibuang dijawab 410.
+// Bersama corpus disimpan keputusan review queue per dokumen, config dan
+// sinonim saat generasi dibangun, jadi review, perubahan config atau sinonim
+// sesudahnya tidak mengubah hasil generasi itu. Blocklist tetap berlaku:
+// dokumen yang diblokir tidak pernah disajikan, generasi mana pun.
+const (
+	GENERATIONS_DIR         = "generations"
+	INDEX_GENERATION_HEADER = "X-Index-Generation"
+	// Index generasi lama yang dipegang di memori; klien yang pin biasanya
+	// hanya memakai satu atau dua generasi
+	GENERATION_CACHE_SIZE = 2
+)
+
+type GenerationRetention struct {
+	Count       int `json:"count"`
+	MaxAgeHours int `json:"max_age_hours"` // 0 = tanpa batas umur
+}
+
+// Generasi yang tersimpan di GENERATIONS_DIR
+type RetainedGeneration struct {
+	Generation int       `json:"generation"`
+	Time       time.Time `json:"time"`
+	Bytes      int64     `json:"bytes"`
+}
+
+var errGenerationNotRetained = errors.New("index generation is not retained")
+
+// Diaktifkan startBackgroundJobs, supaya perintah searchctl yang membangun
+// index tidak ikut menyimpan generasi
+var retainGenerations bool
+
+// Penulisan dan pembuangan generasi tidak boleh bersamaan
+var generationsMu sync.Mutex
+
+// Isi generations/<n>.json: corpus beserta input build yang bisa berubah
+// sesudahnya, supaya index generasi ini bisa dibangun ulang persis
+type GenerationSnapshot struct {
+	Articles []Article `json:"articles"`
+	// Keputusan review queue per docID saat generasi dibangun
+	Allowed  []bool              `json:"allowed"`
+	Config   json.RawMessage     `json:"config"`
+	Synonyms map[string][]string `json:"synonyms"`
+}
+
+// Corpus dan index yang dipakai satu pencarian. Config dan Synonyms hanya
+// diisi untuk generasi lama; nil berarti config request dan sinonim aktif.
+type GenerationIndex struct {
+	Articles []Article
+	Index    *InvertedIndex
+	Config   *Config
+	Synonyms map[string][]string
+}
+
+// Ekspansi sinonim query sesuai generasi
+func (g *GenerationIndex) expand(queryVector map[string]float64) {
+	if g.Config == nil {
+		synonyms.Expand(queryVector)
+		return
+	}
+	expandSynonyms(g.Synonyms, queryVector)
+}
+
+// Index generasi lama yang sudah dibangun, LRU per nomor generasi. Isinya
+// hanya bergantung pada file generasinya, jadi entri cukup dibuang begitu
+// file itu dibuang pruneGenerations.
+type GenerationCache struct {
+	mu      sync.Mutex
+	entries map[int]*list.Element
+	lru     *list.List // depan = paling baru dipakai
+}
+
+type generationEntry struct {
+	generation int
+	index      *GenerationIndex
+}
+
+var generationCache = &GenerationCache{entries: make(map[int]*list.Element), lru: list.New()}
+
+func (gc *GenerationCache) Get(generation int) (*GenerationIndex, bool) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	element, exists := gc.entries[generation]
+	if !exists {
+		return nil, false
+	}
+	gc.lru.MoveToFront(element)
+	return element.Value.(*generationEntry).index, true
+}
+
+func (gc *GenerationCache) Put(generation int, index *GenerationIndex) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	entry := &generationEntry{generation: generation, index: index}
+	if element, exists := gc.entries[generation]; exists {
+		element.Value = entry
+		gc.lru.MoveToFront(element)
+		return
+	}
+	for gc.lru.Len() >= GENERATION_CACHE_SIZE {
+		oldest := gc.lru.Back()
+		gc.lru.Remove(oldest)
+		delete(gc.entries, oldest.Value.(*generationEntry).generation)
+	}
+	gc.entries[generation] = gc.lru.PushFront(entry)
+}
+
+func (gc *GenerationCache) Evict(generation int) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if element, exists := gc.entries[generation]; exists {
+		gc.lru.Remove(element)
+		delete(gc.entries, generation)
+	}
+}
+
+func generationPath(generation int) string {
+	return filepath.Join(GENERATIONS_DIR, strconv.Itoa(generation)+".json")
+}
+
+// Dipanggil noteIndexGeneration dengan artikel berkonten lengkap (buildIndex);
+// yang dipasang untuk pencarian kontennya sudah dilepas ke document store.
+// allowed dan cfg dari build yang sama, disalin sebelum build berikutnya.
+func scheduleGenerationRetention(generation int, articles []Article, allowed []bool, cfg *Config) {
+	policy := cfg.GenerationRetention
+	if !retainGenerations || policy.Count <= 0 {
+		return
+	}
+	config, err := json.Marshal(cfg)
+	if err != nil {
+		log.Printf("Error retaining index generation %d: %v", generation, err)
+		return
+	}
+	snapshot := GenerationSnapshot{
+		Articles: append([]Article(nil), articles...),
+		Allowed:  append([]bool(nil), allowed...),
+		Config:   config,
+		Synonyms: synonyms.Snapshot(),
+	}
+	go func() {
+		if err := retainGeneration(generation, snapshot, policy); err != nil {
+			log.Printf("Error retaining index generation %d: %v", generation, err)
+		}
+	}()
+}
+
+func retainGeneration(generation int, snapshot GenerationSnapshot, retention GenerationRetention) error {
+	generationsMu.Lock()
+	defer generationsMu.Unlock()
+
+	if err := os.MkdirAll(GENERATIONS_DIR, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	path := generationPath(generation)
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	return pruneGenerations(retention, time.Now())
+}
+
+// Buang generasi di luar count terbaru atau yang lebih tua dari
+// max_age_hours; generasi terbaru selalu disimpan
+func pruneGenerations(retention GenerationRetention, now time.Time) error {
+	generations, err := listGenerations()
+	if err != nil {
+		return err
+	}
+	maxAge := time.Duration(retention.MaxAgeHours) * time.Hour
+	for i, generation := range generations {
+		expired := i >= retention.Count || (i > 0 && maxAge > 0 && now.Sub(generation.Time) > maxAge)
+		if !expired {
+			continue
+		}
+		if err := os.Remove(generationPath(generation.Generation)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		generationCache.Evict(generation.Generation)
+	}
+	return nil
+}
+
+// Generasi yang tersimpan, terbaru dulu
+func listGenerations() ([]RetainedGeneration, error) {
+	entries, err := ioutil.ReadDir(GENERATIONS_DIR)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var generations []RetainedGeneration
+	for _, entry := range entries {
+		generation, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil || entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		generations = append(generations, RetainedGeneration{Generation: generation, Time: entry.ModTime(), Bytes: entry.Size()})
+	}
+	sort.Slice(generations, func(i, j int) bool { return generations[i].Generation > generations[j].Generation })
+	return generations, nil
+}
+
+// Corpus generasi lama dan index-nya; dibangun dari file generasinya sekali,
+// dengan keputusan review dan config yang tersimpan, lalu diambil dari
+// generationCache
+func loadGeneration(generation int) (*GenerationIndex, error) {
+	if index, ok := generationCache.Get(generation); ok {
+		return index, nil
+	}
+
+	generationsMu.Lock()
+	data, err := ioutil.ReadFile(generationPath(generation))
+	generationsMu.Unlock()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errGenerationNotRetained
+		}
+		return nil, err
+	}
+	var snapshot GenerationSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("parsing generation %d: %v", generation, err)
+	}
+	if len(snapshot.Allowed) != len(snapshot.Articles) {
+		return nil, fmt.Errorf("generation %d: %d review decisions for %d documents", generation, len(snapshot.Allowed), len(snapshot.Articles))
+	}
+	// Tidak strict: key yang dibuang versi server sesudahnya tidak membuat
+	// generasi lama tidak terbaca
+	cfg, err := parseConfig(snapshot.Config, false)
+	if err != nil {
+		return nil, fmt.Errorf("generation %d config: %v", generation, err)
+	}
+	index := &GenerationIndex{
+		Articles: snapshot.Articles,
+		Index: buildAllowedIndex(snapshot.Articles, func(docID int) bool {
+			return snapshot.Allowed[docID]
+		}, cfg),
+		Config:   cfg,
+		Synonyms: snapshot.Synonyms,
+	}
+
+	// File bisa sudah dibuang selama index dibangun; jangan cache generasi
+	// yang tidak ada lagi
+	generationsMu.Lock()
+	if _, err := os.Stat(generationPath(generation)); err == nil {
+		generationCache.Put(generation, index)
+	}
+	generationsMu.Unlock()
+	return index, nil
+}
+
+// Index generasi yang di-pin (0 = terbaru). Generasi yang sedang aktif
+// tetap bisa di-pin walaupun tidak ada yang disimpan.
+func indexFor(generation int) (*GenerationIndex, error) {
+	if generation != 0 {
+		index, err := loadGeneration(generation)
+		if err != errGenerationNotRetained || generation != currentGeneration() {
+			return index, err
+		}
+	}
+	articles, idx, err := loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	return &GenerationIndex{Articles: articles, Index: idx}, nil
+}
+
+func generationAvailable(generation int) bool {
+	if generation == currentGeneration() {
+		return true
+	}
+	_, err := os.Stat(generationPath(generation))
+	return err == nil
+}
+
+// Generasi dari header X-Index-Generation; 0 = tanpa pin
+func pinnedGeneration(c *gin.Context) (int, error) {
+	value := c.GetHeader(INDEX_GENERATION_HEADER)
+	if value == "" {
+		return 0, nil
+	}
+	generation, err := strconv.Atoi(value)
+	if err != nil || generation < 1 {
+		return 0, fmt.Errorf("%s must be a positive generation number", INDEX_GENERATION_HEADER)
+	}
+	return generation, nil
+}
+
+// GET /api/admin/generations
+func generationsHandler(c *gin.Context) {
+	generations, err := listGenerations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if generations == nil {
+		generations = []RetainedGeneration{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"current":     currentGeneration(),
+		"retention":   requestConfig(c).GenerationRetention,
+		"generations": generations,
+	})
+}
+
+// Nomor generasi melanjutkan generasi tersimpan terakhir, supaya generasi
+// setelah restart tidak menimpa file generasi lama dengan nomor yang sama
+func init() {
+	generations, err := listGenerations()
+	if err != nil {
+		log.Printf("Error listing %s: %v", GENERATIONS_DIR, err)
+		return
+	}
+	if len(generations) > 0 {
+		indexGeneration.mu.Lock()
+		indexGeneration.counter = generations[0].Generation
+		indexGeneration.mu.Unlock()
+	}
+}
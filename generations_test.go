@@ -0,0 +1,118 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Review queue kosong di direktori sementara, dikembalikan setelah test
+func useTestReviewQueue(t *testing.T) *ReviewQueue {
+	t.Helper()
+	rq, err := loadReviewQueue(filepath.Join(t.TempDir(), REVIEW_QUEUE_FILE))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := reviewQueue
+	reviewQueue = rq
+	t.Cleanup(func() { reviewQueue = prev })
+	return rq
+}
+
+func waitForFile(t *testing.T, path string) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(path); err == nil {
+			return
+		}
+	}
+	t.Fatalf("%s not written", path)
+}
+
+func resultURLSet(results []SearchResult) map[string]bool {
+	urls := make(map[string]bool, len(results))
+	for _, result := range results {
+		urls[result.URL] = true
+	}
+	return urls
+}
+
+// Generasi yang di-pin dibangun ulang dari keputusan review dan config saat
+// generasi itu dibangun, bukan dari review queue dan config sekarang
+func TestPinnedGenerationKeepsItsReviewDecisions(t *testing.T) {
+	chdirTemp(t)
+	useTestReviewQueue(t)
+	retainGenerations = true
+	const generation, nextGeneration = 9001, 9002
+	t.Cleanup(func() {
+		retainGenerations = false
+		generationCache.Evict(generation)
+		generationCache.Evict(nextGeneration)
+	})
+
+	cfg := *currentConfig()
+	cfg.GenerationRetention = GenerationRetention{Count: 2}
+	cfg.SoftDeleteDays = 3
+
+	// Artikel terlalu pendek ditahan review queue saat build
+	stub := Article{Title: "Apartemen Baru Dibuka", URL: "https://artikel.rumah123.com/apartemen-baru-dibuka", Date: fixtureDate(2024, 4, 1), Content: "Apartemen baru."}
+	articles := append(fixtureCorpus(), stub)
+	stubID, apartmentURL := len(articles)-1, fixtureArticles[0].URL
+	idx := buildInvertedIndex(articles, &cfg)
+	if idx.Allowed[stubID] || !idx.Allowed[0] {
+		t.Fatalf("allowed at build: stub %v, apartment %v", idx.Allowed[stubID], idx.Allowed[0])
+	}
+	scheduleGenerationRetention(generation, articles, idx.Allowed, &cfg)
+	waitForFile(t, generationPath(generation))
+
+	// Sesudahnya admin membalik keduanya
+	if _, err := reviewQueue.SetStatus(stub.URL, ReviewApproved); err != nil {
+		t.Fatal(err)
+	}
+	reviewQueue.Flag(fixtureArticles[0], nil, nil, false)
+	if _, err := reviewQueue.SetStatus(apartmentURL, ReviewRejected); err != nil {
+		t.Fatal(err)
+	}
+	live := buildInvertedIndex(articles, &cfg)
+	if !live.Allowed[stubID] || live.Allowed[0] {
+		t.Fatalf("allowed after review: stub %v, apartment %v", live.Allowed[stubID], live.Allowed[0])
+	}
+
+	// Membaca generasi lama tidak boleh menandai dokumen di queue yang sekarang
+	rq := useTestReviewQueue(t)
+	pinned, err := loadGeneration(generation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if items := rq.List(""); len(items) != 0 {
+		t.Errorf("loading a generation flagged %d review items", len(items))
+	}
+	if pinned.Index.Allowed[stubID] || !pinned.Index.Allowed[0] {
+		t.Errorf("pinned allowed: stub %v, apartment %v", pinned.Index.Allowed[stubID], pinned.Index.Allowed[0])
+	}
+	if pinned.Config == nil || pinned.Config.SoftDeleteDays != 3 || pinned.Config.GenerationRetention.Count != 2 {
+		t.Errorf("pinned config %+v", pinned.Config)
+	}
+	if cached, err := loadGeneration(generation); err != nil || cached != pinned {
+		t.Errorf("second load not served from the cache (%v)", err)
+	}
+
+	urls := resultURLSet(searching("apartemen", "cosine", SearchOptions{Generation: generation}))
+	if !urls[apartmentURL] || urls[stub.URL] {
+		t.Errorf("pinned search results %v", urls)
+	}
+
+	// Generasi yang dibuang retention juga keluar dari cache
+	cfg.GenerationRetention.Count = 1
+	scheduleGenerationRetention(nextGeneration, articles, live.Allowed, &cfg)
+	waitForFile(t, generationPath(nextGeneration))
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(generationPath(generation)); os.IsNotExist(err) {
+			break
+		}
+	}
+	if _, err := loadGeneration(generation); err != errGenerationNotRetained {
+		t.Errorf("pruned generation: %v", err)
+	}
+}
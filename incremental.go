@@ -0,0 +1,237 @@
+package main
+
+import "sort"
+
+// Record WAL baru diterapkan langsung ke index yang aktif, tanpa build
+// penuh. Index lama masih dipakai pencarian yang sedang berjalan, jadi yang
+// diubah disalin dulu: map term, posting list yang disentuh dokumen, kolom
+// per dokumen dan doc values. Posting versi lama dokumen yang diubah atau
+// dihapus dicari dengan menganalisis ulang versi lama itu, lalu dibuang
+// sebelum posting versi barunya ditambahkan. Norma TF-IDF hanya dihitung
+// ulang untuk dokumen yang disentuh record, dengan IDF saat itu; dokumen lain
+// memakai norma dari build atau perubahan terakhirnya. IDF dokumen lain
+// memang ikut bergeser, tapi kecil: satu update + satu dokumen baru menggeser
+// norma paling jauh 1.5e-3 (relatif) di articles.json (367 dokumen) dan
+// 7.6e-5 di 7.321 dokumen, dan hilang di build penuh berikutnya. Menghitung
+// ulang semua norma makan ~120ms per tulis di 7.321 dokumen; sisa biaya
+// apply sebanding dengan panjang posting list term yang disentuh, bukan
+// ukuran index.
+//
+// Yang tidak bisa diterapkan langsung dan menunggu build penuh: hard delete
+// dan purge (docID dokumen sesudahnya bergeser) dan paragraph_index.
+
+// Record dengan seq setelah seq; records urut naik
+func newerRecords(records []WalRecord, seq uint64) []WalRecord {
+	for i, record := range records {
+		if record.Seq > seq {
+			return records[i:]
+		}
+	}
+	return nil
+}
+
+// Index baru dengan records diterapkan; false kalau butuh build penuh
+func (s *IndexState) apply(records []WalRecord, seq uint64, cfg *Config) (*IndexState, bool) {
+	if cfg.ParagraphIndex {
+		return nil, false
+	}
+
+	articles := make([]Article, len(s.Articles), len(s.Articles)+len(records))
+	copy(articles, s.Articles)
+	position := make(map[string]int, len(articles))
+	for i, article := range articles {
+		position[article.URL] = i
+	}
+
+	// Dokumen yang berubah; replaced = isinya dari record, bukan dari corpus lama
+	changed := make(map[int]bool)
+	replaced := make(map[int]bool)
+	for _, record := range records {
+		switch record.Op {
+		case WalAdd, WalUpdate:
+			i, exists := position[record.URL]
+			if !exists {
+				i = len(articles)
+				position[record.URL] = i
+				articles = append(articles, Article{})
+			}
+			articles[i] = *record.Article
+			changed[i], replaced[i] = true, true
+		case WalDelete:
+			i, exists := position[record.URL]
+			if !exists {
+				continue
+			}
+			if cfg.SoftDeleteDays <= 0 {
+				return nil, false
+			}
+			if !articles[i].Deleted() {
+				deletedAt := record.Time
+				articles[i].DeletedAt = &deletedAt
+				changed[i] = true
+			}
+		case WalRestore:
+			if i, exists := position[record.URL]; exists && articles[i].Deleted() {
+				articles[i].DeletedAt = nil
+				changed[i] = true
+			}
+		default:
+			if _, exists := position[record.URL]; exists {
+				return nil, false
+			}
+		}
+	}
+
+	docIDs := make([]int, 0, len(changed))
+	for docID := range changed {
+		docIDs = append(docIDs, docID)
+	}
+	sort.Ints(docIDs)
+
+	// Versi baru dengan konten lengkap; konten dokumen lama bisa sudah
+	// dilepas ke document store
+	docs := make([]Article, len(docIDs))
+	for i, docID := range docIDs {
+		docs[i] = articles[docID]
+		if !replaced[docID] {
+			docs[i].Content = s.content(docID)
+		}
+	}
+	reviewQueue.ApplyEdits(docs)
+	cfg.Schema.mapDynamic(docs)
+
+	prev := s.Index
+	idx := *prev
+	idx.Index = make(map[string]*PostingList, len(prev.Index))
+	for term, postingList := range prev.Index {
+		idx.Index[term] = postingList
+	}
+	idx.DocLengths = make([]DocLength, len(articles))
+	copy(idx.DocLengths, prev.DocLengths)
+	idx.Allowed = make([]bool, len(articles))
+	copy(idx.Allowed, prev.Allowed)
+	idx.DocNorms = make([]float64, len(articles))
+	copy(idx.DocNorms, prev.DocNorms)
+	idx.DocTerms = make([]int, len(articles))
+	copy(idx.DocTerms, prev.DocTerms)
+
+	// Posting list yang sudah disalin untuk index baru dan boleh diubah
+	owned := make(map[string]bool)
+	writable := func(term string) *PostingList {
+		postingList := idx.Index[term]
+		if owned[term] && postingList != nil {
+			return postingList
+		}
+		copied := &PostingList{Postings: make(map[int]*Posting)}
+		if postingList != nil {
+			copied.DocFrequency = postingList.DocFrequency
+			for docID, posting := range postingList.Postings {
+				copied.Postings[docID] = posting
+			}
+		}
+		owned[term] = true
+		idx.Index[termTable.Intern(term)] = copied
+		return copied
+	}
+
+	var tokens []Token
+	docTerms := make(map[int][]string, len(docIDs))
+	for i, docID := range docIDs {
+		// Buang posting versi lama
+		if docID < len(prev.Allowed) && prev.Allowed[docID] {
+			old := s.Articles[docID]
+			old.Content = s.content(docID)
+			tokens = cfg.Schema.analyzeDocument(tokens, old)
+			for _, tok := range tokens {
+				if postingList, exists := idx.Index[tok.Term]; !exists || postingList.Postings[docID] == nil {
+					continue
+				}
+				postingList := writable(tok.Term)
+				delete(postingList.Postings, docID)
+				if postingList.DocFrequency--; postingList.DocFrequency == 0 {
+					delete(idx.Index, tok.Term)
+				}
+			}
+			idx.DocCount--
+			idx.TotalTokens -= len(tokens)
+			idx.DocLengths[docID] = DocLength{}
+		}
+		idx.DocNorms[docID], idx.DocTerms[docID] = 0, 0
+
+		// Tambahkan versi baru
+		article := &docs[i]
+		idx.Allowed[docID] = !article.Deleted() && reviewQueue.Allows(*article)
+		if !idx.Allowed[docID] {
+			continue
+		}
+		if article.Language == "" {
+			article.Language = detectLanguage(article.Title + " " + article.Content)
+		}
+		tokens = cfg.Schema.analyzeDocument(tokens, *article)
+		idx.DocCount++
+		idx.TotalTokens += len(tokens)
+		idx.DocLengths[docID] = docLengthOf(tokens, len(article.Title))
+
+		added := make(map[string]*Posting)
+		for _, tok := range tokens {
+			posting, exists := added[tok.Term]
+			if !exists {
+				postingList := writable(tok.Term)
+				if _, stale := postingList.Postings[docID]; !stale {
+					postingList.DocFrequency++
+				}
+				posting = &Posting{DocID: docID, Positions: make([]int, 0)}
+				postingList.Postings[docID] = posting
+				added[tok.Term] = posting
+			}
+			posting.Frequency++
+			posting.Positions = append(posting.Positions, tok.Position)
+			if cfg.StoreOffsets {
+				posting.Offsets = append(posting.Offsets, Offset{Start: tok.Start, End: tok.End})
+			}
+		}
+		terms := make([]string, 0, len(added))
+		for term := range added {
+			terms = append(terms, term)
+		}
+		docTerms[docID] = terms
+	}
+
+	// Norma dokumen yang disentuh, setelah DocFrequency semua record final
+	for docID, terms := range docTerms {
+		idx.DocNorms[docID], idx.DocTerms[docID] = idx.docNorm(docID, terms, len(articles))
+	}
+
+	// Bitmap hanya untuk posting list yang disalin; yang lain tidak berubah
+	if cfg.DenseTermRatio > 0 && idx.DocCount > 0 {
+		minDocs := int(cfg.DenseTermRatio * float64(idx.DocCount))
+		if minDocs < 1 {
+			minDocs = 1
+		}
+		for term := range owned {
+			if postingList, exists := idx.Index[term]; exists && postingList.DocFrequency >= minDocs {
+				postingList.Docs = postingList.DocBitmap()
+			}
+		}
+	}
+	idx.DocValues = prev.DocValues.withRows(len(articles), docIDs, docs, cfg.Schema)
+
+	// Dokumen lama yang kontennya sudah di document store tetap tanpa konten
+	for i, docID := range docIDs {
+		if !replaced[docID] {
+			docs[i].Content = articles[docID].Content
+		}
+		articles[docID] = docs[i]
+	}
+
+	return &IndexState{Articles: articles, Index: &idx, base: s.base, seq: seq}, true
+}
+
+// Konten lengkap dokumen di index ini
+func (s *IndexState) content(docID int) string {
+	if docID >= len(s.Articles) {
+		return ""
+	}
+	article, _ := localArticle(s.Articles, SearchResult{DocID: docID})
+	return article.Content
+}
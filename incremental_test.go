@@ -0,0 +1,117 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func searchURLs(articles []Article, idx *InvertedIndex, query string, cfg *Config) []string {
+	results, _ := searchIndex(articles, idx, query, buildQueryVector(query), "cosine", SearchOptions{Config: cfg}, false)
+	urls := make([]string, len(results))
+	for i, result := range results {
+		urls[i] = result.URL
+	}
+	return urls
+}
+
+// Index hasil apply harus sama dengan build penuh corpus yang sama, kecuali
+// norma dokumen yang tidak disentuh (lihat komentar di incremental.go)
+func TestIncrementalApplyMatchesFullBuild(t *testing.T) {
+	cfg := currentConfig()
+	corpus := fixtureCorpus()
+	state := &IndexState{Articles: corpus, Index: buildInvertedIndex(corpus, cfg)}
+
+	updated := corpus[0]
+	updated.Content = strings.Replace(updated.Content, "Lebak Bulus", "Cilandak dan Pondok Indah, dekat stasiun MRT", 1)
+	added := corpus[2]
+	added.Title = "Rumah Subsidi di Jakarta Timur"
+	added.URL = "https://artikel.rumah123.com/rumah-subsidi-jakarta-timur"
+	added.Content = strings.NewReplacer("Bekasi", "Jakarta Timur", "BTN", "DKI", "100 unit", "250 unit").Replace(added.Content)
+	records := []WalRecord{
+		fixtureRecord(WalUpdate, updated),
+		fixtureRecord(WalAdd, added),
+		{Op: WalDelete, URL: corpus[5].URL},
+	}
+	for i := range records {
+		records[i].Seq = uint64(i + 1)
+	}
+
+	next, ok := state.apply(records, 3, cfg)
+	if !ok {
+		t.Fatal("apply needs a full build")
+	}
+	full := applyWalRecords(fixtureCorpus(), records, cfg)
+	want := buildInvertedIndex(full, cfg)
+	got := next.Index
+
+	if len(next.Articles) != len(full) || got.DocCount != want.DocCount || got.TotalTokens != want.TotalTokens {
+		t.Fatalf("%d docs, %d indexed, %d tokens; full build %d, %d, %d",
+			len(next.Articles), got.DocCount, got.TotalTokens, len(full), want.DocCount, want.TotalTokens)
+	}
+	for docID := range full {
+		if got.Allowed[docID] != want.Allowed[docID] || got.DocLengths[docID] != want.DocLengths[docID] || got.DocTerms[docID] != want.DocTerms[docID] {
+			t.Errorf("doc %d: allowed %v, length %+v, terms %d; full build %v, %+v, %d", docID,
+				got.Allowed[docID], got.DocLengths[docID], got.DocTerms[docID], want.Allowed[docID], want.DocLengths[docID], want.DocTerms[docID])
+		}
+	}
+	if len(got.Index) != len(want.Index) {
+		t.Errorf("%d terms, full build %d", len(got.Index), len(want.Index))
+	}
+	for term, wantList := range want.Index {
+		gotList := got.Index[term]
+		if gotList == nil || gotList.DocFrequency != wantList.DocFrequency || len(gotList.Postings) != len(wantList.Postings) {
+			t.Errorf("term %q differs from full build", term)
+			continue
+		}
+		for docID, wantPosting := range wantList.Postings {
+			if posting := gotList.Postings[docID]; posting == nil || posting.Frequency != wantPosting.Frequency {
+				t.Errorf("term %q doc %d differs from full build", term, docID)
+			}
+		}
+	}
+
+	// Norma dokumen yang disentuh persis; yang lain bergeser karena IDF-nya
+	// dari corpus lama. Di corpus 14 dokumen satu dokumen baru menggeser IDF
+	// jauh lebih besar daripada di corpus sungguhan, jadi batasnya longgar.
+	touched := map[int]bool{0: true, 5: true, len(corpus): true}
+	for docID := range full {
+		drift := math.Abs(got.DocNorms[docID]-want.DocNorms[docID]) / want.DocNorms[docID]
+		if want.DocNorms[docID] == 0 {
+			drift = got.DocNorms[docID]
+		}
+		if touched[docID] && drift > 1e-12 || drift > 0.05 {
+			t.Errorf("doc %d norm %v, full build %v", docID, got.DocNorms[docID], want.DocNorms[docID])
+		}
+	}
+
+	// Tidak ada yang tertinggal dari index lama: state awal tidak berubah
+	if state.Articles[0].Content == updated.Content || len(state.Index.Allowed) != len(corpus) || !state.Index.Allowed[5] {
+		t.Error("apply modified the previous index")
+	}
+
+	// Urutan hasil sama; artikel rumah subsidi yang skornya hampir sama boleh
+	// bertukar tempat, jadi untuk query itu hanya hasil teratas dan
+	// himpunannya yang dibandingkan
+	for _, query := range []string{"apartemen", "stasiun mrt"} {
+		gotURLs := searchURLs(next.Articles, got, query, cfg)
+		wantURLs := searchURLs(full, want, query, cfg)
+		if strings.Join(gotURLs, " ") != strings.Join(wantURLs, " ") {
+			t.Errorf("%q: %v, full build %v", query, gotURLs, wantURLs)
+		}
+	}
+	for _, query := range []string{"subsidi jakarta timur", "kpr bekasi"} {
+		gotURLs := searchURLs(next.Articles, got, query, cfg)
+		wantURLs := searchURLs(full, want, query, cfg)
+		if len(gotURLs) == 0 || len(gotURLs) != len(wantURLs) || gotURLs[0] != wantURLs[0] {
+			t.Errorf("%q: %v, full build %v", query, gotURLs, wantURLs)
+			continue
+		}
+		sort.Strings(gotURLs)
+		sort.Strings(wantURLs)
+		if strings.Join(gotURLs, " ") != strings.Join(wantURLs, " ") {
+			t.Errorf("%q: %v, full build %v", query, gotURLs, wantURLs)
+		}
+	}
+}
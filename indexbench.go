@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// searchctl bench index: waktu build index dengan 1 sampai N worker di corpus
+// sintetis (atau articles.json dengan --corpus), beserta speedup terhadap 1
+// worker. Setiap hasil juga dibandingkan dengan index 1 worker, karena build
+// paralel harus menghasilkan index yang sama persis. Review queue tidak
+// dipanggil supaya corpus sintetis tidak masuk review_queue.json.
+func benchIndexCommand(args []string) int {
+	fs := flag.NewFlagSet("bench index", flag.ExitOnError)
+	docs := fs.Int("docs", 5000, "jumlah dokumen corpus sintetis")
+	seed := fs.Int64("seed", 1, "seed corpus sintetis")
+	corpus := fs.Bool("corpus", false, "pakai articles.json, bukan corpus sintetis")
+	maxWorkers := fs.Int("workers", runtime.NumCPU(), "jumlah worker terbanyak")
+	fs.Parse(args)
+
+	articles := generateCorpus(*docs, *seed, 5)
+	if *corpus {
+		var err error
+		if articles, err = loadArticles(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	// Salinan config dengan index_workers diganti, config aktif tidak disentuh
+	withWorkers := func(workers int) *Config {
+		cfg := *currentConfig()
+		cfg.IndexWorkers = workers
+		return &cfg
+	}
+	build := func(workers int) *InvertedIndex {
+		docs := make([]Article, len(articles))
+		copy(docs, articles)
+		return indexDocuments(docs, func(int) bool { return true }, withWorkers(workers))
+	}
+
+	expected := build(1)
+	fmt.Printf("%d documents, %d terms, %d CPUs\n", len(articles), len(expected.Index), runtime.NumCPU())
+
+	var baseline int64
+	status := 0
+	for workers := 1; workers <= *maxWorkers; workers *= 2 {
+		if indexWorkers(len(articles), withWorkers(workers)) != workers {
+			fmt.Printf("  %2d workers: corpus too small (%d docs per worker minimum)\n", workers, INDEX_SHARD_MIN_DOCS)
+			break
+		}
+		if !sameIndex(build(workers), expected) {
+			fmt.Printf("  %2d workers: index differs from 1 worker\n", workers)
+			status = 1
+			continue
+		}
+
+		result := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				build(workers)
+			}
+		})
+		if workers == 1 {
+			baseline = result.NsPerOp()
+		}
+		fmt.Printf("  %2d workers: %12d ns/op %12d B/op %10d allocs/op  %.2fx\n",
+			workers, result.NsPerOp(), result.AllocedBytesPerOp(), result.AllocsPerOp(),
+			float64(baseline)/float64(result.NsPerOp()))
+	}
+	return status
+}
+
+// Index sama: term, posting dan kolom per dokumen. Norma dibandingkan dengan
+// toleransi karena urutan penjumlahan mengikuti urutan map.
+func sameIndex(a, b *InvertedIndex) bool {
+	if a.DocCount != b.DocCount || a.TotalTokens != b.TotalTokens || len(a.Index) != len(b.Index) ||
+		!reflect.DeepEqual(a.DocLengths, b.DocLengths) || !reflect.DeepEqual(a.DocTerms, b.DocTerms) ||
+		len(a.DocNorms) != len(b.DocNorms) {
+		return false
+	}
+	for docID := range a.DocNorms {
+		if math.Abs(a.DocNorms[docID]-b.DocNorms[docID]) > 1e-9 {
+			return false
+		}
+	}
+	for term, postingList := range a.Index {
+		other, exists := b.Index[term]
+		if !exists || postingList.DocFrequency != other.DocFrequency || !reflect.DeepEqual(postingList.Postings, other.Postings) {
+			return false
+		}
+	}
+	return true
+}
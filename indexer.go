@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Indexer membangun corpus dan inverted index (beserta statistik TF-IDF,
+// doc values dan index paragraf) di goroutine background, lalu menukar
+// pointer index yang dipakai pencarian secara atomik. Pencarian selalu
+// memakai index terakhir yang sudah jadi dan tidak pernah menunggu build.
+// Build dipicu oleh:
+//
+//   - perubahan file: articles.json atau WAL berubah, dicek setiap INDEX_WATCH_INTERVAL
+//   - admin: POST /api/admin/reindex, juga setelah keputusan review queue dan reload config
+//   - timer: index_rebuild_interval_seconds di config.json (0 = tidak ada)
+//
+// Pemicu yang datang saat build berjalan digabung jadi satu build berikutnya.
+// Tanpa goroutine background (perintah searchctl) index dibangun langsung
+// saat dibutuhkan dan corpus berubah.
+//
+// Kalau yang berubah hanya record baru di WAL, record itu diterapkan ke
+// salinan index yang aktif (incremental.go) tanpa membangun ulang semuanya.
+// Build penuh tetap dijalankan setelah WAL di-merge ke articles.json, dan
+// hanya build penuh yang menjadi generasi index baru.
+const (
+	INDEX_WATCH_INTERVAL = time.Second
+
+	IndexTriggerStartup = "startup"
+	IndexTriggerChange  = "change"
+	IndexTriggerAdmin   = "admin"
+	IndexTriggerTimer   = "timer"
+	// Perubahan lewat /api/documents, diterapkan sebelum response dikirim
+	IndexTriggerDocument = "document"
+)
+
+// Satu index yang sudah jadi; tidak pernah diubah setelah dipasang
+type IndexState struct {
+	Articles []Article
+	Index    *InvertedIndex
+	// Input build: articles.json ditambah versi invalidasi, dan record WAL
+	// terakhir yang sudah masuk
+	base string
+	seq  uint64
+}
+
+type IndexerStatus struct {
+	Generation    int       `json:"generation"`
+	Docs          int       `json:"docs"`
+	Building      bool      `json:"building"`
+	Builds        int       `json:"builds"`
+	LastTrigger   string    `json:"last_trigger,omitempty"`
+	LastBuildAt   time.Time `json:"last_build_at,omitempty"`
+	LastBuildMs   float64   `json:"last_build_ms"`
+	LastError     string    `json:"last_error,omitempty"`
+	RebuildPeriod int       `json:"rebuild_interval_seconds"`
+	// Record WAL yang diterapkan langsung ke index, sejak build penuh terakhir
+	Incremental       int     `json:"incremental_records"`
+	LastIncrementalMs float64 `json:"last_incremental_ms"`
+}
+
+type Indexer struct {
+	current atomic.Value // *IndexState
+
+	// Satu build dalam satu waktu
+	buildMu sync.Mutex
+	trigger chan string
+	running int32
+	// Naik setiap input index di luar corpus berubah (review queue, config)
+	invalidation int64
+
+	mu     sync.Mutex
+	status IndexerStatus
+}
+
+var indexer = &Indexer{trigger: make(chan string, 1)}
+
+func (ix *Indexer) state() *IndexState {
+	state, _ := ix.current.Load().(*IndexState)
+	return state
+}
+
+func (ix *Indexer) base() string {
+	return fmt.Sprintf("%s|%d", corpusGenerationKey(nil), atomic.LoadInt64(&ix.invalidation))
+}
+
+// Index belum memuat corpus, record WAL atau invalidasi terbaru
+func (ix *Indexer) stale(state *IndexState) bool {
+	return state == nil || state.base != ix.base() || state.seq != lastSeq(documentLog.Pending())
+}
+
+// Corpus dan index yang sedang aktif. Tanpa goroutine background index yang
+// basi dibangun ulang di sini; dengan goroutine background selalu index
+// terakhir, build berikutnya berjalan di belakang.
+func (ix *Indexer) Current() ([]Article, *InvertedIndex, error) {
+	state := ix.state()
+	if state == nil || (atomic.LoadInt32(&ix.running) == 0 && ix.stale(state)) {
+		var err error
+		if state, err = ix.rebuild(IndexTriggerStartup, false); err != nil {
+			return nil, nil, err
+		}
+	}
+	return state.Articles, state.Index, nil
+}
+
+// Minta build di background tanpa menunggu; pemicu yang sudah antre cukup satu
+func (ix *Indexer) Trigger(reason string) {
+	select {
+	case ix.trigger <- reason:
+	default:
+	}
+}
+
+// Input index di luar corpus berubah (review queue, config): build ulang
+// walaupun generasi corpus sama
+func (ix *Indexer) Invalidate() {
+	atomic.AddInt64(&ix.invalidation, 1)
+	if atomic.LoadInt32(&ix.running) == 1 {
+		ix.Trigger(IndexTriggerAdmin)
+	}
+}
+
+// Bangun index baru lalu pasang. Tanpa force, index yang masih sesuai
+// input-nya tidak dibangun ulang, dan record WAL baru diterapkan langsung
+// kalau bisa.
+func (ix *Indexer) rebuild(reason string, force bool) (*IndexState, error) {
+	ix.buildMu.Lock()
+	defer ix.buildMu.Unlock()
+
+	// Satu snapshot config untuk seluruh pass: build, laporan dan retensi
+	cfg := currentConfig()
+	pending := documentLog.Pending()
+	base, seq := ix.base(), lastSeq(pending)
+	if state := ix.state(); state != nil && state.base == base && !force {
+		if state.seq == seq {
+			return state, nil
+		}
+		start := time.Now()
+		records := newerRecords(pending, state.seq)
+		if next, ok := state.apply(records, seq, cfg); ok {
+			ix.current.Store(next)
+			ix.mu.Lock()
+			ix.status.Incremental += len(records)
+			ix.status.LastIncrementalMs = float64(time.Since(start).Microseconds()) / 1000
+			ix.mu.Unlock()
+			purgeResultCaches()
+			return next, nil
+		}
+	}
+
+	ix.mu.Lock()
+	ix.status.Building = true
+	ix.status.LastTrigger = reason
+	ix.mu.Unlock()
+
+	start := time.Now()
+	generationKey, articles, idx, served, err := buildIndex(pending, cfg)
+
+	ix.mu.Lock()
+	ix.status.Building = false
+	if err != nil {
+		ix.status.LastError = err.Error()
+		ix.mu.Unlock()
+		return nil, err
+	}
+	state := &IndexState{Articles: served, Index: idx, base: base, seq: seq}
+	ix.current.Store(state)
+	ix.status.Builds++
+	ix.status.Incremental = 0
+	ix.status.LastBuildAt = time.Now()
+	ix.status.LastBuildMs = float64(time.Since(start).Microseconds()) / 1000
+	ix.status.LastError = ""
+	ix.mu.Unlock()
+
+	// Setelah index dipasang, supaya job yang dijadwalkan generasi baru
+	// (snapshot regresi, query yang dipantau) sudah mencari di index ini
+	noteIndexGeneration(generationKey, articles, idx, time.Since(start), cfg)
+	// Review queue dan config tidak mengubah generasi corpus
+	purgeResultCaches()
+	return state, nil
+}
+
+// Goroutine build: memantau perubahan file, pemicu admin dan timer
+func (ix *Indexer) Start() {
+	if !atomic.CompareAndSwapInt32(&ix.running, 0, 1) {
+		return
+	}
+	if _, err := ix.rebuild(IndexTriggerStartup, false); err != nil {
+		log.Printf("Error building index: %v", err)
+	}
+
+	go func() {
+		watch := time.NewTicker(INDEX_WATCH_INTERVAL)
+		defer watch.Stop()
+		lastTimer := time.Now()
+		for {
+			var reason string
+			select {
+			case reason = <-ix.trigger:
+			case <-watch.C:
+				if period := time.Duration(currentConfig().IndexRebuildIntervalSeconds) * time.Second; period > 0 && time.Since(lastTimer) >= period {
+					reason = IndexTriggerTimer
+				} else if ix.stale(ix.state()) {
+					reason = IndexTriggerChange
+				} else {
+					continue
+				}
+			}
+			if reason == IndexTriggerTimer {
+				lastTimer = time.Now()
+			}
+			// Admin dan timer selalu membangun ulang, perubahan file hanya kalau basi
+			if _, err := ix.rebuild(reason, reason != IndexTriggerChange); err != nil {
+				log.Printf("Error rebuilding index (%s): %v", reason, err)
+			}
+		}
+	}()
+}
+
+func (ix *Indexer) Status() IndexerStatus {
+	ix.mu.Lock()
+	status := ix.status
+	ix.mu.Unlock()
+
+	status.Generation = currentGeneration()
+	if state := ix.state(); state != nil {
+		status.Docs = len(state.Articles)
+	}
+	status.RebuildPeriod = currentConfig().IndexRebuildIntervalSeconds
+	return status
+}
+
+// GET /api/admin/indexer
+func indexerStatusHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, indexer.Status())
+}
+
+// POST /api/admin/reindex: bangun ulang index di background, pencarian tetap
+// memakai index lama sampai yang baru selesai
+func reindexHandler(c *gin.Context) {
+	indexer.Trigger(IndexTriggerAdmin)
+	auditActor(c).Record(AuditReindex, "", nil, nil)
+	c.JSON(http.StatusAccepted, indexer.Status())
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Masalah yang ditemukan saat verifikasi index
+type VerifyIssue struct {
+	Kind   string `json:"kind"`
+	DocID  int    `json:"doc_id"`
+	Term   string `json:"term,omitempty"`
+	Detail string `json:"detail"`
+}
+
+type VerifyReport struct {
+	Docs        int           `json:"docs"`
+	IndexedDocs int           `json:"indexed_docs"`
+	Terms       int           `json:"terms"`
+	Postings    int           `json:"postings"`
+	Issues      []VerifyIssue `json:"issues"`
+	Repaired    []string      `json:"repaired,omitempty"`
+
+	orphanReviews []string
+	badDocStore   bool
+	docStorePath  string // document store yang diperiksa, dipakai repair
+}
+
+func (r *VerifyReport) add(kind string, docID int, term, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, VerifyIssue{Kind: kind, DocID: docID, Term: term, Detail: fmt.Sprintf(format, args...)})
+}
+
+// searchctl index verify [--repair] [--max-issues N]
+func indexVerifyCommand(args []string) int {
+	fs := flag.NewFlagSet("index verify", flag.ExitOnError)
+	repair := fs.Bool("repair", false, "perbaiki masalah yang bisa diperbaiki (document store, entri orphan)")
+	maxIssues := fs.Int("max-issues", 50, "jumlah masalah maksimum yang dicetak")
+	jsonOutput := fs.Bool("json", false, "cetak report sebagai JSON")
+	fs.Parse(args)
+
+	report, err := verifyIndex()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verify failed: %v\n", err)
+		return 1
+	}
+
+	if *repair {
+		if err := repairIndex(report); err != nil {
+			fmt.Fprintf(os.Stderr, "repair failed: %v\n", err)
+			return 1
+		}
+	}
+
+	if *jsonOutput {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		return verifyExitCode(report, *repair)
+	}
+
+	fmt.Printf("docs=%d indexed=%d terms=%d postings=%d issues=%d\n",
+		report.Docs, report.IndexedDocs, report.Terms, report.Postings, len(report.Issues))
+	for i, issue := range report.Issues {
+		if i == *maxIssues {
+			fmt.Printf("... %d more\n", len(report.Issues)-i)
+			break
+		}
+		if issue.Term != "" {
+			fmt.Printf("  %-16s doc=%d term=%q %s\n", issue.Kind, issue.DocID, issue.Term, issue.Detail)
+		} else {
+			fmt.Printf("  %-16s doc=%d %s\n", issue.Kind, issue.DocID, issue.Detail)
+		}
+	}
+
+	for _, action := range report.Repaired {
+		fmt.Println("repaired:", action)
+	}
+	return verifyExitCode(report, *repair)
+}
+
+// Exit 1 kalau ada masalah yang belum diperbaiki
+func verifyExitCode(report *VerifyReport, repaired bool) int {
+	if len(report.Issues) > 0 && !repaired {
+		return 1
+	}
+	return 0
+}
+
+// Bangun index dari corpus lalu cocokkan silang dengan corpus, document store,
+// blocklist dan review queue
+func verifyIndex() (*VerifyReport, error) {
+	raw, err := loadArticles()
+	if err != nil {
+		return nil, err
+	}
+	articles := make([]Article, len(raw))
+	copy(articles, raw)
+	reviewQueue.ApplyEdits(articles)
+
+	cfg := currentConfig()
+	idx := buildInvertedIndex(articles, cfg)
+	report := &VerifyReport{Docs: len(articles), IndexedDocs: idx.DocCount, Terms: len(idx.Index)}
+
+	verifyPostings(report, idx, articles)
+	verifyOrphans(report, articles)
+	if cfg.DocStorePath != "" {
+		report.docStorePath = cfg.DocStorePath
+		verifyDocStore(report, cfg.DocStorePath, raw)
+	}
+
+	return report, nil
+}
+
+// Posting list harus konsisten dengan dirinya sendiri dan dengan hasil analisis ulang dokumen
+func verifyPostings(report *VerifyReport, idx *InvertedIndex, articles []Article) {
+	indexed := make(map[int]map[string]int) // docID -> term -> frequency di index
+
+	for term, postingList := range idx.Index {
+		report.Postings += len(postingList.Postings)
+
+		if postingList.DocFrequency != len(postingList.Postings) {
+			report.add("doc_frequency", -1, term, "df=%d postings=%d", postingList.DocFrequency, len(postingList.Postings))
+		}
+		if postingList.Docs != nil && postingList.Docs.Cardinality() != len(postingList.Postings) {
+			report.add("bitmap", -1, term, "bitmap=%d postings=%d", postingList.Docs.Cardinality(), len(postingList.Postings))
+		}
+
+		for docID, posting := range postingList.Postings {
+			if docID < 0 || docID >= len(articles) {
+				report.add("orphaned_doc", docID, term, "posting for doc outside corpus (%d docs)", len(articles))
+				continue
+			}
+			if postingList.Docs != nil && !postingList.Docs.Contains(docID) {
+				report.add("bitmap", docID, term, "doc missing from bitmap")
+			}
+			if posting.Frequency != len(posting.Positions) {
+				report.add("positions", docID, term, "frequency=%d positions=%d", posting.Frequency, len(posting.Positions))
+			}
+			textLen := len(articles[docID].Title) + 1 + len(articles[docID].Content)
+			for _, off := range posting.Offsets {
+				if off.Start < 0 || off.End > textLen || off.Start >= off.End {
+					report.add("offsets", docID, term, "offset %d-%d outside text (%d bytes)", off.Start, off.End, textLen)
+					break
+				}
+			}
+
+			if indexed[docID] == nil {
+				indexed[docID] = make(map[string]int)
+			}
+			indexed[docID][term] = posting.Frequency
+		}
+	}
+
+	for docID, article := range articles {
+		terms, isIndexed := indexed[docID]
+		allowed := !article.Deleted() && reviewQueue.Allows(article)
+		if !allowed {
+			if isIndexed {
+				report.add("orphaned_doc", docID, "", "doc is held/rejected/deleted but has %d postings", len(terms))
+			}
+			continue
+		}
+
+		expected := make(map[string]int)
+		for _, term := range analyzerFor(article.Language).ProcessText(article.Title + " " + article.Content) {
+			expected[term]++
+		}
+		for term, freq := range expected {
+			if terms[term] != freq {
+				report.add("term_frequency", docID, term, "corpus=%d index=%d", freq, terms[term])
+			}
+		}
+		tokens := 0
+		for term, freq := range terms {
+			tokens += freq
+			if _, exists := expected[term]; !exists {
+				report.add("stale_posting", docID, term, "term not in document")
+			}
+		}
+		if length := idx.DocLengths[docID].Tokens(); length != tokens {
+			report.add("doc_length", docID, "", "stored length=%d postings=%d", length, tokens)
+		}
+	}
+}
+
+// Entri review queue yang menunjuk dokumen yang sudah tidak ada. Blocklist
+// disimpan per URL dan tetap berlaku untuk URL di luar corpus (crawler
+// melewatinya), jadi tidak diperiksa.
+func verifyOrphans(report *VerifyReport, articles []Article) {
+	urls := make(map[string]bool, len(articles))
+	for _, article := range articles {
+		urls[article.URL] = true
+	}
+	for _, item := range reviewQueue.List("") {
+		if !urls[item.URL] {
+			report.add("orphaned_review", -1, "", "review item for %s not in corpus", item.URL)
+			report.orphanReviews = append(report.orphanReviews, item.URL)
+		}
+	}
+}
+
+// Cek checksum setiap block document store dan kecocokan isinya dengan corpus
+func verifyDocStore(report *VerifyReport, path string, articles []Article) {
+	ds, err := openDocStore(path)
+	if err != nil {
+		report.add("docstore", -1, "", "%v", err)
+		report.badDocStore = true
+		return
+	}
+	defer ds.Close()
+
+	if ds.Count() != len(articles) {
+		report.add("docstore", -1, "", "store has %d docs, corpus has %d", ds.Count(), len(articles))
+		report.badDocStore = true
+	}
+
+	for n := 0; n < ds.BlockCount(); n++ {
+		if _, err := ds.block(n); err != nil {
+			report.add("docstore", n*ds.blockDocs, "", "block %d: %v", n, err)
+			report.badDocStore = true
+		}
+	}
+	if report.badDocStore {
+		return
+	}
+
+	for docID, article := range articles {
+		stored, err := ds.Get(docID)
+		if err != nil {
+			report.add("docstore", docID, "", "%v", err)
+			report.badDocStore = true
+			continue
+		}
+		if stored.URL != article.URL || stored.Title != article.Title || stored.Content != article.Content {
+			report.add("docstore", docID, "", "stored document differs from corpus")
+			report.badDocStore = true
+		}
+	}
+}
+
+// Index dibangun ulang dari corpus setiap load, jadi yang diperbaiki adalah
+// data turunan yang tersimpan: document store dan entri orphan
+func repairIndex(report *VerifyReport) error {
+	if report.badDocStore {
+		if err := rebuildDocStore(report.docStorePath); err != nil {
+			return err
+		}
+		report.Repaired = append(report.Repaired, "rebuilt document store "+report.docStorePath)
+	}
+	if len(report.orphanReviews) > 0 {
+		if err := reviewQueue.Remove(report.orphanReviews); err != nil {
+			return err
+		}
+		report.Repaired = append(report.Repaired, fmt.Sprintf("removed %d orphaned review items", len(report.orphanReviews)))
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import "sync"
+
+// Tabel string term bersama. Term hasil analyzer adalah substring teks
+// dokumen, jadi kalau langsung dipakai sebagai key index, setiap key menahan
+// seluruh konten artikel asalnya di memori (walaupun Content sudah dilepas
+// karena document store). Term disalin sekali ke tabel ini dan dipakai ulang
+// oleh setiap index yang dibangun (index dibangun ulang per query), juga oleh
+// map turunannya seperti skor TF-IDF yang memakai key index yang sama.
+type TermTable struct {
+	mu    sync.Mutex
+	terms map[string]string
+}
+
+var termTable = &TermTable{terms: make(map[string]string)}
+
+// Versi tersimpan dari term, disalin kalau belum ada di tabel
+func (t *TermTable) Intern(term string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if interned, exists := t.terms[term]; exists {
+		return interned
+	}
+	interned := string([]byte(term))
+	t.terms[interned] = interned
+	return interned
+}
+
+func (t *TermTable) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.terms)
+}
@@ -0,0 +1,544 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Job yang berjalan di background lewat satu antrian: operasi bulk admin
+// (bulk.go) dan pekerjaan berkala seperti arsip, purge soft delete, mining
+// zero-result, refresh saran, cek reindex, snapshot regresi dan evaluasi
+// alert. Request HTTP hanya memulai job dan langsung mendapat 202 dengan
+// id-nya; progres dibaca lewat GET /api/admin/jobs/:id dan job dibatalkan
+// lewat POST /api/admin/jobs/:id/cancel.
+//
+// Setiap jenis job punya lane. Job di lane yang sama dijalankan satu per
+// satu (operasi bulk berbagi satu lane supaya tidak saling menimpa dokumen
+// yang sama) dan menunggu dengan status queued; lane berbeda berjalan
+// paralel. Job yang gagal dicoba lagi sesuai RetryPolicy jenisnya. Daftar job
+// disimpan di jobs.json setiap status berubah. Setelah restart, job jenis
+// terdaftar yang belum selesai dijalankan lagi; operasi bulk yang terputus
+// ditandai failed, tapi perubahan yang sudah masuk WAL tetap ada.
+const (
+	JOBS_FILE = "jobs.json"
+
+	JobQueued   = "queued"
+	JobRunning  = "running"
+	JobDone     = "done"
+	JobFailed   = "failed"
+	JobCanceled = "canceled"
+
+	JOB_HISTORY    = 50 // job selesai per jenis yang tetap bisa dibaca
+	JOB_MAX_ERRORS = 20 // error per dokumen yang dicatat di job
+)
+
+type Job struct {
+	ID     string            `json:"id"`
+	Kind   string            `json:"kind"`
+	Status string            `json:"status"`
+	Params map[string]string `json:"params,omitempty"`
+	// Dokumen yang akan diproses, diketahui setelah job mulai
+	Total     int `json:"total"`
+	Processed int `json:"processed"`
+	Changed   int `json:"changed"`
+	Failed    int `json:"failed"`
+	// Processed / Total, 0..1
+	Progress float64  `json:"progress"`
+	Errors   []string `json:"errors,omitempty"`
+	Error    string   `json:"error,omitempty"` // alasan job failed, atau percobaan terakhir yang gagal
+	// Percobaan yang sudah dimulai dan batasnya dari RetryPolicy
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	NextRunAt   *time.Time `json:"next_run_at,omitempty"` // job queued yang menunggu retry
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+func (j Job) Finished() bool {
+	return j.Status == JobDone || j.Status == JobFailed || j.Status == JobCanceled
+}
+
+// Job yang gagal dicoba lagi sampai MaxAttempts percobaan. Jeda sebelum
+// percobaan kedua Backoff, lalu berlipat dua setiap percobaan berikutnya.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// Untuk pekerjaan berkala yang gagalnya biasanya sementara (file terkunci,
+// disk penuh sesaat)
+var maintenanceRetry = RetryPolicy{MaxAttempts: 3, Backoff: time.Minute}
+
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	return p.Backoff << uint(attempt-1)
+}
+
+type jobFunc func(ctx context.Context, job jobRun) error
+
+// Jenis job terdaftar. run kosong untuk job yang dijalankan dengan closure
+// (operasi bulk); job seperti itu tidak bisa dilanjutkan setelah restart.
+type jobKind struct {
+	lane  string
+	retry RetryPolicy
+	run   jobFunc
+}
+
+var jobKinds = map[string]jobKind{}
+
+// Dipanggil dari init(); lane kosong = lane sendiri untuk jenis ini
+func registerJobKind(kind, lane string, retry RetryPolicy, run jobFunc) {
+	if lane == "" {
+		lane = kind
+	}
+	jobKinds[kind] = jobKind{lane: lane, retry: retry, run: run}
+}
+
+func kindOf(kind string) jobKind {
+	if registered, exists := jobKinds[kind]; exists {
+		return registered
+	}
+	return jobKind{lane: kind}
+}
+
+type JobManager struct {
+	mu      sync.Mutex
+	path    string // kosong = tidak disimpan (searchctl, test)
+	jobs    map[string]*Job
+	cancels map[string]context.CancelFunc
+	order   []string // urut dibuat
+	seq     int
+	slots   map[string]chan struct{} // satu job berjalan per lane
+}
+
+func newJobManager() *JobManager {
+	return &JobManager{
+		jobs:    make(map[string]*Job),
+		cancels: make(map[string]context.CancelFunc),
+		slots:   make(map[string]chan struct{}),
+	}
+}
+
+var adminJobs = newJobManager()
+
+// Handle progres untuk fungsi yang dijalankan job
+type jobRun struct {
+	manager *JobManager
+	id      string
+}
+
+func (r jobRun) update(fn func(job *Job)) {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	job := r.manager.jobs[r.id]
+	fn(job)
+	if job.Total > 0 {
+		job.Progress = float64(job.Processed) / float64(job.Total)
+	}
+}
+
+func (r jobRun) Params() map[string]string {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+	return r.manager.jobs[r.id].Params
+}
+
+func (r jobRun) SetTotal(total int) {
+	r.update(func(job *Job) { job.Total = total })
+}
+
+// processed dokumen selesai, changed di antaranya benar-benar diubah
+func (r jobRun) Advance(processed, changed int) {
+	r.update(func(job *Job) {
+		job.Processed += processed
+		job.Changed += changed
+	})
+}
+
+// Satu dokumen gagal; job tetap lanjut
+func (r jobRun) Fail(item string, err error) {
+	r.update(func(job *Job) {
+		job.Processed++
+		job.Failed++
+		if len(job.Errors) < JOB_MAX_ERRORS {
+			job.Errors = append(job.Errors, fmt.Sprintf("%s: %v", item, err))
+		}
+	})
+}
+
+// Daftarkan job dan jalankan run di background setelah job sebelumnya di
+// lane yang sama selesai. run harus berhenti begitu ctx dibatalkan.
+func (m *JobManager) Start(kind string, params map[string]string, run jobFunc) Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.seq++
+	job := &Job{
+		ID:          fmt.Sprintf("%s-%d-%d", kind, time.Now().Unix(), m.seq),
+		Kind:        kind,
+		Status:      JobQueued,
+		Params:      params,
+		MaxAttempts: kindOf(kind).retry.attempts(),
+		CreatedAt:   time.Now(),
+	}
+	m.jobs[job.ID] = job
+	m.order = append(m.order, job.ID)
+	m.trim()
+	m.launch(job, run)
+	m.save()
+	return copyJob(job)
+}
+
+// Jalankan job jenis terdaftar
+func (m *JobManager) Enqueue(kind string, params map[string]string) (Job, error) {
+	registered, exists := jobKinds[kind]
+	if !exists || registered.run == nil {
+		return Job{}, fmt.Errorf("unknown job kind %q", kind)
+	}
+	return m.Start(kind, params, registered.run), nil
+}
+
+// Jalankan job jenis terdaftar sekarang lalu setiap interval, kecuali job
+// jenis itu masih queued atau running
+func (m *JobManager) Every(kind string, interval time.Duration) {
+	go func() {
+		for {
+			if !m.pending(kind) {
+				if _, err := m.Enqueue(kind, nil); err != nil {
+					log.Printf("Error scheduling job %s: %v", kind, err)
+				}
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+func (m *JobManager) pending(kind string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, job := range m.jobs {
+		if job.Kind == kind && !job.Finished() {
+			return true
+		}
+	}
+	return false
+}
+
+// Dipanggil dengan mu terkunci
+func (m *JobManager) slot(lane string) chan struct{} {
+	slot, exists := m.slots[lane]
+	if !exists {
+		slot = make(chan struct{}, 1)
+		m.slots[lane] = slot
+	}
+	return slot
+}
+
+// Jalankan job queued di goroutine sendiri; dipanggil dengan mu terkunci
+func (m *JobManager) launch(job *Job, run jobFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancels[job.ID] = cancel
+	kind := kindOf(job.Kind)
+	slot := m.slot(kind.lane)
+
+	var wait time.Duration
+	if job.NextRunAt != nil {
+		wait = time.Until(*job.NextRunAt)
+	}
+
+	go func() {
+		defer cancel()
+		for {
+			if wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					m.finish(job.ID, ctx.Err())
+					return
+				}
+			}
+			select {
+			case slot <- struct{}{}:
+			case <-ctx.Done():
+				m.finish(job.ID, ctx.Err())
+				return
+			}
+
+			m.mu.Lock()
+			now := time.Now()
+			job.Status = JobRunning
+			job.StartedAt = &now
+			job.NextRunAt = nil
+			job.Attempts++
+			// Percobaan ulang mulai dari awal
+			job.Total, job.Processed, job.Changed, job.Failed, job.Progress = 0, 0, 0, 0, 0
+			job.Errors = nil
+			attempts := job.Attempts
+			m.save()
+			m.mu.Unlock()
+
+			err := run(ctx, jobRun{manager: m, id: job.ID})
+			<-slot
+			if err == nil && ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			if err == nil || err == context.Canceled || attempts >= kind.retry.attempts() {
+				m.finish(job.ID, err)
+				return
+			}
+
+			wait = kind.retry.delay(attempts)
+			m.mu.Lock()
+			next := time.Now().Add(wait)
+			job.Status = JobQueued
+			job.NextRunAt = &next
+			job.Error = err.Error()
+			m.save()
+			m.mu.Unlock()
+			log.Printf("Job %s failed (attempt %d of %d), retrying in %s: %v", job.ID, attempts, kind.retry.attempts(), wait, err)
+		}
+	}()
+}
+
+func (m *JobManager) finish(id string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job := m.jobs[id]
+	now := time.Now()
+	job.FinishedAt = &now
+	job.NextRunAt = nil
+	switch {
+	case err == nil:
+		job.Status = JobDone
+		job.Error = ""
+	case err == context.Canceled:
+		job.Status = JobCanceled
+	default:
+		job.Status = JobFailed
+		job.Error = err.Error()
+	}
+	delete(m.cancels, id)
+	m.trim()
+	m.save()
+	logJob(*job)
+}
+
+// Tulis ulang jobs.json; dipanggil dengan mu terkunci
+func (m *JobManager) save() {
+	if m.path == "" {
+		return
+	}
+	list := make([]*Job, len(m.order))
+	for i, id := range m.order {
+		list[i] = m.jobs[id]
+	}
+	if err := writeJSONFile(m.path, list); err != nil {
+		log.Printf("Error saving %s: %v", m.path, err)
+	}
+}
+
+// Baca jobs.json dan lanjutkan job yang terputus restart. Dipanggil sekali
+// saat server mulai, setelah semua jenis job terdaftar di init().
+func (m *JobManager) Load(path string) error {
+	var saved []*Job
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &saved); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.path = path
+
+	order := make([]string, 0, len(saved)+len(m.order))
+	for _, job := range saved {
+		if _, exists := m.jobs[job.ID]; exists {
+			continue
+		}
+		m.jobs[job.ID] = job
+		order = append(order, job.ID)
+		if job.Finished() {
+			continue
+		}
+		registered := jobKinds[job.Kind]
+		if registered.run == nil {
+			now := time.Now()
+			job.Status = JobFailed
+			job.Error = "interrupted by restart"
+			job.FinishedAt = &now
+			logJob(*job)
+			continue
+		}
+		job.Status = JobQueued
+		m.launch(job, registered.run)
+	}
+	m.order = append(order, m.order...)
+	m.seq += len(saved)
+	m.trim()
+	m.save()
+	return nil
+}
+
+func logJob(job Job) {
+	// Job berkala yang tidak memproses apa pun tidak perlu dicatat
+	if job.Status == JobDone && job.Total == 0 && job.Processed == 0 {
+		return
+	}
+	if job.Error != "" {
+		log.Printf("Job %s %s: %s", job.ID, job.Status, job.Error)
+		return
+	}
+	log.Printf("Job %s %s: %d/%d processed, %d changed, %d failed", job.ID, job.Status, job.Processed, job.Total, job.Changed, job.Failed)
+}
+
+// Buang job selesai yang paling lama kalau riwayat jenisnya penuh; dipanggil
+// dengan mu terkunci
+func (m *JobManager) trim() {
+	finished := make(map[string]int)
+	for _, id := range m.order {
+		if job := m.jobs[id]; job.Finished() {
+			finished[job.Kind]++
+		}
+	}
+	kept := m.order[:0]
+	for _, id := range m.order {
+		job := m.jobs[id]
+		if job.Finished() && finished[job.Kind] > JOB_HISTORY {
+			delete(m.jobs, id)
+			finished[job.Kind]--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	m.order = kept
+}
+
+func (m *JobManager) Get(id string) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, exists := m.jobs[id]
+	if !exists {
+		return Job{}, false
+	}
+	return copyJob(job), true
+}
+
+// Job dengan jenis dan status tertentu (kosong = semua), terbaru dulu
+func (m *JobManager) List(kind, status string) []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := make([]Job, 0, len(m.order))
+	for i := len(m.order) - 1; i >= 0; i-- {
+		job := m.jobs[m.order[i]]
+		if (kind != "" && job.Kind != kind) || (status != "" && job.Status != status) {
+			continue
+		}
+		list = append(list, copyJob(job))
+	}
+	return list
+}
+
+// Batalkan job yang belum selesai. Job berhenti di antara dua dokumen/batch,
+// atau langsung kalau masih menunggu retry; perubahan yang sudah masuk WAL
+// tidak dibatalkan.
+func (m *JobManager) Cancel(id string) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, exists := m.jobs[id]
+	if !exists {
+		return Job{}, false
+	}
+	if cancel, running := m.cancels[id]; running {
+		cancel()
+	}
+	return copyJob(job), true
+}
+
+func copyJob(job *Job) Job {
+	snapshot := *job
+	snapshot.Errors = append([]string(nil), job.Errors...)
+	return snapshot
+}
+
+// GET /api/admin/jobs[?kind=archive&status=failed]
+func listJobsHandler(c *gin.Context) {
+	kinds := make([]string, 0, len(jobKinds))
+	for kind, registered := range jobKinds {
+		if registered.run != nil {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	c.JSON(http.StatusOK, gin.H{"jobs": adminJobs.List(c.Query("kind"), c.Query("status")), "kinds": kinds})
+}
+
+// POST /api/admin/jobs {"kind": "archive"}: jalankan job terdaftar sekarang,
+// tanpa menunggu jadwalnya
+func runJobHandler(c *gin.Context) {
+	var req struct {
+		Kind   string            `json:"kind" binding:"required"`
+		Params map[string]string `json:"params"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if adminJobs.pending(req.Kind) {
+		c.JSON(http.StatusConflict, gin.H{"error": "a " + req.Kind + " job is already queued or running"})
+		return
+	}
+	job, err := adminJobs.Enqueue(req.Kind, req.Params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	auditActor(c).Record(AuditJobStart, job.ID, nil, job)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GET /api/admin/jobs/:id
+func getJobHandler(c *gin.Context) {
+	job, exists := adminJobs.Get(c.Param("id"))
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// POST /api/admin/jobs/:id/cancel
+func cancelJobHandler(c *gin.Context) {
+	job, exists := adminJobs.Cancel(c.Param("id"))
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+	if job.Finished() {
+		c.JSON(http.StatusConflict, gin.H{"error": "job already " + job.Status})
+		return
+	}
+	auditActor(c).Record(AuditJobCancel, job.ID, auditSnapshot(job), nil)
+	c.JSON(http.StatusAccepted, job)
+}
@@ -0,0 +1,103 @@
+package main
+
+import "strings"
+
+// Kode bahasa dokumen
+const (
+	LangIndonesian = "id"
+	LangEnglish    = "en"
+)
+
+// Jumlah kata awal yang dipakai untuk deteksi bahasa
+const LANGUAGE_SAMPLE_WORDS = 200
+
+// Bobot pipeline bahasa sekunder untuk query yang bahasanya jelas
+const SECONDARY_LANGUAGE_WEIGHT = 0.5
+
+var englishTextProcessor *TextProcessor
+
+func init() {
+	englishTextProcessor = NewEnglishTextProcessor()
+}
+
+func NewEnglishTextProcessor() *TextProcessor {
+	return &TextProcessor{
+		language:  LangEnglish,
+		stopWords: initializeEnglishStopWords(),
+	}
+}
+
+func initializeEnglishStopWords() map[string]bool {
+	words := []string{
+		"a", "an", "the", "and", "or", "but", "nor", "so", "yet",
+		"of", "in", "on", "at", "to", "for", "from", "by", "with", "about",
+		"as", "into", "through", "over", "after", "before", "between", "under",
+		"is", "am", "are", "was", "were", "be", "been", "being",
+		"have", "has", "had", "do", "does", "did", "will", "would", "shall",
+		"should", "can", "could", "may", "might", "must",
+		"i", "you", "he", "she", "it", "we", "they", "me", "him", "her", "us", "them",
+		"my", "your", "his", "its", "our", "their",
+		"this", "that", "these", "those", "there", "here",
+		"what", "which", "who", "whom", "whose", "when", "where", "why", "how",
+		"not", "no", "than", "then", "too", "very", "just", "also", "only",
+		"all", "any", "both", "each", "more", "most", "other", "some", "such",
+		"if", "while", "because", "until", "again", "further", "once",
+	}
+
+	stopWords := make(map[string]bool, len(words))
+	for _, w := range words {
+		stopWords[w] = true
+	}
+	return stopWords
+}
+
+// Hitung stopword tiap bahasa di awal teks
+func languageHits(text string) (idHits, enHits int) {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) > LANGUAGE_SAMPLE_WORDS {
+		words = words[:LANGUAGE_SAMPLE_WORDS]
+	}
+
+	for _, word := range words {
+		word = strings.Trim(word, ".,;:!?\"'()[]")
+		if textProcessor.stopWords[word] {
+			idHits++
+		}
+		if englishTextProcessor.stopWords[word] {
+			enHits++
+		}
+	}
+	return idHits, enHits
+}
+
+// Deteksi bahasa sederhana berdasarkan jumlah stopword tiap bahasa.
+// Default ke Indonesia kalau tidak ada sinyal yang jelas.
+func detectLanguage(text string) string {
+	idHits, enHits := languageHits(text)
+	if enHits > idHits {
+		return LangEnglish
+	}
+	return LangIndonesian
+}
+
+// Bobot tiap bahasa untuk query. Query pendek sering tanpa stopword
+// ("green building jakarta") sehingga dianggap campuran dengan bobot sama.
+func queryLanguageWeights(query string) map[string]float64 {
+	idHits, enHits := languageHits(query)
+	switch {
+	case enHits > idHits:
+		return map[string]float64{LangEnglish: 1, LangIndonesian: SECONDARY_LANGUAGE_WEIGHT}
+	case idHits > enHits:
+		return map[string]float64{LangIndonesian: 1, LangEnglish: SECONDARY_LANGUAGE_WEIGHT}
+	default:
+		return map[string]float64{LangIndonesian: 1, LangEnglish: 1}
+	}
+}
+
+// Pilih text processor sesuai bahasa
+func analyzerFor(language string) *TextProcessor {
+	if language == LangEnglish {
+		return englishTextProcessor
+	}
+	return textProcessor
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gin-gonic/gin"
+)
+
+// Link graph: crawler menyimpan link keluar di container artikel
+// (Article.Links). Setiap generasi index link itu dijadikan dua graph:
+//   - dokumen: artikel -> artikel lain di corpus
+//   - domain: domain artikel -> domain yang di-link (termasuk domain di luar corpus)
+//
+// PageRank dihitung di kedua graph. Authority dokumen (0-1) adalah rata-rata
+// rank dokumen dan rank domainnya, dinormalisasi log terhadap rank terbesar;
+// rank di bawah rata-rata bernilai 0. Saat ranking skor dikali
+// 1 + link_authority_weight * authority.
+//
+// Ekstraksi link ada juga di crawler/links.go dan harus tetap sama.
+const (
+	LINK_MAX_PER_DOC      = 200
+	LINK_AUTHORITY_WEIGHT = 0.1
+	PAGERANK_DAMPING      = 0.85
+	PAGERANK_ITERATIONS   = 50
+	PAGERANK_TOLERANCE    = 1e-9
+	// Node yang diekspor GET /api/admin/link-graph tanpa limit
+	LINK_GRAPH_EXPORT_LIMIT = 1000
+)
+
+// Link keluar di container artikel: absolut, tanpa fragment, unik, hanya
+// http(s). Link nofollow/sponsored bukan rekomendasi dan dilewati.
+func extractLinks(container *goquery.Selection, base *url.URL) []string {
+	seen := make(map[string]bool)
+	var links []string
+	container.Find("a[href]").EachWithBreak(func(_ int, a *goquery.Selection) bool {
+		rel := strings.ToLower(a.AttrOr("rel", ""))
+		if strings.Contains(rel, "nofollow") || strings.Contains(rel, "sponsored") {
+			return true
+		}
+		ref, err := url.Parse(strings.TrimSpace(a.AttrOr("href", "")))
+		if err != nil {
+			return true
+		}
+		if base != nil {
+			ref = base.ResolveReference(ref)
+		}
+		if (ref.Scheme != "http" && ref.Scheme != "https") || ref.Host == "" {
+			return true
+		}
+		ref.Fragment, ref.RawFragment = "", ""
+		if link := ref.String(); !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+		return len(links) < LINK_MAX_PER_DOC
+	})
+	return links
+}
+
+// Key pencocokan URL link dengan URL artikel: host tanpa www, path tanpa
+// slash di akhir dan query. Kosong kalau URL tidak valid.
+func linkKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	key := normalizeDomain(u.Host) + strings.TrimSuffix(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key
+}
+
+func linkDomain(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return normalizeDomain(u.Hostname())
+}
+
+type LinkNode struct {
+	DocID     int     `json:"doc_id"`
+	URL       string  `json:"url"`
+	Title     string  `json:"title"`
+	Rank      float64 `json:"rank"`
+	Authority float64 `json:"authority"`
+	Inbound   int     `json:"inbound"`
+	Outbound  int     `json:"outbound"`
+}
+
+type DomainNode struct {
+	Domain    string  `json:"domain"`
+	Articles  int     `json:"articles"` // artikel corpus di domain ini
+	Rank      float64 `json:"rank"`
+	Authority float64 `json:"authority"`
+	Inbound   int     `json:"inbound"`
+	Outbound  int     `json:"outbound"`
+}
+
+// Edge graph; Links adalah jumlah artikel yang me-link (graph domain)
+type LinkEdge struct {
+	From  int `json:"from"`
+	To    int `json:"to"`
+	Links int `json:"links,omitempty"`
+}
+
+type LinkGraph struct {
+	Documents   []LinkNode
+	Edges       []LinkEdge // index ke Documents
+	Domains     []DomainNode
+	DomainEdges []LinkEdge // index ke Domains
+	// URL artikel -> authority 0-1, untuk ranking
+	authority map[string]float64
+}
+
+var linkGraph struct {
+	mu    sync.RWMutex
+	graph *LinkGraph
+}
+
+// Dipanggil noteIndexGeneration setelah updateSourceQuality
+func updateLinkGraph(articles []Article) {
+	graph := buildLinkGraph(articles)
+
+	linkGraph.mu.Lock()
+	linkGraph.graph = graph
+	linkGraph.mu.Unlock()
+}
+
+func buildLinkGraph(articles []Article) *LinkGraph {
+	graph := &LinkGraph{authority: make(map[string]float64)}
+
+	// Node dokumen dan domain artikel corpus
+	byKey := make(map[string]int)
+	domainIDs := make(map[string]int)
+	domainOf := func(domain string) int {
+		id, exists := domainIDs[domain]
+		if !exists {
+			id = len(graph.Domains)
+			domainIDs[domain] = id
+			graph.Domains = append(graph.Domains, DomainNode{Domain: domain})
+		}
+		return id
+	}
+	var docDomains []int
+	for docID, article := range articles {
+		if article.Deleted() {
+			continue
+		}
+		key := linkKey(article.URL)
+		if key == "" {
+			continue
+		}
+		if _, exists := byKey[key]; exists {
+			continue
+		}
+		byKey[key] = len(graph.Documents)
+		graph.Documents = append(graph.Documents, LinkNode{DocID: docID, URL: article.URL, Title: article.Title})
+		domain := domainOf(linkDomain(article.URL))
+		graph.Domains[domain].Articles++
+		docDomains = append(docDomains, domain)
+	}
+
+	docTargets := make([][]int, len(graph.Documents))
+	domainLinks := make(map[[2]int]int)
+	for from, node := range graph.Documents {
+		seenDocs, seenDomains := make(map[int]bool), make(map[int]bool)
+		for _, link := range articles[node.DocID].Links {
+			if to, exists := byKey[linkKey(link)]; exists && to != from && !seenDocs[to] {
+				seenDocs[to] = true
+				docTargets[from] = append(docTargets[from], to)
+				graph.Edges = append(graph.Edges, LinkEdge{From: from, To: to})
+			}
+			domain := linkDomain(link)
+			if domain == "" {
+				continue
+			}
+			// Satu artikel dihitung sekali per domain tujuan
+			if to := domainOf(domain); to != docDomains[from] && !seenDomains[to] {
+				seenDomains[to] = true
+				domainLinks[[2]int{docDomains[from], to}]++
+			}
+		}
+	}
+
+	for edge, links := range domainLinks {
+		graph.DomainEdges = append(graph.DomainEdges, LinkEdge{From: edge[0], To: edge[1], Links: links})
+	}
+	sort.Slice(graph.DomainEdges, func(i, j int) bool {
+		a, b := graph.DomainEdges[i], graph.DomainEdges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	// Domain yang di-link banyak artikel mendapat bagian rank lebih besar
+	domainTargets := make([][]int, len(graph.Domains))
+	for _, edge := range graph.DomainEdges {
+		for i := 0; i < edge.Links; i++ {
+			domainTargets[edge.From] = append(domainTargets[edge.From], edge.To)
+		}
+	}
+
+	docRanks := pageRank(docTargets)
+	docAuthority := rankAuthority(docRanks)
+	for i := range graph.Documents {
+		graph.Documents[i].Rank = docRanks[i]
+		graph.Documents[i].Authority = docAuthority[i]
+		graph.Documents[i].Outbound = len(docTargets[i])
+	}
+	for _, edge := range graph.Edges {
+		graph.Documents[edge.To].Inbound++
+	}
+
+	domainRanks := pageRank(domainTargets)
+	domainAuthority := rankAuthority(domainRanks)
+	for i := range graph.Domains {
+		graph.Domains[i].Rank = domainRanks[i]
+		graph.Domains[i].Authority = domainAuthority[i]
+	}
+	for _, edge := range graph.DomainEdges {
+		graph.Domains[edge.From].Outbound++
+		graph.Domains[edge.To].Inbound++
+	}
+
+	for i, node := range graph.Documents {
+		graph.authority[node.URL] = (node.Authority + graph.Domains[docDomains[i]].Authority) / 2
+	}
+	return graph
+}
+
+// PageRank dengan damping. targets[i] adalah node tujuan link node i (boleh
+// berulang sebagai bobot); rank node tanpa link keluar dibagi rata ke semua node.
+func pageRank(targets [][]int) []float64 {
+	n := len(targets)
+	rank := make([]float64, n)
+	if n == 0 {
+		return rank
+	}
+	for i := range rank {
+		rank[i] = 1 / float64(n)
+	}
+	next := make([]float64, n)
+	for iteration := 0; iteration < PAGERANK_ITERATIONS; iteration++ {
+		dangling := 0.0
+		for i := range next {
+			next[i] = 0
+		}
+		for from, to := range targets {
+			if len(to) == 0 {
+				dangling += rank[from]
+				continue
+			}
+			share := rank[from] / float64(len(to))
+			for _, target := range to {
+				next[target] += share
+			}
+		}
+
+		base := (1-PAGERANK_DAMPING)/float64(n) + PAGERANK_DAMPING*dangling/float64(n)
+		delta := 0.0
+		for i := range next {
+			next[i] = base + PAGERANK_DAMPING*next[i]
+			delta += math.Abs(next[i] - rank[i])
+		}
+		rank, next = next, rank
+		if delta < PAGERANK_TOLERANCE {
+			break
+		}
+	}
+	return rank
+}
+
+// Authority 0-1 dari rank: log(rank * n) / log(max * n), jadi rank rata-rata
+// ke bawah bernilai 0 dan rank terbesar 1
+func rankAuthority(ranks []float64) []float64 {
+	authority := make([]float64, len(ranks))
+	n := float64(len(ranks))
+	max := 0.0
+	for _, rank := range ranks {
+		if rank > max {
+			max = rank
+		}
+	}
+	top := math.Log(max * n)
+	if top <= 1e-9 {
+		return authority
+	}
+	for i, rank := range ranks {
+		if value := math.Log(rank*n) / top; value > 0 {
+			authority[i] = math.Min(value, 1)
+		}
+	}
+	return authority
+}
+
+// URL artikel -> authority link 0-1 dari generasi terakhir. Map tidak diubah
+// setelah dibuat, jadi aman dibaca tanpa lock.
+func linkAuthorities(weight float64) map[string]float64 {
+	linkGraph.mu.RLock()
+	defer linkGraph.mu.RUnlock()
+	if linkGraph.graph == nil || weight <= 0 {
+		return nil
+	}
+	return linkGraph.graph.authority
+}
+
+// Pengali skor ranking: 1 + link_authority_weight * authority
+func linkAuthorityBoost(authorities map[string]float64, weight float64, url string) float64 {
+	return 1 + weight*authorities[url]
+}
+
+// GET /api/admin/link-graph?graph=documents|domains&format=json|dot&limit=1000:
+// node dengan rank terbesar beserta edge di antara node itu
+func linkGraphHandler(c *gin.Context) {
+	kind := c.DefaultQuery("graph", "documents")
+	if kind != "documents" && kind != "domains" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "graph must be documents or domains"})
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "dot" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or dot"})
+		return
+	}
+	limit := LINK_GRAPH_EXPORT_LIMIT
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		limit = n
+	}
+
+	linkGraph.mu.RLock()
+	graph := linkGraph.graph
+	linkGraph.mu.RUnlock()
+	if graph == nil {
+		graph = &LinkGraph{}
+	}
+
+	var labels []string
+	var ranks []float64
+	edges := graph.Edges
+	if kind == "domains" {
+		edges = graph.DomainEdges
+		for _, node := range graph.Domains {
+			labels = append(labels, node.Domain)
+			ranks = append(ranks, node.Rank)
+		}
+	} else {
+		for _, node := range graph.Documents {
+			labels = append(labels, node.URL)
+			ranks = append(ranks, node.Rank)
+		}
+	}
+
+	// Node teratas menurut rank; edge hanya di antara node itu
+	order := make([]int, len(ranks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return ranks[order[i]] > ranks[order[j]] })
+	if len(order) > limit {
+		order = order[:limit]
+	}
+	kept := make(map[int]bool, len(order))
+	for _, i := range order {
+		kept[i] = true
+	}
+	var keptEdges []LinkEdge
+	for _, edge := range edges {
+		if kept[edge.From] && kept[edge.To] {
+			keptEdges = append(keptEdges, edge)
+		}
+	}
+
+	if format == "dot" {
+		var b strings.Builder
+		b.WriteString("digraph links {\n")
+		for _, i := range order {
+			fmt.Fprintf(&b, "  n%d [label=%q rank=%.6g];\n", i, labels[i], ranks[i])
+		}
+		for _, edge := range keptEdges {
+			if edge.Links > 0 {
+				fmt.Fprintf(&b, "  n%d -> n%d [weight=%d];\n", edge.From, edge.To, edge.Links)
+			} else {
+				fmt.Fprintf(&b, "  n%d -> n%d;\n", edge.From, edge.To)
+			}
+		}
+		b.WriteString("}\n")
+		c.String(http.StatusOK, b.String())
+		return
+	}
+
+	// Edge JSON memakai doc_id (dokumen) atau nama domain, bukan index internal
+	nodes := make([]interface{}, 0, len(order))
+	jsonEdges := make([]gin.H, 0, len(keptEdges))
+	for _, i := range order {
+		if kind == "domains" {
+			nodes = append(nodes, graph.Domains[i])
+		} else {
+			nodes = append(nodes, graph.Documents[i])
+		}
+	}
+	for _, edge := range keptEdges {
+		if kind == "domains" {
+			jsonEdges = append(jsonEdges, gin.H{"from": graph.Domains[edge.From].Domain, "to": graph.Domains[edge.To].Domain, "links": edge.Links})
+		} else {
+			jsonEdges = append(jsonEdges, gin.H{"from": graph.Documents[edge.From].DocID, "to": graph.Documents[edge.To].DocID})
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"graph":                 kind,
+		"link_authority_weight": requestConfig(c).LinkAuthorityWeight,
+		"nodes":                 nodes,
+		"edges":                 jsonEdges,
+		"total_nodes":           len(ranks),
+		"total_edges":           len(edges),
+	})
+}
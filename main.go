@@ -2,11 +2,17 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"html/template"
+	"log"
 	"math"
 	"net/http"
+	"net/url"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,17 +20,213 @@ import (
 const ITEMS_PER_PAGE = 10
 
 func main() {
-	r := gin.Default()
+	// Argumen tambahan berarti perintah searchctl, bukan web server
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runSearchctl(os.Args[1:]))
+	}
 
-	r.Static("/static", "./static")
+	flag.BoolVar(&benchMode, "bench-mode", false, "matikan cache dan job background untuk load test yang reproducible")
+	flag.Parse()
+	if benchMode {
+		log.Printf("Bench mode: caches, query log and background mining disabled")
+	}
+
+	if err := startupAnalyzerCheck(); err != nil {
+		log.Fatal(err)
+	}
+	if err := openStorage(); err != nil {
+		log.Fatal(err)
+	}
+	startBackgroundJobs()
+
+	r := setupRouter()
+	r.Run(":8080")
+}
+
+// Semua route web dan admin API. Dipisah dari main supaya server bisa
+// dijalankan lewat httptest terhadap corpus fixture.
+func setupRouter() *gin.Engine {
+	r := gin.New()
+	r.Use(gin.Logger(), recoveryHandler(), pinConfig(), apiKeyAuth())
 
-	r.SetFuncMap(templateFunctions())
+	r.Static("/static", "./static")
 
-	r.LoadHTMLGlob("templates/*")
+	htmlTemplates = loadTemplates(TEMPLATES_GLOB)
+	r.SetHTMLTemplate(htmlTemplates)
+	r.NoRoute(notFoundHandler)
 	r.GET("/", indexHandler)
 	r.POST("/search", searchHandler)
 	r.GET("/search", searchHandlerGet)
-	r.Run(":8080")
+	r.GET("/api/search", searchAPIHandler)
+	r.GET("/api/suggest", suggestHandler)
+	r.POST("/api/voice-search", voiceSearchHandler)
+	r.GET("/api/answer", answerHandler)
+	r.GET("/api/_analyze", analyzeHandler)
+	r.POST("/api/_score", scoreHandler)
+	r.GET("/author/:slug", authorPageHandler)
+	r.GET("/document/:id", documentPageHandler)
+
+	// Kelola corpus saat runtime; sama seperti admin API, butuh ADMIN_TOKEN
+	documents := r.Group("/api/documents", adminAuth())
+	documents.POST("", postDocumentHandler)
+	documents.GET("", listDocumentsHandler)
+	documents.GET("/:id", getDocumentHandler)
+	documents.PUT("/:id", updateDocumentHandler)
+	documents.DELETE("/:id", deleteDocumentHandler)
+	documents.GET("/deleted", deletedDocumentsHandler)
+	documents.POST("/:id/restore", restoreDocumentHandler)
+
+	admin := r.Group("/api/admin", adminAuth())
+	admin.GET("/blocklist", getBlocklistHandler)
+	admin.POST("/blocklist", addBlocklistHandler)
+	admin.DELETE("/blocklist", removeBlocklistHandler)
+	admin.GET("/review", listReviewHandler)
+	admin.POST("/review/approve", reviewDecisionHandler(ReviewApproved))
+	admin.POST("/review/reject", reviewDecisionHandler(ReviewRejected))
+	admin.POST("/review/edit", reviewEditHandler)
+	admin.GET("/zero-results", zeroResultsHandler)
+	admin.GET("/synonyms", listSynonymsHandler)
+	admin.POST("/synonyms/approve", synonymDecisionHandler(ReviewApproved))
+	admin.POST("/synonyms/reject", synonymDecisionHandler(ReviewRejected))
+	admin.GET("/index-stats", indexStatsHandler)
+	admin.GET("/index-report", buildReportHandler)
+	admin.GET("/analyzer", analyzerCheckHandler)
+	admin.GET("/filter-cache", filterCacheHandler)
+	admin.GET("/result-cache", resultCacheHandler)
+	admin.GET("/query-cache", queryCacheHandler)
+	admin.POST("/ingest", ingestHandler)
+	admin.POST("/bulk/delete", bulkDeleteHandler)
+	admin.POST("/bulk/retag", bulkRetagHandler)
+	admin.POST("/bulk/recrawl", bulkRecrawlHandler)
+	admin.POST("/bulk/delete-provenance", bulkDeleteProvenanceHandler)
+	admin.GET("/jobs", listJobsHandler)
+	admin.POST("/jobs", runJobHandler)
+	admin.GET("/jobs/:id", getJobHandler)
+	admin.POST("/jobs/:id/cancel", cancelJobHandler)
+	admin.GET("/audit", auditLogHandler)
+	admin.GET("/config", getConfigHandler)
+	admin.POST("/config", updateConfigHandler)
+	admin.GET("/config/versions", configVersionsHandler)
+	admin.POST("/config/rollback", rollbackConfigHandler)
+	admin.GET("/schema", schemaHandler)
+	admin.GET("/merge", mergeStatsHandler)
+	admin.POST("/merge", forceMergeHandler)
+	admin.GET("/sources", listSourcesHandler)
+	admin.POST("/sources", saveSourceHandler)
+	admin.POST("/sources/propose", proposeSourceHandler)
+	admin.GET("/crawl/progress", crawlProgressHandler)
+	admin.POST("/crawl/progress", crawlProgressReportHandler)
+	admin.GET("/crawl/stream", crawlStreamHandler)
+	admin.GET("/crawl/diff", crawlDiffHandler)
+	admin.GET("/categories", categoriesHandler)
+	admin.GET("/flags", listFeatureFlagsHandler)
+	admin.POST("/flags", setFeatureFlagHandler)
+	admin.GET("/shadow", shadowSummaryHandler)
+	admin.GET("/snapshots/report", snapshotReportHandler)
+	admin.GET("/generations", generationsHandler)
+	admin.GET("/indexer", indexerStatusHandler)
+	admin.POST("/reindex", reindexHandler)
+	admin.GET("/archive", archiveStatusHandler)
+	admin.POST("/archive", runArchiveHandler)
+	admin.GET("/syndication", syndicationHandler)
+	admin.POST("/similarity", similarityHandler)
+	admin.GET("/source-quality", sourceQualityHandler)
+	admin.GET("/link-graph", linkGraphHandler)
+	admin.GET("/dashboard", dashboardHandler)
+	admin.GET("/alerts", alertsHandler)
+	admin.GET("/enrichment", enrichmentStatusHandler)
+	admin.GET("/monitored", listMonitoredHandler)
+	admin.POST("/monitored", addMonitoredHandler)
+	admin.DELETE("/monitored", removeMonitoredHandler)
+	admin.GET("/monitored/stream", monitoredStreamHandler)
+
+	r.GET("/admin/review", adminAuth(), reviewPageHandler)
+	r.GET("/admin/crawl", adminAuth(), crawlDashboardHandler)
+	r.GET("/admin/dashboard", adminAuth(), dashboardPageHandler)
+	r.GET("/metrics", adminAuth(), metricsHandler)
+
+	if benchMode {
+		r.GET("/api/bench/corpus", benchCorpusHandler)
+	}
+
+	return r
+}
+
+// Buka write-ahead log dan document store
+func openStorage() error {
+	// Putar ulang perubahan yang belum sempat di-flush sebelum proses terakhir berhenti
+	wal, err := openWAL(WAL_FILE)
+	if err != nil {
+		return fmt.Errorf("opening write-ahead log: %v", err)
+	}
+	if pending := len(wal.Pending()); pending > 0 {
+		log.Printf("Replaying %d write-ahead log records", pending)
+		if err := wal.Flush(); err != nil {
+			return fmt.Errorf("replaying write-ahead log: %v", err)
+		}
+	}
+	documentLog = wal
+
+	if err := migrateBlocklist(); err != nil {
+		return fmt.Errorf("migrating %s: %v", BLOCKLIST_FILE, err)
+	}
+
+	if path := currentConfig().DocStorePath; path != "" {
+		ds, err := ensureDocStore(path)
+		if err != nil {
+			return fmt.Errorf("opening document store: %v", err)
+		}
+		setDocStore(ds)
+	}
+
+	return nil
+}
+
+// Pekerjaan background: build index, flush WAL dan reload config.json sebagai goroutine,
+// sisanya job berkala di antrian job (jobs.go): mining zero-result query,
+// popularitas query untuk saran, arsip artikel lama, purge soft delete, cek
+// reindex, evaluasi alert, enrichment dokumen baru dan snapshot regresi
+// setiap generasi index baru.
+// Job yang terputus restart dilanjutkan dari jobs.json.
+func startBackgroundJobs() {
+	if err := adminJobs.Load(JOBS_FILE); err != nil {
+		log.Printf("Error loading %s: %v", JOBS_FILE, err)
+	}
+	startWALFlusher(documentLog)
+	indexer.Start()
+	if !benchMode {
+		startZeroResultMiner(ZERO_RESULTS_INTERVAL)
+		startSuggestRefresher(SUGGEST_REFRESH_INTERVAL)
+		startArchiver(ARCHIVE_INTERVAL)
+		startSoftDeletePurger(SOFT_DELETE_PURGE_INTERVAL)
+		startConfigWatcher(CONFIG_WATCH_INTERVAL)
+		startQueryMonitor(MONITOR_CHECK_INTERVAL)
+		startAlertEvaluator(ALERT_EVALUATE_INTERVAL)
+		startEnricher(ENRICH_INTERVAL)
+		snapshotOnReindex = true
+		retainGenerations = true
+	}
+}
+
+// Admin API hanya aktif kalau ADMIN_TOKEN di-set.
+// Token dikirim lewat header X-Admin-Token, atau ?token= untuk halaman admin.
+func adminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := os.Getenv("ADMIN_TOKEN")
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin API disabled"})
+			return
+		}
+		given := c.GetHeader("X-Admin-Token")
+		if given == "" {
+			given = c.Query("token")
+		}
+		if given != token {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid admin token"})
+			return
+		}
+		c.Next()
+	}
 }
 
 // Template functions
@@ -43,7 +245,15 @@ func templateFunctions() template.FuncMap {
 		"add": func(a, b int) int {
 			return a + b
 		},
-		"hasPrefix": strings.HasPrefix,
+		"hasPrefix":     strings.HasPrefix,
+		"authorSlug":    authorSlug,
+		"categorySlug":  categorySlug,
+		"formatNumber":  formatNumber,
+		"formatRupiah":  formatRupiah,
+		"formatDate":    formatDate,
+		"formatPercent": formatPercent,
+		"docTypeLabel":  docTypeLabel,
+		"relativeTime":  relativeTime,
 		"trimURLPath": func(url string) string {
 			// Hapus protokol
 			url = strings.TrimPrefix(url, "https://")
@@ -63,24 +273,100 @@ func templateFunctions() template.FuncMap {
 }
 
 func indexHandler(c *gin.Context) {
-	c.HTML(http.StatusOK, "index.html", nil)
+	renderHTML(c, http.StatusOK, "index.html", nil)
 }
 
 func searchHandler(c *gin.Context) {
-	query := c.PostForm("query")
-	method := c.PostForm("method")
+	values := url.Values{}
+	values.Set("q", normalizeQuery(c.PostForm("query")))
+	values.Set("method", c.PostForm("method"))
+	values.Set("page", "1")
 	// Redirect ke GET untuk handle pagination
-	c.Redirect(http.StatusFound, "/search?q="+query+"&method="+method+"&page=1")
+	c.Redirect(http.StatusFound, "/search?"+values.Encode())
 }
 
 func searchHandlerGet(c *gin.Context) {
-	query := c.Query("q")
+	query := normalizeQuery(c.Query("q"))
 	method := c.Query("method")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	opts := searchOptionsFromQuery(c)
 
-	allResults := searching(query, method)
+	start := time.Now()
+	cacheKey := resultPageKey(query, method, page, opts)
+	if cached, exists := resultPageCache.Get(cacheKey, opts.Config); exists {
+		logQuery(QueryLogEntry{
+			Time:       start,
+			Query:      query,
+			Method:     method,
+			Results:    cached.results,
+			DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+		})
+		c.Data(http.StatusOK, "text/html; charset=utf-8", cached.body)
+		return
+	}
+
+	allResults, plan, finished := searchWithTimeout(query, method, opts)
+	if !finished {
+		renderError(c, http.StatusServiceUnavailable)
+		return
+	}
 	totalResults := len(allResults)
-	totalPages := int(math.Ceil(float64(totalResults) / float64(ITEMS_PER_PAGE)))
+	shadowQuery(query, method, opts, allResults, time.Since(start))
+
+	logQuery(QueryLogEntry{
+		Time:       start,
+		Query:      query,
+		Method:     method,
+		Results:    totalResults,
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	})
+	pagedResults, page, totalPages := paginate(allResults, page, ITEMS_PER_PAGE)
+
+	body := renderHTML(c, http.StatusOK, "results.html", gin.H{
+		"results":       pagedResults,
+		"query":         query,
+		"method":        method,
+		"currentPage":   page,
+		"totalPages":    totalPages,
+		"totalResults":  totalResults,
+		"previousPage":  page - 1,
+		"nextPage":      page + 1,
+		"showPrevious":  page > 1,
+		"showNext":      page < totalPages,
+		"sort":          opts.Sort,
+		"filters":       filterParams(opts),
+		"archive":       opts.IncludeArchive,
+		"plan":          explainPlan(plan, opts.Explain),
+		"partial":       plan.Partial(),
+		"translation":   plan.translation(),
+		"rewrite":       plan.rewrite(),
+		"answers":       opts.Config.Answer.Enabled,
+		"featured":      pageFeaturedAnswer(query, allResults, page, opts),
+		"structure":     parseQueryStructure(query, method, opts),
+		"facetLinks":    facetLinks(query, method, opts),
+		"dateForm":      facetParams(query, method, opts, "from", "to", "days"),
+		"sourceFacet":   sourceFacet(allResults, SOURCE_FACET_SIZE),
+		"source":        opts.Filters.Source,
+		"yearFacet":     yearFacet(allResults, YEAR_FACET_SIZE),
+		"from":          formatFilterDate(opts.Filters.From),
+		"to":            formatFilterDate(opts.Filters.To),
+		"authorFacet":   authorFacet(allResults, AUTHOR_FACET_SIZE),
+		"author":        opts.Filters.Author,
+		"categoryFacet": categoryFacet(allResults, CATEGORY_FACET_SIZE),
+		"category":      opts.Filters.Category,
+		"docTypeFacet":  docTypeFacet(allResults, DOCTYPE_FACET_SIZE),
+		"doctype":       opts.Filters.DocType,
+		"now":           time.Now(),
+	})
+	// Hasil tanpa tier yang timeout tidak di-cache
+	if body != nil && !plan.Partial() {
+		resultPageCache.Put(cacheKey, body, totalResults, opts.Config)
+	}
+}
+
+// Potong hasil untuk satu halaman; page di-clamp ke rentang yang valid
+func paginate(results []SearchResult, page, perPage int) ([]SearchResult, int, int) {
+	totalPages := int(math.Ceil(float64(len(results)) / float64(perPage)))
 
 	if page < 1 {
 		page = 1
@@ -88,26 +374,385 @@ func searchHandlerGet(c *gin.Context) {
 		page = totalPages
 	}
 
-	var pagedResults []SearchResult
-	if totalResults > 0 {
-		start := (page - 1) * ITEMS_PER_PAGE
-		end := start + ITEMS_PER_PAGE
-		if end > totalResults {
-			end = totalResults
+	if len(results) == 0 {
+		return nil, page, totalPages
+	}
+	start := (page - 1) * perPage
+	end := start + perPage
+	if end > len(results) {
+		end = len(results)
+	}
+	return results[start:end], page, totalPages
+}
+
+// Baca parameter sort dan filter dari query string
+// (?op=and&sort=date_desc&source=rumah123&lang=id&location=bekasi&author=&category=&doctype=&min_price=&max_price=&min_words=&days=&from=2024-01-01&to=2024-06-30)
+func searchOptionsFromQuery(c *gin.Context) SearchOptions {
+	opts := SearchOptions{
+		Sort:     c.DefaultQuery("sort", SortRelevance),
+		MatchAll: c.Query("op") == "and",
+		Explain:  c.Query("explain") == "1",
+		Features: featureFlags.Active(c.ClientIP()),
+		Scopes:   clientScopes(c),
+		Config:   requestConfig(c),
+		Filters: SearchFilters{
+			Source:   c.Query("source"),
+			Language: c.Query("lang"),
+			Location: c.Query("location"),
+			Author:   c.Query("author"),
+			Category: c.Query("category"),
+			DocType:  c.Query("doctype"),
+			Fields:   schemaFilters(c.Request.URL.Query()),
+		},
+	}
+	opts.IncludeArchive = c.Query("include_archive") == "1"
+	opts.Local = c.Query("local") == "1"
+	opts.NoCollapse = c.Query("collapse") == "false" || c.Query("collapse") == "0"
+	opts.NoTranslate = c.Query("translate") == "false" || c.Query("translate") == "0"
+	opts.NoSegment = c.Query("segment") == "false" || c.Query("segment") == "0"
+	opts.NoSpell = c.Query("spell") == "false" || c.Query("spell") == "0"
+	opts.Filters.MinPrice, _ = strconv.ParseInt(c.Query("min_price"), 10, 64)
+	opts.Filters.MaxPrice, _ = strconv.ParseInt(c.Query("max_price"), 10, 64)
+	opts.Filters.MinWords, _ = strconv.Atoi(c.Query("min_words"))
+	if days, err := strconv.Atoi(c.Query("days")); err == nil && days > 0 {
+		// Dibulatkan ke awal hari supaya bitmap filter-nya bisa di-cache
+		opts.Filters.Since = time.Now().AddDate(0, 0, -days).Truncate(24 * time.Hour)
+	}
+	// Tanggal dalam WIB seperti tanggal artikel; yang tidak valid diabaikan
+	opts.Filters.From, _ = time.ParseInLocation(FILTER_DATE_LAYOUT, c.Query("from"), defaultDateLocation)
+	opts.Filters.To, _ = time.ParseInLocation(FILTER_DATE_LAYOUT, c.Query("to"), defaultDateLocation)
+	opts.Generation, _ = pinnedGeneration(c)
+	return opts
+}
+
+// Query plan hanya ditampilkan kalau ?explain=1
+func explainPlan(plan *QueryPlan, explain bool) string {
+	if !explain || plan == nil {
+		return ""
+	}
+	return plan.String()
+}
+
+// Parameter sort/filter yang ditambahkan ke link pagination
+func filterParams(opts SearchOptions) template.URL {
+	values := url.Values{}
+	if opts.MatchAll {
+		values.Set("op", "and")
+	}
+	if opts.Explain {
+		values.Set("explain", "1")
+	}
+	if opts.IncludeArchive {
+		values.Set("include_archive", "1")
+	}
+	if opts.Local {
+		values.Set("local", "1")
+	}
+	if opts.NoCollapse {
+		values.Set("collapse", "false")
+	}
+	if opts.NoTranslate {
+		values.Set("translate", "false")
+	}
+	if opts.NoSegment {
+		values.Set("segment", "false")
+	}
+	if opts.NoSpell {
+		values.Set("spell", "false")
+	}
+	if opts.Sort != "" && opts.Sort != SortRelevance {
+		values.Set("sort", opts.Sort)
+	}
+	if opts.Filters.Source != "" {
+		values.Set("source", opts.Filters.Source)
+	}
+	if opts.Filters.Language != "" {
+		values.Set("lang", opts.Filters.Language)
+	}
+	if opts.Filters.Location != "" {
+		values.Set("location", opts.Filters.Location)
+	}
+	if opts.Filters.Author != "" {
+		values.Set("author", opts.Filters.Author)
+	}
+	if opts.Filters.Category != "" {
+		values.Set("category", opts.Filters.Category)
+	}
+	if opts.Filters.DocType != "" {
+		values.Set("doctype", opts.Filters.DocType)
+	}
+	for name, value := range opts.Filters.Fields {
+		values.Set(SCHEMA_FILTER_PREFIX+name, value)
+	}
+	if opts.Filters.MinPrice > 0 {
+		values.Set("min_price", strconv.FormatInt(opts.Filters.MinPrice, 10))
+	}
+	if opts.Filters.MaxPrice > 0 {
+		values.Set("max_price", strconv.FormatInt(opts.Filters.MaxPrice, 10))
+	}
+	if opts.Filters.MinWords > 0 {
+		values.Set("min_words", strconv.Itoa(opts.Filters.MinWords))
+	}
+	if !opts.Filters.Since.IsZero() {
+		days := int(math.Round(time.Since(opts.Filters.Since).Hours() / 24))
+		values.Set("days", strconv.Itoa(days))
+	}
+	if !opts.Filters.From.IsZero() {
+		values.Set("from", opts.Filters.From.Format(FILTER_DATE_LAYOUT))
+	}
+	if !opts.Filters.To.IsZero() {
+		values.Set("to", opts.Filters.To.Format(FILTER_DATE_LAYOUT))
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	return template.URL("&" + values.Encode())
+}
+
+func getBlocklistHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, blocklist.Entries())
+}
+
+// Payload blocklist dengan doc_ids sudah diubah ke URL artikel
+func bindBlocklistUpdate(c *gin.Context) (BlocklistUpdate, bool) {
+	var update BlocklistUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return update, false
+	}
+	if len(update.DocIDs) > 0 {
+		articles, _, err := loadIndex()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error loading articles"})
+			return update, false
+		}
+		if err := update.resolveDocIDs(articles); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return update, false
+		}
+	}
+	return update, true
+}
+
+func addBlocklistHandler(c *gin.Context) {
+	update, ok := bindBlocklistUpdate(c)
+	if !ok {
+		return
+	}
+	before := auditSnapshot(blocklist.Entries())
+	if err := blocklist.Add(update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	purgeResultCaches()
+	entries := blocklist.Entries()
+	auditActor(c).Record(AuditBlocklistAdd, "", before, entries)
+	c.JSON(http.StatusOK, entries)
+}
+
+func removeBlocklistHandler(c *gin.Context) {
+	update, ok := bindBlocklistUpdate(c)
+	if !ok {
+		return
+	}
+	before := auditSnapshot(blocklist.Entries())
+	if err := blocklist.Remove(update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	purgeResultCaches()
+	entries := blocklist.Entries()
+	auditActor(c).Record(AuditBlocklistRemove, "", before, entries)
+	c.JSON(http.StatusOK, entries)
+}
+
+func listReviewHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"items": reviewQueue.List(c.Query("status")),
+	})
+}
+
+// Handler approve/reject, body: {"url": "..."}
+func reviewDecisionHandler(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			URL string `json:"url" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		before := auditSnapshot(reviewQueue.Get(req.URL))
+		item, err := reviewQueue.SetStatus(req.URL, status)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		purgeResultCaches()
+		indexer.Invalidate()
+		auditActor(c).Record(AuditReviewDecision+status, req.URL, before, item)
+		c.JSON(http.StatusOK, item)
+	}
+}
+
+// Edit dokumen di review queue, body: {"url": "...", "title": "...", "content": "...", "date": "..."}
+func reviewEditHandler(c *gin.Context) {
+	var req struct {
+		URL string `json:"url" binding:"required"`
+		ArticleEdit
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.ArticleEdit.Date = req.ArticleEdit.Date.UTC()
+	before := auditSnapshot(reviewQueue.Get(req.URL))
+	item, err := reviewQueue.Edit(req.URL, req.ArticleEdit)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	purgeResultCaches()
+	indexer.Invalidate()
+	auditActor(c).Record(AuditReviewEdit, req.URL, before, item)
+	c.JSON(http.StatusOK, item)
+}
+
+func reviewPageHandler(c *gin.Context) {
+	renderHTML(c, http.StatusOK, "admin_review.html", gin.H{
+		"items": reviewQueue.List(ReviewPending),
+		"token": c.Query("token"),
+	})
+}
+
+// Report zero-result query terbaru, ?refresh=1 untuk mining ulang sekarang
+func zeroResultsHandler(c *gin.Context) {
+	report := latestZeroResultReport()
+	if report == nil || c.Query("refresh") == "1" {
+		var err error
+		report, err = mineZeroResultQueries()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
 		}
-		pagedResults = allResults[start:end]
-	}
-
-	c.HTML(http.StatusOK, "results.html", gin.H{
-		"results":      pagedResults,
-		"query":        query,
-		"method":       method,
-		"currentPage":  page,
-		"totalPages":   totalPages,
-		"totalResults": totalResults,
-		"previousPage": page - 1,
-		"nextPage":     page + 1,
-		"showPrevious": page > 1,
-		"showNext":     page < totalPages,
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func listSynonymsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"candidates": synonyms.List(c.Query("status")),
 	})
 }
+
+// Approve/reject kandidat sinonim, body: {"term": "...", "synonym": "..."}
+func synonymDecisionHandler(status string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req struct {
+			Term    string `json:"term" binding:"required"`
+			Synonym string `json:"synonym" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		before := auditSnapshot(synonyms.Candidate(req.Term, req.Synonym))
+		candidate, err := synonyms.Decide(req.Term, req.Synonym, status)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		purgeResultCaches()
+		auditActor(c).Record(AuditSynonymDecision+status, candidate.Term+" -> "+candidate.Synonym, before, candidate)
+		c.JSON(http.StatusOK, candidate)
+	}
+}
+
+// Statistik index: jumlah dokumen/term/posting dan rata-rata panjang dokumen
+func indexStatsHandler(c *gin.Context) {
+	_, idx, err := loadIndex()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, idx.Stats())
+}
+
+func filterCacheHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, filterCache.Stats())
+}
+
+// Terima perubahan dokumen: [{"op": "add|update|delete", "url": "...", "article": {...}}].
+// Response dikirim setelah perubahan aman di write-ahead log. Record yang
+// melanggar aturan validasi (validation.go) ditolak satu per satu di
+// "rejected", record lain tetap diterima.
+func ingestHandler(c *gin.Context) {
+	var records []WalRecord
+	if err := c.ShouldBindJSON(&records); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	cfg := requestConfig(c)
+	if limit := cfg.Validation.MaxBatchRecords; limit > 0 && len(records) > limit {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("batch has %d records, limit is %d", len(records), limit)})
+		return
+	}
+
+	valid, rejected := cfg.Validation.validateBatch(records, cfg.Schema)
+	if len(valid) == 0 && len(rejected) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":    fmt.Sprintf("all %d records rejected, first: %v", len(records), rejected[0]),
+			"accepted": []uint64{},
+			"rejected": rejected,
+		})
+		return
+	}
+	for _, record := range valid {
+		if record.Article != nil {
+			record.Article.Date = record.Article.Date.UTC()
+			record.Article.Provenance = requestProvenance(c, ProvenanceIngest, record.Article.Provenance)
+		}
+	}
+
+	accepted, err := auditActor(c).appendDocuments(valid...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	seqs := make([]uint64, len(accepted))
+	for i, record := range accepted {
+		seqs[i] = record.Seq
+	}
+	if len(rejected) > 0 {
+		log.Printf("Ingest rejected %d of %d records", len(rejected), len(records))
+	}
+	scheduleEnrichment()
+	c.JSON(http.StatusAccepted, gin.H{"accepted": seqs, "rejected": rejected, "pending": len(documentLog.Pending())})
+}
+
+func mergeStatsHandler(c *gin.Context) {
+	cfg := requestConfig(c)
+	c.JSON(http.StatusOK, gin.H{
+		"policy": gin.H{
+			"wal_flush_records":          cfg.WalFlushRecords,
+			"wal_flush_interval_seconds": cfg.WalFlushIntervalSeconds,
+		},
+		"stats": documentLog.Stats(),
+	})
+}
+
+// Paksa merge WAL dan compaction document store
+func forceMergeHandler(c *gin.Context) {
+	before := auditSnapshot(documentLog.Stats())
+	if err := forceMerge(documentLog); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	stats := documentLog.Stats()
+	auditActor(c).Record(AuditIndexMerge, "", before, stats)
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Test integrasi: server lengkap (setupRouter) dijalankan lewat httptest
+// terhadap corpus fixture kecil di direktori sementara, dari query string
+// sampai HTML/JSON yang dirender.
+
+// Artikel fixture. Konten minimal MinContentLength karakter dan berjudul,
+// supaya tidak ditahan quality check.
+var fixtureArticles = []Article{
+	{
+		Title:   "Apartemen Murah di Jakarta Selatan",
+		URL:     "https://artikel.rumah123.com/apartemen-murah-jakarta-selatan",
+		Date:    fixtureDate(2024, 3, 1),
+		Author:  "Sinta Dewi",
+		Content: "Apartemen murah di Jakarta Selatan makin dicari pekerja muda. Apartemen studio dekat stasiun MRT dijual mulai tiga ratus juta. Pengembang menawarkan cicilan apartemen tanpa uang muka untuk pembeli pertama, dan apartemen dua kamar tersedia di kawasan Lebak Bulus serta Fatmawati.",
+	},
+	{
+		Title:   "Tips Memilih Hunian Vertikal untuk Keluarga",
+		URL:     "https://propertiterkini.com/tips-hunian-vertikal-keluarga",
+		Date:    fixtureDate(2023, 8, 12),
+		Author:  "Budi Santoso",
+		Content: "Keluarga dengan anak kecil perlu memperhatikan fasilitas taman bermain, keamanan lift dan jarak ke sekolah sebelum membeli hunian vertikal. Harga sebuah apartemen keluarga biasanya lebih tinggi, tetapi biaya transportasi harian bisa jauh lebih hemat dibanding rumah tapak di pinggiran kota.",
+	},
+}
+
+// Dua belas artikel rumah subsidi untuk paginasi, masing-masing dengan kota
+// dan bank berbeda supaya tidak dianggap duplikat
+var fixtureCities = []string{"Bekasi", "Depok", "Bogor", "Tangerang", "Cikarang", "Karawang", "Serang", "Cilegon", "Sukabumi", "Cianjur", "Purwakarta", "Subang"}
+
+var fixtureBanks = []string{"BTN", "BRI", "Mandiri", "BNI", "BSI", "BCA", "CIMB", "Danamon", "Permata", "Maybank", "OCBC", "Panin"}
+
+func fixtureDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 8, 0, 0, 0, defaultDateLocation)
+}
+
+func fixtureCorpus() []Article {
+	articles := append([]Article(nil), fixtureArticles...)
+	for i, city := range fixtureCities {
+		host := "https://artikel.rumah123.com/"
+		if i%3 == 2 {
+			host = "https://propertiterkini.com/"
+		}
+		slug := strings.ToLower(city)
+		articles = append(articles, Article{
+			Title:  "Rumah Subsidi di " + city,
+			URL:    host + "rumah-subsidi-" + slug,
+			Date:   fixtureDate(2022, time.Month(i%12+1), 10+i),
+			Author: "Redaksi",
+			Content: fmt.Sprintf("Program rumah subsidi di %s dibuka kembali tahun ini. Calon pembeli bisa mengajukan KPR lewat bank %s "+
+				"dengan bunga tetap lima persen selama dua puluh tahun. Pemerintah daerah %s menyiapkan %d unit baru di dekat "+
+				"kawasan industri, lengkap dengan akses jalan, air bersih dan sekolah dasar bagi penghuni.", city, fixtureBanks[i], city, 100*(i+1)),
+		})
+	}
+	return articles
+}
+
+var testRouter *gin.Engine
+
+func TestMain(m *testing.M) {
+	os.Exit(runWithFixture(m))
+}
+
+// Server dijalankan dari direktori sementara: corpus, WAL, query log dan
+// file state lain tidak menyentuh working tree
+func runWithFixture(m *testing.M) int {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = ioutil.Discard
+	log.SetOutput(ioutil.Discard)
+
+	repo, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	dir, err := ioutil.TempDir("", "search-engine-test")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"templates", "static"} {
+		if err := os.Symlink(filepath.Join(repo, name), filepath.Join(dir, name)); err != nil {
+			panic(err)
+		}
+	}
+	if err := writeJSONFile(filepath.Join(dir, ARTICLES_FILE), fixtureCorpus()); err != nil {
+		panic(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+	defer os.Chdir(repo)
+
+	if err := openStorage(); err != nil {
+		panic(err)
+	}
+	// Index pertama juga mengisi kosakata saran
+	if _, _, err := loadIndex(); err != nil {
+		panic(err)
+	}
+	testRouter = setupRouter()
+	return m.Run()
+}
+
+func get(t *testing.T, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func getJSON(t *testing.T, target string, out interface{}) {
+	t.Helper()
+	w := get(t, target)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s: status %d, body %s", target, w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+		t.Fatalf("GET %s: %v", target, err)
+	}
+}
+
+// Posisi kemunculan setiap string di body; gagal kalau ada yang tidak muncul
+func positions(t *testing.T, body string, needles ...string) []int {
+	t.Helper()
+	indexes := make([]int, len(needles))
+	for i, needle := range needles {
+		if indexes[i] = strings.Index(body, needle); indexes[i] < 0 {
+			t.Fatalf("%q not in response", needle)
+		}
+	}
+	return indexes
+}
+
+func TestSearchPage(t *testing.T) {
+	w := get(t, "/search?q=apartemen&method=cosine")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d", w.Code)
+	}
+	body := w.Body.String()
+
+	if !strings.Contains(body, "About 2 results (Page 1 of 1)") {
+		t.Error("missing result count")
+	}
+	// Artikel yang membahas apartemen di setiap kalimat di atas artikel
+	// yang hanya menyebutnya sekali
+	at := positions(t, body,
+		`href="https://artikel.rumah123.com/apartemen-murah-jakarta-selatan"`,
+		`href="https://propertiterkini.com/tips-hunian-vertikal-keluarga"`)
+	if at[0] > at[1] {
+		t.Error("apartemen-murah-jakarta-selatan should rank first")
+	}
+	positions(t, body,
+		"<em>Apartemen</em> murah di Jakarta Selatan makin dicari pekerja muda.",
+		"Harga sebuah <em>apartemen</em> keluarga biasanya lebih tinggi")
+}
+
+func TestSearchPagePagination(t *testing.T) {
+	first := get(t, "/search?q=subsidi&method=cosine").Body.String()
+	if !strings.Contains(first, "About 12 results (Page 1 of 2)") {
+		t.Error("missing result count on page 1")
+	}
+	if n := strings.Count(first, `class="search-result-link"`); n != ITEMS_PER_PAGE {
+		t.Errorf("page 1 has %d results, want %d", n, ITEMS_PER_PAGE)
+	}
+	positions(t, first, `<a href="/search?q=subsidi&method=cosine&page=2" aria-label="Next page">`)
+
+	second := get(t, "/search?q=subsidi&method=cosine&page=2").Body.String()
+	if !strings.Contains(second, "About 12 results (Page 2 of 2)") {
+		t.Error("missing result count on page 2")
+	}
+	if n := strings.Count(second, `class="search-result-link"`); n != 2 {
+		t.Errorf("page 2 has %d results, want 2", n)
+	}
+}
+
+func TestSearchPageFilters(t *testing.T) {
+	body := get(t, "/search?q=subsidi&method=cosine&source=propertiterkini").Body.String()
+	if !strings.Contains(body, "About 4 results (Page 1 of 1)") {
+		t.Error("source filter should leave 4 results")
+	}
+	if strings.Contains(body, `href="https://artikel.rumah123.com/`) {
+		t.Error("source filter let a rumah123 result through")
+	}
+}
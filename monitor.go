@@ -0,0 +1,374 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Query yang dipantau ("tapera", "suku bunga KPR"): jumlah hasilnya dihitung
+// ulang setiap generasi index baru dan dikirim lewat SSE ke dashboard
+// /admin/dashboard, jadi pertumbuhan cakupan corpus terlihat langsung.
+// Karena index dibangun saat dibutuhkan, job reindex memeriksa setiap
+// MONITOR_CHECK_INTERVAL apakah corpus (articles.json atau WAL) berubah dan
+// membangun index baru kalau perlu. Query dan riwayat jumlahnya (maksimal
+// MONITOR_HISTORY_SIZE titik per query) disimpan di monitored_queries.json.
+const (
+	MONITORED_QUERIES_FILE = "monitored_queries.json"
+	MONITOR_HISTORY_SIZE   = 100
+	MONITOR_CHECK_INTERVAL = 30 * time.Second
+	MONITOR_MAX_QUERIES    = 50
+
+	JobReindex = "reindex"
+)
+
+// Jumlah hasil query pada satu generasi index
+type MonitorPoint struct {
+	Generation int       `json:"generation"`
+	Time       time.Time `json:"time"`
+	Total      int       `json:"total"`
+}
+
+type MonitoredQuery struct {
+	Query  string         `json:"query"`
+	Points []MonitorPoint `json:"points"` // terlama dulu
+}
+
+// Event "count" di stream: jumlah terbaru satu query dan selisihnya dengan
+// generasi sebelumnya
+type MonitorUpdate struct {
+	Query      string    `json:"query"`
+	Generation int       `json:"generation"`
+	Time       time.Time `json:"time"`
+	Total      int       `json:"total"`
+	Delta      int       `json:"delta"`
+	// Selisih dengan titik pertama di riwayat
+	Growth int `json:"growth"`
+}
+
+type QueryMonitor struct {
+	mu          sync.Mutex
+	path        string
+	Queries     []MonitoredQuery `json:"queries"`
+	subscribers map[chan MonitorUpdate]struct{}
+	// Diaktifkan startQueryMonitor, supaya perintah searchctl yang membangun
+	// index tidak ikut menghitung
+	active bool
+}
+
+var queryMonitor = &QueryMonitor{path: MONITORED_QUERIES_FILE, subscribers: make(map[chan MonitorUpdate]struct{})}
+
+func loadQueryMonitor(path string) (*QueryMonitor, error) {
+	monitor := &QueryMonitor{path: path, subscribers: make(map[chan MonitorUpdate]struct{})}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return monitor, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, monitor); err != nil {
+		return nil, err
+	}
+	return monitor, nil
+}
+
+// Dipanggil dengan mu terkunci
+func (m *QueryMonitor) save() error {
+	return writeJSONFile(m.path, m)
+}
+
+func (m *QueryMonitor) find(query string) int {
+	for i, mq := range m.Queries {
+		if strings.EqualFold(mq.Query, query) {
+			return i
+		}
+	}
+	return -1
+}
+
+func monitorUpdate(mq MonitoredQuery) MonitorUpdate {
+	last := mq.Points[len(mq.Points)-1]
+	update := MonitorUpdate{Query: mq.Query, Generation: last.Generation, Time: last.Time, Total: last.Total}
+	if len(mq.Points) > 1 {
+		update.Delta = last.Total - mq.Points[len(mq.Points)-2].Total
+		update.Growth = last.Total - mq.Points[0].Total
+	}
+	return update
+}
+
+// Jumlah terbaru setiap query yang sudah pernah dihitung
+func (m *QueryMonitor) Latest() []MonitorUpdate {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	updates := make([]MonitorUpdate, 0, len(m.Queries))
+	for _, mq := range m.Queries {
+		if len(mq.Points) > 0 {
+			updates = append(updates, monitorUpdate(mq))
+		}
+	}
+	return updates
+}
+
+func (m *QueryMonitor) List() []MonitoredQuery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list := make([]MonitoredQuery, len(m.Queries))
+	for i, mq := range m.Queries {
+		list[i] = MonitoredQuery{Query: mq.Query, Points: append([]MonitorPoint{}, mq.Points...)}
+	}
+	return list
+}
+
+func (m *QueryMonitor) Add(query string) error {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return fmt.Errorf("query is required")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.find(query) >= 0 {
+		return fmt.Errorf("query %q is already monitored", query)
+	}
+	if len(m.Queries) >= MONITOR_MAX_QUERIES {
+		return fmt.Errorf("at most %d queries can be monitored", MONITOR_MAX_QUERIES)
+	}
+	m.Queries = append(m.Queries, MonitoredQuery{Query: query, Points: []MonitorPoint{}})
+	return m.save()
+}
+
+func (m *QueryMonitor) Remove(query string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.find(query)
+	if i < 0 {
+		return fmt.Errorf("query %q is not monitored", query)
+	}
+	m.Queries = append(m.Queries[:i], m.Queries[i+1:]...)
+	return m.save()
+}
+
+// Hitung ulang jumlah hasil query untuk generasi index; query yang sudah
+// punya titik untuk generasi itu dilewati
+func (m *QueryMonitor) Evaluate(generation int) {
+	m.mu.Lock()
+	var queries []string
+	for _, mq := range m.Queries {
+		if len(mq.Points) == 0 || mq.Points[len(mq.Points)-1].Generation != generation {
+			queries = append(queries, mq.Query)
+		}
+	}
+	m.mu.Unlock()
+	if len(queries) == 0 {
+		return
+	}
+
+	// Pencarian di luar lock, karena loadIndex bisa membangun generasi baru
+	// yang memanggil Evaluate lagi
+	totals := make(map[string]int, len(queries))
+	for _, query := range queries {
+		results, _ := searchWithPlan(query, "cosine", SearchOptions{Sort: SortRelevance})
+		totals[query] = len(results)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var updates []MonitorUpdate
+	for i := range m.Queries {
+		mq := &m.Queries[i]
+		total, evaluated := totals[mq.Query]
+		if !evaluated {
+			continue
+		}
+		if n := len(mq.Points); n > 0 && mq.Points[n-1].Generation >= generation {
+			continue
+		}
+		mq.Points = append(mq.Points, MonitorPoint{Generation: generation, Time: now, Total: total})
+		if len(mq.Points) > MONITOR_HISTORY_SIZE {
+			mq.Points = mq.Points[len(mq.Points)-MONITOR_HISTORY_SIZE:]
+		}
+		updates = append(updates, monitorUpdate(*mq))
+	}
+	if err := m.save(); err != nil {
+		log.Printf("Error saving %s: %v", m.path, err)
+	}
+	for _, update := range updates {
+		for ch := range m.subscribers {
+			// Subscriber yang lambat melewatkan update; generasi berikutnya menyusul
+			select {
+			case ch <- update:
+			default:
+			}
+		}
+	}
+}
+
+// Dipanggil setiap generasi index baru
+func (m *QueryMonitor) IndexSwapped(generation int) {
+	m.mu.Lock()
+	active := m.active
+	m.mu.Unlock()
+	if active {
+		go m.Evaluate(generation)
+	}
+}
+
+func (m *QueryMonitor) Subscribe() (<-chan MonitorUpdate, func()) {
+	ch := make(chan MonitorUpdate, 64)
+	m.mu.Lock()
+	m.subscribers[ch] = struct{}{}
+	m.mu.Unlock()
+
+	return ch, func() {
+		m.mu.Lock()
+		delete(m.subscribers, ch)
+		m.mu.Unlock()
+	}
+}
+
+// Generasi index yang sedang aktif, 0 kalau index belum pernah dibangun
+func currentGeneration() int {
+	indexGeneration.mu.Lock()
+	defer indexGeneration.mu.Unlock()
+	return indexGeneration.counter
+}
+
+func startQueryMonitor(interval time.Duration) {
+	queryMonitor.mu.Lock()
+	queryMonitor.active = true
+	queryMonitor.mu.Unlock()
+
+	adminJobs.Every(JobReindex, interval)
+}
+
+// Bangun index kalau corpus berubah dan indexer belum sempat, lalu hitung
+// ulang query yang dipantau untuk generasi terakhir
+func reindexJob(ctx context.Context, job jobRun) error {
+	if indexer.stale(indexer.state()) {
+		state, err := indexer.rebuild(IndexTriggerChange, false)
+		if err != nil {
+			return err
+		}
+		job.Advance(len(state.Articles), len(state.Articles))
+	}
+
+	// Query yang ditambahkan sebelum index pertama dibangun
+	if generation := currentGeneration(); generation > 0 {
+		queryMonitor.Evaluate(generation)
+	}
+	return nil
+}
+
+// GET /api/admin/monitored: query yang dipantau beserta riwayat jumlahnya
+func listMonitoredHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"generation": currentGeneration(), "queries": queryMonitor.List()})
+}
+
+// POST /api/admin/monitored, body: {"query": "tapera"}. Jumlahnya langsung
+// dihitung untuk generasi sekarang.
+func addMonitoredHandler(c *gin.Context) {
+	var req struct {
+		Query string `json:"query" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	before := auditSnapshot(monitoredQueryNames())
+	if err := queryMonitor.Add(req.Query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	auditActor(c).Record(AuditMonitorAdd, req.Query, before, monitoredQueryNames())
+
+	if generation := currentGeneration(); generation > 0 {
+		queryMonitor.Evaluate(generation)
+	}
+	c.JSON(http.StatusOK, gin.H{"generation": currentGeneration(), "queries": queryMonitor.List()})
+}
+
+// DELETE /api/admin/monitored, body: {"query": "tapera"}
+func removeMonitoredHandler(c *gin.Context) {
+	var req struct {
+		Query string `json:"query" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	before := auditSnapshot(monitoredQueryNames())
+	if err := queryMonitor.Remove(req.Query); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	auditActor(c).Record(AuditMonitorRemove, req.Query, before, monitoredQueryNames())
+	c.JSON(http.StatusOK, gin.H{"generation": currentGeneration(), "queries": queryMonitor.List()})
+}
+
+func monitoredQueryNames() []string {
+	list := queryMonitor.List()
+	names := make([]string, len(list))
+	for i, mq := range list {
+		names[i] = mq.Query
+	}
+	return names
+}
+
+// GET /api/admin/monitored/stream: Server-Sent Events, satu event "count"
+// per query setiap generasi index baru. Jumlah terbaru dikirim dulu saat
+// terhubung.
+func monitoredStreamHandler(c *gin.Context) {
+	updates, unsubscribe := queryMonitor.Subscribe()
+	defer unsubscribe()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	for _, update := range queryMonitor.Latest() {
+		c.SSEvent("count", update)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(CRAWL_STREAM_HEARTBEAT)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case update := <-updates:
+			c.SSEvent("count", update)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+func init() {
+	// Tanpa retry: job berikutnya jalan MONITOR_CHECK_INTERVAL lagi
+	registerJobKind(JobReindex, "", RetryPolicy{}, reindexJob)
+
+	monitor, err := loadQueryMonitor(MONITORED_QUERIES_FILE)
+	if err != nil {
+		log.Printf("Error loading %s: %v", MONITORED_QUERIES_FILE, err)
+		return
+	}
+	queryMonitor = monitor
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OCR untuk PDF hasil scan (peraturan yang hanya berupa gambar halaman).
+// Kalau extractor teks hampir tidak menemukan kata, setiap halaman
+// di-OCR dengan tesseract (CLI, atau server OCR lewat HTTP) dan teksnya
+// disimpan per halaman di Article.Pages. Hasil pencarian dokumen seperti
+// ini menautkan halaman yang paling cocok (#page=N).
+//
+//	"ocr": {"enabled": true, "language": "ind", "min_word_confidence": 60, "min_page_confidence": 50}
+//
+// Gambar halaman diambil dari JPEG yang tertanam di PDF (scanner biasanya
+// menyimpan satu JPEG per halaman). Untuk scan CCITT/JBIG2 atau PDF campuran,
+// pakai rasterize_command, mis. ["pdftoppm", "-r", "300", "-png", "{input}", "{output}/page"].
+// Kata di bawah min_word_confidence dibuang; halaman yang rata-ratanya di
+// bawah min_page_confidence tetap disimpan tapi tidak ikut diindex.
+const (
+	OCR_LANGUAGE               = "ind"
+	OCR_MIN_WORD_CONFIDENCE    = 60
+	OCR_MIN_PAGE_CONFIDENCE    = 50
+	OCR_MIN_TEXT_WORDS         = 20  // teks hasil extractor lebih sedikit dari ini dianggap scan
+	OCR_MIN_IMAGE_WIDTH        = 600 // gambar lebih kecil (logo, stempel) bukan halaman
+	OCR_MAX_PAGES              = 200
+	OCR_PAGE_TIMEOUT           = time.Minute
+	OCR_LANGUAGE_PLACEHOLDER   = "{lang}"
+	OCR_INPUT_PLACEHOLDER      = "{input}"
+	OCR_OUTPUT_DIR_PLACEHOLDER = "{output}"
+)
+
+type OCRConfig struct {
+	Enabled bool `json:"enabled"`
+	// Perintah OCR: gambar di stdin, TSV tesseract di stdout
+	Command []string `json:"command"`
+	// Kalau di-set, gambar di-POST ke URL ini (?lang=...) dan response-nya TSV tesseract
+	URL               string   `json:"url"`
+	Language          string   `json:"language"`
+	MinWordConfidence float64  `json:"min_word_confidence"`
+	MinPageConfidence float64  `json:"min_page_confidence"`
+	RasterizeCommand  []string `json:"rasterize_command"`
+}
+
+func defaultOCRConfig() OCRConfig {
+	return OCRConfig{
+		Command:           []string{"tesseract", "stdin", "stdout", "-l", OCR_LANGUAGE_PLACEHOLDER, "tsv"},
+		Language:          OCR_LANGUAGE,
+		MinWordConfidence: OCR_MIN_WORD_CONFIDENCE,
+		MinPageConfidence: OCR_MIN_PAGE_CONFIDENCE,
+	}
+}
+
+// Teks satu halaman dokumen
+type DocumentPage struct {
+	Number     int     `json:"number"` // 1-based
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence,omitempty"` // rata-rata confidence kata OCR (0-100)
+	OCR        bool    `json:"ocr,omitempty"`
+	Skipped    bool    `json:"skipped,omitempty"` // di bawah min_page_confidence, tidak diindex
+}
+
+var pdfWidthPattern = regexp.MustCompile(`/Width\s+(\d+)`)
+
+// Perlu OCR: extractor tidak menemukan cukup kata
+func needsOCR(doc *ExtractedDocument) bool {
+	return doc == nil || countWords(doc.Text) < OCR_MIN_TEXT_WORDS
+}
+
+// OCR setiap halaman PDF; language kosong memakai ocr.language
+func ocrPDF(data []byte, language string, cfg OCRConfig) ([]DocumentPage, error) {
+	if language == "" {
+		language = cfg.Language
+	}
+	images, err := pdfPageImages(data, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) == 0 {
+		return nil, errors.New("no page images found for OCR")
+	}
+	if len(images) > OCR_MAX_PAGES {
+		return nil, fmt.Errorf("document has %d pages, OCR is limited to %d", len(images), OCR_MAX_PAGES)
+	}
+
+	pages := make([]DocumentPage, len(images))
+	for i, image := range images {
+		tsv, err := recognizeImage(image, language, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %v", i+1, err)
+		}
+		text, confidence := parseTesseractTSV(tsv, cfg.MinWordConfidence)
+		pages[i] = DocumentPage{
+			Number:     i + 1,
+			Text:       text,
+			Confidence: confidence,
+			OCR:        true,
+			Skipped:    text == "" || confidence < cfg.MinPageConfidence,
+		}
+	}
+	return pages, nil
+}
+
+// Konten yang diindex: halaman yang lolos threshold, dipisah baris baru
+func pagesText(pages []DocumentPage) string {
+	var texts []string
+	for _, page := range pages {
+		if !page.Skipped && page.Text != "" {
+			texts = append(texts, page.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// Gambar halaman: lewat rasterize_command kalau ada, kalau tidak JPEG
+// yang tertanam di PDF sesuai urutan di file
+func pdfPageImages(data []byte, cfg OCRConfig) ([][]byte, error) {
+	if len(cfg.RasterizeCommand) > 0 {
+		return rasterizePDF(data, cfg.RasterizeCommand)
+	}
+
+	var images [][]byte
+	for _, stream := range pdfStreams(data) {
+		if !strings.Contains(stream.Dict, "/Image") || !strings.Contains(stream.Dict, "/DCTDecode") {
+			continue
+		}
+		// Filter berantai (mis. FlateDecode + DCTDecode) tidak didukung
+		if strings.Contains(stream.Dict, "/FlateDecode") {
+			continue
+		}
+		if m := pdfWidthPattern.FindStringSubmatch(stream.Dict); m != nil {
+			if width, _ := strconv.Atoi(m[1]); width < OCR_MIN_IMAGE_WIDTH {
+				continue
+			}
+		}
+		images = append(images, stream.Data)
+	}
+	return images, nil
+}
+
+// Jalankan rasterizer ke direktori sementara, halaman diurutkan per nama file
+func rasterizePDF(data []byte, command []string) ([][]byte, error) {
+	dir, err := ioutil.TempDir("", "ocr")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.pdf")
+	if err := ioutil.WriteFile(input, data, 0644); err != nil {
+		return nil, err
+	}
+	output := filepath.Join(dir, "pages")
+	if err := os.Mkdir(output, 0755); err != nil {
+		return nil, err
+	}
+
+	args := make([]string, len(command))
+	for i, arg := range command {
+		arg = strings.Replace(arg, OCR_INPUT_PLACEHOLDER, input, -1)
+		args[i] = strings.Replace(arg, OCR_OUTPUT_DIR_PLACEHOLDER, output, -1)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), OCR_PAGE_TIMEOUT)
+	defer cancel()
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	files, err := ioutil.ReadDir(output)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+	var images [][]byte
+	for _, file := range files {
+		image, err := ioutil.ReadFile(filepath.Join(output, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+// TSV tesseract untuk satu gambar, lewat server OCR atau perintah lokal
+func recognizeImage(image []byte, language string, cfg OCRConfig) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), OCR_PAGE_TIMEOUT)
+	defer cancel()
+
+	if cfg.URL != "" {
+		req, err := http.NewRequest(http.MethodPost, cfg.URL+"?lang="+url.QueryEscape(language), bytes.NewReader(image))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", http.DetectContentType(image))
+		res, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return "", err
+		}
+		defer res.Body.Close()
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return "", err
+		}
+		if res.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("ocr server: %s: %s", res.Status, strings.TrimSpace(string(body)))
+		}
+		return string(body), nil
+	}
+
+	command := cfg.Command
+	if len(command) == 0 {
+		return "", errors.New("no ocr command or url configured")
+	}
+	args := make([]string, len(command))
+	for i, arg := range command {
+		args[i] = strings.Replace(arg, OCR_LANGUAGE_PLACEHOLDER, language, -1)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(image)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// Teks dari TSV tesseract (level, page_num, block_num, par_num, line_num,
+// word_num, left, top, width, height, conf, text). Kata dengan confidence
+// di bawah minConfidence dibuang; paragraf tesseract dipisah baris baru.
+// Confidence halaman adalah rata-rata semua kata, sebelum dibuang.
+func parseTesseractTSV(tsv string, minConfidence float64) (string, float64) {
+	var paragraphs []string
+	var paragraph []string
+	currentPar := ""
+	total, words := 0.0, 0
+
+	for _, line := range strings.Split(tsv, "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), "\t")
+		if len(fields) < 12 || fields[0] != "5" {
+			continue
+		}
+		confidence, err := strconv.ParseFloat(fields[10], 64)
+		text := strings.TrimSpace(fields[11])
+		if err != nil || confidence < 0 || text == "" {
+			continue
+		}
+		total += confidence
+		words++
+
+		// block_num + par_num
+		if par := fields[2] + "/" + fields[3]; par != currentPar {
+			if len(paragraph) > 0 {
+				paragraphs = append(paragraphs, strings.Join(paragraph, " "))
+			}
+			paragraph, currentPar = nil, par
+		}
+		if confidence >= minConfidence {
+			paragraph = append(paragraph, text)
+		}
+	}
+	if len(paragraph) > 0 {
+		paragraphs = append(paragraphs, strings.Join(paragraph, " "))
+	}
+	if words == 0 {
+		return "", 0
+	}
+	return strings.Join(paragraphs, "\n"), total / float64(words)
+}
+
+// Halaman dengan bobot term query terbanyak, 0 kalau tidak ada yang cocok
+func bestPage(pages []DocumentPage, language string, queryVector map[string]float64) int {
+	best, bestScore := 0, 0.0
+	for _, page := range pages {
+		if page.Skipped {
+			continue
+		}
+		score := 0.0
+		for _, token := range analyzerFor(language).Analyze(page.Text) {
+			score += queryVector[token.Term]
+		}
+		if score > bestScore {
+			best, bestScore = page.Number, score
+		}
+	}
+	return best
+}
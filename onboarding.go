@@ -0,0 +1,357 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Onboarding sumber baru: dari satu contoh URL artikel, tebak selector
+// title/content/date/author dengan heuristik lalu kembalikan draft
+// CrawlSource. Hasilnya hanya usulan; admin meninjau, menyesuaikan, lalu
+// menyimpannya lewat POST /api/admin/sources dan mengeceknya dengan
+// searchctl crawl <name> --dry-run.
+const (
+	ONBOARDING_MAX_BYTES   = 5 << 20
+	ONBOARDING_TIMEOUT     = 15 * time.Second
+	ONBOARDING_CANDIDATES  = 3   // kandidat per field
+	ONBOARDING_MIN_CONTENT = 200 // panjang teks minimum container konten
+	ONBOARDING_SAMPLE      = 160
+)
+
+// Layout tanggal yang diusulkan sebagai date_layout kalau cocok persis
+var onboardingDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"02/01/2006",
+}
+
+type SelectorCandidate struct {
+	Field    string `json:"field"`
+	Selector string `json:"selector"`
+	Attr     string `json:"attr,omitempty"`
+	Layout   string `json:"layout,omitempty"` // hanya untuk date
+	Sample   string `json:"sample"`
+	Matches  int    `json:"matches"` // jumlah elemen yang cocok di halaman contoh
+	Reason   string `json:"reason"`
+
+	score int
+}
+
+type SourceProposal struct {
+	Draft      CrawlSource         `json:"draft"`
+	Candidates []SelectorCandidate `json:"candidates"`
+	Warnings   []string            `json:"warnings,omitempty"`
+}
+
+func proposeSource(rawURL string) (*SourceProposal, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, errors.New("url must be an absolute http(s) URL")
+	}
+
+	doc, err := fetchDocument(u.String())
+	if err != nil {
+		return nil, err
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	proposal := &SourceProposal{
+		Draft: CrawlSource{
+			Name:     slugify(strings.Split(host, ".")[0]),
+			StartURL: u.Scheme + "://" + u.Host + "/",
+			Domain:   u.Hostname(),
+			MaxDepth: 2,
+		},
+	}
+	if blocklist.IsURLBlocked(u.String()) {
+		proposal.Warnings = append(proposal.Warnings, "this URL is blocklisted, the crawler will skip it")
+	}
+	if _, exists := findCrawlSource(proposal.Draft.Name); exists {
+		proposal.Warnings = append(proposal.Warnings, fmt.Sprintf("a source named %s already exists, pick another name", proposal.Draft.Name))
+	}
+
+	// Container artikel: crawler hanya mengekstrak halaman yang punya elemen ini
+	container := "article"
+	if doc.Find("article").Length() == 0 {
+		container = "body"
+		proposal.Draft.Article = container
+		proposal.Warnings = append(proposal.Warnings, "page has no <article> element, every page (including listings) will be treated as an article")
+	}
+
+	fields := []struct {
+		name       string
+		candidates []SelectorCandidate
+	}{
+		{"title", titleCandidates(doc, container)},
+		{"content", contentCandidates(doc, container)},
+		{"date", dateCandidates(doc, container)},
+		{"author", authorCandidates(doc, container)},
+	}
+
+	for _, field := range fields {
+		candidates := field.candidates
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+		if len(candidates) > ONBOARDING_CANDIDATES {
+			candidates = candidates[:ONBOARDING_CANDIDATES]
+		}
+		proposal.Candidates = append(proposal.Candidates, candidates...)
+
+		if len(candidates) == 0 {
+			if field.name == "title" || field.name == "content" {
+				proposal.Warnings = append(proposal.Warnings, "no "+field.name+" selector found, set it manually")
+			}
+			continue
+		}
+		best := candidates[0]
+		switch field.name {
+		case "title":
+			proposal.Draft.Title = best.Selector
+		case "content":
+			proposal.Draft.Content = best.Selector
+		case "date":
+			proposal.Draft.Date, proposal.Draft.DateAttr, proposal.Draft.DateLayout = best.Selector, best.Attr, best.Layout
+		case "author":
+			proposal.Draft.Author, proposal.Draft.AuthorAttr = best.Selector, best.Attr
+		}
+	}
+
+	return proposal, nil
+}
+
+func fetchDocument(pageURL string) (*goquery.Document, error) {
+	client := &http.Client{Timeout: ONBOARDING_TIMEOUT}
+	req, err := http.NewRequest(http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; search-engine2 onboarding)")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: status %d", pageURL, resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, ONBOARDING_MAX_BYTES))
+	if err != nil {
+		return nil, err
+	}
+	// URL akhir setelah redirect, untuk membuat link relatif jadi absolut
+	doc.Url = resp.Request.URL
+	return doc, nil
+}
+
+// h1 yang teksnya cocok dengan og:title / <title> paling mungkin judul artikel
+func titleCandidates(doc *goquery.Document, container string) []SelectorCandidate {
+	pageTitle := strings.ToLower(strings.TrimSpace(doc.Find(`meta[property="og:title"]`).AttrOr("content", "")))
+	if pageTitle == "" {
+		pageTitle = strings.ToLower(strings.TrimSpace(doc.Find("title").First().Text()))
+	}
+
+	var candidates []SelectorCandidate
+	seen := make(map[string]bool)
+	doc.Find("h1").Each(func(_ int, h *goquery.Selection) {
+		text := collapseSpaces(h.Text())
+		selector := selectorFor(h)
+		if text == "" || seen[selector] {
+			return
+		}
+		seen[selector] = true
+
+		candidate := SelectorCandidate{Field: "title", Selector: selector, Sample: sampleText(text), Matches: doc.Find(selector).Length()}
+		lower := strings.ToLower(text)
+		if pageTitle != "" && (strings.Contains(pageTitle, lower) || strings.Contains(lower, pageTitle)) {
+			candidate.score += 2
+			candidate.Reason = "matches the page title"
+		} else {
+			candidate.Reason = "h1 on the page"
+		}
+		scoreLocation(&candidate, h, container)
+		candidates = append(candidates, candidate)
+	})
+	return candidates
+}
+
+// Container dengan teks paragraf (<p> langsung di bawahnya) terpanjang
+func contentCandidates(doc *goquery.Document, container string) []SelectorCandidate {
+	var candidates []SelectorCandidate
+	seen := make(map[string]bool)
+	doc.Find("article, main, section, div").Each(func(_ int, block *goquery.Selection) {
+		paragraphs := block.ChildrenFiltered("p")
+		length := 0
+		var first string
+		paragraphs.Each(func(_ int, p *goquery.Selection) {
+			text := collapseSpaces(p.Text())
+			length += len(text)
+			if first == "" {
+				first = text
+			}
+		})
+		if length < ONBOARDING_MIN_CONTENT {
+			return
+		}
+
+		selector := selectorFor(block) + " p"
+		if seen[selector] {
+			return
+		}
+		seen[selector] = true
+
+		candidate := SelectorCandidate{
+			Field:    "content",
+			Selector: selector,
+			Sample:   sampleText(first),
+			Matches:  doc.Find(selector).Length(),
+			Reason:   fmt.Sprintf("%d paragraphs, %d chars of text", paragraphs.Length(), length),
+			score:    length / 100,
+		}
+		scoreLocation(&candidate, block, container)
+		candidates = append(candidates, candidate)
+	})
+	return candidates
+}
+
+// Meta tag terbitan artikel, <time>, lalu elemen dengan class "date"
+func dateCandidates(doc *goquery.Document, container string) []SelectorCandidate {
+	var candidates []SelectorCandidate
+	add := func(selector, attr, value, reason string, score int) {
+		// Layout kosong = format yang hanya dikenali parseArticleDate (mis. bulan Indonesia)
+		if _, err := parseArticleDate(value, nil); err != nil {
+			return
+		}
+		layout := dateLayoutOf(value)
+		candidates = append(candidates, SelectorCandidate{
+			Field: "date", Selector: selector, Attr: attr, Layout: layout,
+			Sample: sampleText(value), Matches: doc.Find(selector).Length(), Reason: reason, score: score,
+		})
+	}
+
+	for _, selector := range []string{`meta[property="article:published_time"]`, `meta[itemprop="datePublished"]`, `meta[name="pubdate"]`} {
+		if value, exists := doc.Find(selector).First().Attr("content"); exists {
+			add(selector, "content", strings.TrimSpace(value), "publication meta tag", 3)
+		}
+	}
+
+	seen := make(map[string]bool)
+	doc.Find("time, [class*=date]").Each(func(_ int, el *goquery.Selection) {
+		selector := selectorFor(el)
+		if seen[selector] || !inContainer(el, container) {
+			return
+		}
+		seen[selector] = true
+
+		if value, exists := el.Attr("datetime"); exists {
+			add(selector, "datetime", strings.TrimSpace(value), "datetime attribute", 2)
+		} else if text := collapseSpaces(el.Text()); len(text) < 60 {
+			add(selector, "", text, "date text", 1)
+		}
+	})
+	return candidates
+}
+
+func authorCandidates(doc *goquery.Document, container string) []SelectorCandidate {
+	var candidates []SelectorCandidate
+	if value := strings.TrimSpace(doc.Find(`meta[name="author"]`).AttrOr("content", "")); value != "" {
+		candidates = append(candidates, SelectorCandidate{
+			Field: "author", Selector: `meta[name="author"]`, Attr: "content",
+			Sample: value, Matches: 1, Reason: "author meta tag", score: 2,
+		})
+	}
+
+	seen := make(map[string]bool)
+	doc.Find(`[rel="author"], [class*=author]`).Each(func(_ int, el *goquery.Selection) {
+		text := collapseSpaces(el.Text())
+		selector := selectorFor(el)
+		if text == "" || len(text) > 80 || seen[selector] || !inContainer(el, container) {
+			return
+		}
+		seen[selector] = true
+		candidates = append(candidates, SelectorCandidate{
+			Field: "author", Selector: selector, Sample: text,
+			Matches: doc.Find(selector).Length(), Reason: "author element", score: 1,
+		})
+	})
+	return candidates
+}
+
+// Selector CSS sederhana untuk elemen: tag.class atau tag#id. Id yang
+// mengandung angka (mis. post-1234) biasanya unik per artikel, jadi dilewati.
+func selectorFor(el *goquery.Selection) string {
+	tag := goquery.NodeName(el)
+	if class, exists := el.Attr("class"); exists {
+		for _, name := range strings.Fields(class) {
+			if isCSSIdent(name) {
+				return tag + "." + name
+			}
+		}
+	}
+	if id, exists := el.Attr("id"); exists && isCSSIdent(id) && !strings.ContainsAny(id, "0123456789") {
+		return tag + "#" + id
+	}
+	return tag
+}
+
+func isCSSIdent(name string) bool {
+	if name == "" || !((name[0] >= 'a' && name[0] <= 'z') || (name[0] >= 'A' && name[0] <= 'Z')) {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isWordByte(name[i]) && name[i] != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// Field diekstrak di dalam container artikel; elemen di luarnya tidak akan ketemu
+func inContainer(el *goquery.Selection, container string) bool {
+	return container == "body" || el.Closest(container).Length() > 0
+}
+
+func scoreLocation(candidate *SelectorCandidate, el *goquery.Selection, container string) {
+	if !inContainer(el, container) {
+		candidate.score -= 10
+		candidate.Reason += ", outside <" + container + ">"
+		return
+	}
+	if candidate.Matches == 1 {
+		candidate.score++
+	}
+}
+
+func dateLayoutOf(value string) string {
+	for _, layout := range onboardingDateLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return layout
+		}
+	}
+	return ""
+}
+
+func collapseSpaces(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func sampleText(text string) string {
+	if len(text) > ONBOARDING_SAMPLE {
+		return text[:ONBOARDING_SAMPLE] + "..."
+	}
+	return text
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"html/template"
+	"strings"
+)
+
+// Artikel explainer yang panjang sering menang di cosine hanya karena term
+// query tersebar di banyak paragraf yang tidak saling berhubungan. Dengan
+// paragraph_index, setiap paragraf (diawali judul artikel) juga diindex
+// sebagai sub-dokumen yang menunjuk ke artikelnya. Query di-score per
+// paragraf, skor artikel = skor paragraf terbaiknya, dan snippet diambil
+// dari paragraf itu.
+const PARAGRAPH_MIN_WORDS = 20
+
+type Paragraph struct {
+	Parent  int // docID artikel
+	Ordinal int // urutan di splitParagraphs(content)
+}
+
+type ParagraphIndex struct {
+	Index      *InvertedIndex
+	Paragraphs []Paragraph // per sub-docID
+	MinWords   int         // dipakai lagi saat memecah konten untuk snippet
+}
+
+// Paragraf terbaik sebuah artikel untuk satu query
+type Passage struct {
+	Paragraph
+	DocID int // sub-docID di ParagraphIndex
+	Score float64
+}
+
+func buildParagraphIndex(articles []Article, allowed []bool, cfg *Config) *ParagraphIndex {
+	minWords := cfg.ParagraphMinWords
+	pi := &ParagraphIndex{MinWords: minWords}
+	var docs []Article
+
+	for docID, article := range articles {
+		if !allowed[docID] {
+			continue
+		}
+		paragraphs := splitParagraphs(article.Content, minWords)
+		if len(paragraphs) == 0 {
+			// Artikel tanpa konten tetap bisa ditemukan lewat judulnya
+			paragraphs = []string{""}
+		}
+		for ordinal, text := range paragraphs {
+			docs = append(docs, Article{
+				Title:    article.Title,
+				Content:  text,
+				URL:      article.URL,
+				Language: article.Language,
+			})
+			pi.Paragraphs = append(pi.Paragraphs, Paragraph{Parent: docID, Ordinal: ordinal})
+		}
+	}
+
+	pi.Index = indexDocuments(docs, func(int) bool { return true }, cfg)
+	return pi
+}
+
+// Pecah konten per baris (crawler menggabungkan <p> dengan "\n"). Baris
+// pendek digabung dengan baris berikutnya sampai minimal minWords kata; sisa
+// yang masih pendek di akhir ikut paragraf terakhir. Hasilnya substring
+// konten, tanpa salinan.
+func splitParagraphs(content string, minWords int) []string {
+	var spans [][2]int
+	start, last, words := -1, 0, 0
+
+	for pos := 0; pos <= len(content); {
+		end := strings.IndexByte(content[pos:], '\n')
+		if end < 0 {
+			end = len(content)
+		} else {
+			end += pos
+		}
+
+		if n := len(strings.Fields(content[pos:end])); n > 0 {
+			if start < 0 {
+				start = pos
+			}
+			last = end
+			words += n
+			if words >= minWords {
+				spans = append(spans, [2]int{start, end})
+				start, words = -1, 0
+			}
+		}
+		pos = end + 1
+	}
+	if start >= 0 {
+		if len(spans) > 0 {
+			spans[len(spans)-1][1] = last
+		} else {
+			spans = append(spans, [2]int{start, last})
+		}
+	}
+
+	paragraphs := make([]string, len(spans))
+	for i, span := range spans {
+		paragraphs[i] = content[span[0]:span[1]]
+	}
+	return paragraphs
+}
+
+// Score paragraf milik artikel kandidat, lalu ambil paragraf terbaik per artikel
+func (pi *ParagraphIndex) bestPassages(queryVector map[string]float64, candidates *Bitmap, method string, params BM25Params) map[int]Passage {
+	subCandidates := NewBitmap()
+	for term := range queryVector {
+		postingList, exists := pi.Index.Index[term]
+		if !exists {
+			continue
+		}
+		for subID := range postingList.Postings {
+			if candidates.Contains(pi.Paragraphs[subID].Parent) {
+				subCandidates.Add(subID)
+			}
+		}
+	}
+
+	var scores map[int]float64
+	switch method {
+	case "jaccard":
+		scores = pi.Index.jaccardScores(queryVector, subCandidates)
+	case "bm25":
+		scores = pi.Index.bm25Scores(queryVector, subCandidates, params)
+	default:
+		scores = pi.Index.cosineScores(queryVector, subCandidates, len(pi.Paragraphs))
+	}
+
+	best := make(map[int]Passage)
+	for subID, score := range scores {
+		paragraph := pi.Paragraphs[subID]
+		current, exists := best[paragraph.Parent]
+		// Skor sama: paragraf yang lebih awal menang supaya deterministik
+		if !exists || score > current.Score || (score == current.Score && subID < current.DocID) {
+			best[paragraph.Parent] = Passage{Paragraph: paragraph, DocID: subID, Score: score}
+		}
+	}
+	return best
+}
+
+// Teks paragraf passage di konten artikel; seluruh konten kalau konten
+// sudah berubah sejak index dibangun
+func (pi *ParagraphIndex) text(content string, passage Passage) (string, bool) {
+	paragraphs := splitParagraphs(content, pi.MinWords)
+	if passage.Ordinal < len(paragraphs) {
+		return paragraphs[passage.Ordinal], true
+	}
+	return content, false
+}
+
+// Snippet dari paragraf terbaik, bukan dari bagian awal artikel
+func (pi *ParagraphIndex) snippet(article Article, passage Passage, query string, queryVector map[string]float64, maxLength int, storeOffsets bool) (string, string) {
+	var found bool
+	if article.Content, found = pi.text(article.Content, passage); found && storeOffsets {
+		return offsetSnippet(article, pi.Index, passage.DocID, queryVector, maxLength)
+	}
+
+	preview := getContentPreview(article.Content, query, article.Language, maxLength)
+	return preview, highlightText(template.HTMLEscapeString(preview), query)
+}
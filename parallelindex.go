@@ -0,0 +1,145 @@
+package main
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Build index paralel. Dokumen dibagi ke shard dengan rentang docID yang
+// bersebelahan; worker mengambil shard satu per satu dan membangun index
+// parsial sendiri tanpa lock (analisis, deteksi bahasa, posting). Index
+// parsial digabung berurutan di akhir: posting satu docID hanya ada di satu
+// shard, jadi posting list cukup dipindah atau disatukan, dan hasilnya sama
+// persis dengan build satu worker.
+//
+// Jumlah worker dari config index_workers (0 = jumlah CPU). Corpus kecil
+// tetap satu worker karena ongkos penggabungan lebih besar dari hasilnya.
+const (
+	INDEX_SHARD_MIN_DOCS    = 500
+	INDEX_SHARDS_PER_WORKER = 4 // shard lebih kecil supaya worker selesai bersamaan
+)
+
+// Jumlah worker untuk build n dokumen
+func indexWorkers(n int, cfg *Config) int {
+	workers := cfg.IndexWorkers
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+	if max := n / INDEX_SHARD_MIN_DOCS; workers > max {
+		workers = max
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
+
+// Index dokumen docs[from:to] ke idx. Key term baru dilewatkan ke intern;
+// index parsial memakai term apa adanya dan di-intern saat digabung.
+func indexRange(idx *InvertedIndex, docs []Article, from, to int, allow func(docID int) bool, intern func(string) string, cfg *Config) {
+	var tokens []Token // buffer dipakai ulang antar dokumen
+
+	for docID := from; docID < to; docID++ {
+		article := docs[docID]
+		if !allow(docID) {
+			continue
+		}
+
+		idx.DocCount++
+
+		if article.Language == "" {
+			docs[docID].Language = detectLanguage(article.Title + " " + article.Content)
+		}
+		tokens = cfg.Schema.analyzeDocument(tokens, docs[docID])
+		idx.DocLengths[docID] = docLengthOf(tokens, len(article.Title))
+		idx.TotalTokens += len(tokens)
+
+		// Track position untuk setiap term
+		for _, tok := range tokens {
+			postingList, exists := idx.Index[tok.Term]
+			if !exists {
+				postingList = &PostingList{
+					DocFrequency: 0,
+					Postings:     make(map[int]*Posting),
+				}
+				// Key disalin lewat termTable supaya tidak menahan teks dokumen
+				idx.Index[intern(tok.Term)] = postingList
+			}
+
+			posting, exists := postingList.Postings[docID]
+			if !exists {
+				posting = &Posting{
+					DocID:     docID,
+					Frequency: 0,
+					Positions: make([]int, 0),
+				}
+				postingList.Postings[docID] = posting
+				postingList.DocFrequency++
+			}
+
+			posting.Frequency++
+			posting.Positions = append(posting.Positions, tok.Position)
+			if cfg.StoreOffsets {
+				posting.Offsets = append(posting.Offsets, Offset{Start: tok.Start, End: tok.End})
+			}
+		}
+	}
+}
+
+// Bangun index parsial per shard dengan beberapa worker lalu gabungkan ke idx.
+// DocLengths dan docs ditulis langsung oleh worker; setiap docID hanya
+// disentuh satu shard.
+func indexShards(idx *InvertedIndex, docs []Article, allow func(docID int) bool, workers int, cfg *Config) {
+	shards := workers * INDEX_SHARDS_PER_WORKER
+	size := (len(docs) + shards - 1) / shards
+	parts := make([]*InvertedIndex, shards)
+
+	next := make(chan int, shards)
+	for shard := range parts {
+		next <- shard
+	}
+	close(next)
+
+	keep := func(term string) string { return term }
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for shard := range next {
+				from, to := shard*size, (shard+1)*size
+				if to > len(docs) {
+					to = len(docs)
+				}
+				part := &InvertedIndex{Index: make(map[string]*PostingList), DocLengths: idx.DocLengths}
+				if from < to {
+					indexRange(part, docs, from, to, allow, keep, cfg)
+				}
+				parts[shard] = part
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, part := range parts {
+		idx.mergeShard(part)
+	}
+}
+
+// Gabungkan index parsial shard berikutnya ke idx
+func (idx *InvertedIndex) mergeShard(part *InvertedIndex) {
+	idx.DocCount += part.DocCount
+	idx.TotalTokens += part.TotalTokens
+
+	for term, postingList := range part.Index {
+		merged, exists := idx.Index[term]
+		if !exists {
+			idx.Index[termTable.Intern(term)] = postingList
+			continue
+		}
+		for docID, posting := range postingList.Postings {
+			merged.Postings[docID] = posting
+		}
+		merged.DocFrequency += postingList.DocFrequency
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// Phrase query: bagian query di antara tanda kutip ("rumah subsidi murah")
+// dicocokkan lewat posisi token di posting list. Term phrase harus muncul
+// dengan jarak yang sama seperti di query; stopword di dalam phrase tidak
+// diindex, jadi posisinya boleh diisi stopword lain. Kata di dalam dan di
+// luar tanda kutip tetap dicari seperti biasa, tapi dokumen yang memuat semua
+// phrase persis diranking di atas dokumen yang hanya memuat term-nya secara
+// terpisah.
+
+// Term phrase beserta jaraknya dari term pertama
+type phraseTerm struct {
+	term   string
+	offset int
+}
+
+// Teks di antara pasangan tanda kutip; kutip tanpa pasangan diabaikan
+func queryPhrases(query string) []string {
+	parts := strings.Split(query, `"`)
+	var phrases []string
+	for i := 1; i < len(parts)-1; i += 2 {
+		if phrase := strings.TrimSpace(parts[i]); phrase != "" {
+			phrases = append(phrases, phrase)
+		}
+	}
+	return phrases
+}
+
+// Term phrase per pipeline bahasa query, varian yang sama hanya sekali.
+// Phrase yang tinggal satu term setelah analisis tidak punya varian, karena
+// term tunggal sudah dicocokkan oleh query biasa.
+func analyzePhrase(query, phrase string) [][]phraseTerm {
+	languages := make([]string, 0, 2)
+	for language := range queryLanguageWeights(query) {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	var variants [][]phraseTerm
+	seen := make(map[string]bool)
+	for _, language := range languages {
+		tokens := analyzerFor(language).Analyze(phrase)
+		if len(tokens) < 2 {
+			continue
+		}
+		terms := make([]phraseTerm, len(tokens))
+		keys := make([]string, len(tokens))
+		for i, token := range tokens {
+			terms[i] = phraseTerm{term: token.Term, offset: token.Position - tokens[0].Position}
+			keys[i] = token.Term
+		}
+		if key := strings.Join(keys, " "); !seen[key] {
+			seen[key] = true
+			variants = append(variants, terms)
+		}
+	}
+	return variants
+}
+
+// Ada posisi awal p sehingga setiap term phrase muncul di p + offset-nya.
+// positions mengembalikan posisi term di dokumen, urut naik.
+func matchPhrase(terms []phraseTerm, positions func(term string) []int) bool {
+	lists := make([][]int, len(terms))
+	for i, t := range terms {
+		if lists[i] = positions(t.term); len(lists[i]) == 0 {
+			return false
+		}
+	}
+
+	for _, position := range lists[0] {
+		start := position - terms[0].offset
+		found := true
+		for i := 1; i < len(terms) && found; i++ {
+			want := start + terms[i].offset
+			j := sort.SearchInts(lists[i], want)
+			found = j < len(lists[i]) && lists[i][j] == want
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// Dokumen memuat setiap phrase (salah satu variannya)
+func containsPhrases(phrases [][][]phraseTerm, positions func(term string) []int) bool {
+	for _, variants := range phrases {
+		matched := false
+		for _, terms := range variants {
+			if matchPhrase(terms, positions) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Varian semua phrase di query; nil kalau query tidak punya phrase multi-term
+func queryPhraseTerms(query string) [][][]phraseTerm {
+	var phrases [][][]phraseTerm
+	for _, phrase := range queryPhrases(query) {
+		if variants := analyzePhrase(query, phrase); len(variants) > 0 {
+			phrases = append(phrases, variants)
+		}
+	}
+	return phrases
+}
+
+// Posisi per term dari token hasil analisis, untuk dokumen yang belum diindex
+func tokenPositions(tokens []Token) func(term string) []int {
+	positions := make(map[string][]int)
+	for _, tok := range tokens {
+		positions[tok.Term] = append(positions[tok.Term], tok.Position)
+	}
+	return func(term string) []int { return positions[term] }
+}
+
+func (idx *InvertedIndex) postingPositions(docID int) func(term string) []int {
+	return func(term string) []int {
+		postingList, exists := idx.Index[term]
+		if !exists {
+			return nil
+		}
+		if posting, exists := postingList.Postings[docID]; exists {
+			return posting.Positions
+		}
+		return nil
+	}
+}
+
+// Naikkan skor hasil yang memuat semua phrase query di atas skor tertinggi
+// hasil lain, urutan di dalam kedua kelompok tidak berubah. Mengembalikan
+// jumlah hasil yang cocok.
+func (idx *InvertedIndex) boostPhraseMatches(results []SearchResult, phrases [][][]phraseTerm) int {
+	matched := make([]bool, len(results))
+	count := 0
+	topOther := 0.0
+	for i, result := range results {
+		if containsPhrases(phrases, idx.postingPositions(result.DocID)) {
+			matched[i] = true
+			count++
+		} else if result.Score > topOther {
+			topOther = result.Score
+		}
+	}
+	for i := range results {
+		if matched[i] {
+			results[i].Score += topOther
+		}
+	}
+	return count
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Filter dianggap selektif (dievaluasi sebelum term) kalau lolos untuk
+// paling banyak fraksi dokumen ini
+const FILTER_SELECTIVE_RATIO = 0.25
+
+// Langkah evaluasi query, ditampilkan di explain mode (?explain=1)
+type PlanStep struct {
+	Op           string // filter, and, or
+	Term         string
+	DocFrequency int
+	Result       int  // jumlah kandidat setelah langkah ini
+	Skipped      bool // dilewati karena konjungsi sudah kosong
+}
+
+type QueryPlan struct {
+	Mode        string // "and" atau "or"
+	FilterFirst bool
+	Groups      map[string][]PlanStep // per bahasa analyzer
+	Candidates  int
+
+	// Status tiap tier kalau query difederasi (include_archive=1)
+	Tiers []TierStatus
+	// Terjemahan query yang ikut dicari, nil kalau tidak ada
+	Translation *QueryTranslation
+	// Query yang ditulis ulang atau disarankan, lihat segmentation.go
+	Rewrite *QueryRewrite
+
+	// Phrase dalam tanda kutip dan jumlah hasil yang memuat semuanya persis
+	Phrases       []string
+	PhraseMatches int
+}
+
+// Susun dan jalankan plan: term dievaluasi dari document frequency terkecil,
+// filter selektif diterapkan lebih dulu, dan konjungsi berhenti begitu kosong.
+func planQuery(idx *InvertedIndex, query string, queryVector map[string]float64, opts SearchOptions, filterBitmap *Bitmap) (*Bitmap, *QueryPlan) {
+	plan := &QueryPlan{Mode: "or", Groups: make(map[string][]PlanStep)}
+	if opts.MatchAll {
+		plan.Mode = "and"
+	}
+
+	if filterBitmap != nil && idx.DocCount > 0 {
+		plan.FilterFirst = float64(filterBitmap.Cardinality()) <= FILTER_SELECTIVE_RATIO*float64(idx.DocCount)
+	}
+
+	candidates := NewBitmap()
+
+	if !opts.MatchAll {
+		// OR: gabungan semua term (termasuk sinonim), filter selektif memotong hasilnya
+		terms := make([]string, 0, len(queryVector))
+		for term := range queryVector {
+			terms = append(terms, term)
+		}
+		terms = idx.orderByDocFrequency(terms)
+
+		var steps []PlanStep
+		if plan.FilterFirst {
+			steps = append(steps, PlanStep{Op: "filter", Result: filterBitmap.Cardinality()})
+		}
+		for _, term := range terms {
+			postingList, exists := idx.Index[term]
+			if !exists {
+				steps = append(steps, PlanStep{Op: "or", Term: term, Result: candidates.Cardinality()})
+				continue
+			}
+			docs := postingList.DocBitmap()
+			if plan.FilterFirst {
+				docs = docs.And(filterBitmap)
+			}
+			candidates = candidates.Or(docs)
+			steps = append(steps, PlanStep{Op: "or", Term: term, DocFrequency: postingList.DocFrequency, Result: candidates.Cardinality()})
+		}
+		plan.Groups["all"] = steps
+		plan.Candidates = candidates.Cardinality()
+		return candidates, plan
+	}
+
+	// AND per pipeline bahasa, hasilnya digabung (term Indonesia dan Inggris tidak dicampur)
+	weights := queryLanguageWeights(query)
+	languages := make([]string, 0, len(weights))
+	for language := range weights {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	for _, language := range languages {
+		terms := idx.orderByDocFrequency(uniqueTerms(analyzerFor(language).ProcessText(query)))
+		if len(terms) == 0 {
+			continue
+		}
+
+		var steps []PlanStep
+		var result *Bitmap
+		if plan.FilterFirst {
+			result = filterBitmap
+			steps = append(steps, PlanStep{Op: "filter", Result: result.Cardinality()})
+		}
+
+		for i, term := range terms {
+			if result != nil && result.Cardinality() == 0 {
+				for _, skipped := range terms[i:] {
+					steps = append(steps, PlanStep{Op: "and", Term: skipped, DocFrequency: idx.docFrequency(skipped), Skipped: true})
+				}
+				break
+			}
+
+			postingList, exists := idx.Index[term]
+			if !exists {
+				result = NewBitmap()
+			} else if result == nil {
+				result = postingList.DocBitmap()
+			} else {
+				result = result.And(postingList.DocBitmap())
+			}
+			steps = append(steps, PlanStep{Op: "and", Term: term, DocFrequency: idx.docFrequency(term), Result: result.Cardinality()})
+		}
+
+		plan.Groups[language] = steps
+		candidates = candidates.Or(result)
+	}
+
+	plan.Candidates = candidates.Cardinality()
+	return candidates, plan
+}
+
+func (idx *InvertedIndex) docFrequency(term string) int {
+	if postingList, exists := idx.Index[term]; exists {
+		return postingList.DocFrequency
+	}
+	return 0
+}
+
+// Urutkan term dari document frequency terkecil (term yang tidak ada di index duluan)
+func (idx *InvertedIndex) orderByDocFrequency(terms []string) []string {
+	sort.SliceStable(terms, func(i, j int) bool {
+		di, dj := idx.docFrequency(terms[i]), idx.docFrequency(terms[j])
+		if di != dj {
+			return di < dj
+		}
+		return terms[i] < terms[j]
+	})
+	return terms
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
+
+// Format plan untuk explain mode
+func (p *QueryPlan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "mode=%s filter_first=%v candidates=%d\n", p.Mode, p.FilterFirst, p.Candidates)
+	b.WriteString(p.tiersString())
+	if p.Translation != nil {
+		fmt.Fprintf(&b, "translation: %s -> %s %q\n", p.Translation.Source, p.Translation.Target, p.Translation.Text)
+	}
+	if p.Rewrite != nil {
+		fmt.Fprintf(&b, "%s: %q -> %q applied=%v\n", p.Rewrite.Kind, p.Rewrite.Original, p.Rewrite.Text, p.Rewrite.Applied)
+	}
+	if len(p.Phrases) > 0 {
+		fmt.Fprintf(&b, "phrases: %q -> %d exact\n", p.Phrases, p.PhraseMatches)
+	}
+
+	groups := make([]string, 0, len(p.Groups))
+	for group := range p.Groups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		fmt.Fprintf(&b, "[%s]\n", group)
+		for _, step := range p.Groups[group] {
+			switch {
+			case step.Op == "filter":
+				fmt.Fprintf(&b, "  filter            -> %d\n", step.Result)
+			case step.Skipped:
+				fmt.Fprintf(&b, "  %s %-12s df=%d  (skipped)\n", step.Op, step.Term, step.DocFrequency)
+			default:
+				fmt.Fprintf(&b, "  %s %-12s df=%d  -> %d\n", step.Op, step.Term, step.DocFrequency, step.Result)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,270 @@
+package main
+
+import "strings"
+
+// Porter2 (Snowball English) stemmer untuk artikel berbahasa Inggris.
+// Input diasumsikan sudah lowercase dan tanpa tanda baca.
+
+var porter2Exceptions = map[string]string{
+	"skis": "ski", "skies": "sky", "dying": "die", "lying": "lie", "tying": "tie",
+	"idly": "idl", "gently": "gentl", "ugly": "ugli", "early": "earli", "only": "onli",
+	"singly": "singl", "sky": "sky", "news": "news", "howe": "howe",
+	"atlas": "atlas", "cosmos": "cosmos", "bias": "bias", "andes": "andes",
+}
+
+var porter2Invariant = map[string]bool{
+	"inning": true, "outing": true, "canning": true, "herring": true,
+	"earring": true, "proceed": true, "exceed": true, "succeed": true,
+}
+
+var porter2Step2 = []struct{ suffix, replacement string }{
+	{"ization", "ize"}, {"ational", "ate"}, {"fulness", "ful"}, {"ousness", "ous"},
+	{"iveness", "ive"}, {"tional", "tion"}, {"biliti", "ble"}, {"lessli", "less"},
+	{"entli", "ent"}, {"ation", "ate"}, {"alism", "al"}, {"aliti", "al"},
+	{"ousli", "ous"}, {"iviti", "ive"}, {"fulli", "ful"}, {"enci", "ence"},
+	{"anci", "ance"}, {"abli", "able"}, {"izer", "ize"}, {"ator", "ate"},
+	{"alli", "al"}, {"bli", "ble"}, {"ogi", "og"}, {"li", ""},
+}
+
+var porter2Step3 = []struct{ suffix, replacement string }{
+	{"ational", "ate"}, {"tional", "tion"}, {"alize", "al"}, {"icate", "ic"},
+	{"iciti", "ic"}, {"ative", ""}, {"ical", "ic"}, {"ness", ""}, {"ful", ""},
+}
+
+var porter2Step4 = []string{
+	"ement", "ance", "ence", "able", "ible", "ment", "ant", "ent", "ism",
+	"ate", "iti", "ous", "ive", "ize", "ion", "al", "er", "ic",
+}
+
+func porter2IsVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+func porter2IsDouble(w string) bool {
+	if len(w) < 2 {
+		return false
+	}
+	switch w[len(w)-2:] {
+	case "bb", "dd", "ff", "gg", "mm", "nn", "pp", "rr", "tt":
+		return true
+	}
+	return false
+}
+
+func porter2IsLiEnding(c byte) bool {
+	return strings.IndexByte("cdeghkmnrt", c) >= 0
+}
+
+// Posisi awal region setelah non-vowel pertama yang mengikuti vowel
+func porter2Region(w string, start int) int {
+	for i := start + 1; i < len(w); i++ {
+		if !porter2IsVowel(w[i]) && porter2IsVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+// Short syllable di akhir w
+func porter2EndsShortSyllable(w string) bool {
+	n := len(w)
+	if n == 2 {
+		return porter2IsVowel(w[0]) && !porter2IsVowel(w[1])
+	}
+	if n >= 3 {
+		c := w[n-1]
+		return !porter2IsVowel(w[n-3]) && porter2IsVowel(w[n-2]) &&
+			!porter2IsVowel(c) && c != 'w' && c != 'x' && c != 'Y'
+	}
+	return false
+}
+
+func porter2IsShort(w string, r1 int) bool {
+	return r1 >= len(w) && porter2EndsShortSyllable(w)
+}
+
+func porter2HasVowel(w string) bool {
+	for i := 0; i < len(w); i++ {
+		if porter2IsVowel(w[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func porter2Stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	if stem, ok := porter2Exceptions[word]; ok {
+		return stem
+	}
+
+	// Tandai y konsonan sebagai Y
+	b := []byte(word)
+	for i := range b {
+		if b[i] == 'y' && (i == 0 || porter2IsVowel(b[i-1])) {
+			b[i] = 'Y'
+		}
+	}
+	w := string(b)
+
+	r1 := porter2Region(w, 0)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(w, prefix) {
+			r1 = len(prefix)
+			break
+		}
+	}
+	r2 := porter2Region(w, r1)
+
+	// Step 0: possessive
+	for _, suffix := range []string{"'s'", "'s", "'"} {
+		if strings.HasSuffix(w, suffix) {
+			w = w[:len(w)-len(suffix)]
+			break
+		}
+	}
+
+	// Step 1a
+	switch {
+	case strings.HasSuffix(w, "sses"):
+		w = w[:len(w)-2]
+	case strings.HasSuffix(w, "ied"), strings.HasSuffix(w, "ies"):
+		if len(w) > 4 {
+			w = w[:len(w)-2]
+		} else {
+			w = w[:len(w)-1]
+		}
+	case strings.HasSuffix(w, "us"), strings.HasSuffix(w, "ss"):
+	case strings.HasSuffix(w, "s"):
+		if len(w) >= 3 && porter2HasVowel(w[:len(w)-2]) {
+			w = w[:len(w)-1]
+		}
+	}
+
+	if porter2Invariant[w] {
+		return w
+	}
+
+	// Step 1b
+	w = porter2Step1b(w, r1)
+
+	// Step 1c
+	if n := len(w); n > 2 && (w[n-1] == 'y' || w[n-1] == 'Y') && !porter2IsVowel(w[n-2]) {
+		w = w[:n-1] + "i"
+	}
+
+	// Step 2
+	for _, rule := range porter2Step2 {
+		if !strings.HasSuffix(w, rule.suffix) {
+			continue
+		}
+		stem := w[:len(w)-len(rule.suffix)]
+		if len(stem) >= r1 {
+			switch rule.suffix {
+			case "ogi":
+				if strings.HasSuffix(stem, "l") {
+					w = stem + rule.replacement
+				}
+			case "li":
+				if len(stem) > 0 && porter2IsLiEnding(stem[len(stem)-1]) {
+					w = stem
+				}
+			default:
+				w = stem + rule.replacement
+			}
+		}
+		break
+	}
+
+	// Step 3
+	for _, rule := range porter2Step3 {
+		if !strings.HasSuffix(w, rule.suffix) {
+			continue
+		}
+		stem := w[:len(w)-len(rule.suffix)]
+		if len(stem) >= r1 {
+			if rule.suffix == "ative" {
+				if len(stem) >= r2 {
+					w = stem
+				}
+			} else {
+				w = stem + rule.replacement
+			}
+		}
+		break
+	}
+
+	// Step 4
+	for _, suffix := range porter2Step4 {
+		if !strings.HasSuffix(w, suffix) {
+			continue
+		}
+		stem := w[:len(w)-len(suffix)]
+		if len(stem) >= r2 {
+			if suffix == "ion" {
+				if strings.HasSuffix(stem, "s") || strings.HasSuffix(stem, "t") {
+					w = stem
+				}
+			} else {
+				w = stem
+			}
+		}
+		break
+	}
+
+	// Step 5
+	if n := len(w); n > 0 {
+		switch w[n-1] {
+		case 'e':
+			stem := w[:n-1]
+			if n-1 >= r2 || (n-1 >= r1 && !porter2EndsShortSyllable(stem)) {
+				w = stem
+			}
+		case 'l':
+			if n-1 >= r2 && n >= 2 && w[n-2] == 'l' {
+				w = w[:n-1]
+			}
+		}
+	}
+
+	return strings.ReplaceAll(w, "Y", "y")
+}
+
+func porter2Step1b(w string, r1 int) string {
+	for _, suffix := range []string{"eedly", "eed"} {
+		if strings.HasSuffix(w, suffix) {
+			if len(w)-len(suffix) >= r1 {
+				return w[:len(w)-len(suffix)] + "ee"
+			}
+			return w
+		}
+	}
+
+	for _, suffix := range []string{"ingly", "edly", "ing", "ed"} {
+		if !strings.HasSuffix(w, suffix) {
+			continue
+		}
+		stem := w[:len(w)-len(suffix)]
+		if !porter2HasVowel(stem) {
+			return w
+		}
+
+		switch {
+		case strings.HasSuffix(stem, "at"), strings.HasSuffix(stem, "bl"), strings.HasSuffix(stem, "iz"):
+			return stem + "e"
+		case porter2IsDouble(stem):
+			return stem[:len(stem)-1]
+		case porter2IsShort(stem, r1):
+			return stem + "e"
+		}
+		return stem
+	}
+
+	return w
+}
@@ -0,0 +1,35 @@
+package main
+
+// Term yang muncul di banyak dokumen menyimpan keberadaannya sebagai roaring
+// bitmap (PostingList.Docs). Frekuensi dan posisi tetap di Postings, bitmap
+// hanya dipakai untuk operasi boolean (irisan/gabungan) yang jauh lebih murah
+// daripada iterasi map.
+
+// Isi bitmap untuk term dengan DocFrequency >= ratio * jumlah dokumen
+func (idx *InvertedIndex) buildDenseBitmaps(ratio float64) {
+	if ratio <= 0 || idx.DocCount == 0 {
+		return
+	}
+
+	minDocs := int(ratio * float64(idx.DocCount))
+	if minDocs < 1 {
+		minDocs = 1
+	}
+	for _, postingList := range idx.Index {
+		if postingList.DocFrequency >= minDocs {
+			postingList.Docs = postingList.DocBitmap()
+		}
+	}
+}
+
+// Bitmap dokumen yang mengandung term; term jarang dibuatkan bitmap sementara
+func (pl *PostingList) DocBitmap() *Bitmap {
+	if pl.Docs != nil {
+		return pl.Docs
+	}
+	b := NewBitmap()
+	for docID := range pl.Postings {
+		b.Add(docID)
+	}
+	return b
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Provenance: bagaimana setiap dokumen masuk corpus. Dicatat server setiap
+// kali dokumen ditulis lewat ingest, unggah dokumen atau recrawl, jadi selalu
+// menggambarkan versi terakhir dokumen:
+//
+//   - method: crawler, ingest, upload atau recrawl (unknown untuk dokumen lama)
+//   - crawler_version, source dan source_config_hash dari crawler; hash
+//     berubah setiap konfigurasi sumber di crawlers.json/sources.json diubah
+//   - api_key: nama API key (X-API-Key) yang mengirim dokumen, bukan key-nya
+//   - actor: admin yang mengirim (X-Admin-Actor), import_file: nama file unggahan
+//
+// Dokumen bisa didaftar dan dihapus per provenance, misalnya semua dokumen
+// dari crawler versi tertentu atau dari satu API key:
+//
+//	GET  /api/documents?crawler_version=1&source=rumah123
+//	POST /api/admin/bulk/delete-provenance?api_key=crm&dry_run=1
+const (
+	ProvenanceCrawler = "crawler"
+	ProvenanceIngest  = "ingest"
+	ProvenanceUpload  = "upload"
+	ProvenanceRecrawl = "recrawl"
+	ProvenanceUnknown = "unknown"
+
+	JobBulkDeleteProvenance = "bulk_delete_provenance"
+
+	DOCUMENT_LIST_LIMIT = 100
+)
+
+type Provenance struct {
+	Method           string    `json:"method"`
+	CrawlerVersion   string    `json:"crawler_version,omitempty"`
+	Source           string    `json:"source,omitempty"`
+	SourceConfigHash string    `json:"source_config_hash,omitempty"`
+	APIKey           string    `json:"api_key,omitempty"`
+	Actor            string    `json:"actor,omitempty"`
+	ImportFile       string    `json:"import_file,omitempty"`
+	IngestedAt       time.Time `json:"ingested_at"`
+}
+
+// Provenance artikel; dokumen dari sebelum provenance dicatat bermethod unknown
+func (a Article) provenance() Provenance {
+	if a.Provenance == nil {
+		return Provenance{Method: ProvenanceUnknown}
+	}
+	return *a.Provenance
+}
+
+// Hash konfigurasi sumber crawler, sama dengan yang dihitung crawler/
+func sourceConfigHash(source CrawlSource) string {
+	data, err := json.Marshal(source)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// Provenance dokumen yang dikirim lewat request ini. Hanya field crawler
+// (versi, sumber, hash konfigurasi) yang diambil dari klien; method, API key,
+// actor dan waktu selalu diisi server.
+func requestProvenance(c *gin.Context, method string, sent *Provenance) *Provenance {
+	provenance := &Provenance{
+		Method:     method,
+		Actor:      auditActor(c).Name,
+		IngestedAt: time.Now().UTC(),
+	}
+	if value, exists := c.Get(apiKeyContextKey); exists {
+		provenance.APIKey = value.(APIKey).Name
+	}
+	if sent != nil && sent.CrawlerVersion != "" {
+		provenance.Method = ProvenanceCrawler
+		provenance.CrawlerVersion = sent.CrawlerVersion
+		provenance.Source = sent.Source
+		provenance.SourceConfigHash = sent.SourceConfigHash
+	}
+	return provenance
+}
+
+// Filter provenance dari parameter URL; field kosong tidak difilter
+type ProvenanceFilter struct {
+	Method           string `json:"method,omitempty"`
+	CrawlerVersion   string `json:"crawler_version,omitempty"`
+	Source           string `json:"source,omitempty"`
+	SourceConfigHash string `json:"source_config_hash,omitempty"`
+	APIKey           string `json:"api_key,omitempty"`
+	ImportFile       string `json:"import_file,omitempty"`
+	// Hanya dokumen yang ditulis sebelum/sesudah waktu ini
+	Before time.Time `json:"before,omitempty"`
+	After  time.Time `json:"after,omitempty"`
+}
+
+func provenanceFilterFromQuery(c *gin.Context) (ProvenanceFilter, error) {
+	filter := ProvenanceFilter{
+		Method:           c.Query("method"),
+		CrawlerVersion:   c.Query("crawler_version"),
+		Source:           c.Query("source"),
+		SourceConfigHash: c.Query("source_config_hash"),
+		APIKey:           c.Query("api_key"),
+		ImportFile:       c.Query("import_file"),
+	}
+	for name, target := range map[string]*time.Time{"before": &filter.Before, "after": &filter.After} {
+		if value := c.Query(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, err
+			}
+			*target = t
+		}
+	}
+	return filter, nil
+}
+
+func (f ProvenanceFilter) empty() bool {
+	return f == ProvenanceFilter{}
+}
+
+func (f ProvenanceFilter) Matches(article Article) bool {
+	p := article.provenance()
+	switch {
+	case f.Method != "" && p.Method != f.Method,
+		f.CrawlerVersion != "" && p.CrawlerVersion != f.CrawlerVersion,
+		f.Source != "" && p.Source != f.Source,
+		f.SourceConfigHash != "" && p.SourceConfigHash != f.SourceConfigHash,
+		f.APIKey != "" && p.APIKey != f.APIKey,
+		f.ImportFile != "" && p.ImportFile != f.ImportFile,
+		!f.Before.IsZero() && !p.IngestedAt.Before(f.Before),
+		!f.After.IsZero() && !p.IngestedAt.After(f.After):
+		return false
+	}
+	return true
+}
+
+// Dokumen di daftar dokumen admin
+type DocumentSummary struct {
+	DocID      int        `json:"doc_id"`
+	URL        string     `json:"url"`
+	Title      string     `json:"title"`
+	Deleted    bool       `json:"deleted,omitempty"`
+	Provenance Provenance `json:"provenance"`
+}
+
+func documentsByProvenance(articles []Article, filter ProvenanceFilter) []DocumentSummary {
+	documents := []DocumentSummary{}
+	for docID, article := range articles {
+		if filter.Matches(article) {
+			documents = append(documents, DocumentSummary{
+				DocID:      docID,
+				URL:        article.URL,
+				Title:      article.Title,
+				Deleted:    article.Deleted(),
+				Provenance: article.provenance(),
+			})
+		}
+	}
+	return documents
+}
+
+// GET /api/documents?method=&crawler_version=&source=&source_config_hash=&api_key=&import_file=&before=&after=&limit=
+func listDocumentsHandler(c *gin.Context) {
+	filter, err := provenanceFilterFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "before/after must be RFC 3339: " + err.Error()})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(DOCUMENT_LIST_LIMIT)))
+	if err != nil || limit < 1 {
+		limit = DOCUMENT_LIST_LIMIT
+	}
+	articles, _, err := loadIndex()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	documents := documentsByProvenance(articles, filter)
+	total := len(documents)
+	if len(documents) > limit {
+		documents = documents[:limit]
+	}
+	c.JSON(http.StatusOK, gin.H{"total": total, "documents": documents})
+}
+
+// GET /api/documents/:id: dokumen lengkap beserta provenance-nya
+func getDocumentHandler(c *gin.Context) {
+	docID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid document id"})
+		return
+	}
+	articles, _, err := loadIndex()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if docID < 0 || docID >= len(articles) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "document not found"})
+		return
+	}
+	article, _ := localArticle(articles, SearchResult{DocID: docID})
+	c.JSON(http.StatusOK, gin.H{
+		"doc_id":     docID,
+		"article":    article,
+		"provenance": article.provenance(),
+	})
+}
+
+// POST /api/admin/bulk/delete-provenance: hapus (soft delete) semua dokumen
+// yang cocok dengan filter provenance; minimal satu filter wajib diisi
+func bulkDeleteProvenanceHandler(c *gin.Context) {
+	filter, err := provenanceFilterFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "before/after must be RFC 3339: " + err.Error()})
+		return
+	}
+	if filter.empty() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "at least one provenance filter is required"})
+		return
+	}
+	matches := func() ([]BulkMatch, error) {
+		articles, _, err := loadIndex()
+		if err != nil {
+			return nil, err
+		}
+		matches := []BulkMatch{}
+		for _, document := range documentsByProvenance(articles, filter) {
+			if !document.Deleted {
+				matches = append(matches, BulkMatch{DocID: document.DocID, URL: document.URL, Title: document.Title})
+			}
+		}
+		return matches, nil
+	}
+
+	if c.Query("dry_run") == "1" {
+		found, err := matches()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		sample := found
+		if len(sample) > BULK_DRY_RUN_SAMPLE {
+			sample = sample[:BULK_DRY_RUN_SAMPLE]
+		}
+		c.JSON(http.StatusOK, gin.H{"matched": len(found), "sample": sample})
+		return
+	}
+
+	actor := auditActor(c)
+	job := adminJobs.Start(JobBulkDeleteProvenance, bulkParams(c), func(ctx context.Context, job jobRun) error {
+		found, err := matches()
+		if err != nil {
+			return err
+		}
+		job.SetTotal(len(found))
+		records := make([]WalRecord, len(found))
+		for i, match := range found {
+			records[i] = WalRecord{Op: WalDelete, URL: match.URL}
+		}
+		return appendBatches(ctx, job, actor.forJob(job), records)
+	})
+	actor.Record(AuditJobStart, job.ID, nil, job)
+	c.JSON(http.StatusAccepted, job)
+}
+
+func init() {
+	registerJobKind(JobBulkDeleteProvenance, JOB_LANE_DOCUMENTS, RetryPolicy{}, nil)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Proximity boost: dokumen yang memuat term query berdekatan ("harga rumah
+// subsidi" dalam satu kalimat) dinaikkan di atas dokumen yang memuat term
+// yang sama berjauhan. Untuk setiap term query yang ada di dokumen dicari
+// jarak terdekatnya ke term query lain lewat posisi di posting list. Jarak 1
+// (bersebelahan) bernilai 1, lalu turun linear sampai 0 di luar window.
+// Nilai per term dirata-rata dengan bobot query-nya, dan skor dikali
+// 1 + boost * rata-rata itu.
+//
+//	{"proximity": {"window": 8, "boost": 0.25}}
+//
+// boost 0 mematikan proximity. Query satu term tidak terpengaruh.
+const (
+	PROXIMITY_WINDOW = 8
+	PROXIMITY_BOOST  = 0.25
+	// Term query dengan bobot terbesar yang dihitung, supaya query panjang
+	// dan sinonim tidak membuat scoring per dokumen mahal
+	PROXIMITY_MAX_TERMS = 8
+)
+
+type ProximityConfig struct {
+	Window int     `json:"window"`
+	Boost  float64 `json:"boost"`
+}
+
+func defaultProximityConfig() ProximityConfig {
+	return ProximityConfig{Window: PROXIMITY_WINDOW, Boost: PROXIMITY_BOOST}
+}
+
+func (p ProximityConfig) validate() error {
+	if p.Boost < 0 {
+		return fmt.Errorf("boost must not be negative")
+	}
+	if p.Boost > 0 && p.Window < 1 {
+		return fmt.Errorf("window must be at least 1")
+	}
+	return nil
+}
+
+// Term query yang dipakai untuk proximity, bobot terbesar dulu
+func proximityTerms(queryVector map[string]float64) []string {
+	terms := make([]string, 0, len(queryVector))
+	for term, weight := range queryVector {
+		if weight > 0 {
+			terms = append(terms, term)
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if queryVector[terms[i]] != queryVector[terms[j]] {
+			return queryVector[terms[i]] > queryVector[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > PROXIMITY_MAX_TERMS {
+		terms = terms[:PROXIMITY_MAX_TERMS]
+	}
+	return terms
+}
+
+// Pengali skor dokumen, 1 kalau term query tidak berdekatan. terms dari
+// proximityTerms; positions mengembalikan posisi term di dokumen, urut naik.
+func (p ProximityConfig) multiplier(terms []string, queryVector map[string]float64, positions func(term string) []int) float64 {
+	if p.Boost <= 0 || len(terms) < 2 {
+		return 1
+	}
+	return 1 + p.Boost*p.score(terms, queryVector, positions)
+}
+
+// Rata-rata kedekatan term query di dokumen (0-1), berbobot bobot query
+func (p ProximityConfig) score(terms []string, queryVector map[string]float64, positions func(term string) []int) float64 {
+	lists := make([][]int, 0, len(terms))
+	weights := make([]float64, 0, len(terms))
+	for _, term := range terms {
+		if list := positions(term); len(list) > 0 {
+			lists = append(lists, list)
+			weights = append(weights, queryVector[term])
+		}
+	}
+	if len(lists) < 2 {
+		return 0
+	}
+
+	var total, weightSum float64
+	for i := range lists {
+		best := 0.0
+		for j := range lists {
+			if i != j {
+				if closeness := p.closeness(minDistance(lists[i], lists[j])); closeness > best {
+					best = closeness
+				}
+			}
+		}
+		total += weights[i] * best
+		weightSum += weights[i]
+	}
+	return total / weightSum
+}
+
+// 1 untuk term bersebelahan (atau bertumpuk di posisi yang sama), 0 di luar window
+func (p ProximityConfig) closeness(distance int) float64 {
+	if distance < 1 {
+		distance = 1
+	}
+	if distance > p.Window {
+		return 0
+	}
+	return 1 - float64(distance-1)/float64(p.Window)
+}
+
+// Jarak terkecil antara dua daftar posisi yang urut naik
+func minDistance(a, b []int) int {
+	best := -1
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		distance := a[i] - b[j]
+		if distance < 0 {
+			distance = -distance
+		}
+		if best < 0 || distance < best {
+			best = distance
+		}
+		if a[i] < b[j] {
+			i++
+		} else {
+			j++
+		}
+	}
+	return best
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"log"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Jawaban unggulan untuk query berupa pertanyaan ("berapa DP rumah subsidi
+// 2025"). Kalimat di dokumen teratas di-score dari cakupan term query
+// (dibobot IDF) dan kecocokan tipe jawaban: pertanyaan "berapa" butuh
+// kalimat dengan angka, "kapan" butuh tanggal atau tahun. Kalimat terbaik
+// tampil di atas hasil. Murni leksikal, tanpa layanan eksternal, jadi selalu
+// jalan; matikan dengan "featured_answers": false.
+const (
+	QA_TOP_DOCS           = 5
+	QA_MIN_COVERAGE       = 0.6 // bagian bobot IDF term query yang harus ada di kalimat
+	QA_MIN_SENTENCE_WORDS = 5
+	QA_MAX_SENTENCE_WORDS = 60
+	QA_SHORT_SENTENCE     = 12   // kalimat sependek ini disambung kalimat berikutnya
+	QA_TYPE_BONUS         = 0.25 // kalimat memuat jenis jawaban yang ditanyakan
+	QA_RANK_BONUS         = 0.1  // dibagi peringkat dokumen, dokumen teratas sedikit diutamakan
+)
+
+// Jenis jawaban yang ditanyakan
+const (
+	QuestionGeneral = "general"
+	QuestionNumber  = "number"
+	QuestionDate    = "date"
+)
+
+// Kata tanya di awal query dan jenis jawabannya
+var questionWords = map[string]string{
+	"apa": QuestionGeneral, "apakah": QuestionGeneral, "bagaimana": QuestionGeneral,
+	"mengapa": QuestionGeneral, "kenapa": QuestionGeneral, "siapa": QuestionGeneral,
+	"dimana": QuestionGeneral, "bisakah": QuestionGeneral, "bolehkah": QuestionGeneral,
+	"berapa": QuestionNumber, "berapakah": QuestionNumber,
+	"kapan": QuestionDate, "kapankah": QuestionDate,
+	"what": QuestionGeneral, "how": QuestionGeneral, "why": QuestionGeneral,
+	"who": QuestionGeneral, "where": QuestionGeneral, "which": QuestionGeneral,
+	"is": QuestionGeneral, "are": QuestionGeneral, "can": QuestionGeneral,
+	"does": QuestionGeneral, "do": QuestionGeneral, "should": QuestionGeneral,
+	"when": QuestionDate,
+}
+
+// Kata tanya yang tetap menandai pertanyaan walaupun tidak di awal
+// ("DP rumah subsidi berapa")
+var trailingQuestionWords = map[string]bool{
+	"berapa": true, "kapan": true, "bagaimana": true, "mengapa": true, "kenapa": true, "siapa": true,
+}
+
+// "how much/many/long" menanyakan angka
+var numberQuestionFollowers = map[string]bool{"much": true, "many": true, "long": true, "big": true, "large": true}
+
+var (
+	digitPattern = regexp.MustCompile(`\d`)
+	datePattern  = regexp.MustCompile(`(?i)\b(19|20)\d{2}\b|\b(januari|februari|maret|april|mei|juni|juli|agustus|september|oktober|november|desember|january|february|march|may|june|july|august|october|december|tanggal)\b`)
+)
+
+// Singkatan yang diakhiri titik tapi bukan akhir kalimat
+var sentenceAbbreviations = map[string]bool{
+	"rp": true, "jl": true, "jln": true, "no": true, "dr": true, "ir": true, "h": true, "hj": true,
+	"pt": true, "tbk": true, "dll": true, "dsb": true, "dkk": true, "st": true, "mr": true, "mrs": true,
+	"kec": true, "kab": true, "prof": true, "drs": true, "kel": true, "gg": true, "tgl": true, "bpk": true,
+}
+
+type FeaturedAnswer struct {
+	Text  string `json:"text"`
+	DocID int    `json:"doc_id"`
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	// Text directive untuk lompat ke kalimat jawaban di halaman sumber
+	TextFragment string  `json:"text_fragment,omitempty"`
+	Score        float64 `json:"score"`
+}
+
+// Jenis pertanyaan dan kata-kata isinya (tanpa kata tanya); ok false kalau
+// query bukan pertanyaan
+func parseQuestion(query string) (kind string, content []string, ok bool) {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return "", nil, false
+	}
+	kind = QuestionGeneral
+	question := strings.HasSuffix(words[len(words)-1], "?")
+	for i, word := range words {
+		word = strings.Trim(word, "?.,!")
+		wordKind, interrogative := questionWords[word]
+		if interrogative && (i == 0 || trailingQuestionWords[word]) {
+			question = true
+			if wordKind != QuestionGeneral {
+				kind = wordKind
+			}
+			if word == "how" && i+1 < len(words) && numberQuestionFollowers[words[i+1]] {
+				kind = QuestionNumber
+			}
+			continue
+		}
+		if word != "" {
+			content = append(content, word)
+		}
+	}
+	return kind, content, question && len(content) > 0
+}
+
+// Pecah teks jadi kalimat per baris. Titik di dalam angka (1.500), URL dan
+// singkatan umum (Rp., Jl., PT.) bukan akhir kalimat.
+func splitSentences(text string) []string {
+	var sentences []string
+	for _, line := range strings.Split(text, "\n") {
+		start := 0
+		for i := 0; i < len(line); i++ {
+			if !endsSentence(line, i) {
+				continue
+			}
+			if sentence := strings.TrimSpace(line[start : i+1]); sentence != "" {
+				sentences = append(sentences, sentence)
+			}
+			start = i + 1
+		}
+		if sentence := strings.TrimSpace(line[start:]); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+	}
+	return sentences
+}
+
+// Apakah tanda baca di text[i] mengakhiri kalimat; dipakai juga untuk batas
+// snippet (snippet.go)
+func endsSentence(text string, i int) bool {
+	c := text[i]
+	if c != '.' && c != '!' && c != '?' {
+		return false
+	}
+	// Akhir kalimat diikuti spasi, atau huruf besar langsung setelah
+	// huruf kecil/angka ("tahun 2024.Ketua") dari teks crawler
+	if i+1 < len(text) && !isSpaceByte(text[i+1]) && !(i > 0 && isSentenceGlue(text[i-1], text[i+1])) {
+		return false
+	}
+	if c == '.' {
+		word := i
+		for word > 0 && !isSpaceByte(text[word-1]) {
+			word--
+		}
+		if sentenceAbbreviations[strings.ToLower(text[word:i])] {
+			return false
+		}
+	}
+	return true
+}
+
+func isSentenceGlue(before, after byte) bool {
+	return (before >= 'a' && before <= 'z' || before >= '0' && before <= '9') && after >= 'A' && after <= 'Z'
+}
+
+// Apakah kalimat memuat jenis jawaban yang ditanyakan
+func answersKind(sentence, kind string) bool {
+	switch kind {
+	case QuestionNumber:
+		return digitPattern.MatchString(sentence)
+	case QuestionDate:
+		return datePattern.MatchString(sentence)
+	}
+	return false
+}
+
+// Jawaban unggulan dari dokumen teratas, nil kalau query bukan pertanyaan
+// atau tidak ada kalimat yang cukup cocok
+func featuredAnswer(query string, results []SearchResult) *FeaturedAnswer {
+	kind, content, ok := parseQuestion(query)
+	if !ok {
+		return nil
+	}
+	articles, idx, err := loadIndex()
+	if err != nil {
+		log.Printf("Error loading index for featured answer: %v", err)
+		return nil
+	}
+	contentQuery := strings.Join(content, " ")
+
+	var best *FeaturedAnswer
+	docs := 0
+	for _, result := range results {
+		if docs == QA_TOP_DOCS {
+			break
+		}
+		article, local := localArticle(articles, result)
+		if !local || result.DuplicateOf != nil {
+			continue
+		}
+		docs++
+
+		// Bobot IDF term query menurut analyzer bahasa artikel; term yang
+		// tidak ada di index diabaikan
+		analyzer := analyzerFor(article.Language)
+		weights := make(map[string]float64)
+		total := 0.0
+		for _, term := range uniqueTerms(analyzer.ProcessText(contentQuery)) {
+			if df := idx.docFrequency(term); df > 0 {
+				weights[term] = math.Log(1 + float64(idx.DocCount)/float64(df))
+				total += weights[term]
+			}
+		}
+		if total == 0 {
+			continue
+		}
+
+		sentences := splitSentences(article.Content)
+		for i, sentence := range sentences {
+			words := len(strings.Fields(sentence))
+			if words < QA_MIN_SENTENCE_WORDS || words > QA_MAX_SENTENCE_WORDS {
+				continue
+			}
+			covered := 0.0
+			for _, term := range uniqueTerms(analyzer.ProcessText(sentence)) {
+				covered += weights[term]
+			}
+			coverage := covered / total
+			if coverage < QA_MIN_COVERAGE {
+				continue
+			}
+			// Pertanyaan angka/tanggal harus dijawab dengan angka/tanggal
+			score := coverage + QA_RANK_BONUS/float64(docs)
+			if kind != QuestionGeneral {
+				if !answersKind(sentence, kind) {
+					continue
+				}
+				score += QA_TYPE_BONUS
+			}
+			if best != nil && score <= best.Score {
+				continue
+			}
+
+			text := sentence
+			if words < QA_SHORT_SENTENCE && i+1 < len(sentences) {
+				text += " " + sentences[i+1]
+			}
+			best = &FeaturedAnswer{
+				Text:         text,
+				DocID:        result.DocID,
+				URL:          result.URL,
+				Title:        result.Title,
+				TextFragment: fragmentDirective(sentence),
+				Score:        score,
+			}
+		}
+	}
+	return best
+}
+
+// Jawaban unggulan hanya di halaman pertama hasil yang diurutkan per
+// relevansi, dan hanya kalau featured_answers aktif
+func pageFeaturedAnswer(query string, results []SearchResult, page int, opts SearchOptions) *FeaturedAnswer {
+	if page != 1 || (opts.Sort != "" && opts.Sort != SortRelevance) || !opts.withConfig().Config.FeaturedAnswers {
+		return nil
+	}
+	return featuredAnswer(query, results)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const QUALITY_RULES_FILE = "quality_rules.json"
+
+// Alasan dokumen dianggap ekstraksi berkualitas rendah
+const (
+	QualityTooShort     = "too_short"
+	QualityFallback     = "fallback_extraction"
+	QualityMissingDate  = "missing_date"
+	QualityMissingTitle = "missing_title"
+)
+
+// QualityRules menentukan kapan hasil crawl perlu dicek manual
+type QualityRules struct {
+	MinContentLength int  `json:"min_content_length"`
+	FlagFallback     bool `json:"flag_fallback"`
+	// Hanya source yang crawler-nya mengambil tanggal yang wajib punya tanggal
+	RequireDateDomains []string `json:"require_date_domains"`
+}
+
+var qualityRules QualityRules
+
+func defaultQualityRules() QualityRules {
+	return QualityRules{
+		MinContentLength:   200,
+		FlagFallback:       true,
+		RequireDateDomains: []string{"propertyandthecity.com"},
+	}
+}
+
+func loadQualityRules(path string) (QualityRules, error) {
+	rules := defaultQualityRules()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return rules, nil
+		}
+		return rules, err
+	}
+
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return rules, err
+	}
+	if rules.MinContentLength < 0 {
+		return rules, fmt.Errorf("min_content_length must not be negative")
+	}
+
+	return rules, nil
+}
+
+// Cek kualitas ekstraksi, mengembalikan daftar alasan (kosong = lolos)
+func checkQuality(article Article) []string {
+	var reasons []string
+
+	if strings.TrimSpace(article.Title) == "" {
+		reasons = append(reasons, QualityMissingTitle)
+	}
+	if len(strings.TrimSpace(article.Content)) < qualityRules.MinContentLength {
+		reasons = append(reasons, QualityTooShort)
+	}
+	if qualityRules.FlagFallback && article.Extraction == "fallback" {
+		reasons = append(reasons, QualityFallback)
+	}
+	if article.Date.IsZero() && requiresDate(article.URL) {
+		reasons = append(reasons, QualityMissingDate)
+	}
+
+	return reasons
+}
+
+func requiresDate(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := normalizeDomain(u.Hostname())
+	for _, domain := range qualityRules.RequireDateDomains {
+		if host == normalizeDomain(domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	rules, err := loadQualityRules(QUALITY_RULES_FILE)
+	if err != nil {
+		log.Printf("Error loading %s: %v", QUALITY_RULES_FILE, err)
+		rules = defaultQualityRules()
+	}
+	qualityRules = rules
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Satu normalisasi query untuk halaman HTML, API dan key cache, supaya query
+// yang sama secara logika ("  Rumah\tSubsidi", "Ｒｕｍａｈ subsidi", query
+// dengan zero-width space) selalu menghasilkan hasil dan entri cache yang
+// sama. Tokenizer hanya mengenal huruf ASCII, jadi huruf Latin beraksen
+// dilipat ke huruf dasarnya (precomposed maupun dengan combining mark).
+const MAX_QUERY_LENGTH = 200 // rune
+
+// Huruf Latin-1 beraksen -> huruf dasar
+var latinFolds = map[rune]string{
+	'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Ä': "A", 'Å': "A", 'Æ': "AE", 'Ç': "C",
+	'È': "E", 'É': "E", 'Ê': "E", 'Ë': "E", 'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I",
+	'Ñ': "N", 'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ö': "O", 'Ø': "O",
+	'Ù': "U", 'Ú': "U", 'Û': "U", 'Ü': "U", 'Ý': "Y", 'ß': "ss",
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae", 'ç': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'ñ': "n", 'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ý': "y", 'ÿ': "y",
+}
+
+// Tanda baca tipografis (dari copy-paste dokumen) -> ASCII
+var punctuationFolds = map[rune]string{
+	'‘': "'", '’': "'", '“': `"`, '”': `"`,
+	'–': "-", '—': "-", '…': "...",
+}
+
+func normalizeQuery(query string) string {
+	var b strings.Builder
+	runes := 0
+
+	for _, r := range query {
+		switch {
+		case unicode.IsSpace(r) || r == '　':
+			r = ' '
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r) || unicode.Is(unicode.Mn, r):
+			// Karakter kontrol, zero-width dan combining mark dibuang
+			continue
+		case r >= '！' && r <= '～':
+			// Fullwidth ASCII (keyboard CJK)
+			r -= 0xfee0
+		}
+		if fold, exists := latinFolds[r]; exists {
+			b.WriteString(fold)
+		} else if fold, exists := punctuationFolds[r]; exists {
+			b.WriteString(fold)
+		} else {
+			b.WriteRune(r)
+		}
+
+		if runes++; runes >= MAX_QUERY_LENGTH {
+			break
+		}
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"container/list"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cache hasil pencarian yang sudah diranking (sebelum paginasi dan render),
+// dikunci dengan (generasi corpus, query ternormalisasi, method, sort/filter).
+// Query yang sama di halaman lain, di /api/search atau lewat pencarian suara
+// tidak perlu dihitung ulang skornya. LRU: entri yang paling lama tidak
+// dipakai dibuang begitu query_cache_size tercapai. Dikosongkan bersama
+// cache halaman hasil, lihat purgeResultCaches.
+type QueryResultCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // depan = paling baru dipakai
+	hits    int
+	misses  int
+}
+
+type queryResultEntry struct {
+	key     string
+	results []SearchResult
+	plan    *QueryPlan
+	expires time.Time
+}
+
+var queryResultCache = &QueryResultCache{entries: make(map[string]*list.Element), lru: list.New()}
+
+func queryResultKey(query, method string, opts SearchOptions) string {
+	return fmt.Sprintf("%s|%d|%s|%s|%s|%s|%s", corpusGenerationKey(documentLog.Pending()), opts.Generation, normalizeQuery(query), method, filterParams(opts), strings.Join(opts.Features, ","), strings.Join(opts.Scopes, ","))
+}
+
+func (qc *QueryResultCache) enabled(cfg *Config) bool {
+	return cfg.QueryCacheTTLSeconds > 0 && cfg.QueryCacheSize > 0 && !benchMode
+}
+
+// Salinan hasil, supaya paginasi dan facet pemanggil tidak mengubah isi cache
+func (qc *QueryResultCache) Get(key string, cfg *Config) ([]SearchResult, *QueryPlan, bool) {
+	if !qc.enabled(cfg) {
+		return nil, nil, false
+	}
+
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+	if element, exists := qc.entries[key]; exists {
+		entry := element.Value.(*queryResultEntry)
+		if time.Now().Before(entry.expires) {
+			qc.lru.MoveToFront(element)
+			qc.hits++
+			return append([]SearchResult(nil), entry.results...), entry.plan, true
+		}
+		qc.lru.Remove(element)
+		delete(qc.entries, key)
+	}
+	qc.misses++
+	return nil, nil, false
+}
+
+func (qc *QueryResultCache) Put(key string, results []SearchResult, plan *QueryPlan, cfg *Config) {
+	if !qc.enabled(cfg) {
+		return
+	}
+
+	entry := &queryResultEntry{
+		key:     key,
+		results: append([]SearchResult(nil), results...),
+		plan:    plan,
+		expires: time.Now().Add(time.Duration(cfg.QueryCacheTTLSeconds) * time.Second),
+	}
+
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+	if element, exists := qc.entries[key]; exists {
+		element.Value = entry
+		qc.lru.MoveToFront(element)
+		return
+	}
+	for qc.lru.Len() >= cfg.QueryCacheSize {
+		oldest := qc.lru.Back()
+		qc.lru.Remove(oldest)
+		delete(qc.entries, oldest.Value.(*queryResultEntry).key)
+	}
+	qc.entries[key] = qc.lru.PushFront(entry)
+}
+
+func (qc *QueryResultCache) Purge() {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+	qc.entries = make(map[string]*list.Element)
+	qc.lru.Init()
+}
+
+type QueryResultCacheStats struct {
+	Entries    int `json:"entries"`
+	MaxEntries int `json:"max_entries"`
+	Hits       int `json:"hits"`
+	Misses     int `json:"misses"`
+	TTLSeconds int `json:"ttl_seconds"`
+}
+
+func (qc *QueryResultCache) Stats(cfg *Config) QueryResultCacheStats {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	return QueryResultCacheStats{
+		Entries:    qc.lru.Len(),
+		MaxEntries: cfg.QueryCacheSize,
+		Hits:       qc.hits,
+		Misses:     qc.misses,
+		TTLSeconds: cfg.QueryCacheTTLSeconds,
+	}
+}
+
+// Hasil yang sudah diranking dan halaman yang sudah dirender sama-sama basi
+// begitu index, blocklist, review queue, sinonim, feature flag atau config berubah
+func purgeResultCaches() {
+	queryResultCache.Purge()
+	resultPageCache.Purge()
+}
+
+// GET /api/admin/query-cache
+func queryCacheHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, queryResultCache.Stats(requestConfig(c)))
+}
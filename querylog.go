@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+const QUERY_LOG_FILE = "queries.log"
+
+// Satu baris query log (JSON lines)
+type QueryLogEntry struct {
+	Time       time.Time `json:"time"`
+	Query      string    `json:"query"`
+	Method     string    `json:"method"`
+	Results    int       `json:"results"`
+	DurationMs float64   `json:"duration_ms"`
+}
+
+var queryLogMu sync.Mutex
+
+// Append query ke log, error hanya dicatat supaya search tidak gagal
+func logQuery(entry QueryLogEntry) {
+	// Traffic load test tidak boleh mencemari log yang dipakai untuk membuat skenario
+	if benchMode {
+		return
+	}
+
+	queryLogMu.Lock()
+	defer queryLogMu.Unlock()
+
+	f, err := os.OpenFile(QUERY_LOG_FILE, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Printf("Error opening query log: %v", err)
+		return
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Error encoding query log entry: %v", err)
+		return
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		log.Printf("Error writing query log: %v", err)
+	}
+}
+
+// Baca semua entry sejak waktu tertentu, baris yang rusak dilewati
+func readQueryLog(path string, since time.Time) ([]QueryLogEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []QueryLogEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry QueryLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.Time.Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Struktur query untuk chip di frontend, mis. [lokasi: Jakarta] [tipe:
+// apartemen] [sumber: rumah123]. Selain phrase dalam tanda kutip (phrase.go)
+// engine ini tidak punya sintaks query terstruktur (field:nilai), jadi
+// strukturnya disusun dari dua hal yang memang berlaku: entitas yang dikenali
+// di teks query (lokasi dari daftar knownLocations, tipe properti, harga
+// "Rp ...") dan filter yang aktif dari parameter URL. Setiap chip membawa Remove, parameter pencarian setelah
+// chip itu dibuang.
+const (
+	ChipLocation     = "location"
+	ChipPropertyType = "property_type"
+	ChipPrice        = "price"
+	ChipFilter       = "filter"
+)
+
+// Tipe properti yang dikenali di query
+var propertyTypes = []string{
+	"apartemen", "apartment", "rumah", "house", "ruko", "rukan", "tanah", "land",
+	"kavling", "villa", "vila", "gudang", "kost", "kos", "townhouse", "rusun",
+	"condo", "cluster", "perumahan",
+}
+
+var propertyTypePattern = regexp.MustCompile(`(?i)\b(` + strings.Join(propertyTypes, "|") + `)\b`)
+
+// Label chip filter per parameter URL
+var filterLabels = map[string]string{
+	"source":    "sumber",
+	"lang":      "bahasa",
+	"location":  "lokasi",
+	"author":    "penulis",
+	"category":  "kategori",
+	"doctype":   "jenis",
+	"min_price": "harga min",
+	"max_price": "harga maks",
+	"min_words": "min kata",
+	"days":      "hari terakhir",
+	"from":      "dari",
+	"to":        "sampai",
+}
+
+type QueryChip struct {
+	Kind  string `json:"kind"`  // location, property_type, price, filter
+	Label string `json:"label"` // lokasi, tipe, harga, atau label filter
+	Value string `json:"value"`
+	// Entitas: posisi byte di q. Filter: nama parameter URL.
+	Start int    `json:"start,omitempty"`
+	End   int    `json:"end,omitempty"`
+	Param string `json:"param,omitempty"`
+	// Query string (q, method dan filter) setelah chip dibuang, untuk
+	// /search atau /api/search
+	Remove string `json:"remove"`
+}
+
+type QueryStructure struct {
+	// Kata query di luar entitas, seperti diketik
+	Text  []string    `json:"text"`
+	Chips []QueryChip `json:"chips"`
+}
+
+// Entitas di teks query, urut posisi; yang tumpang tindih dengan entitas
+// sebelumnya dilewati
+func queryEntities(query string) []QueryChip {
+	var chips []QueryChip
+	for _, span := range locationPattern.FindAllStringIndex(query, -1) {
+		chips = append(chips, QueryChip{Kind: ChipLocation, Label: "lokasi", Value: canonicalLocation(query[span[0]:span[1]]), Start: span[0], End: span[1]})
+	}
+	for _, span := range propertyTypePattern.FindAllStringIndex(query, -1) {
+		chips = append(chips, QueryChip{Kind: ChipPropertyType, Label: "tipe", Value: strings.ToLower(query[span[0]:span[1]]), Start: span[0], End: span[1]})
+	}
+	for _, span := range pricePattern.FindAllStringIndex(query, -1) {
+		chips = append(chips, QueryChip{Kind: ChipPrice, Label: "harga", Value: strings.TrimSpace(query[span[0]:span[1]]), Start: span[0], End: span[1]})
+	}
+	sort.SliceStable(chips, func(i, j int) bool { return chips[i].Start < chips[j].Start })
+
+	var entities []QueryChip
+	end := 0
+	for _, chip := range chips {
+		if chip.Start < end {
+			continue
+		}
+		entities = append(entities, chip)
+		end = chip.End
+	}
+	return entities
+}
+
+// Nama lokasi seperti di knownLocations ("jakarta selatan" -> "Jakarta Selatan")
+func canonicalLocation(text string) string {
+	for _, location := range knownLocations {
+		if strings.EqualFold(location, text) {
+			return location
+		}
+	}
+	return text
+}
+
+// Struktur query dan filter aktif, dengan query string untuk membuang tiap chip
+func parseQueryStructure(query, method string, opts SearchOptions) QueryStructure {
+	filters, _ := url.ParseQuery(strings.TrimPrefix(string(filterParams(opts)), "&"))
+	params := func(q string, without string) string {
+		values := url.Values{}
+		for key, value := range filters {
+			if key != without {
+				values[key] = value
+			}
+		}
+		values.Set("q", q)
+		if method != "" {
+			values.Set("method", method)
+		}
+		return values.Encode()
+	}
+
+	structure := QueryStructure{Text: []string{}, Chips: []QueryChip{}}
+	last := 0
+	for _, chip := range queryEntities(query) {
+		structure.Text = append(structure.Text, strings.Fields(strings.ToLower(query[last:chip.Start]))...)
+		chip.Remove = params(strings.Join(strings.Fields(query[:chip.Start]+" "+query[chip.End:]), " "), "")
+		structure.Chips = append(structure.Chips, chip)
+		last = chip.End
+	}
+	structure.Text = append(structure.Text, strings.Fields(strings.ToLower(query[last:]))...)
+
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		if _, isFilter := filterLabels[key]; isFilter || strings.HasPrefix(key, SCHEMA_FILTER_PREFIX) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		label := filterLabels[key]
+		if label == "" {
+			label = strings.TrimPrefix(key, SCHEMA_FILTER_PREFIX)
+		}
+		structure.Chips = append(structure.Chips, QueryChip{
+			Kind:   ChipFilter,
+			Label:  label,
+			Value:  filters.Get(key),
+			Param:  key,
+			Remove: params(query, key),
+		})
+	}
+	return structure
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"html/template"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/Mahathirrr/search-engine2/client"
+)
+
+// Federasi ke instance search engine lain (mis. satu deployment per situs)
+// lewat JSON API mereka, supaya satu frontend bisa mencari semuanya:
+//
+//	"remote_instances": [
+//	  {"name": "rumah123", "url": "http://rumah123-search:8080", "timeout_ms": 1500, "weight": 1}
+//	]
+//
+// Query diteruskan dengan local=1 supaya instance tujuan hanya mencari index
+// sendiri (tidak meneruskan lagi). Setiap instance adalah satu tier di
+// federate dengan timeout sendiri; skornya dinormalisasi di mergeTiers.
+const (
+	REMOTE_TIMEOUT     = 2 * time.Second
+	REMOTE_MAX_RESULTS = API_MAX_PER_PAGE // hasil teratas yang diambil per instance
+)
+
+type RemoteInstance struct {
+	Name      string  `json:"name"`
+	URL       string  `json:"url"`
+	TimeoutMs int     `json:"timeout_ms"` // 0 = REMOTE_TIMEOUT
+	Weight    float64 `json:"weight"`     // pengali skor ternormalisasi, 0 = 1
+}
+
+// Dipakai bersama semua instance supaya koneksi di-reuse
+var remoteHTTPClient = &http.Client{}
+
+func remoteTiers(query, method string, opts SearchOptions) []SearchTier {
+	opts = opts.withConfig()
+	tiers := make([]SearchTier, 0, len(opts.Config.RemoteInstances))
+	for _, instance := range opts.Config.RemoteInstances {
+		instance := instance
+		timeout := REMOTE_TIMEOUT
+		if instance.TimeoutMs > 0 {
+			timeout = time.Duration(instance.TimeoutMs) * time.Millisecond
+		}
+		tiers = append(tiers, SearchTier{
+			Name:    "remote:" + instance.Name,
+			Node:    instance.Name,
+			Timeout: timeout,
+			Weight:  instance.Weight,
+			Search: func() ([]SearchResult, *QueryPlan, error) {
+				results, err := searchRemote(instance, timeout, query, method, opts)
+				return results, nil, err
+			},
+		})
+	}
+	return tiers
+}
+
+func searchRemote(instance RemoteInstance, timeout time.Duration, query, method string, opts SearchOptions) ([]SearchResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	c := client.New(instance.URL, client.WithHTTPClient(remoteHTTPClient), client.WithRetries(0, 0))
+	res, err := c.Search(ctx, remoteRequest(query, method, opts))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SearchResult, 0, len(res.Results))
+	for _, r := range res.Results {
+		// Blocklist lokal tetap berlaku untuk hasil instance lain
+		if blocklist.IsURLBlocked(r.URL) {
+			continue
+		}
+		results = append(results, SearchResult{
+			DocID:   r.DocID,
+			Title:   r.Title,
+			Content: r.Snippet,
+			URL:     r.URL,
+			Score:   r.Score,
+			// Highlight dibuat ulang dari snippet yang di-escape, HTML dari
+			// instance lain tidak dipakai
+			HighlightedContent: template.HTML(highlightText(template.HTMLEscapeString(r.Snippet), query)),
+			Favicon:            getFaviconPath(r.URL),
+			Author:             r.Author,
+			Category:           r.Category,
+			TextFragment:       r.TextFragment,
+			Words:              r.Words,
+			ReadingMinutes:     r.ReadingMinutes,
+			Date:               r.Date,
+			Price:              r.Price,
+			Archived:           r.Archived,
+			Node:               instance.Name,
+			DocType:            r.DocType,
+			Page:               r.Page,
+			Fields:             r.Fields,
+			Collapsed:          remoteCollapsed(r.Collapsed, instance.Name),
+			DuplicateOf:        remoteDuplicateOf(r.DuplicateOf, instance.Name),
+		})
+	}
+	return results, nil
+}
+
+// Opsi pencarian sebagai request JSON API untuk instance lain
+func remoteRequest(query, method string, opts SearchOptions) client.SearchRequest {
+	req := client.SearchRequest{
+		Query:          query,
+		Method:         method,
+		PerPage:        REMOTE_MAX_RESULTS,
+		Sort:           opts.Sort,
+		MatchAll:       opts.MatchAll,
+		IncludeArchive: opts.IncludeArchive,
+		Local:          true,
+		NoCollapse:     opts.NoCollapse,
+		NoTranslate:    opts.NoTranslate,
+		NoSegment:      opts.NoSegment,
+		NoSpell:        opts.NoSpell,
+		Source:         opts.Filters.Source,
+		Language:       opts.Filters.Language,
+		Location:       opts.Filters.Location,
+		Author:         opts.Filters.Author,
+		Category:       opts.Filters.Category,
+		DocType:        opts.Filters.DocType,
+		MinPrice:       opts.Filters.MinPrice,
+		MaxPrice:       opts.Filters.MaxPrice,
+		MinWords:       opts.Filters.MinWords,
+		Fields:         opts.Filters.Fields,
+		From:           formatFilterDate(opts.Filters.From),
+		To:             formatFilterDate(opts.Filters.To),
+	}
+	if !opts.Filters.Since.IsZero() {
+		req.Days = int(math.Round(time.Since(opts.Filters.Since).Hours() / 24))
+	}
+	return req
+}
+
+// Penggabungan yang dilakukan instance lain; node kosong berarti instance itu
+func remoteCollapsed(collapsed []client.CollapsedResult, node string) []CollapsedResult {
+	var out []CollapsedResult
+	for _, c := range collapsed {
+		out = append(out, remoteCollapsedResult(c, node))
+	}
+	return out
+}
+
+func remoteDuplicateOf(duplicateOf *client.CollapsedResult, node string) *CollapsedResult {
+	if duplicateOf == nil {
+		return nil
+	}
+	c := remoteCollapsedResult(*duplicateOf, node)
+	return &c
+}
+
+func remoteCollapsedResult(c client.CollapsedResult, node string) CollapsedResult {
+	if c.Node == "" {
+		c.Node = node
+	}
+	return CollapsedResult{DocID: c.DocID, URL: c.URL, Node: c.Node, Reason: c.Reason}
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// searchctl replay: putar ulang query log terhadap build lokal sebelum rilis
+// dan laporkan latency serta error rate. Default secepat mungkin dengan
+// --concurrency worker; --recorded-rate memakai jarak waktu asli antar query
+// (dipercepat dengan --speed). Jalankan server target dengan --bench-mode
+// supaya traffic replay tidak masuk query log-nya sendiri.
+const (
+	REPLAY_DEFAULT_CONCURRENCY = 8
+	REPLAY_DEFAULT_TIMEOUT     = 10 * time.Second
+	REPLAY_MAX_ERRORS_LISTED   = 10
+)
+
+type replayRequest struct {
+	Offset  time.Duration // sejak query pertama, untuk --recorded-rate
+	Query   string
+	Method  string
+	Results int // jumlah hasil di log
+	// Latency server saat query ini tercatat (0 = tidak ada di log lama)
+	RecordedMs float64
+}
+
+type replayOutcome struct {
+	Latency    time.Duration
+	StatusCode int // 0 = error jaringan/timeout
+	Err        string
+	Results    int // hanya --api
+}
+
+type ReplayLatency struct {
+	P50  float64 `json:"p50_ms"`
+	P90  float64 `json:"p90_ms"`
+	P95  float64 `json:"p95_ms"`
+	P99  float64 `json:"p99_ms"`
+	Max  float64 `json:"max_ms"`
+	Mean float64 `json:"mean_ms"`
+}
+
+type ReplayReport struct {
+	Target      string         `json:"target"`
+	Requests    int            `json:"requests"`
+	Errors      int            `json:"errors"`
+	ErrorRate   float64        `json:"error_rate"`
+	StatusCodes map[string]int `json:"status_codes"` // "200", "500", "error"
+	DurationSec float64        `json:"duration_sec"`
+	Throughput  float64        `json:"throughput_rps"`
+	Latency     ReplayLatency  `json:"latency"`
+	// Latency server yang tercatat di log untuk query yang sama, pembanding
+	Recorded ReplayLatency `json:"recorded"`
+	// --api: query yang dulu ada hasilnya sekarang kosong
+	ZeroResultRegressions []string `json:"zero_result_regressions,omitempty"`
+	SampleErrors          []string `json:"sample_errors,omitempty"`
+}
+
+// searchctl replay --log queries.log --target URL [--recorded-rate] [--speed 1]
+func replayCommand(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	logPath := fs.String("log", QUERY_LOG_FILE, "query log yang diputar ulang")
+	target := fs.String("target", "http://localhost:8080", "base URL server")
+	days := fs.Int("days", 0, "hanya query dari N hari terakhir (0 = semua)")
+	limit := fs.Int("limit", 0, "maksimal jumlah request (0 = semua)")
+	recordedRate := fs.Bool("recorded-rate", false, "pakai jarak waktu antar query seperti di log")
+	speed := fs.Float64("speed", 1, "pengali kecepatan untuk --recorded-rate")
+	concurrency := fs.Int("concurrency", REPLAY_DEFAULT_CONCURRENCY, "request bersamaan (tanpa --recorded-rate: jumlah worker)")
+	timeout := fs.Duration("timeout", REPLAY_DEFAULT_TIMEOUT, "timeout per request")
+	api := fs.Bool("api", false, "pakai /api/search dan bandingkan jumlah hasil dengan log")
+	maxErrorRate := fs.Float64("max-error-rate", 0.01, "exit 1 kalau error rate di atas ini")
+	maxP99 := fs.Float64("max-p99", 0, "exit 1 kalau p99 latency di atas ini dalam ms (0 = tidak dicek)")
+	asJSON := fs.Bool("json", false, "cetak laporan sebagai JSON")
+	fs.Parse(args)
+
+	if *speed <= 0 || *concurrency < 1 {
+		fmt.Fprintln(os.Stderr, "--speed must be positive and --concurrency at least 1")
+		return 2
+	}
+	var since time.Time
+	if *days > 0 {
+		since = time.Now().AddDate(0, 0, -*days)
+	}
+	entries, err := readQueryLog(*logPath, since)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	requests := replayRequests(entries, *limit)
+	if len(requests) == 0 {
+		fmt.Fprintln(os.Stderr, "no queries in log")
+		return 1
+	}
+
+	client := &http.Client{Timeout: *timeout}
+	start := time.Now()
+	outcomes := replay(requests, *concurrency, *recordedRate, *speed, func(req replayRequest) replayOutcome {
+		return replayOne(client, *target, req, *api)
+	})
+	report := replayReport(*target, requests, outcomes, time.Since(start), *api)
+
+	if *asJSON {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		printReplayReport(report)
+	}
+	if report.ErrorRate > *maxErrorRate || (*maxP99 > 0 && report.Latency.P99 > *maxP99) {
+		return 1
+	}
+	return 0
+}
+
+// Entry log urut waktu, dengan offset dari query pertama
+func replayRequests(entries []QueryLogEntry, limit int) []replayRequest {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	var requests []replayRequest
+	for _, entry := range entries {
+		if limit > 0 && len(requests) >= limit {
+			break
+		}
+		if strings.TrimSpace(entry.Query) == "" {
+			continue
+		}
+		method := entry.Method
+		if method == "" {
+			method = "cosine"
+		}
+		requests = append(requests, replayRequest{
+			Offset:     entry.Time.Sub(entries[0].Time),
+			Query:      entry.Query,
+			Method:     method,
+			Results:    entry.Results,
+			RecordedMs: entry.DurationMs,
+		})
+	}
+	return requests
+}
+
+// Jalankan semua request; outcome di indeks yang sama dengan request-nya.
+// Dengan recordedRate, request dikirim pada offset/speed sejak mulai dan
+// concurrency hanya membatasi request yang sedang berjalan.
+func replay(requests []replayRequest, concurrency int, recordedRate bool, speed float64, send func(replayRequest) replayOutcome) []replayOutcome {
+	outcomes := make([]replayOutcome, len(requests))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for i, req := range requests {
+		if recordedRate {
+			due := time.Duration(float64(req.Offset) / speed)
+			if wait := due - time.Since(start); wait > 0 {
+				time.Sleep(wait)
+			}
+		}
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(i int, req replayRequest) {
+			defer wg.Done()
+			outcomes[i] = send(req)
+			<-slots
+		}(i, req)
+	}
+	wg.Wait()
+	return outcomes
+}
+
+func replayOne(client *http.Client, target string, req replayRequest, api bool) replayOutcome {
+	values := url.Values{}
+	values.Set("q", req.Query)
+	values.Set("method", req.Method)
+	path := "/search?"
+	if api {
+		path = "/api/search?"
+		values.Set("fields", "url")
+	}
+
+	start := time.Now()
+	res, err := client.Get(strings.TrimSuffix(target, "/") + path + values.Encode())
+	if err != nil {
+		return replayOutcome{Latency: time.Since(start), Err: err.Error()}
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	outcome := replayOutcome{Latency: time.Since(start), StatusCode: res.StatusCode}
+	if err != nil {
+		outcome.Err = err.Error()
+		return outcome
+	}
+	if res.StatusCode >= 400 {
+		outcome.Err = fmt.Sprintf("%s: %.200s", res.Status, strings.TrimSpace(string(body)))
+		return outcome
+	}
+	if api {
+		var page struct {
+			TotalResults int `json:"total_results"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			outcome.Err = "invalid response: " + err.Error()
+			return outcome
+		}
+		outcome.Results = page.TotalResults
+	}
+	return outcome
+}
+
+func replayReport(target string, requests []replayRequest, outcomes []replayOutcome, elapsed time.Duration, api bool) ReplayReport {
+	report := ReplayReport{
+		Target:      target,
+		Requests:    len(requests),
+		StatusCodes: make(map[string]int),
+		DurationSec: elapsed.Seconds(),
+	}
+	var latencies, recorded []float64
+	regressed := make(map[string]bool)
+	for i, outcome := range outcomes {
+		status := "error"
+		if outcome.StatusCode != 0 {
+			status = fmt.Sprint(outcome.StatusCode)
+		}
+		report.StatusCodes[status]++
+		latencies = append(latencies, float64(outcome.Latency.Microseconds())/1000)
+
+		if outcome.Err != "" {
+			report.Errors++
+			if len(report.SampleErrors) < REPLAY_MAX_ERRORS_LISTED {
+				report.SampleErrors = append(report.SampleErrors, fmt.Sprintf("%q: %s", requests[i].Query, outcome.Err))
+			}
+			continue
+		}
+		if api && requests[i].Results > 0 && outcome.Results == 0 && !regressed[requests[i].Query] {
+			regressed[requests[i].Query] = true
+			report.ZeroResultRegressions = append(report.ZeroResultRegressions, requests[i].Query)
+		}
+	}
+	for _, req := range requests {
+		if req.RecordedMs > 0 {
+			recorded = append(recorded, req.RecordedMs)
+		}
+	}
+	report.ErrorRate = float64(report.Errors) / float64(report.Requests)
+	if elapsed > 0 {
+		report.Throughput = float64(report.Requests) / elapsed.Seconds()
+	}
+	report.Latency = latencySummary(latencies)
+	report.Recorded = latencySummary(recorded)
+	sort.Strings(report.ZeroResultRegressions)
+	return report
+}
+
+// Persentil nearest-rank dari latency dalam milidetik
+func latencySummary(latencies []float64) ReplayLatency {
+	if len(latencies) == 0 {
+		return ReplayLatency{}
+	}
+	sorted := append([]float64(nil), latencies...)
+	sort.Float64s(sorted)
+	percentile := func(p float64) float64 {
+		rank := int(p*float64(len(sorted))+0.999999) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		return sorted[rank]
+	}
+	sum := 0.0
+	for _, latency := range sorted {
+		sum += latency
+	}
+	return ReplayLatency{
+		P50:  percentile(0.50),
+		P90:  percentile(0.90),
+		P95:  percentile(0.95),
+		P99:  percentile(0.99),
+		Max:  sorted[len(sorted)-1],
+		Mean: sum / float64(len(sorted)),
+	}
+}
+
+func printReplayReport(report ReplayReport) {
+	fmt.Printf("%d requests to %s in %.1fs (%.1f req/s)\n", report.Requests, report.Target, report.DurationSec, report.Throughput)
+	fmt.Printf("errors: %d (%.2f%%)\n", report.Errors, report.ErrorRate*100)
+
+	codes := make([]string, 0, len(report.StatusCodes))
+	for code := range report.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		fmt.Printf("  %s: %d\n", code, report.StatusCodes[code])
+	}
+
+	fmt.Printf("%-10s %8s %8s %8s %8s %8s %8s\n", "latency", "p50", "p90", "p95", "p99", "max", "mean")
+	for _, row := range []struct {
+		name    string
+		latency ReplayLatency
+	}{{"replay", report.Latency}, {"recorded", report.Recorded}} {
+		l := row.latency
+		fmt.Printf("%-10s %8.1f %8.1f %8.1f %8.1f %8.1f %8.1f\n", row.name, l.P50, l.P90, l.P95, l.P99, l.Max, l.Mean)
+	}
+
+	if len(report.ZeroResultRegressions) > 0 {
+		fmt.Printf("%d queries with results in the log now return none:\n", len(report.ZeroResultRegressions))
+		for _, query := range report.ZeroResultRegressions {
+			fmt.Printf("  %s\n", query)
+		}
+	}
+	for _, sample := range report.SampleErrors {
+		fmt.Printf("  ! %s\n", sample)
+	}
+}
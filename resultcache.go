@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cache halaman hasil yang sudah dirender (HTML lengkap), dikunci dengan
+// (query, method, page, sort/filter, generasi corpus). Query populer tidak
+// perlu dicari dan dirender ulang sampai TTL habis. Cache dikosongkan begitu
+// generasi index baru aktif (lihat noteIndexGeneration) dan setelah admin
+// mengubah blocklist, review queue atau sinonim.
+type ResultPageCache struct {
+	mu      sync.Mutex
+	entries map[string]*resultPage
+	order   []string
+	hits    int
+	misses  int
+}
+
+type resultPage struct {
+	body    []byte
+	results int // jumlah hasil, untuk query log saat cache hit
+	expires time.Time
+}
+
+var resultPageCache = &ResultPageCache{entries: make(map[string]*resultPage)}
+
+func resultPageKey(query, method string, page int, opts SearchOptions) string {
+	return fmt.Sprintf("%s|%d|%s|%s|%d|%s|%s|%s", corpusGenerationKey(documentLog.Pending()), opts.Generation, normalizeQuery(query), method, page, filterParams(opts), strings.Join(opts.Features, ","), strings.Join(opts.Scopes, ","))
+}
+
+func (rc *ResultPageCache) enabled(cfg *Config) bool {
+	return cfg.ResultCacheTTLSeconds > 0 && cfg.ResultCacheSize > 0 && !benchMode
+}
+
+func (rc *ResultPageCache) Get(key string, cfg *Config) (*resultPage, bool) {
+	if !rc.enabled(cfg) {
+		return nil, false
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if page, exists := rc.entries[key]; exists && time.Now().Before(page.expires) {
+		rc.hits++
+		return page, true
+	}
+	rc.misses++
+	return nil, false
+}
+
+func (rc *ResultPageCache) Put(key string, body []byte, results int, cfg *Config) {
+	if !rc.enabled(cfg) {
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if _, exists := rc.entries[key]; !exists {
+		for len(rc.order) >= cfg.ResultCacheSize {
+			delete(rc.entries, rc.order[0])
+			rc.order = rc.order[1:]
+		}
+		rc.order = append(rc.order, key)
+	}
+	rc.entries[key] = &resultPage{
+		body:    body,
+		results: results,
+		expires: time.Now().Add(time.Duration(cfg.ResultCacheTTLSeconds) * time.Second),
+	}
+}
+
+func (rc *ResultPageCache) Purge() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries = make(map[string]*resultPage)
+	rc.order = nil
+}
+
+type ResultPageCacheStats struct {
+	Entries    int `json:"entries"`
+	Hits       int `json:"hits"`
+	Misses     int `json:"misses"`
+	TTLSeconds int `json:"ttl_seconds"`
+}
+
+func (rc *ResultPageCache) Stats(cfg *Config) ResultPageCacheStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	return ResultPageCacheStats{
+		Entries:    len(rc.entries),
+		Hits:       rc.hits,
+		Misses:     rc.misses,
+		TTLSeconds: cfg.ResultCacheTTLSeconds,
+	}
+}
+
+// GET /api/admin/result-cache
+func resultCacheHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, resultPageCache.Stats(requestConfig(c)))
+}
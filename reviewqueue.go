@@ -0,0 +1,289 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const REVIEW_QUEUE_FILE = "review_queue.json"
+
+// Status item di review queue
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// ReviewItem adalah dokumen yang ditandai filter (spam atau kualitas ekstraksi)
+// dan menunggu keputusan admin. Dikunci dengan URL karena docID bisa berubah
+// setiap corpus di-load ulang.
+type ReviewItem struct {
+	URL        string        `json:"url"`
+	Title      string        `json:"title"`
+	Matches    []FilterMatch `json:"matches,omitempty"`
+	Reasons    []string      `json:"reasons,omitempty"`
+	Hold       bool          `json:"hold"` // tidak diindex selama masih pending
+	Status     string        `json:"status"`
+	Edit       *ArticleEdit  `json:"edit,omitempty"`
+	FlaggedAt  time.Time     `json:"flagged_at"`
+	ReviewedAt *time.Time    `json:"reviewed_at,omitempty"`
+}
+
+// ArticleEdit adalah koreksi admin yang menimpa hasil crawl
+type ArticleEdit struct {
+	Title   string    `json:"title,omitempty"`
+	Content string    `json:"content,omitempty"`
+	Date    time.Time `json:"date,omitempty"`
+	Author  string    `json:"author,omitempty"`
+}
+
+type ReviewQueue struct {
+	mu    sync.RWMutex
+	path  string
+	items map[string]*ReviewItem
+}
+
+var reviewQueue *ReviewQueue
+
+func loadReviewQueue(path string) (*ReviewQueue, error) {
+	rq := &ReviewQueue{
+		path:  path,
+		items: make(map[string]*ReviewItem),
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return rq, nil
+		}
+		return nil, err
+	}
+
+	var items []*ReviewItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		rq.items[item.URL] = item
+	}
+
+	return rq, nil
+}
+
+// Simpan queue ke disk, dipanggil dengan lock sudah dipegang
+func (rq *ReviewQueue) save() error {
+	items := rq.sorted("")
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(rq.path, data, 0644)
+}
+
+func (rq *ReviewQueue) sorted(status string) []*ReviewItem {
+	items := make([]*ReviewItem, 0, len(rq.items))
+	for _, item := range rq.items {
+		if status == "" || item.Status == status {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].FlaggedAt.Before(items[j].FlaggedAt)
+	})
+	return items
+}
+
+// Tandai dokumen, item yang sudah ada tidak diubah supaya keputusan admin tetap berlaku
+func (rq *ReviewQueue) Flag(article Article, matches []FilterMatch, reasons []string, hold bool) *ReviewItem {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	if item, exists := rq.items[article.URL]; exists {
+		return item.copy()
+	}
+
+	item := &ReviewItem{
+		URL:       article.URL,
+		Title:     article.Title,
+		Matches:   matches,
+		Reasons:   reasons,
+		Hold:      hold,
+		Status:    ReviewPending,
+		FlaggedAt: time.Now(),
+	}
+	rq.items[article.URL] = item
+
+	if err := rq.save(); err != nil {
+		log.Printf("Error saving review queue: %v", err)
+	}
+
+	return item.copy()
+}
+
+// Salinan item, nil kalau URL tidak ada di queue
+func (rq *ReviewQueue) Get(url string) *ReviewItem {
+	rq.mu.RLock()
+	defer rq.mu.RUnlock()
+
+	item, exists := rq.items[url]
+	if !exists {
+		return nil
+	}
+	return item.copy()
+}
+
+// Item yang dikembalikan ke luar selalu salinan: handler meng-encode-nya
+// setelah lock dilepas, sementara SetStatus/Edit mengubah item di map.
+// Slice dan Edit tidak pernah diubah di tempat, jadi salinan dangkal cukup.
+func (item *ReviewItem) copy() *ReviewItem {
+	copied := *item
+	return &copied
+}
+
+// Ubah status item (approve/reject)
+func (rq *ReviewQueue) SetStatus(url, status string) (*ReviewItem, error) {
+	if status != ReviewPending && status != ReviewApproved && status != ReviewRejected {
+		return nil, fmt.Errorf("unknown review status %q", status)
+	}
+
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	item, exists := rq.items[url]
+	if !exists {
+		return nil, fmt.Errorf("no review item for %s", url)
+	}
+
+	now := time.Now()
+	item.Status = status
+	item.ReviewedAt = &now
+
+	return item.copy(), rq.save()
+}
+
+// Simpan koreksi admin; dokumen yang sudah diedit otomatis di-approve
+func (rq *ReviewQueue) Edit(url string, edit ArticleEdit) (*ReviewItem, error) {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	item, exists := rq.items[url]
+	if !exists {
+		return nil, fmt.Errorf("no review item for %s", url)
+	}
+
+	now := time.Now()
+	item.Edit = &edit
+	item.Status = ReviewApproved
+	item.ReviewedAt = &now
+
+	return item.copy(), rq.save()
+}
+
+// Hapus item, dipakai untuk membersihkan item yang URL-nya sudah tidak ada di corpus
+func (rq *ReviewQueue) Remove(urls []string) error {
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+
+	for _, url := range urls {
+		delete(rq.items, url)
+	}
+	return rq.save()
+}
+
+// Terapkan koreksi admin ke artikel hasil load
+func (rq *ReviewQueue) ApplyEdits(articles []Article) {
+	rq.mu.RLock()
+	defer rq.mu.RUnlock()
+
+	for i := range articles {
+		item, exists := rq.items[articles[i].URL]
+		if !exists || item.Edit == nil {
+			continue
+		}
+		if item.Edit.Title != "" {
+			articles[i].Title = item.Edit.Title
+		}
+		if item.Edit.Content != "" {
+			articles[i].Content = item.Edit.Content
+		}
+		if !item.Edit.Date.IsZero() {
+			articles[i].Date = item.Edit.Date
+		}
+		if item.Edit.Author != "" {
+			articles[i].Author = item.Edit.Author
+		}
+	}
+}
+
+func (rq *ReviewQueue) List(status string) []*ReviewItem {
+	rq.mu.RLock()
+	defer rq.mu.RUnlock()
+
+	items := rq.sorted(status)
+	for i, item := range items {
+		items[i] = item.copy()
+	}
+	return items
+}
+
+// Tentukan apakah artikel boleh masuk index berdasarkan filter dan keputusan admin.
+// Ekstraksi berkualitas rendah selalu ditahan sampai di-review.
+func (rq *ReviewQueue) Allows(article Article) bool {
+	matches := checkContent(article)
+	reasons := checkQuality(article)
+	if len(matches) > 0 || len(reasons) > 0 {
+		rq.Flag(article, matches, reasons, hasExcludeMatch(matches) || len(reasons) > 0)
+	}
+
+	rq.mu.RLock()
+	defer rq.mu.RUnlock()
+
+	item, exists := rq.items[article.URL]
+	if !exists {
+		return true
+	}
+
+	switch item.Status {
+	case ReviewApproved:
+		return true
+	case ReviewRejected:
+		return false
+	default:
+		return !item.Hold
+	}
+}
+
+// Alasan dokumen tidak diindex, untuk laporan build: "rejected" atau
+// "held: <filter>" / "held: <alasan kualitas>"
+func (rq *ReviewQueue) SkipReason(url string) string {
+	rq.mu.RLock()
+	defer rq.mu.RUnlock()
+
+	item, exists := rq.items[url]
+	switch {
+	case !exists:
+		return "unknown"
+	case item.Status == ReviewRejected:
+		return "rejected"
+	case len(item.Matches) > 0:
+		return "held: " + item.Matches[0].Filter
+	case len(item.Reasons) > 0:
+		return "held: " + item.Reasons[0]
+	}
+	return "held"
+}
+
+func init() {
+	rq, err := loadReviewQueue(REVIEW_QUEUE_FILE)
+	if err != nil {
+		log.Printf("Error loading %s: %v", REVIEW_QUEUE_FILE, err)
+		rq = &ReviewQueue{path: REVIEW_QUEUE_FILE, items: make(map[string]*ReviewItem)}
+	}
+	reviewQueue = rq
+}
@@ -0,0 +1,207 @@
+package main
+
+// Kamus kata dasar untuk stemmer (stemmer.go). Kata dasar umum bahasa
+// Indonesia ditambah istilah properti, konstruksi, dan desain interior yang
+// sering muncul di korpus. Kata serapan dan nama yang tidak pernah diberi
+// imbuhan tidak perlu masuk; kata yang kata dasarnya tidak ada di kamus
+// dibiarkan apa adanya. Istilah tambahan bisa ditaruh di root_words.txt
+// tanpa mengubah kode.
+const rootWords = `
+abad abadi abai abang abdi abjad abu acak acara acu acuan acung ada adab adam adaptasi adat adegan
+adhesi adik adil administrasi adon adopsi adu aduk agak agama agar agen agun agunan agung ahli air
+ajaib ajak ajang ajar aju akad akademis akal akan akar akhir akhlak akibat akomodasi akomodir akrab
+akreditasi akselerasi aksen akses aksesibilitas aksesori aksi aktif aktifitas aktivasi aktivitas
+aktual aku akui akuisisi akumulasi akur alam alamat alami alas alat alergi alhamdulillah alih alir
+alis allah alokasi alun alur amal aman amat ambang ambil ambisi ambisius amin ampun amuk anak
+analisa ancam anda andai andal aneh aneka angan anggap anggar anggota angguk anggun anggur angin
+angka angkat angkut angsur aniaya anime anjak anjlok anjur anomali antar antara anti antisipasi
+antre antri anugerah anut anyar apa apalagi apartemen api apik apit aplikasi apresiasi apung arab
+arah arang area arif aromaterapi arsitek arsitektur arteri arti arus asah asal asap aset asih asin
+asing asli asosiasi aspirasi asrama asri astrologi asuh asuransi asyik atap atas atraksi atuh atur
+audiens awal awas awet ayah ayun
+baca badai badan bagai bagaimana bagi bagian bagus bahagia bahan baharu bahas bahasa bahaya bahu
+baik bait baja bajak bajik bakar bakat bakteri bakti balai balas balik baling balkon balok balut
+bandar banderol banding bandrol bangga bangkang bangkit bangku bangsa bangun banjir bank bantah
+bantu banyak bapa bapak bara barang barat bareng baris baru basah basis batal batang batas baterai
+batu bau bawa bawah baya bayang bayar bayi beban bebas bebat beber beda bedah begitu bekal bekas
+bekasi beku bela belah belai belakang belanja belas beli belit belok beluk belum benah benam benar
+bencana benci benda bendahara bendera bendung bengkak bengkel bengkok bening bentang bentuk benturan
+benua beranda berangkat berani berantakan berantas berapa berat beres beri beringin berisik berita
+beritahu berkah berkas berkat berlian bersih besar besi besok betah betapa betina beton betul biar
+biasa biaya bicara bidang bidik bijak bijaksana bikin bilang bilas bimbing bina bincang bingkai
+bingung bintang bisa bisik bising bisnis blender blokir bobol bobot bocor bohong boleh bolong
+bombardir bondong bongkar bonsai bor borong boros bosan botol buah buang buat budaya budi bujang
+bujuk buka bukan bukit bukti buku bulan bulat bully bulu bumi bunga bungkus bunuh bunyi bupati buru
+buruk burung busa busuk buta butuh
+cabang cabut cacat cadang cahaya cair cakap cakar cakup calon camat camil campur canang canda
+canggih cantik cantum cap capai capek cara cari cat catat cebur cecer cegah cek celah celaka celana
+celup cemar cemburu cemooh cenderung cengkrama cepat cerah cerai cerdas cerdik ceria cerita cermat
+cermin cerna cetak cetus cicil cinta cipta ciri cita cium coba cocok cokelat coklat colok contoh cor
+cuat cuci cukup culik cuma curah curam curang curi curiga
+dadak daerah daftar dagang daging dahulu daki dalam damai damba dampak dampar damping dampingi dana
+danau dapat dapur darah darat dari dasar data datang datar daulat daun daur daya debat debu debut
+dedikasi definisi deforestasi dekarbonisasi dekat dekor dekorasi delapan demam demikian demonstrasi
+denda dengan dengar depan derajat deras deret derita dermawan desa desain desak desinfeksi deskripsi
+destinasi detail deteksi developer dewan dewasa diagnosis diagonal dialog diam diameter diang didik
+difabel diferensiasi digit digital digitalisasi dikari diktator dimensi dinamika dinamis dinar dinas
+dinasti dinding dingin dini dipan diplomat direktorat direktur diri disinfeksi disiplin diskon
+diskusi distorsi distribusi distributor diversifikasi doa dobrak dokter dokumen dominan dominasi
+domisili dompet dongkrak dorong dosa drama dua duduk duga duka dukung dulu dunia durasi duri durian
+dusta duta
+edar edisi edit edukasi efektif efisien efisiensi eja ejek ekonomi ekor ekosistem eksekusi eksklusif
+ekspansi ekspektasi eksperimen eksplorasi ekspor ekspos ekspresi ekstensi elak elegan elektrifikasi
+elektrik eliminasi eluh emas emban embus emisi empat empati enak enam encer endap endus energi
+enggan engkau engsel entah entas erat erti estetika estimasi etalase evaluasi evolusi
+fakta fantasi fasilitas fasum favorit februari film filosofi fitur fluktuasi fokus fondasi formasi
+format fotografi fotokopi frekuensi fungsi furnitur
+gabung gadang gagal gagas gairah gaji gala galak gali galvanis gambar gambaran gampang ganda gandeng
+gandrung ganggu ganjar ganti gantung garansi garap garasi garis garuk gaul gaya gebrak gedung gejala
+gelap gelar gelegar geliat gelincir gelombang gelontor gelut gemar gemas gembira geming gempa gempar
+gemuk genang gencar generasi genggam gengsi genjot gerai gerak gerombol gesek geser giat gigil gigit
+gila giling gilir girang giring gitar giur global goda golong gores gosok gosong gotong goyah
+gradasi grafiti gubris gudang gugah gugur gula guling gulir gumul guna guncang guntur gunung gurih
+guru gusur
+habis hadap hadapi hadiah hadir hadirat hafal hak hakim hal halaman halang halau halus hamba hambat
+hampar hampir hancur handal hangat hanya hanyut hapus harap harga hari harmoni harmonis harmonisasi
+harta haru harum harus hasil hati hawa hebat heboh helat helm hemat hembus hendak henti heran hewan
+hias hibah hibur hidang hidup hijau hikmat hilang himbau himpun hindar hingga hinggap hisap hitam
+hitung hobi hormat hotel hubung hujan hukum hulu huni hunian hutan hutang
+ialah iba ibadah ibarat ibu idam ide ideal identifikasi identitas ikan ikat iklan iklim ikut ilham
+ilmu ilusi ilustrasi imajinasi iman imbal imbang imbas imbau imbuh impi impian implementasi
+implikasi impor inap incar inci indah indikasi induk industri industrialis inefisiensi infeksi
+inflasi info informasi infrastruktur ingat inggris ingin ingkar injak inokulasi inovasi insan
+insinyur inspirasi instal instalasi instan institusi instruksi instrumen intai integrasi intensif
+interaksi interior inti intim intimidasi intip intonasi invasi inventarisasi investasi investor
+iring iris isap isi islam isolasi istilah istimewa istirahat istri iuran iya izin
+jabar jabat jadi jadwal jaga jagat jahat jahit jajah jajal jajan jajar jaksa jalan jalar jalin jaman
+jamin jamu jangan jangka jangkau janji jantan januari jarak jaring jasa jati jatuh jauh jawa jawab
+jaya jebak jejak jejaring jelajah jelang jelas jelma jembatan jemput jemur jengkel jenis jenjang
+jerih jernih jerumus jiwa jodoh jual juang juara judul jujur juluk jumlah jumpa junjung juru jurus
+juta
+kabar kabul kabur kaca kacau kadang kadar kader kagum kain kaisar kait kaji kaki kaku kala kalah
+kalam kali kalian kaligrafi kalimantan kamar kamera kampung kamuflase kanak kanan kanca kandang
+kandidat kandung kanopi kantong kantor kapan kapasitas karakter karakteristik karang karena karier
+karir karunia karya karyawan kasa kasar kasih kata kategori kawal kawan kawasan kawin kaya kayu
+kebal kebon kebun kecap kecewa kecil kecimpung kecuali kedai kedap kediri kedok kejam kejar kejut
+kekal kelabu kelahi kelak kelam kelamin kelapa kelas keledai keliling kelinci kelok kelola kelompok
+keluar keluarga keluh kemarau kemas kembali kembang kemudi kemudian kena kenal kenan kenang kenari
+kendala kendali kendara kendati kenop kental kentang kenyang kepada kepala keponakan kerabat kerah
+kerak keramik kerap keras kerbau keren kereta kering keringat kerja keroncong kertas keruk kerut
+kesan ketat ketik khas khawatir khianat khotbah khusus kian kibar kilap kilas kilat kilau kini kipas
+kira kirim kisah kisar kisi kita klaim klasifikasi klaster klien klik kobar kocok kokoh kolaborasi
+kolam koleksi kolong kombinasi komentar komisi komitmen kompak kompensasi kompetensi kompetisi
+kompleks kompor komposisi komunikasi komunitas kondisi koneksi konferensi konfirmasi konsekuensi
+konsep konsisten konsistensi konsolidasi konstitusi konstruksi konsultan konsultasi konsumen
+konsumsi kontaminasi konteks kontrak kontraksi kontribusi kontrol kontur koordinasi koperasi koran
+korban koreksi korosi korupsi kos kosakata kosong kota kotor kreasi kreatif kredit kristen kuak
+kualifikasi kualitas kuas kuasa kuat kubik kubur kuda kukuh kuliah kulit kulkas kuman kumbang kumpul
+kunci kuning kunjung kupas kurang kuras kurasi kurban kurs kursi kurun kurung kutat kutip kutuk
+labuh lacak lafal lagi lahan lahir lain lajang laju lakban lakon laksana laku lalai lalu lama laman
+lamar lamban lambang lambat lamin lampau lampir lampu lancar landa landas langgan langgar langit
+langka langkah langsung lanjur lanjut lansir lantai lantaran lantik lapang lapis lapor larang laras
+lari laris larut lata latar latarbelakang latih laut lawan layak layan layang layar lebah lebar
+lebaran lebih legenda lejit lekat lelah lelang leleh leluasa lemah lemari lembab lembaga lembap
+lembar lembut lempar lengkap lengkung lenyap lepas lerai lesat lestari letak lewat lezat liar liat
+libat libur licin lihat liku lima limpah limpung lindung lingkar lingkung lintas lipat liput lirik
+lisan listing listrik literasi liuk lokasi lombok lompat lonjak lontar lowong luang luar luas lucu
+luhur luka lukis lulus lumayan lumpuh lunas luncur lupa lurah lurus luwes
+maaf macam macet madani mahal mahoni main majalah maju maka makan maki makin makmur makna maksimal
+maksud malah malam malas malu maluku mampu mana mandi mandiri manfaat mangkrak manis manja mantan
+mantap manusia mapan marah marak mari marin markas masa masak masalah masehi masif masih masuk
+masyarakat mata matahari matang matematika materi material mati mau maulid mebel medali medan media
+megah meja mekanik mekanisme mekar melati melodi memang mempelai menang menantu menara mendung menit
+mental mentari mentereng menteri merah meranti merdeka merek mereka meriah merpati mertua mesin
+meski mesti meter metode mewah migrasi miliar milik mimpi minat minggu minim minimal minimalisir
+minta minum minyak mirip misal miskin misteri mitigasi mitologi mitra modal model modern modifikasi
+mohon motif motivasi motor muat muda mudah mudik mujur muka mukim mula mulai mulia multi multifungsi
+mulut mumpuni munafik muncul mundur mungkin murah murid murni musim muslim musnah musuh mutiara
+mutlak mutu
+nada nafas nafik nafkah naik nakal nama nanti napas nasabah nasib nasihat naskah naung navigasi
+negara negatif negeri negosiasi nenek netral ngeri niat nikah nikmat nilai niscaya nista noda
+nominal nonaktif notifikasi nuansa nukil nutrisi nyala nyaman nyanyi nyata nyawa
+obat obrol okupansi olah olahraga oleh oles ombak omong ongkos operasi operasional opini optimal
+orang organisasi organisir orientasi ornamen otomasi otomatis otonom
+pabrik pacar pacu pada padam padan padat padu padupadan pagar pahala paham pahat pahit pahlawan
+pajak pajang pakai pakar paket paksa paku paling palsu pamer pamit panas pancar panda pandai pandan
+pandang pandemi pandu panen pangan pangeran panggil panggung pangkas pangkat pangku panjang panjat
+pantai pantang pantas pantau pantik pantul panut papa papan papar para parah park partai partisi
+partisipasi paruh pasal pasang pasar pasir pasok pasti patah patih patok patri patuh patuk patung
+patut payah pecah pedestrian pedoman peduli pegang pegawai pekan pekat pelan pelangi peleset
+pelihara pelita pelopor pelosok peluh peluk pemilu pemirsa pena penalti penasaran pencet pencil
+pendek pendeta penetrasi pengap pengaruh penjara pensil pensiun pentas penting penuh perabot perah
+perak peran perang perangkap perangkat peras percaya percik perdana perempuan performa pergi perihal
+perikop periksa perilaku peringkat perintah periode periodik peristiwa perjaka perkara perlu
+permanen permata permen pernah peroleh persen persentase persepsi persero persia persis personal
+pertama pertiwi perwira pesan pesantren pesat pesawat peserta pesona pesta peta petak petir picu
+pidato pihak pikat pikir pilah pilar pilih pimpin pindah pindai pinggir pinjam pintas pintu piring
+pirsa pisah pita plafon plester pohon poin pola polisi politik politisi polusi pondasi ponsel
+popularitas populasi populer porsi posisi positif potensi potong potret praktek praktik praktis
+prediksi preferensi premi presensi presentasi presiden presisi prestasi pribadi prihatin prinsip
+prioritas privasi produk produksi profesi program progres promosi propaganda properti proporsi
+prosedur proses provinsi provisi proyek proyeksi puas puasa pudar puisi puja puji pukau pukul pula
+pulang pulau pulih puluh puncak pungkas pungkir pungut punya pupuk pura puruk pusat pustaka putar
+putih putra putri putus
+rabu racik racun radiasi raga ragam ragu rahayu rahmat raih raja rajin rajut rakit rakyat rama
+ramadhan ramah ramai ramal rambah ramban rambat rambut rampung rana rancang rangka rangkai rangkap
+rangkul rangkum rangsang ranjang rapat rapi rasa rasuk rata ratap ratus raup rawan rawat raya rayap
+rayu realisasi realistis rebah rebut reda redaksi redam reduksi referensi refleksi registrasi
+regulasi rehabilitasi rejeki rekam rekan rekat rekayasa rekomendasi rekreasi rekrut relevan religi
+rem remaja rembes remuk renang rencana rendah rendam renggang renggut renovasi rentan renung replika
+repot representasi reputasi resah resap resensi resep reset resmi resolusi respon respons restoran
+restrukturisasi retak retas revisi revitalisasi rezeki rias ribu ribut rilis rinci rinding rindu
+ring ringan ringkas rintang rintih rintis risau risiko ritel ritual robek roboh rogoh rohani roket
+rokok romawi rombak rombong rompi rotan router ruang rugi rujuk rumah rumit rumus rundung runtuh
+runtun rupa rupiah rusa rusak rusuh rusuk rusun
+saat sabar sabda sablon sabun sadar sadur sahabat sahaja saham saing sajak saji sakit saksi sakti
+salah salam salib salih salur sama samak samar sambang sambar sambil sambung sambut sampah sampai
+samping sanak sandal sandar sandi sanding sang sangat sangga sangka sangkut sanitasi sanksi santai
+santap santri sapa sapu saran sarang sarapan sari saring sarung sasar satu saturasi saudara saudari
+sayang sayat sebab sebar sebarluas sebentar seberang sebut sedang sedap sederhana sedia sedih
+sedikit sedot segala segan segar segel segenap segera segi segitiga segmen segregasi sehat sejahtera
+sejak sejarah sejati sejuk sekaligus sekarang sekat sekian sekolah sekretaris seksi sektor sekuel
+sekutu sela selaku selam selamat selang selaras selat selatan selawat seleksi selenggara seleo
+selera selesai selidik selimut seling selip selisih seluk seluler seluruh semai semangat semat
+sembah sembarang sembilan sembuh sembunyi semen semenanjung sementara semesta semester seminar
+sempat sempit semprot sempurna semua semut senang senantiasa sendiri seng sengaja sengketa sengon
+sengsara seni senin senior senjang senjata sensitif sensor sentra sentral sentuh senyap senyum
+sepadan sepak sepakat separuh sepatu sepeda sepele seperti sepi september sepuh sepuluh serah seram
+serang serangga serap serasi serat serba serbaguna serbuk seremoni serentak seret seri serikat
+sering serius serta sertifikasi sertifikat seru sesak sesal sesat sesuai setan setara setel setia
+setor setrika setuju sewa shalat sia sial siap siapa siar siasat sibuk sidik sifat signifikan sikap
+sikat sila silakan silaturahmi simak simbol simpan simpang simpul simulasi sinambung sinar sinergi
+singgah singgung singkap singkat singkir sini siram sirat sirkulasi sisa sisi sisih sisik sisip
+sistem siswa siswi sita situ situasi skala skripsi soal soda sofa sokong solusi sopan sorot
+sosialisasi sosiologi sosok spekulasi spesial spesifikasi sprei stabil stagnan stagnasi standar
+status sterilisasi stimulus strategi strategis struktur studi suai suami suap suara suatu subsidi
+subur suci sudah sudut suguh suhu suka sukses suksesor suku sulap sulit sultan suluh sumbang sumber
+sumpah sunda sungai sungguh sunyi supaya suplai surat surut survei susah susu susul susun susut
+syarat syariah syukur
+taat tabrak tabung tabur tadi tafakur tafsir tagih tahajjud tahan tahap tahu tahun tajam tajuk taka
+takar takjub taksi taksir takut tali taman tambah tambang tampak tampik tampil tampung tanah tanak
+tanam tanda tandatangan tanding tandus tangan tangga tanggal tanggap tangguh tanggulang tanggung
+tangis tangkal tangkap tangki tani tanpa tantang tanya tapak tapi tara taraf target tari tarik taruh
+tarung tata tatap tawa tawan tawar tawon tayang tebak tebal tebang tebar teduh tegak tegang tegar
+tegas teguh tegur tekad tekan teken teknik teknis teknologi teks tekstur tekun teladan telah telan
+telantar telat telepon televisi telinga teliti teluk telur telusur tema teman tematis tembak tembok
+tembus tempat tempel temperatur tempo tempuh tempur temu tenaga tenang tenar tengah tenggara tenggat
+tengkar tengok tenor tentang tentram tentu teori tepat tepi tepuk tera terakota teralis terampil
+terang terap teras terbang terbit teriak terik terima terjemah terjun terkam termin terminal ternak
+terobos teropong teroris terowongan terpa tertib terus tes tetangga tetap tetapi tetes tiada tiap
+tiba tidak tidur tiga tikai tiket tilik timbang timbul timbun timpa timpal timpang tindak tindas
+tinggal tinggi tingkah tingkat tinjau tipis tips tipu tirai tiru titip tobat toilet toko tolak
+toleransi tolong tonjol tonton topang topografi total tradisi tragedi transaksi transformasi
+transisi transmigrasi transmisi transparan transparansi transportasi trik triliun triwulan tropis
+tua tuang tubi tubuh tuduh tugas tuhan tuju tujuh tukang tukar tulang tular tuli tulis tulus tumbang
+tumbuh tumpah tumpang tumpuk tumpul tunai tunda tunduh tunduk tunggak tunggu tungku tunjang tunjuk
+tunjung tuntas tuntut turun turut tutup tutur
+uang ubah ucap udara ujar uji ujicoba ujung ukir ukur ulang ular ulas ulik umpama umpan umum umur
+undang undi unduh unggah unggul ungkap ungsi ungu unik unit unjuk unsur untuk untung upaya urai
+urban urbanisasi urus urut usah usaha usang usap usia usir usul usung utama utang utara utilisasi
+utus
+vaksinasi validasi valuasi varian variasi variatif ventilasi verifikasi versi video visi
+wadah wahai wahyu wajah wajar wajib wakil waktu walaupun wali waralaba warga warganegara waris warna
+warni warta wartawan waspada wawancara wawasan wejangan wenang wibawa wilayah wirausaha wisata
+wisuda wujud
+yakin yang yayasan
+zalim zaman ziarah zona zonasi
+`
@@ -0,0 +1,667 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Schema index: daftar field dokumen beserta tipe, analyzer dan flag
+// indexed/stored. Field bawaan (title, content, url, date, author, language,
+// breadcrumb, doctype) selalu ada dengan tipe tetap; field tambahan diisi
+// lewat Article.Fields saat ingestion.
+//
+//	"schema": {"fields": [
+//	  {"name": "title", "type": "text", "analyzer": "language"},
+//	  {"name": "developer", "type": "keyword", "required": true},
+//	  {"name": "luas_tanah", "type": "numeric"},
+//	  {"name": "serah_terima", "type": "date"},
+//	  {"name": "lokasi", "type": "geo"},
+//	  {"name": "fasilitas", "type": "text", "analyzer": "id", "stored": false}
+//	]}
+//
+// Field text yang indexed ikut diindex ke inverted index dengan analyzer-nya.
+// Field keyword/numeric/date/geo yang indexed menjadi kolom doc values dan
+// bisa difilter dengan f.<nama>=...; field stored ikut dikembalikan di hasil
+// pencarian. Semua field tetap disimpan di corpus karena index dibangun ulang
+// dari corpus.
+const (
+	FieldText    = "text"
+	FieldKeyword = "keyword"
+	FieldDate    = "date"
+	FieldNumeric = "numeric"
+	FieldGeo     = "geo"
+)
+
+// Analyzer field text; "language" memakai bahasa dokumen (terdeteksi otomatis)
+const (
+	AnalyzerLanguage = "language"
+	AnalyzerID       = LangIndonesian
+	AnalyzerEN       = LangEnglish
+)
+
+// Prefix parameter filter field schema: f.developer=ciputra
+const SCHEMA_FILTER_PREFIX = "f."
+
+// Rule FieldError dari schema
+const (
+	RuleType         = "type"
+	RuleUnknownField = "unknown_field"
+)
+
+type SchemaField struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Analyzer string `json:"analyzer,omitempty"` // hanya field text
+	Indexed  bool   `json:"indexed"`
+	Stored   bool   `json:"stored"`
+	Required bool   `json:"required,omitempty"`
+	// Aturan kata majemuk, hanya field text; lihat compounds.go
+	Compounds []string `json:"compounds,omitempty"`
+}
+
+// indexed dan stored default true kalau tidak ditulis di config
+func (f *SchemaField) UnmarshalJSON(data []byte) error {
+	type plain SchemaField
+	field := plain{Indexed: true, Stored: true}
+	if err := json.Unmarshal(data, &field); err != nil {
+		return err
+	}
+	*f = SchemaField(field)
+	return nil
+}
+
+type IndexSchema struct {
+	Fields []SchemaField `json:"fields"`
+	// Field yang tidak dideklarasikan: strict, map atau ignore, lihat dynamicmapping.go
+	Dynamic string `json:"dynamic"`
+}
+
+// Koordinat field geo
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Field bawaan Article dengan tipenya; urutan ini juga urutan di schema
+var builtinFields = []SchemaField{
+	{Name: "title", Type: FieldText, Analyzer: AnalyzerLanguage, Indexed: true, Stored: true},
+	{Name: "content", Type: FieldText, Analyzer: AnalyzerLanguage, Indexed: true, Stored: true},
+	{Name: "url", Type: FieldKeyword, Indexed: true, Stored: true},
+	{Name: "date", Type: FieldDate, Indexed: true, Stored: true},
+	{Name: "author", Type: FieldKeyword, Indexed: true, Stored: true},
+	{Name: "language", Type: FieldKeyword, Indexed: true, Stored: true},
+	{Name: "breadcrumb", Type: FieldKeyword, Indexed: true, Stored: true},
+	{Name: "doctype", Type: FieldKeyword, Indexed: true, Stored: true},
+}
+
+func defaultIndexSchema() IndexSchema {
+	fields := make([]SchemaField, len(builtinFields))
+	copy(fields, builtinFields)
+	return IndexSchema{Fields: fields, Dynamic: DynamicStrict}
+}
+
+func builtinField(name string) (SchemaField, bool) {
+	for _, field := range builtinFields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return SchemaField{}, false
+}
+
+// Cek schema dari config lalu lengkapi dengan field bawaan yang tidak
+// ditulis. Field bawaan hanya boleh mengubah analyzer, indexed (title dan
+// content) dan required.
+func (s *IndexSchema) compile() error {
+	switch s.Dynamic {
+	case "":
+		s.Dynamic = DynamicStrict
+	case DynamicStrict, DynamicMap, DynamicIgnore:
+	default:
+		return fmt.Errorf("unknown dynamic mode %q (strict, map or ignore)", s.Dynamic)
+	}
+
+	seen := make(map[string]bool)
+	var fields []SchemaField
+	for _, field := range s.Fields {
+		if field.Name == "" {
+			return fmt.Errorf("field without name")
+		}
+		if strings.HasPrefix(field.Name, SCHEMA_FILTER_PREFIX) || strings.ContainsAny(field.Name, " =&") {
+			return fmt.Errorf("invalid field name %q", field.Name)
+		}
+		if seen[field.Name] {
+			return fmt.Errorf("duplicate field %q", field.Name)
+		}
+		seen[field.Name] = true
+
+		switch field.Type {
+		case FieldText:
+			if field.Analyzer == "" {
+				field.Analyzer = AnalyzerLanguage
+			}
+			if field.Analyzer != AnalyzerLanguage && field.Analyzer != AnalyzerID && field.Analyzer != AnalyzerEN {
+				return fmt.Errorf("field %q: unknown analyzer %q", field.Name, field.Analyzer)
+			}
+			if _, err := compileCompoundRules(field.Compounds); err != nil {
+				return fmt.Errorf("field %q: %v", field.Name, err)
+			}
+		case FieldKeyword, FieldDate, FieldNumeric, FieldGeo:
+			if field.Analyzer != "" {
+				return fmt.Errorf("field %q: analyzer is only valid for text fields", field.Name)
+			}
+			if len(field.Compounds) > 0 {
+				return fmt.Errorf("field %q: compounds is only valid for text fields", field.Name)
+			}
+		default:
+			return fmt.Errorf("field %q: unknown type %q", field.Name, field.Type)
+		}
+
+		if builtin, exists := builtinField(field.Name); exists {
+			if field.Type != builtin.Type {
+				return fmt.Errorf("field %q is built in with type %s", field.Name, builtin.Type)
+			}
+			if builtin.Type != FieldText {
+				field.Indexed = true
+			}
+			field.Stored = true
+		}
+		fields = append(fields, field)
+	}
+
+	for _, builtin := range builtinFields {
+		if !seen[builtin.Name] {
+			fields = append(fields, builtin)
+		}
+	}
+	s.Fields = fields
+	return nil
+}
+
+// Field dari config, lalu field yang dipetakan otomatis
+func (s IndexSchema) Field(name string) (SchemaField, bool) {
+	for _, field := range s.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return dynamicMappings.Field(name)
+}
+
+// Field tambahan (bukan bawaan Article), termasuk yang dipetakan otomatis
+func (s IndexSchema) customFields() []SchemaField {
+	var fields []SchemaField
+	declared := make(map[string]bool)
+	for _, field := range s.Fields {
+		declared[field.Name] = true
+		if _, builtin := builtinField(field.Name); !builtin {
+			fields = append(fields, field)
+		}
+	}
+	for _, field := range dynamicMappings.List() {
+		if !declared[field.Name] {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Nilai field tambahan dari JSON: text/keyword string, numeric angka, date
+// "2006-01-02" atau RFC 3339 (unix seconds), geo "lat,lon" atau {"lat", "lon"}
+func parseFieldValue(field SchemaField, value interface{}) (interface{}, error) {
+	switch field.Type {
+	case FieldText, FieldKeyword:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("must be a string")
+	case FieldNumeric:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		}
+		return nil, fmt.Errorf("must be a number")
+	case FieldDate:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a date string (2006-01-02 or RFC 3339)")
+		}
+		for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+			if t, err := time.Parse(layout, s); err == nil {
+				return float64(t.Unix()), nil
+			}
+		}
+		return nil, fmt.Errorf("must be a date string (2006-01-02 or RFC 3339)")
+	case FieldGeo:
+		var point GeoPoint
+		switch v := value.(type) {
+		case string:
+			p, err := parseGeoPoint(v)
+			if err != nil {
+				return nil, err
+			}
+			point = p
+		case map[string]interface{}:
+			lat, latOK := v["lat"].(float64)
+			lon, lonOK := v["lon"].(float64)
+			if !latOK || !lonOK {
+				return nil, fmt.Errorf(`must be "lat,lon" or {"lat": ..., "lon": ...}`)
+			}
+			point = GeoPoint{Lat: lat, Lon: lon}
+		case GeoPoint:
+			point = v
+		default:
+			return nil, fmt.Errorf(`must be "lat,lon" or {"lat": ..., "lon": ...}`)
+		}
+		if point.Lat < -90 || point.Lat > 90 || point.Lon < -180 || point.Lon > 180 {
+			return nil, fmt.Errorf("coordinates out of range")
+		}
+		return point, nil
+	}
+	return nil, fmt.Errorf("unknown type %q", field.Type)
+}
+
+func parseGeoPoint(s string) (GeoPoint, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return GeoPoint{}, fmt.Errorf(`must be "lat,lon"`)
+	}
+	lat, err1 := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	lon, err2 := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err1 != nil || err2 != nil {
+		return GeoPoint{}, fmt.Errorf(`must be "lat,lon"`)
+	}
+	return GeoPoint{Lat: lat, Lon: lon}, nil
+}
+
+// Pelanggaran schema untuk satu artikel: field tambahan yang tidak
+// dideklarasikan (sesuai mode dynamic), tipe nilai yang salah dan field
+// required yang kosong
+func (s IndexSchema) validateArticle(article *Article) []FieldError {
+	var errs []FieldError
+	for _, field := range s.Fields {
+		if !field.Required {
+			continue
+		}
+		if _, builtin := builtinField(field.Name); builtin {
+			if value, known := articleField(article, field.Name); known && strings.TrimSpace(value) == "" {
+				errs = append(errs, FieldError{Field: field.Name, Rule: RuleRequired, Message: "is required"})
+			}
+			continue
+		}
+		if value, exists := article.Fields[field.Name]; !exists || value == nil || value == "" {
+			errs = append(errs, FieldError{Field: field.Name, Rule: RuleRequired, Message: "is required"})
+		}
+	}
+
+	for _, name := range sortedFieldNames(article.Fields) {
+		field, exists := s.Field(name)
+		if _, builtin := builtinField(name); builtin {
+			errs = append(errs, FieldError{Field: name, Rule: RuleUnknownField, Message: "is a built-in field, set it on the article itself"})
+			continue
+		}
+		if !exists {
+			if err := s.unknownField(name, article.Fields[name]); err != nil {
+				errs = append(errs, *err)
+			}
+			continue
+		}
+		if article.Fields[name] == nil {
+			continue
+		}
+		if _, err := parseFieldValue(field, article.Fields[name]); err != nil {
+			errs = append(errs, FieldError{Field: name, Rule: RuleType, Message: fmt.Sprintf("%s field %v", field.Type, err)})
+		}
+	}
+	return errs
+}
+
+// Bagian teks dokumen yang diindex dengan satu analyzer (byte offset)
+type textSegment struct {
+	start, end int
+	analyzer   string
+	rules      CompoundRules
+}
+
+// Teks yang dianalisis untuk satu dokumen: title + " " + content, teks tabel
+// artikel (dianalisis seperti content) lalu field text tambahan yang indexed.
+// Title dan content selalu ada supaya offset snippet tetap sama; segmen
+// menandai bagian yang benar-benar diindex.
+func (s IndexSchema) documentText(article Article) (string, []textSegment) {
+	var b strings.Builder
+	var segments []textSegment
+	add := func(field SchemaField, value string) {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		start := b.Len()
+		b.WriteString(value)
+		if field.Indexed && value != "" {
+			segments = append(segments, textSegment{start, b.Len(), field.Analyzer, field.compoundRules()})
+		}
+	}
+
+	title, _ := s.Field("title")
+	content, _ := s.Field("content")
+	b.WriteString(article.Title)
+	if title.Indexed && article.Title != "" {
+		segments = append(segments, textSegment{0, b.Len(), title.Analyzer, title.compoundRules()})
+	}
+	add(content, article.Content)
+	if len(article.Tables) > 0 {
+		add(content, tablesText(article.Tables))
+	}
+	for _, field := range s.customFields() {
+		if field.Type != FieldText || !field.Indexed {
+			continue
+		}
+		if value, ok := article.Fields[field.Name].(string); ok {
+			add(field, value)
+		}
+	}
+	return b.String(), segments
+}
+
+// Analisis dokumen sesuai schema. Kalau title dan content diindex dan semua
+// segmen memakai analyzer dan aturan kata majemuk yang sama, teks
+// dianalisis sekali seperti biasa.
+// Posisi token dihitung atas seluruh teks, jadi phrase query tetap konsisten.
+func (s IndexSchema) analyzeDocument(buf []Token, article Article) []Token {
+	text, segments := s.documentText(article)
+	analyzerOf := func(name string) *TextProcessor {
+		if name == AnalyzerLanguage {
+			return analyzerFor(article.Language)
+		}
+		return analyzerFor(name)
+	}
+
+	title, _ := s.Field("title")
+	content, _ := s.Field("content")
+	single := title.Indexed && content.Indexed
+	for _, segment := range segments {
+		if analyzerOf(segment.analyzer) != analyzerOf(segments[0].analyzer) || segment.rules != segments[0].rules {
+			single = false
+		}
+	}
+	if single && len(segments) > 0 {
+		return analyzerOf(segments[0].analyzer).AnalyzeCompounds(buf, text, segments[0].rules)
+	}
+
+	type pipeline struct {
+		analyzer *TextProcessor
+		rules    CompoundRules
+	}
+	tokens := buf[:0]
+	done := make(map[pipeline]bool)
+	for _, segment := range segments {
+		p := pipeline{analyzerOf(segment.analyzer), segment.rules}
+		if done[p] {
+			continue
+		}
+		done[p] = true
+		for _, tok := range p.analyzer.AnalyzeCompounds(nil, text, p.rules) {
+			for _, other := range segments {
+				if tok.Start >= other.start && tok.Start < other.end && (pipeline{analyzerOf(other.analyzer), other.rules}) == p {
+					tokens = append(tokens, tok)
+					break
+				}
+			}
+		}
+	}
+	sort.SliceStable(tokens, func(i, j int) bool { return tokens[i].Start < tokens[j].Start })
+	return tokens
+}
+
+// Kolom doc values untuk field tambahan keyword/numeric/date/geo yang
+// indexed. Nilai yang kosong atau tidak valid: "" untuk keyword, NaN untuk
+// numeric/date/geo, sehingga tidak pernah lolos filter.
+func (s IndexSchema) buildFieldValues(dv *DocValues, articles []Article) {
+	for _, field := range s.customFields() {
+		if !field.Indexed || field.Type == FieldText {
+			continue
+		}
+		switch field.Type {
+		case FieldKeyword:
+			if dv.Keywords == nil {
+				dv.Keywords = make(map[string][]string)
+			}
+			column := make([]string, len(articles))
+			for i, article := range articles {
+				if value, err := parseFieldValue(field, article.Fields[field.Name]); err == nil {
+					column[i] = strings.TrimSpace(value.(string))
+				}
+			}
+			dv.Keywords[field.Name] = column
+		case FieldNumeric, FieldDate:
+			if dv.Numbers == nil {
+				dv.Numbers = make(map[string][]float64)
+			}
+			column := make([]float64, len(articles))
+			for i, article := range articles {
+				column[i] = math.NaN()
+				if value, err := parseFieldValue(field, article.Fields[field.Name]); err == nil {
+					column[i] = value.(float64)
+				}
+			}
+			dv.Numbers[field.Name] = column
+		case FieldGeo:
+			if dv.Points == nil {
+				dv.Points = make(map[string][]GeoPoint)
+			}
+			column := make([]GeoPoint, len(articles))
+			for i, article := range articles {
+				column[i] = GeoPoint{Lat: math.NaN(), Lon: math.NaN()}
+				if value, err := parseFieldValue(field, article.Fields[field.Name]); err == nil {
+					column[i] = value.(GeoPoint)
+				}
+			}
+			dv.Points[field.Name] = column
+		}
+	}
+}
+
+// Nama kolom field schema di doc values, terurut
+func (dv *DocValues) fieldNames() []string {
+	var names []string
+	for name := range dv.Keywords {
+		names = append(names, name)
+	}
+	for name := range dv.Numbers {
+		names = append(names, name)
+	}
+	for name := range dv.Points {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Field tambahan yang stored, untuk hasil pencarian
+func (s IndexSchema) storedFields(article Article) map[string]interface{} {
+	var stored map[string]interface{}
+	for _, field := range s.customFields() {
+		value, exists := article.Fields[field.Name]
+		if !field.Stored || !exists || value == nil {
+			continue
+		}
+		if stored == nil {
+			stored = make(map[string]interface{})
+		}
+		stored[field.Name] = value
+	}
+	return stored
+}
+
+// Klausa filter field schema. Nilai filter:
+//   - keyword: nilai persis (tidak peka huruf besar/kecil)
+//   - numeric: "100", "100..500", "100.." atau "..500"
+//   - date: sama dengan numeric dengan tanggal 2006-01-02
+//   - geo: "lat,lon,km", dokumen dalam radius km dari titik itu
+//
+// Field yang tidak ada di schema atau nilai yang tidak bisa dibaca tidak
+// cocok dengan dokumen mana pun.
+func (s IndexSchema) fieldClauses(filters map[string]string) []filterClause {
+	names := make([]string, 0, len(filters))
+	for name := range filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var clauses []filterClause
+	for _, name := range names {
+		value := strings.TrimSpace(filters[name])
+		if value == "" {
+			continue
+		}
+		key := SCHEMA_FILTER_PREFIX + name + "=" + value
+		field, exists := s.Field(name)
+		if _, builtin := builtinField(name); !exists || builtin || !field.Indexed {
+			clauses = append(clauses, filterClause{key, matchNone})
+			continue
+		}
+
+		switch field.Type {
+		case FieldKeyword:
+			clauses = append(clauses, filterClause{SCHEMA_FILTER_PREFIX + name + "=" + strings.ToLower(value), func(dv *DocValues, docID int) bool {
+				return strings.EqualFold(dv.Keywords[name][docID], value)
+			}})
+		case FieldNumeric, FieldDate:
+			min, max, err := parseRange(field, value)
+			if err != nil {
+				clauses = append(clauses, filterClause{key, matchNone})
+				continue
+			}
+			clauses = append(clauses, filterClause{key, func(dv *DocValues, docID int) bool {
+				v := dv.Numbers[name][docID]
+				return v >= min && v <= max
+			}})
+		case FieldGeo:
+			center, radius, err := parseGeoFilter(value)
+			if err != nil {
+				clauses = append(clauses, filterClause{key, matchNone})
+				continue
+			}
+			clauses = append(clauses, filterClause{key, func(dv *DocValues, docID int) bool {
+				point := dv.Points[name][docID]
+				return !math.IsNaN(point.Lat) && geoDistanceKm(center, point) <= radius
+			}})
+		default:
+			clauses = append(clauses, filterClause{key, matchNone})
+		}
+	}
+	return clauses
+}
+
+func matchNone(dv *DocValues, docID int) bool {
+	return false
+}
+
+// "min..max" dengan salah satu sisi boleh kosong, atau satu nilai persis
+func parseRange(field SchemaField, value string) (float64, float64, error) {
+	parse := func(s string) (float64, error) {
+		if field.Type == FieldDate {
+			t, err := time.Parse("2006-01-02", s)
+			if err != nil {
+				return 0, err
+			}
+			return float64(t.Unix()), nil
+		}
+		return strconv.ParseFloat(s, 64)
+	}
+
+	if !strings.Contains(value, "..") {
+		v, err := parse(value)
+		if err != nil {
+			return 0, 0, err
+		}
+		if field.Type == FieldDate {
+			// Tanggal persis berarti sepanjang hari itu
+			return v, v + 24*60*60 - 1, nil
+		}
+		return v, v, nil
+	}
+
+	parts := strings.SplitN(value, "..", 2)
+	min, max := math.Inf(-1), math.Inf(1)
+	var err error
+	if parts[0] != "" {
+		if min, err = parse(parts[0]); err != nil {
+			return 0, 0, err
+		}
+	}
+	if parts[1] != "" {
+		if max, err = parse(parts[1]); err != nil {
+			return 0, 0, err
+		}
+		if field.Type == FieldDate {
+			max += 24*60*60 - 1
+		}
+	}
+	return min, max, nil
+}
+
+// "lat,lon,km"
+func parseGeoFilter(value string) (GeoPoint, float64, error) {
+	i := strings.LastIndex(value, ",")
+	if i < 0 {
+		return GeoPoint{}, 0, fmt.Errorf(`must be "lat,lon,km"`)
+	}
+	center, err := parseGeoPoint(value[:i])
+	if err != nil {
+		return GeoPoint{}, 0, err
+	}
+	radius, err := strconv.ParseFloat(strings.TrimSpace(value[i+1:]), 64)
+	if err != nil || radius <= 0 {
+		return GeoPoint{}, 0, fmt.Errorf(`must be "lat,lon,km"`)
+	}
+	return center, radius, nil
+}
+
+// Jarak great-circle (haversine) dalam km
+func geoDistanceKm(a, b GeoPoint) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(b.Lat - a.Lat)
+	dLon := toRad(b.Lon - a.Lon)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(a.Lat))*math.Cos(toRad(b.Lat))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// Filter f.<nama>=nilai dari query string
+func schemaFilters(values map[string][]string) map[string]string {
+	var filters map[string]string
+	for key, value := range values {
+		if !strings.HasPrefix(key, SCHEMA_FILTER_PREFIX) || len(value) == 0 || value[0] == "" {
+			continue
+		}
+		if filters == nil {
+			filters = make(map[string]string)
+		}
+		filters[strings.TrimPrefix(key, SCHEMA_FILTER_PREFIX)] = value[0]
+	}
+	return filters
+}
+
+// GET /api/admin/schema: schema index yang berlaku beserta field yang
+// dipetakan otomatis
+func schemaHandler(c *gin.Context) {
+	schema := requestConfig(c).Schema
+	c.JSON(http.StatusOK, gin.H{
+		"fields":  schema.Fields,
+		"dynamic": schema.Dynamic,
+		"mapped":  dynamicMappings.List(),
+	})
+}
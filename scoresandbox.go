@@ -0,0 +1,375 @@
+package main
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sandbox scoring: POST /api/_score menghitung skor satu dokumen untuk satu
+// query dengan setiap ranker (cosine, jaccard, bm25) berdampingan, lengkap dengan
+// kontribusi tiap term, tanpa mengubah corpus.
+//
+//	{"query": "rumah subsidi bekasi", "doc_id": 12}
+//	{"query": "rumah subsidi bekasi", "document": {"title": "...", "content": "...", "url": "..."}}
+//
+// Statistik corpus (jumlah dokumen, document frequency) diambil dari index
+// utama. Dokumen eksplisit dihitung seolah sudah diindex: jumlah dokumen +1
+// dan df +1 untuk term yang dimuatnya. Untuk doc_id yang diindex, skornya
+// sama dengan skor di hasil pencarian sebelum boost tier lain (arsip,
+// terjemahan).
+const (
+	RankerCosine  = "cosine"
+	RankerJaccard = "jaccard"
+	RankerBM25    = "bm25"
+
+	SCORE_MAX_DOCUMENT = 1 << 20 // byte title + content
+)
+
+var scoreRankers = []string{RankerCosine, RankerJaccard, RankerBM25}
+
+type ScoreRequest struct {
+	Query    string   `json:"query"`
+	DocID    *int     `json:"doc_id"`
+	Document *Article `json:"document"`
+}
+
+// Kontribusi satu term query
+type ScoreTerm struct {
+	Term         string  `json:"term"`
+	QueryWeight  float64 `json:"query_weight"` // setelah normalisasi (cosine) atau bobot mentah
+	Frequency    int     `json:"frequency"`    // di dokumen
+	DocFrequency int     `json:"doc_frequency"`
+	IDF          float64 `json:"idf"`
+	Contribution float64 `json:"contribution"`
+}
+
+// Skor dokumen (atau satu paragraf) untuk satu ranker
+type RankerScore struct {
+	Score float64     `json:"score"`
+	Terms []ScoreTerm `json:"terms"`
+	// Cosine: panjang vektor TF-IDF dokumen
+	DocNorm float64 `json:"doc_norm,omitempty"`
+	// Jaccard: |query ∩ dokumen| / |query ∪ dokumen|
+	Intersection int `json:"intersection,omitempty"`
+	Union        int `json:"union,omitempty"`
+	// BM25: panjang dokumen dalam token dan rata-rata panjang corpus
+	DocLength    int     `json:"doc_length,omitempty"`
+	AvgDocLength float64 `json:"avg_doc_length,omitempty"`
+}
+
+type ParagraphScore struct {
+	Ordinal int `json:"ordinal"`
+	RankerScore
+}
+
+type RankerBreakdown struct {
+	Ranker   string      `json:"ranker"`
+	Score    float64     `json:"score"` // skor akhir seperti di hasil pencarian
+	Document RankerScore `json:"document"`
+	// Paragraf terbaik kalau paragraph_index aktif; skor artikel diambil dari sini
+	Paragraph      *ParagraphScore `json:"paragraph,omitempty"`
+	AuthorityBoost float64         `json:"authority_boost"`
+	ProximityBoost float64         `json:"proximity_boost"`
+	// Authority dari link graph, lihat linkgraph.go
+	LinkAuthorityBoost float64 `json:"link_authority_boost"`
+}
+
+type ScoreResponse struct {
+	Query       string             `json:"query"`
+	QueryVector map[string]float64 `json:"query_vector"`
+	DocID       *int               `json:"doc_id,omitempty"`
+	Title       string             `json:"title"`
+	Language    string             `json:"language"`
+	Source      string             `json:"source"`
+	Length      DocLength          `json:"length"`
+	// false untuk dokumen eksplisit dan doc_id yang ditahan review/tidak diindex
+	Indexed bool `json:"indexed"`
+	// Dokumen memuat minimal satu term query; kalau tidak, tidak pernah jadi kandidat
+	Matched bool `json:"matched"`
+	// Phrase dalam tanda kutip di query dan apakah dokumen memuat semuanya
+	// persis. Di pencarian, skor hasil yang cocok dinaikkan di atas hasil
+	// lain, jadi skor di bawah belum termasuk kenaikan itu.
+	Phrases     []string          `json:"phrases,omitempty"`
+	PhraseMatch bool              `json:"phrase_match,omitempty"`
+	Rankers     []RankerBreakdown `json:"rankers"`
+	Messages    []string          `json:"messages,omitempty"`
+}
+
+// Statistik corpus untuk scoring dokumen yang mungkin belum ada di index
+type scoreCorpus struct {
+	idx       *InvertedIndex
+	totalDocs int
+	extra     bool // dokumen belum diindex: df +1 untuk term-nya
+	bm25      BM25Params
+}
+
+func (sc scoreCorpus) docFrequency(term string, inDocument bool) int {
+	df := sc.idx.docFrequency(term)
+	if sc.extra && inDocument {
+		df++
+	}
+	return df
+}
+
+// Frekuensi term dokumen, dianalisis persis seperti saat index
+func documentTerms(article Article, schema IndexSchema) (map[string]int, DocLength) {
+	tokens := schema.analyzeDocument(nil, article)
+	frequencies := make(map[string]int)
+	for _, tok := range tokens {
+		frequencies[tok.Term]++
+	}
+	return frequencies, docLengthOf(tokens, len(article.Title))
+}
+
+// Cosine seperti cosineScores, per term
+func cosineBreakdown(queryVector map[string]float64, frequencies map[string]int, corpus scoreCorpus) RankerScore {
+	var norm float64
+	for term, frequency := range frequencies {
+		weight := float64(frequency) * idf(corpus.totalDocs, corpus.docFrequency(term, true))
+		norm += weight * weight
+	}
+	score := RankerScore{Terms: []ScoreTerm{}, DocNorm: math.Sqrt(norm)}
+
+	for term, queryWeight := range normalizeVector(queryVector) {
+		frequency := frequencies[term]
+		df := corpus.docFrequency(term, frequency > 0)
+		item := ScoreTerm{Term: term, QueryWeight: queryWeight, Frequency: frequency, DocFrequency: df}
+		if df > 0 {
+			item.IDF = idf(corpus.totalDocs, df)
+		}
+		if frequency > 0 && score.DocNorm > 0 {
+			item.Contribution = queryWeight * float64(frequency) * item.IDF / score.DocNorm
+			score.Score += item.Contribution
+		}
+		score.Terms = append(score.Terms, item)
+	}
+	sortScoreTerms(score.Terms)
+	return score
+}
+
+// Jaccard seperti jaccardScores: term query yang tidak ada di index tidak
+// pernah dihitung sebagai irisan
+func jaccardBreakdown(queryVector map[string]float64, frequencies map[string]int, corpus scoreCorpus) RankerScore {
+	score := RankerScore{Terms: []ScoreTerm{}}
+	for term, queryWeight := range queryVector {
+		frequency := frequencies[term]
+		df := corpus.docFrequency(term, frequency > 0)
+		item := ScoreTerm{Term: term, QueryWeight: queryWeight, Frequency: frequency, DocFrequency: df}
+		if frequency > 0 && df > 0 {
+			score.Intersection++
+		}
+		score.Terms = append(score.Terms, item)
+	}
+	if score.Intersection > 0 {
+		score.Union = len(queryVector) + len(frequencies) - score.Intersection
+		score.Score = float64(score.Intersection) / float64(score.Union)
+		for i := range score.Terms {
+			if score.Terms[i].Frequency > 0 && score.Terms[i].DocFrequency > 0 {
+				score.Terms[i].Contribution = 1 / float64(score.Union)
+			}
+		}
+	}
+	sortScoreTerms(score.Terms)
+	return score
+}
+
+// BM25 seperti bm25Scores. Dokumen yang belum diindex ikut dihitung di
+// jumlah dokumen dan rata-rata panjang.
+func bm25Breakdown(queryVector map[string]float64, frequencies map[string]int, corpus scoreCorpus) RankerScore {
+	score := RankerScore{Terms: []ScoreTerm{}}
+	for _, frequency := range frequencies {
+		score.DocLength += frequency
+	}
+	totalDocs, totalTokens := corpus.idx.DocCount, corpus.idx.TotalTokens
+	if corpus.extra {
+		totalDocs++
+		totalTokens += score.DocLength
+	}
+	if totalDocs > 0 {
+		score.AvgDocLength = float64(totalTokens) / float64(totalDocs)
+	}
+
+	for term, queryWeight := range queryVector {
+		frequency := frequencies[term]
+		df := corpus.docFrequency(term, frequency > 0)
+		item := ScoreTerm{Term: term, QueryWeight: queryWeight, Frequency: frequency, DocFrequency: df}
+		if df > 0 {
+			item.IDF = bm25IDF(totalDocs, df)
+		}
+		if frequency > 0 {
+			item.Contribution = queryWeight * item.IDF * corpus.bm25.termWeight(frequency, score.DocLength, score.AvgDocLength)
+			score.Score += item.Contribution
+		}
+		score.Terms = append(score.Terms, item)
+	}
+	sortScoreTerms(score.Terms)
+	return score
+}
+
+func sortScoreTerms(terms []ScoreTerm) {
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Contribution != terms[j].Contribution {
+			return terms[i].Contribution > terms[j].Contribution
+		}
+		return terms[i].Term < terms[j].Term
+	})
+}
+
+func rankerBreakdown(ranker string, queryVector map[string]float64, frequencies map[string]int, corpus scoreCorpus) RankerScore {
+	switch ranker {
+	case RankerJaccard:
+		return jaccardBreakdown(queryVector, frequencies, corpus)
+	case RankerBM25:
+		return bm25Breakdown(queryVector, frequencies, corpus)
+	}
+	return cosineBreakdown(queryVector, frequencies, corpus)
+}
+
+// Skor dokumen untuk query dengan setiap ranker
+func scoreDocument(query string, article Article, docID int, cfg *Config) ScoreResponse {
+	articles, idx, _ := loadIndex()
+	indexed := docID >= 0 && docID < len(idx.Allowed) && idx.Allowed[docID]
+	if article.Language == "" {
+		article.Language = detectLanguage(article.Title + " " + article.Content)
+	}
+
+	queryVector := buildQueryVector(query)
+	synonyms.Expand(queryVector)
+	frequencies, length := documentTerms(article, cfg.Schema)
+
+	res := ScoreResponse{
+		Query:       query,
+		QueryVector: queryVector,
+		Title:       article.Title,
+		Language:    article.Language,
+		Source:      sourceOf(article.URL),
+		Length:      length,
+		Indexed:     indexed,
+		Rankers:     []RankerBreakdown{},
+	}
+	if docID >= 0 {
+		res.DocID = &docID
+	}
+	for term := range queryVector {
+		if frequencies[term] > 0 {
+			res.Matched = true
+		}
+	}
+	positions := tokenPositions(cfg.Schema.analyzeDocument(nil, article))
+	if phrases := queryPhraseTerms(query); len(phrases) > 0 {
+		res.Phrases = queryPhrases(query)
+		res.PhraseMatch = containsPhrases(phrases, positions)
+	}
+	if !indexed {
+		res.Messages = append(res.Messages, "document is not in the index; corpus statistics include it as one extra document")
+	}
+	if blocklist.IsURLBlocked(article.URL) {
+		res.Messages = append(res.Messages, "document is blocklisted and never returned")
+	}
+
+	corpus := scoreCorpus{idx: idx, totalDocs: len(articles), extra: !indexed, bm25: cfg.BM25}
+	if docID < 0 {
+		corpus.totalDocs++
+	}
+	boost := 1.0
+	if value, exists := authorityBoosts(cfg.AuthorityWeight)[res.Source]; exists {
+		boost = value
+	}
+	proximity := cfg.Proximity.multiplier(proximityTerms(queryVector), queryVector, positions)
+	linkBoost := linkAuthorityBoost(linkAuthorities(cfg.LinkAuthorityWeight), cfg.LinkAuthorityWeight, article.URL)
+
+	var paragraphs []string
+	if idx.Paragraphs != nil {
+		paragraphs = splitParagraphs(article.Content, idx.Paragraphs.MinWords)
+		if len(paragraphs) == 0 {
+			paragraphs = []string{""}
+		}
+	}
+
+	for _, ranker := range scoreRankers {
+		breakdown := RankerBreakdown{
+			Ranker:             ranker,
+			Document:           rankerBreakdown(ranker, queryVector, frequencies, corpus),
+			AuthorityBoost:     boost,
+			ProximityBoost:     proximity,
+			LinkAuthorityBoost: linkBoost,
+		}
+		breakdown.Score = breakdown.Document.Score
+
+		if paragraphs != nil {
+			// Paragraf di-score terhadap index paragraf, sama seperti bestPassages
+			paragraphCorpus := scoreCorpus{idx: idx.Paragraphs.Index, totalDocs: len(idx.Paragraphs.Paragraphs), extra: !indexed, bm25: cfg.BM25}
+			if !indexed {
+				paragraphCorpus.totalDocs += len(paragraphs)
+			}
+			for ordinal, text := range paragraphs {
+				paragraphTerms, _ := documentTerms(Article{Title: article.Title, Content: text, Language: article.Language}, cfg.Schema)
+				score := rankerBreakdown(ranker, queryVector, paragraphTerms, paragraphCorpus)
+				if breakdown.Paragraph == nil || score.Score > breakdown.Paragraph.Score {
+					breakdown.Paragraph = &ParagraphScore{Ordinal: ordinal, RankerScore: score}
+				}
+			}
+			breakdown.Score = breakdown.Paragraph.Score
+		}
+		breakdown.Score *= boost * proximity * linkBoost
+		res.Rankers = append(res.Rankers, breakdown)
+	}
+	return res
+}
+
+// POST /api/_score
+func scoreHandler(c *gin.Context) {
+	var req ScoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Query = normalizeQuery(req.Query)
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query is required"})
+		return
+	}
+	if (req.DocID == nil) == (req.Document == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of doc_id or document is required"})
+		return
+	}
+
+	articles, _, err := loadIndex()
+	if err != nil {
+		log.Printf("Error loading index for score sandbox: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "index unavailable"})
+		return
+	}
+
+	docID := -1
+	var article Article
+	if req.DocID != nil {
+		docID = *req.DocID
+		exists := docID >= 0
+		if exists {
+			article, exists = localArticle(articles, SearchResult{DocID: docID})
+		}
+		// Dokumen internal tidak terlihat tanpa scope-nya
+		exists = exists && article.VisibleTo(clientScopes(c))
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "document not found"})
+			return
+		}
+	} else {
+		article = *req.Document
+		if article.Title == "" && article.Content == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "document needs a title or content"})
+			return
+		}
+		if len(article.Title)+len(article.Content) > SCORE_MAX_DOCUMENT {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "document is too large"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, scoreDocument(req.Query, article, docID, requestConfig(c)))
+}
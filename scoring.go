@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Scoring langsung dari posting list. Bobot TF-IDF dihitung saat traversal
+// posting term query, jadi tidak ada lagi matriks skor term x dokumen yang
+// menduplikasi index. Panjang vektor dan jumlah term unik tiap dokumen
+// disimpan di index (DocNorms, DocTerms) saat build.
+
+// Metode scoring yang bisa dipilih lewat parameter method
+var searchMethods = []string{"cosine", "jaccard", "bm25"}
+
+func validSearchMethod(method string) bool {
+	return containsString(searchMethods, method)
+}
+
+// Parameter BM25: k1 mengatur saturasi frekuensi term, b seberapa kuat
+// panjang dokumen menormalisasi skor (0 = tanpa normalisasi panjang)
+const (
+	BM25_K1 = 1.2
+	BM25_B  = 0.75
+)
+
+type BM25Params struct {
+	K1 float64 `json:"k1"`
+	B  float64 `json:"b"`
+}
+
+func (p BM25Params) validate() error {
+	if p.K1 < 0 {
+		return fmt.Errorf("k1 must not be negative")
+	}
+	if p.B < 0 || p.B > 1 {
+		return fmt.Errorf("b must be between 0 and 1")
+	}
+	return nil
+}
+
+// IDF: log(total dokumen / dokumen yang mengandung term)
+func idf(totalDocs, docFrequency int) float64 {
+	return math.Log(float64(totalDocs) / float64(docFrequency))
+}
+
+// Hitung panjang vektor TF-IDF dan jumlah term unik setiap dokumen
+func (idx *InvertedIndex) computeDocNorms(totalDocs int) {
+	idx.DocNorms = make([]float64, totalDocs)
+	idx.DocTerms = make([]int, totalDocs)
+
+	for _, postingList := range idx.Index {
+		termIDF := idf(totalDocs, postingList.DocFrequency)
+		for docID, posting := range postingList.Postings {
+			weight := float64(posting.Frequency) * termIDF
+			idx.DocNorms[docID] += weight * weight
+			idx.DocTerms[docID]++
+		}
+	}
+
+	for docID, sum := range idx.DocNorms {
+		idx.DocNorms[docID] = math.Sqrt(sum)
+	}
+}
+
+// Panjang vektor TF-IDF dan jumlah term unik satu dokumen dari term-nya,
+// sama dengan computeDocNorms untuk dokumen itu
+func (idx *InvertedIndex) docNorm(docID int, terms []string, totalDocs int) (float64, int) {
+	var sum float64
+	for _, term := range terms {
+		postingList := idx.Index[term]
+		weight := float64(postingList.Postings[docID].Frequency) * idf(totalDocs, postingList.DocFrequency)
+		sum += weight * weight
+	}
+	return math.Sqrt(sum), len(terms)
+}
+
+// Cosine similarity antara query dan setiap kandidat
+func (idx *InvertedIndex) cosineScores(queryVector map[string]float64, candidates *Bitmap, totalDocs int) map[int]float64 {
+	scores := make(map[int]float64)
+
+	for term, queryWeight := range normalizeVector(queryVector) {
+		postingList, exists := idx.Index[term]
+		if !exists {
+			continue
+		}
+		termIDF := idf(totalDocs, postingList.DocFrequency)
+		for docID, posting := range postingList.Postings {
+			if !candidates.Contains(docID) || idx.DocNorms[docID] == 0 {
+				continue
+			}
+			scores[docID] += queryWeight * float64(posting.Frequency) * termIDF / idx.DocNorms[docID]
+		}
+	}
+
+	return scores
+}
+
+// Jaccard similarity antara himpunan term query dan term setiap kandidat
+func (idx *InvertedIndex) jaccardScores(queryVector map[string]float64, candidates *Bitmap) map[int]float64 {
+	intersections := make(map[int]int)
+	for term := range queryVector {
+		postingList, exists := idx.Index[term]
+		if !exists {
+			continue
+		}
+		for docID := range postingList.Postings {
+			if candidates.Contains(docID) {
+				intersections[docID]++
+			}
+		}
+	}
+
+	scores := make(map[int]float64, len(intersections))
+	for docID, intersection := range intersections {
+		union := len(queryVector) + idx.DocTerms[docID] - intersection
+		scores[docID] = float64(intersection) / float64(union)
+	}
+	return scores
+}
+
+// IDF BM25 (varian Lucene, selalu positif)
+func bm25IDF(totalDocs, docFrequency int) float64 {
+	return math.Log(1 + (float64(totalDocs)-float64(docFrequency)+0.5)/(float64(docFrequency)+0.5))
+}
+
+// Bobot satu term BM25 untuk frekuensi term di dokumen sepanjang docLength token
+func (p BM25Params) termWeight(frequency, docLength int, avgDocLength float64) float64 {
+	norm := 1.0
+	if avgDocLength > 0 {
+		norm = 1 - p.B + p.B*float64(docLength)/avgDocLength
+	}
+	f := float64(frequency)
+	return f * (p.K1 + 1) / (f + p.K1*norm)
+}
+
+// BM25 antara query dan setiap kandidat; bobot term query ikut mengalikan
+// (bahasa query, sinonim). Jumlah dokumen dan rata-rata panjang dihitung dari
+// dokumen yang diindex.
+func (idx *InvertedIndex) bm25Scores(queryVector map[string]float64, candidates *Bitmap, params BM25Params) map[int]float64 {
+	scores := make(map[int]float64)
+	avgDocLength := idx.AvgDocLength()
+
+	for term, queryWeight := range queryVector {
+		postingList, exists := idx.Index[term]
+		if !exists {
+			continue
+		}
+		termIDF := bm25IDF(idx.DocCount, postingList.DocFrequency)
+		for docID, posting := range postingList.Postings {
+			if !candidates.Contains(docID) {
+				continue
+			}
+			scores[docID] += queryWeight * termIDF * params.termWeight(posting.Frequency, idx.DocLengths[docID].Tokens(), avgDocLength)
+		}
+	}
+
+	return scores
+}
@@ -9,58 +9,142 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
+const ARTICLES_FILE = "articles.json"
+
 // Struktur dasar
 type Article struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	URL     string `json:"url"`
+	Title      string    `json:"title"`
+	Content    string    `json:"content"`
+	URL        string    `json:"url"`
+	Date       time.Time `json:"date,omitempty"`
+	Author     string    `json:"author,omitempty"`
+	Extraction string    `json:"extraction,omitempty"`
+	Language   string    `json:"language,omitempty"`
+	Breadcrumb []string  `json:"breadcrumb,omitempty"` // kategori di situs sumber, lihat breadcrumb.go
+	DocType    string    `json:"doctype,omitempty"`    // pdf/docx untuk dokumen unggahan, kosong = halaman web
+	// Teks per halaman untuk dokumen hasil OCR, lihat ocr.go
+	Pages []DocumentPage `json:"pages,omitempty"`
+	// Tabel di halaman artikel per baris, lihat tables.go
+	Tables []ArticleTable `json:"tables,omitempty"`
+	// Link keluar di container artikel, lihat linkgraph.go
+	Links []string `json:"links,omitempty"`
+	// Field tambahan yang dideklarasikan di schema index, lihat schema.go
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// Waktu soft delete, nil kalau tidak dihapus; lihat softdelete.go
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// public (kosong) atau internal, lihat visibility.go
+	Visibility string `json:"visibility,omitempty"`
+	// Hasil lookup eksternal per stage beserta asalnya, lihat enrichment.go
+	Enrichment map[string]*Enrichment `json:"enrichment,omitempty"`
+	// Asal dokumen (crawler, ingest, upload, recrawl), lihat provenance.go
+	Provenance *Provenance `json:"provenance,omitempty"`
 }
 
 type SearchResult struct {
+	DocID              int
 	Title              string
 	Content            string
 	URL                string
 	Score              float64
 	HighlightedContent template.HTML
 	Favicon            string
+	Author             string
+	Category           string
+	TextFragment       string // text directive ("text=..."), lihat textfragment.go
+
+	// Metadata untuk screen reader (aria-posinset/aria-setsize) dan <time datetime>
+	Position       int       // peringkat 1-based di seluruh hasil
+	Total          int       // jumlah seluruh hasil
+	Words          int       // jumlah kata konten
+	ReadingMinutes int       // estimasi waktu baca
+	Date           time.Time // UTC, zero kalau tanggal tidak diketahui
+	Price          int64     // rupiah, 0 = tanpa harga
+	Archived       bool      // dari index arsip (include_archive=1)
+	Node           string    // instance remote asal hasil, "" = lokal
+	DocType        string    // article, pdf, docx (lihat documents.go)
+	Page           int       // halaman dokumen OCR yang paling cocok, 0 = tidak ada
+	// Field schema tambahan yang stored, lihat schema.go
+	Fields map[string]interface{}
+
+	// Salinan sindikasi di source lain, lihat syndication.go
+	AlsoPublished []SyndicatedCopy
+
+	// Hasil lain yang digabung ke hasil ini (sindikasi, URL sama dari
+	// instance lain). Dengan collapse=false hasil itu tetap tampil dan
+	// DuplicateOf menunjuk hasil yang akan menggantikannya.
+	Collapsed   []CollapsedResult
+	DuplicateOf *CollapsedResult
+
+	// Ditemukan (atau lebih cocok) lewat terjemahan query, lihat translation.go
+	Translated bool
+}
+
+// Alasan hasil digabung ke hasil lain
+const (
+	CollapseSyndicated = "syndicated"
+	CollapseSameURL    = "same_url"
+)
+
+type CollapsedResult struct {
+	DocID  int    `json:"doc_id"`
+	URL    string `json:"url"`
+	Node   string `json:"node,omitempty"`
+	Reason string `json:"reason"`
+}
+
+func collapsedFrom(result SearchResult, reason string) CollapsedResult {
+	return CollapsedResult{DocID: result.DocID, URL: result.URL, Node: result.Node, Reason: reason}
 }
 
 // Struktur untuk inverted index
 type InvertedIndex struct {
-	Index map[string]*PostingList
+	Index     map[string]*PostingList
+	DocCount  int // jumlah dokumen yang diindex
+	DocValues *DocValues
+
+	// Per docID, untuk scoring tanpa matriks TF-IDF (lihat scoring.go)
+	DocNorms []float64 // panjang vektor TF-IDF dokumen
+	DocTerms []int     // jumlah term unik dokumen
+
+	DocLengths  []DocLength // jumlah token per field, lihat doclength.go
+	TotalTokens int         // jumlah token semua dokumen yang diindex
+
+	Paragraphs *ParagraphIndex // nil kecuali config paragraph_index, lihat paragraphs.go
+
+	Allowed []bool // per docID, false = ditahan/ditolak review queue
 }
 
 type PostingList struct {
 	DocFrequency int
 	Postings     map[int]*Posting
+	Docs         *Bitmap // hanya untuk term berfrekuensi tinggi, lihat postings.go
 }
 
 type Posting struct {
 	DocID     int
 	Frequency int
 	Positions []int
+	Offsets   []Offset // hanya terisi kalau config.StoreOffsets aktif
+}
+
+// Byte offset kemunculan term di teks yang diindex (title + " " + content)
+type Offset struct {
+	Start int
+	End   int
 }
 
 // Text Processor
 type TextProcessor struct {
-	stopWords   map[string]bool
-	punctuation *regexp.Regexp
-	numbers     *regexp.Regexp
+	language  string
+	stopWords map[string]bool
 }
 
 // Variabel global
 var (
-	prefixes = []string{
-		"me", "pe", "be", "te", "di", "ke", "se",
-		"ber", "per", "ter", "mem", "pem", "pen",
-		"meng", "peng", "meny", "peny",
-	}
-	suffixes = []string{
-		"kan", "an", "i", "lah", "kah", "nya", "ku", "mu",
-		"wan", "wati", "isme",
-	}
 	textProcessor *TextProcessor
 )
 
@@ -70,9 +154,8 @@ func init() {
 
 func NewTextProcessor() *TextProcessor {
 	return &TextProcessor{
-		stopWords:   initializeStopWords(),
-		punctuation: regexp.MustCompile(`[^\w\s]`),
-		numbers:     regexp.MustCompile(`\b\d+\b`),
+		language:  LangIndonesian,
+		stopWords: initializeStopWords(),
 	}
 }
 
@@ -113,98 +196,167 @@ func initializeStopWords() map[string]bool {
 	}
 }
 
-// Text Processing Steps
-// 1. Remove punctuations dan nomor/angka
-func (tp *TextProcessor) removePunctuationsAndNumbers(text string) string {
-	text = tp.punctuation.ReplaceAllString(text, " ")
-	text = tp.numbers.ReplaceAllString(text, " ")
-	return strings.TrimSpace(text)
+// Token hasil analisis. Position adalah urutan kata di teks asli (stopword
+// dan angka yang dibuang tetap menghabiskan posisi), Start/End adalah byte offset.
+type Token struct {
+	Term     string
+	Position int
+	Start    int
+	End      int
 }
 
-// 2. Remove Stopword
-func (tp *TextProcessor) removeStopwords(text string) []string {
-	words := strings.Fields(text)
-	filtered := make([]string, 0)
-	for _, word := range words {
-		if !tp.stopWords[strings.ToLower(word)] {
-			filtered = append(filtered, word)
+// Text Processing Steps
+// 1. Remove punctuations dan nomor/angka, sekaligus memecah teks menjadi kata
+// dengan posisi dan offset aslinya. Token ditambahkan ke buffer yang diberikan.
+func (tp *TextProcessor) splitWords(tokens []Token, text string) []Token {
+	position := 0
+
+	for i := 0; i < len(text); {
+		if !isWordByte(text[i]) {
+			i++
+			continue
 		}
-	}
-	return filtered
-}
 
-// 3. Case folding
-func (tp *TextProcessor) caseFolding(tokens []string) []string {
-	folded := make([]string, len(tokens))
-	for i, token := range tokens {
-		folded[i] = strings.ToLower(token)
-	}
-	return folded
-}
+		start := i
+		numeric := true
+		for i < len(text) && isWordByte(text[i]) {
+			if text[i] < '0' || text[i] > '9' {
+				numeric = false
+			}
+			i++
+		}
 
-// 4. Stemming
-func (tp *TextProcessor) stem(word string) string {
-	if len(word) < 4 {
-		return word
+		if !numeric {
+			tokens = append(tokens, Token{
+				Term:     text[start:i],
+				Position: position,
+				Start:    start,
+				End:      i,
+			})
+		}
+		position++
 	}
 
-	origWord := word
+	return tokens
+}
 
-	// Coba hapus suffix terlebih dahulu
-	for _, suffix := range suffixes {
-		if strings.HasSuffix(word, suffix) {
-			word = strings.TrimSuffix(word, suffix)
-			break
-		}
+// Karakter kata sama dengan \w pada regex: huruf ASCII, angka, underscore
+func isWordByte(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// 2. Case folding. Dijalankan sebelum stopword removal supaya setiap kata
+// cukup di-lowercase sekali (strings.ToLower tidak alokasi untuk kata yang
+// sudah huruf kecil semua).
+func (tp *TextProcessor) caseFolding(tokens []Token) []Token {
+	for i := range tokens {
+		tokens[i].Term = strings.ToLower(tokens[i].Term)
 	}
+	return tokens
+}
 
-	// Kemudian hapus prefix
-	for _, prefix := range prefixes {
-		if strings.HasPrefix(word, prefix) {
-			stemmed := strings.TrimPrefix(word, prefix)
-			if len(stemmed) >= 4 {
-				word = stemmed
-				break
+// 2b. Kata yang sama berturut-turut dihitung sekali ("rumah rumah", juga
+// reduplikasi "rumah-rumah"). Token yang dibuang tetap menghabiskan posisi.
+// Dulu dilakukan cleanContent, yang ikut mengubah teks preview.
+func (tp *TextProcessor) removeRepeats(tokens []Token) []Token {
+	filtered := tokens[:0]
+	// Term di posisi sebelumnya dan posisi ini sebelum ada yang dibuang,
+	// supaya "rumah rumah rumah" tetap jadi satu; bentuk majemuk bisa
+	// menumpuk beberapa token di satu posisi
+	var previous, current []string
+	position := -1
+	for _, token := range tokens {
+		if token.Position != position {
+			if token.Position != position+1 {
+				current = current[:0]
 			}
+			previous, current = current, previous[:0]
+			position = token.Position
+		}
+		repeated := false
+		for _, term := range previous {
+			repeated = repeated || term == token.Term
+		}
+		current = append(current, token.Term)
+		if !repeated {
+			filtered = append(filtered, token)
 		}
 	}
+	return filtered
+}
 
-	if len(word) < 3 {
-		return origWord
+// 3. Remove Stopword di slice yang sama, posisi token lain tidak digeser
+func (tp *TextProcessor) removeStopwords(tokens []Token) []Token {
+	filtered := tokens[:0]
+	for _, token := range tokens {
+		if !tp.stopWords[token.Term] {
+			filtered = append(filtered, token)
+		}
 	}
+	return filtered
+}
 
-	return word
+// 4. Stemming (stemmer.go)
+func (tp *TextProcessor) stem(word string) string {
+	return cachedStemIndonesian(word)
 }
 
-func (tp *TextProcessor) stemming(tokens []string) []string {
-	stemmed := make([]string, len(tokens))
-	for i, token := range tokens {
-		stemmed[i] = tp.stem(token)
+func (tp *TextProcessor) stemming(tokens []Token) []Token {
+	for i := range tokens {
+		// Artikel berbahasa Inggris pakai Porter2, bukan affix stripping Indonesia
+		if tp.language == LangEnglish {
+			tokens[i].Term = porter2Stem(tokens[i].Term)
+		} else {
+			tokens[i].Term = tp.stem(tokens[i].Term)
+		}
 	}
-	return stemmed
+	return tokens
 }
 
-// 5. Tokenisasi (final)
-func (tp *TextProcessor) tokenize(text string) []string {
-	return strings.Fields(text)
+// Proses text lengkap dengan urutan yang benar, menghasilkan token beserta
+// posisi dan offset di teks asli
+func (tp *TextProcessor) Analyze(text string) []Token {
+	return tp.AnalyzeInto(make([]Token, 0, len(text)/6), text)
 }
 
-// Proses text lengkap dengan urutan yang benar
-func (tp *TextProcessor) ProcessText(text string) []string {
-	// 1. Remove punctuations dan nomor/angka
-	cleaned := tp.removePunctuationsAndNumbers(text)
+// Sama dengan Analyze tapi memakai ulang buffer token milik caller; semua
+// tahap bekerja di slice yang sama tanpa slice perantara
+func (tp *TextProcessor) AnalyzeInto(buf []Token, text string) []Token {
+	// 1. Remove punctuations dan nomor/angka + tokenisasi
+	tokens := tp.splitWords(buf[:0], text)
 
-	// 2. Remove Stopword
-	withoutStopwords := tp.removeStopwords(cleaned)
+	// 2. Case folding
+	tokens = tp.caseFolding(tokens)
+	tokens = tp.removeRepeats(tokens)
 
-	// 3. Case folding
-	folded := tp.caseFolding(withoutStopwords)
+	// 3. Remove Stopword
+	tokens = tp.removeStopwords(tokens)
 
 	// 4. Stemming
-	stemmed := tp.stemming(folded)
+	return tp.stemming(tokens)
+}
+
+// Buffer token untuk ProcessText, yang hanya butuh term-nya
+var tokenBufferPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]Token, 0, 1024)
+		return &buf
+	},
+}
+
+// Proses text dan ambil term-nya saja
+func (tp *TextProcessor) ProcessText(text string) []string {
+	bufPtr := tokenBufferPool.Get().(*[]Token)
+	tokens := tp.AnalyzeInto(*bufPtr, text)
+
+	terms := make([]string, len(tokens))
+	for i, token := range tokens {
+		terms[i] = token.Term
+	}
 
-	// 5. Tokenisasi adalah hasil akhir dari proses stemming
-	return stemmed
+	*bufPtr = tokens[:0]
+	tokenBufferPool.Put(bufPtr)
+	return terms
 }
 
 // Fungsi untuk membuat inverted index baru
@@ -214,58 +366,55 @@ func NewInvertedIndex() *InvertedIndex {
 	}
 }
 
-// Fungsi untuk membangun inverted index
-func buildInvertedIndex(articles []Article) *InvertedIndex {
-	idx := NewInvertedIndex()
+// Fungsi untuk membangun inverted index. cfg adalah snapshot config untuk
+// seluruh pass ini (currentConfig), jadi reload di tengah build tidak
+// mencampur schema atau analyzer lama dan baru.
+func buildInvertedIndex(articles []Article, cfg *Config) *InvertedIndex {
+	// Dokumen spam/yang ditahan review queue tidak masuk index
+	return buildAllowedIndex(articles, func(docID int) bool {
+		return !articles[docID].Deleted() && reviewQueue.Allows(articles[docID])
+	}, cfg)
+}
 
-	for docID, article := range articles {
-		tokens := textProcessor.ProcessText(article.Title + " " + article.Content)
+// Index dokumen yang lolos allow; generasi yang di-pin memakai keputusan
+// allow yang tersimpan, bukan review queue sekarang (generations.go)
+func buildAllowedIndex(articles []Article, allow func(docID int) bool, cfg *Config) *InvertedIndex {
+	allowed := make([]bool, len(articles))
+	idx := indexDocuments(articles, func(docID int) bool {
+		allowed[docID] = allow(docID)
+		return allowed[docID]
+	}, cfg)
 
-		// Track position untuk setiap term
-		for pos, token := range tokens {
-			if _, exists := idx.Index[token]; !exists {
-				idx.Index[token] = &PostingList{
-					DocFrequency: 0,
-					Postings:     make(map[int]*Posting),
-				}
-			}
+	idx.Allowed = allowed
 
-			if _, exists := idx.Index[token].Postings[docID]; !exists {
-				idx.Index[token].Postings[docID] = &Posting{
-					DocID:     docID,
-					Frequency: 0,
-					Positions: make([]int, 0),
-				}
-				idx.Index[token].DocFrequency++
-			}
+	// Kolom sort/filter dibangun setelah bahasa tiap dokumen terdeteksi
+	idx.DocValues = buildDocValues(articles, cfg.Schema)
 
-			posting := idx.Index[token].Postings[docID]
-			posting.Frequency++
-			posting.Positions = append(posting.Positions, pos)
-		}
+	if cfg.ParagraphIndex {
+		idx.Paragraphs = buildParagraphIndex(articles, allowed, cfg)
 	}
 
 	return idx
 }
 
-// Menghitung TF-IDF dengan inverted index
-func calculateTFIDF(invertedIndex *InvertedIndex, totalDocs int) map[string]map[int]float64 {
-	tfidfScores := make(map[string]map[int]float64)
-
-	for term, postingList := range invertedIndex.Index {
-		tfidfScores[term] = make(map[int]float64)
-
-		// Hitung IDF: log(Total Dokumen / Dokumen yang mengandung term)
-		idf := math.Log(float64(totalDocs) / float64(postingList.DocFrequency))
+// Index field teks setiap dokumen yang lolos allow sesuai schema index
+// (title + content, lalu field text tambahan). Bahasa dokumen yang belum
+// diketahui dideteksi dari title + content dan disimpan ke docs. Corpus besar
+// dibangun paralel per shard, lihat parallelindex.go.
+func indexDocuments(docs []Article, allow func(docID int) bool, cfg *Config) *InvertedIndex {
+	idx := NewInvertedIndex()
+	idx.DocLengths = make([]DocLength, len(docs))
 
-		for docID, posting := range postingList.Postings {
-			// TF * IDF
-			tf := float64(posting.Frequency)
-			tfidfScores[term][docID] = tf * idf
-		}
+	if workers := indexWorkers(len(docs), cfg); workers > 1 {
+		indexShards(idx, docs, allow, workers, cfg)
+	} else {
+		indexRange(idx, docs, 0, len(docs), allow, termTable.Intern, cfg)
 	}
 
-	return tfidfScores
+	idx.buildDenseBitmaps(cfg.DenseTermRatio)
+	idx.computeDocNorms(len(docs))
+
+	return idx
 }
 
 // Normalisasi vector
@@ -289,205 +438,170 @@ func normalizeVector(vector map[string]float64) map[string]float64 {
 	return normalized
 }
 
-// Cosine Similarity dengan TF-IDF
-func cosineSimilarityWithTFIDF(queryVector map[string]float64, tfidfScores map[string]map[int]float64, docID int) float64 {
-	docVector := make(map[string]float64)
+// Content Preview Generator. Preview memakai teks sumber apa adanya (tanda
+// baca, angka dan kata berulang tetap ada); hanya boilerplate dan link yang
+// dibuang lewat displayContent. Normalisasi lain hanya terjadi di analyzer.
+// Batasnya mengikuti kalimat, lihat snippetBounds. Konten dan query
+// dianalisis dengan analyzer bahasa dokumen supaya term-nya sebanding.
+func getContentPreview(content, query, language string, maxLength int) string {
+	content = displayContent(content)
+	maxLength = 160
 
-	// Buat vektor dokumen dari TF-IDF scores
-	for term, scores := range tfidfScores {
-		if score, exists := scores[docID]; exists {
-			docVector[term] = score
-		}
+	if len(content) <= maxLength {
+		return content
 	}
 
-	// Normalisasi kedua vektor
-	normalizedQuery := normalizeVector(queryVector)
-	normalizedDoc := normalizeVector(docVector)
+	// Offset token menunjuk langsung ke teks preview
+	first := Offset{Start: -1, End: -1}
+	analyzer := analyzerFor(language)
+	if match := matchTerms(analyzer.Analyze(content), analyzer.ProcessText(query)); match != nil {
+		first = Offset{Start: match.Start, End: match.End}
+	}
+	start, end, cutStart, cutEnd := snippetBounds(content, first, maxLength)
 
-	// Hitung dot product
-	var dotProduct float64
-	for term, queryWeight := range normalizedQuery {
-		if docWeight, exists := normalizedDoc[term]; exists {
-			dotProduct += queryWeight * docWeight
-		}
+	result := content[start:end]
+	if cutStart {
+		result = "..." + result
+	}
+	if cutEnd {
+		result = result + "..."
 	}
 
-	return dotProduct
+	return result
 }
 
-// Jaccard Similarity dengan TF-IDF
-func jaccardSimilarityWithTFIDF(queryVector map[string]float64, tfidfScores map[string]map[int]float64, docID int) float64 {
-	querySet := make(map[string]bool)
-	docSet := make(map[string]bool)
-
-	// Convert weighted vectors to sets
-	for term := range queryVector {
-		querySet[term] = true
+// Token pertama dari urutan term query di konten; kalau urutan lengkapnya
+// tidak ada, token pertama yang cocok dengan term query mana pun
+func matchTerms(tokens []Token, terms []string) *Token {
+	if len(terms) == 0 {
+		return nil
 	}
-
-	for term, scores := range tfidfScores {
-		if _, exists := scores[docID]; exists {
-			docSet[term] = true
+	for i := 0; i+len(terms) <= len(tokens); i++ {
+		j := 0
+		for j < len(terms) && tokens[i+j].Term == terms[j] {
+			j++
 		}
-	}
-
-	// Calculate intersection
-	intersection := 0
-	for term := range querySet {
-		if docSet[term] {
-			intersection++
+		if j == len(terms) {
+			return &tokens[i]
 		}
 	}
-
-	// Calculate union
-	union := len(querySet) + len(docSet) - intersection
-	if union == 0 {
-		return 0
+	for i := range tokens {
+		for _, term := range terms {
+			if tokens[i].Term == term {
+				return &tokens[i]
+			}
+		}
 	}
-
-	return float64(intersection) / float64(union)
+	return nil
 }
 
-// Content Preview Generator
-func getContentPreview(content, query string, maxLength int) string {
-	cleanedContent := cleanContent(content)
-	maxLength = 160
-
-	if len(cleanedContent) <= maxLength {
-		return cleanedContent
-	}
-
-	processedQueryTokens := textProcessor.ProcessText(query)
-	processedContentTokens := textProcessor.ProcessText(cleanedContent)
-
-	queryText := strings.Join(processedQueryTokens, " ")
-	contentText := strings.Join(processedContentTokens, " ")
-
-	pos := strings.Index(strings.ToLower(contentText), strings.ToLower(queryText))
-	if pos == -1 {
-		return cleanedContent[:maxLength] + "..."
-	}
-
-	// Cari posisi kata di konten asli
-	words := strings.Fields(cleanedContent)
-	wordCount := len(strings.Fields(contentText[:pos]))
-
-	// Hitung posisi karakter berdasarkan jumlah kata
-	wordPos := 0
-	for i := 0; i < wordCount && i < len(words); i++ {
-		wordPos += len(words[i]) + 1
-	}
-
-	start := wordPos - 60
-	if start < 0 {
-		start = 0
-	}
-
-	end := start + maxLength
-	if end > len(cleanedContent) {
-		end = len(cleanedContent)
-	}
-
-	result := cleanedContent[start:end]
-	if start > 0 {
-		result = "..." + result
+// Teks untuk ditampilkan: tanpa boilerplate (unwantedTexts), URL, email dan
+// handle sosmed, whitespace dirapikan, selebihnya sama dengan sumbernya
+func displayContent(content string) string {
+	for _, text := range unwantedTexts {
+		content = strings.ReplaceAll(content, text, "")
 	}
-	if end < len(cleanedContent) {
-		result = result + "..."
+	words := strings.Fields(content)
+	kept := words[:0]
+	for _, word := range words {
+		if word = stripWordLinks(word); word != "" {
+			kept = append(kept, word)
+		}
 	}
-
-	return result
+	return strings.Join(kept, " ")
 }
 
-// Clean content for better processing
-func cleanContent(content string) string {
-	// 1. Remove unwanted texts
-	unwantedTexts := []string{
+// Pattern cleanContent, dikompilasi sekali saat startup
+var (
+	unwantedTexts = []string{
 		"Baca juga:", "Baca Juga:",
 		"Simak breaking news", "Google News",
 		"Terus ikuti", "Lebih banyak informasi",
 		"Follow", "Instagram", "Twitter", "Facebook",
 		"Bagikan:", "Share:", "Read more",
 	}
+	urlPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`https?://\S+`),          // http:// atau https://
+		regexp.MustCompile(`www\.\S+`),              // www.
+		regexp.MustCompile(`\S+\.(com|net|org)\S*`), // domain common
+	}
+	emailPattern        = regexp.MustCompile(`\S+@\S+\.\S+`)
+	socialPattern       = regexp.MustCompile(`@\S+`)
+	specialCharsPattern = regexp.MustCompile(`[^a-zA-Z0-9\s]+`)
+	numberPattern       = regexp.MustCompile(`\s+\d+\s+`)
+)
 
+// Clean content for better processing. Default memakai scanner
+// (cleanContentScan); versi regex masih bisa dipilih lewat config
+// regex_clean_content selama parity belum terbukti di semua corpus.
+func cleanContent(content string) string {
+	if currentConfig().RegexCleanContent {
+		return cleanContentRegex(content)
+	}
+	return cleanContentScan(content)
+}
+
+func cleanContentRegex(content string) string {
+	// 1. Remove unwanted texts
 	for _, text := range unwantedTexts {
 		content = strings.ReplaceAll(content, text, "")
 	}
 
 	// 2. Remove all URLs
-	urlPatterns := []*regexp.Regexp{
-		regexp.MustCompile(`https?://\S+`),          // http:// atau https://
-		regexp.MustCompile(`www\.\S+`),              // www.
-		regexp.MustCompile(`\S+\.(com|net|org)\S*`), // domain common
-	}
-
 	for _, pattern := range urlPatterns {
 		content = pattern.ReplaceAllString(content, "")
 	}
 
 	// 3. Remove email addresses
-	emailPattern := regexp.MustCompile(`\S+@\S+\.\S+`)
 	content = emailPattern.ReplaceAllString(content, "")
 
 	// 4. Remove social media handles
-	socialPattern := regexp.MustCompile(`@\S+`)
 	content = socialPattern.ReplaceAllString(content, "")
 
-	// 5. Remove special characters dan punctuation
-	specialCharsPattern := regexp.MustCompile(`[^a-zA-Z0-9\s]+`)
+	// 5. Remove special characters dan punctuation (termasuk .,!?;: sehingga
+	// normalisasi spasi setelah tanda baca tidak diperlukan lagi)
 	content = specialCharsPattern.ReplaceAllString(content, " ")
 
 	// 6. Remove standalone numbers
-	numberPattern := regexp.MustCompile(`\s+\d+\s+`)
 	content = numberPattern.ReplaceAllString(content, " ")
 
-	// 7. Remove extra whitespace
-	content = regexp.MustCompile(`\s+`).ReplaceAllString(content, " ")
-
-	// 8. Remove leading/trailing spaces
-	content = strings.TrimSpace(content)
-
-	// 9. Normalize spaces setelah tanda baca
-	content = regexp.MustCompile(`\s*[.,!?;:]\s*`).ReplaceAllString(content, " ")
-
-	// 10. Remove repeated words
-	words := strings.Fields(content)
-	uniqueWords := make([]string, 0)
-	prev := ""
-	for _, word := range words {
-		if word != prev {
-			uniqueWords = append(uniqueWords, word)
-			prev = word
-		}
-	}
-	content = strings.Join(uniqueWords, " ")
-
-	// 11. Normalize whitespace final
-	content = strings.TrimSpace(content)
-	content = regexp.MustCompile(`\s+`).ReplaceAllString(content, " ")
-
-	return content
+	// 7. Rapikan whitespace. Kata berulang tidak dibuang di sini lagi, tapi
+	// di analyzer (removeRepeats)
+	return strings.Join(strings.Fields(content), " ")
 }
 
-// Highlight matched text
+// Highlight matched text. Term query diambil dari analyzer setiap bahasa
+// query (queryLanguageWeights), jadi stem Porter2 query Inggris juga ikut
+// di-highlight.
 func highlightText(text string, query string) string {
 	if query == "" {
 		return text
 	}
 
-	queryTokens := textProcessor.ProcessText(query)
-	highlighted := text
+	languages := make([]string, 0, 2)
+	for language := range queryLanguageWeights(query) {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	var terms []string
+	for _, language := range languages {
+		terms = append(terms, analyzerFor(language).ProcessText(query)...)
+	}
 
-	for _, token := range queryTokens {
+	// Satu regex untuk semua token query, bukan satu regex per token
+	var alternatives []string
+	for _, token := range uniqueTerms(terms) {
 		if len(token) < 2 {
 			continue
 		}
-		pattern := `(?i)\b[\wа-я]*` + regexp.QuoteMeta(token) + `[\wа-я]*\b`
-		re := regexp.MustCompile(pattern)
-		highlighted = re.ReplaceAllString(highlighted, `<em>$0</em>`)
+		alternatives = append(alternatives, regexp.QuoteMeta(token))
+	}
+	if len(alternatives) == 0 {
+		return text
 	}
 
-	return highlighted
+	re := regexp.MustCompile(`(?i)\b[\wа-я]*(?:` + strings.Join(alternatives, "|") + `)[\wа-я]*\b`)
+	return re.ReplaceAllString(text, `<em>$0</em>`)
 }
 
 // Get favicon path for URL
@@ -508,73 +622,341 @@ func getFaviconPath(url string) string {
 func loadArticles() ([]Article, error) {
 	var allArticles []Article
 
-	data, err := ioutil.ReadFile("articles.json")
+	data, err := ioutil.ReadFile(ARTICLES_FILE)
 	if err != nil {
-		log.Printf("Error reading articles.json: %v", err)
+		log.Printf("Error reading %s: %v", ARTICLES_FILE, err)
 		return nil, err
 	}
 
 	if err := json.Unmarshal(data, &allArticles); err != nil {
-		log.Printf("Error parsing JSON from articles.json: %v", err)
+		log.Printf("Error parsing JSON from %s: %v", ARTICLES_FILE, err)
 		return nil, err
 	}
+	normalizeArticleDates(allArticles)
 
 	return allArticles, nil
 }
 
+// Corpus dan inverted index yang sedang aktif (indexer.go)
+func loadIndex() ([]Article, *InvertedIndex, error) {
+	return indexer.Current()
+}
+
+// Load corpus ditambah perubahan WAL yang belum di-flush (pending), lalu
+// bangun inverted index-nya. articles masih berisi konten lengkap untuk
+// noteIndexGeneration; served adalah salinan yang dipasang untuk pencarian.
+func buildIndex(pending []WalRecord, cfg *Config) (generationKey string, articles []Article, idx *InvertedIndex, served []Article, err error) {
+	generationKey = corpusGenerationKey(pending)
+
+	articles, err = loadArticles()
+	if err != nil {
+		return "", nil, nil, nil, err
+	}
+
+	articles = applyWalRecords(articles, pending, cfg)
+
+	// Terapkan koreksi admin dari review queue
+	reviewQueue.ApplyEdits(articles)
+
+	// Field baru dari crawler/ingestion dipetakan sebelum index dibangun
+	cfg.Schema.mapDynamic(articles)
+
+	idx = buildInvertedIndex(articles, cfg)
+
+	// Konten lengkap diambil dari document store saat dibutuhkan. Selama masih
+	// ada perubahan di WAL, docID belum sejajar dengan document store.
+	served = articles
+	if hasDocStore() && len(pending) == 0 {
+		served = make([]Article, len(articles))
+		copy(served, articles)
+		for i := range served {
+			served[i].Content = ""
+		}
+	}
+
+	return generationKey, articles, idx, served, nil
+}
+
+// Analisis query dengan pipeline Indonesia dan Inggris lalu gabungkan term-nya.
+// Term yang muncul di kedua pipeline memakai bobot terbesar.
+func buildQueryVector(query string) map[string]float64 {
+	queryVector := make(map[string]float64)
+
+	for language, weight := range queryLanguageWeights(query) {
+		counts := make(map[string]float64)
+		for _, token := range analyzerFor(language).ProcessText(query) {
+			counts[token]++
+		}
+		for token, count := range counts {
+			if count*weight > queryVector[token] {
+				queryVector[token] = count * weight
+			}
+		}
+	}
+
+	return queryVector
+}
+
 // Main search function
-func searching(query string, method string) []SearchResult {
-	articles, err := loadArticles()
+func searching(query string, method string, opts SearchOptions) []SearchResult {
+	results, _ := searchWithPlan(query, method, opts)
+	return results
+}
+
+// Sama dengan searching, ditambah query plan untuk explain mode
+func searchWithPlan(query string, method string, opts SearchOptions) ([]SearchResult, *QueryPlan) {
+	opts = opts.withConfig()
+
+	// Query yang sama di generasi corpus yang sama tidak perlu di-score ulang
+	cacheKey := queryResultKey(query, method, opts)
+	if results, plan, exists := queryResultCache.Get(cacheKey, opts.Config); exists {
+		return results, plan
+	}
+
+	pinned, err := indexFor(opts.Generation)
 	if err != nil {
 		log.Printf("Error loading articles: %v", err)
-		return nil
+		return nil, nil
+	}
+	articles, invertedIndex := pinned.Articles, pinned.Index
+	// Generasi lama dicari dengan config saat generasi itu dibangun
+	if pinned.Config != nil {
+		opts.Config = pinned.Config
 	}
 
-	// Build inverted index
-	invertedIndex := buildInvertedIndex(articles)
+	// Kata gabungan ("hargarumahsubsidi") dipecah sebelum dianalisis
+	rewrite := segmentQuery(query, opts)
+	if rewrite != nil && rewrite.Applied {
+		query = rewrite.Text
+	}
 
-	// Calculate TF-IDF scores
-	tfidfScores := calculateTFIDF(invertedIndex, len(articles))
+	// Kata tanpa posting dikoreksi ke kata corpus terdekat ("Maksud Anda")
+	if rewrite == nil {
+		rewrite = correctSpelling(query, invertedIndex, opts)
+	}
 
 	// Process query
-	queryTokens := textProcessor.ProcessText(query)
-	queryVector := make(map[string]float64)
-	for _, token := range queryTokens {
-		queryVector[token]++
+	queryVector := buildQueryVector(query)
+	pinned.expand(queryVector)
+
+	tiers := []SearchTier{{
+		Name:    TIER_HOT,
+		Timeout: tierTimeout(opts.Config, TIER_HOT, 0),
+		Search: func() ([]SearchResult, *QueryPlan, error) {
+			results, plan := searchIndex(articles, invertedIndex, query, queryVector, method, opts, false)
+			return results, plan, nil
+		},
+	}}
+	// Arsip (cold index) hanya ikut dicari dengan include_archive=1
+	if opts.IncludeArchive {
+		tiers = append(tiers, SearchTier{
+			Name:    TIER_ARCHIVE,
+			Timeout: tierTimeout(opts.Config, TIER_ARCHIVE, ARCHIVE_TIER_TIMEOUT),
+			Search: func() ([]SearchResult, *QueryPlan, error) {
+				results, err := searchArchive(articles, query, queryVector, method, opts)
+				return results, nil, err
+			},
+		})
+	}
+	// Instance lain di remote_instances, kecuali query ini sendiri diteruskan
+	// dari instance lain (local=1) atau generasinya di-pin: generasi instance
+	// lain tidak ikut tersimpan
+	if !opts.Local && opts.Generation == 0 {
+		tiers = append(tiers, remoteTiers(query, method, opts)...)
+	}
+	// Terjemahan query selalu tier terakhir, hasilnya digabung ke tier hot
+	var translation *QueryTranslation
+	translating := shouldTranslate(query, opts)
+	if translating {
+		tiers = append(tiers, translatedTier(pinned, query, method, opts, &translation))
+	}
+
+	var results []SearchResult
+	var plan *QueryPlan
+	if len(tiers) == 1 {
+		results, plan = searchIndex(articles, invertedIndex, query, queryVector, method, opts, false)
+	} else {
+		// Federasi dengan timeout per tier
+		var tierResults [][]SearchResult
+		tierResults, plan = federate(tiers)
+		if translating {
+			last := len(tiers) - 1
+			if status := plan.Tiers[last]; !status.TimedOut && status.Error == "" {
+				plan.Translation = translation
+				tierResults[0] = mergeTranslated(tierResults[0], tierResults[last], opts.Config.Translation.Weight)
+			}
+			tiers, tierResults = tiers[:last], tierResults[:last]
+		}
+		results = mergeTiers(tiers, tierResults, !opts.NoCollapse)
+		sortMergedResults(results, opts.Sort)
+	}
+
+	// Query tanpa hasil dicari ulang dengan ejaan yang dikoreksi
+	// (spelling_correction=rewrite); plan disalin karena plan query yang
+	// dikoreksi ikut tersimpan di cache
+	if rewrite != nil && rewrite.Kind == RewriteSpelling && len(results) == 0 && opts.Config.SpellingCorrection == SpellingRewrite {
+		corrected := opts
+		corrected.NoSpell = true
+		results, plan = searchWithPlan(rewrite.Text, method, corrected)
+		rewrite.Applied = true
+		if plan != nil {
+			copied := *plan
+			plan = &copied
+		}
+	}
+	if plan != nil {
+		plan.Rewrite = rewrite
+	}
+
+	// Salinan sindikasi disembunyikan kalau versi kanoniknya ikut muncul
+	results = collapseSyndicated(results, !opts.NoCollapse)
+
+	for i := range results {
+		results[i].Position = i + 1
+		results[i].Total = len(results)
+	}
+
+	// Hasil tanpa tier yang timeout atau error tidak di-cache
+	if !plan.Partial() {
+		queryResultCache.Put(cacheKey, results, plan, opts.Config)
+	}
+
+	return results, plan
+}
+
+// Scoring satu index. archived true untuk index arsip: konten selalu ada di
+// memori (tanpa document store) dan blocklist dicek per URL saja karena
+// docID-nya bukan docID corpus utama.
+func searchIndex(articles []Article, invertedIndex *InvertedIndex, query string, queryVector map[string]float64, method string, opts SearchOptions, archived bool) ([]SearchResult, *QueryPlan) {
+	opts = opts.withConfig()
+	cfg := opts.Config
+
+	// Bitmap filter dari cache, nil kalau tanpa filter
+	filterBitmap := filterCache.Bitmap(invertedIndex.DocValues, opts.Filters, cfg.Schema)
+	// Dokumen yang tidak boleh dilihat klien ini tidak pernah jadi kandidat
+	filterBitmap = restrictVisibility(filterBitmap, invertedIndex.DocValues, opts.Scopes)
+
+	// Hanya dokumen yang mengandung term query yang perlu di-score
+	candidates, plan := planQuery(invertedIndex, query, queryVector, opts, filterBitmap)
+
+	// Skor dihitung sekali lewat posting list term query
+	var scores map[int]float64
+	var passages map[int]Passage
+	switch {
+	case invertedIndex.Paragraphs != nil:
+		// Skor artikel = skor paragraf terbaiknya
+		passages = invertedIndex.Paragraphs.bestPassages(queryVector, candidates, method, cfg.BM25)
+		scores = make(map[int]float64, len(passages))
+		for docID, passage := range passages {
+			scores[docID] = passage.Score
+		}
+	case method == "jaccard":
+		scores = invertedIndex.jaccardScores(queryVector, candidates)
+	case method == "bm25":
+		scores = invertedIndex.bm25Scores(queryVector, candidates, cfg.BM25)
+	default:
+		scores = invertedIndex.cosineScores(queryVector, candidates, len(articles))
 	}
 
 	var results []SearchResult
+	// Authority boost dari skor kualitas source (sourcequality.go)
+	boosts := authorityBoosts(cfg.AuthorityWeight)
+	nearTerms := proximityTerms(queryVector)
+	// Authority dari link graph (linkgraph.go)
+	linkAuthority := linkAuthorities(cfg.LinkAuthorityWeight)
 
 	for i, article := range articles {
-		var score float64
-		switch method {
-		case "cosine":
-			score = cosineSimilarityWithTFIDF(queryVector, tfidfScores, i)
-		case "jaccard":
-			score = jaccardSimilarityWithTFIDF(queryVector, tfidfScores, i)
-		default:
-			score = cosineSimilarityWithTFIDF(queryVector, tfidfScores, i)
+		if !candidates.Contains(i) {
+			continue
 		}
 
-		if score > 0 {
-			contentPreview := getContentPreview(article.Content, query, 160)
-			highlightedContent := highlightText(contentPreview, query)
+		// Dokumen di blocklist langsung dilewati tanpa perlu reindex
+		if blocklist.IsURLBlocked(article.URL) {
+			continue
+		}
+
+		// Filter yang tidak selektif dievaluasi per dokumen sebelum scoring
+		if filterBitmap != nil && !plan.FilterFirst && !filterBitmap.Contains(i) {
+			continue
+		}
+
+		if score := scores[i]; score > 0 {
+			if boost, exists := boosts[invertedIndex.DocValues.Sources[i]]; exists {
+				score *= boost
+			}
+			// Term query yang berdekatan (proximity.go)
+			score *= cfg.Proximity.multiplier(nearTerms, queryVector, invertedIndex.postingPositions(i))
+			score *= linkAuthorityBoost(linkAuthority, cfg.LinkAuthorityWeight, article.URL)
+			if article.Content == "" && hasDocStore() && !archived {
+				stored, err := storedArticle(i)
+				if err != nil {
+					log.Printf("Error reading doc %d from document store: %v", i, err)
+				}
+				article.Content = stored.Content
+			}
+
+			var contentPreview, highlightedContent string
+			// Text fragment diambil dari paragraf terbaik kalau ada
+			fragmentSource := article.Content
+			if passage, exists := passages[i]; exists {
+				contentPreview, highlightedContent = invertedIndex.Paragraphs.snippet(article, passage, query, queryVector, 160, cfg.StoreOffsets)
+				fragmentSource, _ = invertedIndex.Paragraphs.text(article.Content, passage)
+			} else if cfg.StoreOffsets {
+				contentPreview, highlightedContent = offsetSnippet(article, invertedIndex, i, queryVector, 160)
+			} else {
+				contentPreview = getContentPreview(article.Content, query, article.Language, 160)
+				highlightedContent = highlightText(template.HTMLEscapeString(contentPreview), query)
+			}
+
+			// Text fragment hanya berguna untuk halaman web
+			fragment := ""
+			if invertedIndex.DocValues.DocTypes[i] == DOCTYPE_ARTICLE {
+				fragment = textFragment(fragmentSource, article.Language, queryVector)
+			}
+			page := 0
+			if len(article.Pages) > 0 {
+				page = bestPage(article.Pages, article.Language, queryVector)
+			}
 
 			results = append(results, SearchResult{
+				DocID:              i,
 				Title:              article.Title,
 				Content:            contentPreview,
 				URL:                article.URL,
 				Score:              score,
 				HighlightedContent: template.HTML(highlightedContent),
 				Favicon:            getFaviconPath(article.URL),
+				Author:             invertedIndex.DocValues.Authors[i],
+				Category:           invertedIndex.DocValues.Categories[i],
+				TextFragment:       fragment,
+				Words:              invertedIndex.DocValues.Words[i],
+				ReadingMinutes:     invertedIndex.DocValues.ReadingMinutes[i],
+				Date:               article.Date,
+				Price:              invertedIndex.DocValues.Prices[i],
+				Archived:           archived,
+				DocType:            invertedIndex.DocValues.DocTypes[i],
+				Page:               page,
+				Fields:             cfg.Schema.storedFields(article),
 			})
 		}
 	}
 
-	// Sort results by score descending
+	// Phrase dalam tanda kutip yang cocok persis diranking di atas term yang terpisah
+	if phrases := queryPhraseTerms(query); len(phrases) > 0 {
+		plan.Phrases = queryPhrases(query)
+		plan.PhraseMatches = invertedIndex.boostPhraseMatches(results, phrases)
+	}
+
+	// Sort results by score descending, skor sama diurutkan per docID supaya deterministik
 	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].DocID < results[j].DocID
 	})
 
-	return results
+	// Sort lain (tanggal/harga) memakai doc values, score jadi tie-breaker
+	invertedIndex.DocValues.Sort(results, opts.Sort)
+
+	return results, plan
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Perintah CLI (searchctl), dijalankan lewat binary yang sama:
+//
+//	go build -o searchctl . && ./searchctl index verify
+//	go run . index verify
+//
+// Tanpa argumen binary menjalankan web server seperti biasa.
+var searchctlCommands = map[string]func(args []string) int{
+	"index verify":    indexVerifyCommand,
+	"corpus generate": corpusGenerateCommand,
+	"bench scenario":  benchScenarioCommand,
+	"bench analyzer":  benchAnalyzerCommand,
+	"bench index":     benchIndexCommand,
+	"analyzer parity": analyzerParityCommand,
+	"analyzer check":  analyzerCheckCommand,
+	"crawl":           crawlCommand,
+	"snapshot take":   snapshotTakeCommand,
+	"snapshot diff":   snapshotDiffCommand,
+	"corpus diff":     corpusDiffCommand,
+	"similar":         similarCommand,
+	"extract":         extractCommand,
+	"validate":        validateCommand,
+	"replay":          replayCommand,
+}
+
+func runSearchctl(args []string) int {
+	for words := 2; words >= 1; words-- {
+		if len(args) < words {
+			continue
+		}
+		if command, exists := searchctlCommands[strings.Join(args[:words], " ")]; exists {
+			return command(args[words:])
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, "usage: searchctl <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	names := make([]string, 0, len(searchctlCommands))
+	for name := range searchctlCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintln(os.Stderr, "  "+name)
+	}
+	return 2
+}
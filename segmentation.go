@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Segmentasi query yang diketik tanpa spasi ("hargarumahsubsidi" -> "harga
+// rumah subsidi"). Kata query yang tidak ada di kosakata corpus dipecah
+// dengan dynamic programming menjadi deretan kata corpus yang paling mungkin
+// (jumlah -log frekuensi terkecil). query_segmentation di config.json:
+//
+//   - rewrite (default): query yang sudah dipecah langsung dicari, dengan link
+//     untuk mencari query asli (segment=false)
+//   - suggest: hasil tetap dari query asli, query yang dipecah jadi saran
+//   - off
+const (
+	SegmentRewrite = "rewrite"
+	SegmentSuggest = "suggest"
+	SegmentOff     = "off"
+
+	// Kata query sependek ini tidak dipecah
+	SEGMENT_MIN_LENGTH = 6
+	// Potongan terpendek dan terpanjang yang dicari di kosakata
+	SEGMENT_MIN_PART = 2
+	SEGMENT_MAX_PART = 20
+
+	RewriteSegmentation = "segmentation"
+)
+
+// Query yang ditulis ulang sebelum dicari (Applied) atau hanya disarankan
+type QueryRewrite struct {
+	Kind     string `json:"kind"` // segmentation atau spelling
+	Original string `json:"original"`
+	Text     string `json:"text"`
+	Applied  bool   `json:"applied"`
+}
+
+// Frekuensi kata (bukan stem) di judul dan konten dokumen publik, dipakai
+// segmentasi dan koreksi ejaan query (spelling.go)
+type QueryVocabulary struct {
+	mu     sync.RWMutex
+	counts map[string]int
+	total  int
+	// BK-tree koreksi ejaan, dibangun saat pertama dibutuhkan
+	spelling *BKTree
+}
+
+var queryVocabulary = &QueryVocabulary{}
+
+// Dipanggil noteIndexGeneration, sama seperti kosakata saran
+func updateQueryVocabulary(articles []Article) {
+	counts := make(map[string]int)
+	total := 0
+	for _, article := range articles {
+		// Kata dari dokumen internal tidak boleh bocor lewat query yang dipecah
+		if article.Deleted() || article.visibility() != VisibilityPublic {
+			continue
+		}
+		for _, text := range []string{article.Title, article.Content} {
+			for _, tok := range textProcessor.splitWords(nil, strings.ToLower(text)) {
+				counts[tok.Term]++
+				total++
+			}
+		}
+	}
+
+	queryVocabulary.mu.Lock()
+	queryVocabulary.counts, queryVocabulary.total = counts, total
+	queryVocabulary.spelling = nil
+	queryVocabulary.mu.Unlock()
+}
+
+// Pecah satu kata menjadi kata-kata corpus; false kalau kata itu sendiri
+// ada di corpus atau tidak bisa dipecah seluruhnya
+func (qv *QueryVocabulary) segment(word string) ([]string, bool) {
+	qv.mu.RLock()
+	defer qv.mu.RUnlock()
+
+	runes := []rune(word)
+	if len(runes) < SEGMENT_MIN_LENGTH || qv.total == 0 || qv.counts[word] > 0 {
+		return nil, false
+	}
+
+	// cost[i]: biaya terkecil untuk memecah runes[:i], prev[i]: awal kata terakhirnya
+	cost := make([]float64, len(runes)+1)
+	prev := make([]int, len(runes)+1)
+	for i := 1; i <= len(runes); i++ {
+		cost[i] = math.Inf(1)
+		for j := i - SEGMENT_MIN_PART; j >= 0 && i-j <= SEGMENT_MAX_PART; j-- {
+			count := qv.counts[string(runes[j:i])]
+			if count == 0 || math.IsInf(cost[j], 1) {
+				continue
+			}
+			if c := cost[j] - math.Log(float64(count)/float64(qv.total)); c < cost[i] {
+				cost[i], prev[i] = c, j
+			}
+		}
+	}
+	if math.IsInf(cost[len(runes)], 1) {
+		return nil, false
+	}
+
+	var parts []string
+	for i := len(runes); i > 0; i = prev[i] {
+		parts = append([]string{string(runes[prev[i]:i])}, parts...)
+	}
+	return parts, len(parts) > 1
+}
+
+// Query dengan kata gabungan yang sudah dipecah, nil kalau tidak ada yang berubah
+func segmentQuery(query string, opts SearchOptions) *QueryRewrite {
+	mode := opts.withConfig().Config.QuerySegmentation
+	if mode == SegmentOff || opts.NoSegment {
+		return nil
+	}
+
+	words := strings.Fields(strings.ToLower(query))
+	changed := false
+	for i, word := range words {
+		if !isPlainWord(word) {
+			continue
+		}
+		if parts, ok := queryVocabulary.segment(word); ok {
+			words[i] = strings.Join(parts, " ")
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return &QueryRewrite{
+		Kind:     RewriteSegmentation,
+		Original: query,
+		Text:     strings.Join(words, " "),
+		Applied:  mode != SegmentSuggest,
+	}
+}
+
+// Hanya huruf dan angka; phrase dalam kutip dan sintaks query lain tidak diubah
+func isPlainWord(word string) bool {
+	for _, r := range word {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateQuerySegmentation(mode string) error {
+	switch mode {
+	case SegmentRewrite, SegmentSuggest, SegmentOff:
+		return nil
+	}
+	return fmt.Errorf("must be %s, %s or %s", SegmentRewrite, SegmentSuggest, SegmentOff)
+}
+
+func (p *QueryPlan) rewrite() *QueryRewrite {
+	if p == nil {
+		return nil
+	}
+	return p.Rewrite
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Shadow traffic: selama shadow_features di-set, setiap query produksi juga
+// dijalankan di background dengan flag itu dinyalakan (ranker kandidat).
+// Hasilnya tidak pernah dikirim ke user; yang dicatat ke shadow.log hanya
+// overlap top-k, korelasi ranking (Kendall tau) dan selisih latency, supaya
+// flag bisa divalidasi dengan traffic nyata sebelum di-rollout.
+const (
+	SHADOW_LOG_FILE     = "shadow.log"
+	SHADOW_TOP_K        = 10
+	SHADOW_MAX_INFLIGHT = 4 // query shadow yang berjalan bersamaan; sisanya dilewati
+)
+
+type ShadowLogEntry struct {
+	Time       time.Time `json:"time"`
+	Query      string    `json:"query"`
+	Method     string    `json:"method"`
+	Features   []string  `json:"features"`
+	Overlap    float64   `json:"overlap"`               // irisan top-k / daftar terpanjang
+	KendallTau *float64  `json:"kendall_tau,omitempty"` // atas dokumen di kedua top-k, nil kalau < 2
+	// Jumlah hasil dan latency produksi vs kandidat
+	ProductionResults int     `json:"production_results"`
+	CandidateResults  int     `json:"candidate_results"`
+	ProductionMs      float64 `json:"production_ms"`
+	CandidateMs       float64 `json:"candidate_ms"`
+	DeltaMs           float64 `json:"delta_ms"`
+}
+
+var (
+	shadowSlots = make(chan struct{}, SHADOW_MAX_INFLIGHT)
+	shadowLogMu sync.Mutex
+)
+
+// Opsi ranker kandidat: opsi produksi plus shadow_features. ok false kalau
+// shadow mati atau semua flag kandidat sudah aktif di request produksi.
+func shadowOptions(opts SearchOptions) (SearchOptions, bool) {
+	opts = opts.withConfig()
+	if len(opts.Config.ShadowFeatures) == 0 || benchMode {
+		return opts, false
+	}
+
+	features := append([]string(nil), opts.Features...)
+	added := false
+	for _, feature := range opts.Config.ShadowFeatures {
+		if !opts.Feature(feature) {
+			features = append(features, feature)
+			added = true
+		}
+	}
+	sort.Strings(features)
+	opts.Features = features
+	return opts, added
+}
+
+// Jalankan query yang sama dengan ranker kandidat tanpa menahan response
+func shadowQuery(query, method string, opts SearchOptions, production []SearchResult, productionDuration time.Duration) {
+	candidateOpts, ok := shadowOptions(opts)
+	if !ok {
+		return
+	}
+	select {
+	case shadowSlots <- struct{}{}:
+	default:
+		return
+	}
+
+	go func() {
+		defer func() { <-shadowSlots }()
+
+		start := time.Now()
+		candidate, _ := searchWithPlan(query, method, candidateOpts)
+		candidateDuration := time.Since(start)
+
+		overlap, tau := compareRankings(topDocIDs(production, SHADOW_TOP_K), topDocIDs(candidate, SHADOW_TOP_K))
+		writeShadowLog(ShadowLogEntry{
+			Time:              start,
+			Query:             query,
+			Method:            method,
+			Features:          candidateOpts.Config.ShadowFeatures,
+			Overlap:           overlap,
+			KendallTau:        tau,
+			ProductionResults: len(production),
+			CandidateResults:  len(candidate),
+			ProductionMs:      float64(productionDuration.Microseconds()) / 1000,
+			CandidateMs:       float64(candidateDuration.Microseconds()) / 1000,
+			DeltaMs:           float64((candidateDuration - productionDuration).Microseconds()) / 1000,
+		})
+	}()
+}
+
+func topDocIDs(results []SearchResult, k int) []int {
+	if len(results) > k {
+		results = results[:k]
+	}
+	ids := make([]int, len(results))
+	for i, result := range results {
+		ids[i] = result.DocID
+	}
+	return ids
+}
+
+// Overlap top-k dan Kendall tau atas urutan dokumen yang ada di kedua daftar
+func compareRankings(production, candidate []int) (float64, *float64) {
+	candidateRank := make(map[int]int, len(candidate))
+	for rank, docID := range candidate {
+		candidateRank[docID] = rank
+	}
+
+	// Peringkat kandidat untuk dokumen bersama, dalam urutan produksi
+	var shared []int
+	for _, docID := range production {
+		if rank, exists := candidateRank[docID]; exists {
+			shared = append(shared, rank)
+		}
+	}
+
+	overlap := 1.0
+	if longest := len(production); longest > 0 || len(candidate) > 0 {
+		if len(candidate) > longest {
+			longest = len(candidate)
+		}
+		overlap = float64(len(shared)) / float64(longest)
+	}
+	if len(shared) < 2 {
+		return overlap, nil
+	}
+
+	concordant, discordant := 0, 0
+	for i := 0; i < len(shared); i++ {
+		for j := i + 1; j < len(shared); j++ {
+			if shared[i] < shared[j] {
+				concordant++
+			} else {
+				discordant++
+			}
+		}
+	}
+	tau := float64(concordant-discordant) / float64(concordant+discordant)
+	return overlap, &tau
+}
+
+func writeShadowLog(entry ShadowLogEntry) {
+	shadowLogMu.Lock()
+	defer shadowLogMu.Unlock()
+
+	f, err := os.OpenFile(SHADOW_LOG_FILE, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Printf("Error opening shadow log: %v", err)
+		return
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Error encoding shadow log entry: %v", err)
+		return
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		log.Printf("Error writing shadow log: %v", err)
+	}
+}
+
+type ShadowSummary struct {
+	Queries       int      `json:"queries"`
+	Features      []string `json:"features"`
+	MeanOverlap   float64  `json:"mean_overlap"`
+	MeanTau       float64  `json:"mean_kendall_tau"`
+	MeanDeltaMs   float64  `json:"mean_delta_ms"`
+	P95DeltaMs    float64  `json:"p95_delta_ms"`
+	ZeroResultGap int      `json:"zero_result_gap"` // query yang hanya kosong di salah satu ranker
+}
+
+// Ringkasan shadow.log sejak waktu tertentu
+func summarizeShadowLog(path string, since time.Time, features []string) (ShadowSummary, error) {
+	summary := ShadowSummary{Features: features}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return summary, nil
+		}
+		return summary, err
+	}
+	defer f.Close()
+
+	var deltas []float64
+	taus := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry ShadowLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if entry.Time.Before(since) {
+			continue
+		}
+		summary.Queries++
+		summary.MeanOverlap += entry.Overlap
+		if entry.KendallTau != nil {
+			summary.MeanTau += *entry.KendallTau
+			taus++
+		}
+		if (entry.ProductionResults == 0) != (entry.CandidateResults == 0) {
+			summary.ZeroResultGap++
+		}
+		deltas = append(deltas, entry.DeltaMs)
+		summary.MeanDeltaMs += entry.DeltaMs
+	}
+
+	if summary.Queries > 0 {
+		summary.MeanOverlap /= float64(summary.Queries)
+		summary.MeanDeltaMs /= float64(summary.Queries)
+		sort.Float64s(deltas)
+		summary.P95DeltaMs = deltas[(len(deltas)-1)*95/100]
+	}
+	if taus > 0 {
+		summary.MeanTau /= float64(taus)
+	}
+	return summary, scanner.Err()
+}
+
+// GET /api/admin/shadow?hours=24
+func shadowSummaryHandler(c *gin.Context) {
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if err != nil || hours <= 0 {
+		hours = 24
+	}
+	summary, err := summarizeShadowLog(SHADOW_LOG_FILE, time.Now().Add(-time.Duration(hours)*time.Hour), requestConfig(c).ShadowFeatures)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
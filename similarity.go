@@ -0,0 +1,267 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Laporan kemiripan (plagiarisme): untuk sebuah dokumen corpus atau teks
+// yang ditempel, cari dokumen corpus yang paling mirip berdasarkan shingle
+// (lihat syndication.go) beserta passage yang sama persis, disejajarkan
+// antara teks sumber dan dokumen. Berguna untuk mengenali content farm di
+// antara kandidat source baru.
+const (
+	SIMILARITY_LIMIT    = 10
+	SIMILARITY_PASSAGES = 5 // passage terpanjang per dokumen
+)
+
+type AlignedPassage struct {
+	SourceOffset int    `json:"source_offset"` // posisi kata di teks sumber
+	DocOffset    int    `json:"doc_offset"`    // posisi kata di dokumen
+	Words        int    `json:"words"`
+	Text         string `json:"text"`
+}
+
+type SimilarDoc struct {
+	DocID          int              `json:"doc_id"`
+	URL            string           `json:"url"`
+	Title          string           `json:"title"`
+	Source         string           `json:"source"`
+	Similarity     float64          `json:"similarity"`  // Jaccard shingle
+	Containment    float64          `json:"containment"` // fraksi shingle sumber yang ada di dokumen
+	SharedShingles int              `json:"shared_shingles"`
+	Passages       []AlignedPassage `json:"passages"`
+}
+
+type SimilarityReport struct {
+	SourceDocID *int         `json:"source_doc_id,omitempty"`
+	SourceWords int          `json:"source_words"`
+	Matches     []SimilarDoc `json:"matches"`
+}
+
+// Corpus dengan docID yang sama dengan index (articles.json + WAL + koreksi admin)
+func corpusArticles() ([]Article, error) {
+	articles, err := loadArticles()
+	if err != nil {
+		return nil, err
+	}
+	articles = applyWalRecords(articles, documentLog.Pending(), currentConfig())
+	reviewQueue.ApplyEdits(articles)
+	return articles, nil
+}
+
+// Dokumen yang paling mirip dengan text, diurutkan dari containment
+// tertinggi; skipDocID (-1 = tidak ada) tidak ikut dibandingkan
+func similarDocuments(articles []Article, text string, skipDocID, limit int) SimilarityReport {
+	words := fingerprintWords(text)
+	report := SimilarityReport{SourceWords: len(words), Matches: []SimilarDoc{}}
+	source := shingleSet(words)
+	if len(source) == 0 {
+		return report
+	}
+
+	for docID, article := range articles {
+		if docID == skipDocID {
+			continue
+		}
+		docWords := fingerprintWords(article.Content)
+		shingles := shingleSet(docWords)
+		shared := 0
+		for shingle := range source {
+			if shingles[shingle] {
+				shared++
+			}
+		}
+		if shared == 0 {
+			continue
+		}
+		report.Matches = append(report.Matches, SimilarDoc{
+			DocID:          docID,
+			URL:            article.URL,
+			Title:          article.Title,
+			Source:         sourceOf(article.URL),
+			Similarity:     float64(shared) / float64(len(source)+len(shingles)-shared),
+			Containment:    float64(shared) / float64(len(source)),
+			SharedShingles: shared,
+			Passages:       alignPassages(words, docWords),
+		})
+	}
+
+	sort.Slice(report.Matches, func(i, j int) bool {
+		a, b := report.Matches[i], report.Matches[j]
+		if a.Containment != b.Containment {
+			return a.Containment > b.Containment
+		}
+		return a.DocID < b.DocID
+	})
+	if len(report.Matches) > limit {
+		report.Matches = report.Matches[:limit]
+	}
+	return report
+}
+
+// Passage yang sama di kedua teks: berawal dari shingle yang sama lalu
+// diperpanjang kata demi kata selama masih cocok. Passage terpanjang dulu.
+func alignPassages(source, doc []string) []AlignedPassage {
+	positions := make(map[uint64][]int)
+	for j, shingle := range shingleHashes(doc) {
+		positions[shingle] = append(positions[shingle], j)
+	}
+
+	var passages []AlignedPassage
+	for i, shingle := range shingleHashes(source) {
+		for _, j := range positions[shingle] {
+			// Lewati kalau passage ini kelanjutan dari passage sebelumnya
+			if i > 0 && j > 0 && source[i-1] == doc[j-1] {
+				continue
+			}
+			length := 0
+			for i+length < len(source) && j+length < len(doc) && source[i+length] == doc[j+length] {
+				length++
+			}
+			if length < SHINGLE_SIZE {
+				continue // tabrakan hash
+			}
+			passages = append(passages, AlignedPassage{
+				SourceOffset: i,
+				DocOffset:    j,
+				Words:        length,
+				Text:         strings.Join(source[i:i+length], " "),
+			})
+		}
+	}
+
+	sort.Slice(passages, func(a, b int) bool {
+		if passages[a].Words != passages[b].Words {
+			return passages[a].Words > passages[b].Words
+		}
+		return passages[a].SourceOffset < passages[b].SourceOffset
+	})
+	if len(passages) > SIMILARITY_PASSAGES {
+		passages = passages[:SIMILARITY_PASSAGES]
+	}
+	return passages
+}
+
+// Laporan untuk dokumen corpus (docID >= 0) atau teks
+func similarityReport(docID int, text string, limit int) (*SimilarityReport, error) {
+	articles, err := corpusArticles()
+	if err != nil {
+		return nil, err
+	}
+	if docID >= 0 {
+		if docID >= len(articles) {
+			return nil, fmt.Errorf("doc %d not found", docID)
+		}
+		text = articles[docID].Content
+	}
+	if limit <= 0 {
+		limit = SIMILARITY_LIMIT
+	}
+
+	report := similarDocuments(articles, text, docID, limit)
+	if docID >= 0 {
+		report.SourceDocID = &docID
+	}
+	return &report, nil
+}
+
+// POST /api/admin/similarity, body: {"doc_id": 12} atau {"text": "..."}, opsional "limit"
+func similarityHandler(c *gin.Context) {
+	var req struct {
+		DocID *int   `json:"doc_id"`
+		Text  string `json:"text"`
+		Limit int    `json:"limit"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if (req.DocID == nil) == (strings.TrimSpace(req.Text) == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of doc_id or text is required"})
+		return
+	}
+
+	docID := -1
+	if req.DocID != nil {
+		if *req.DocID < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid doc_id"})
+			return
+		}
+		docID = *req.DocID
+	}
+	report, err := similarityReport(docID, req.Text, req.Limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// searchctl similar [--limit n] [--json] <doc-id> | --file path | --text "..."
+func similarCommand(args []string) int {
+	fs := flag.NewFlagSet("similar", flag.ExitOnError)
+	limit := fs.Int("limit", SIMILARITY_LIMIT, "jumlah dokumen")
+	file := fs.String("file", "", "bandingkan isi file ini (- untuk stdin)")
+	text := fs.String("text", "", "bandingkan teks ini")
+	asJSON := fs.Bool("json", false, "cetak laporan sebagai JSON")
+	fs.Parse(args)
+
+	docID := -1
+	switch {
+	case fs.NArg() == 1 && *file == "" && *text == "":
+		id, err := strconv.Atoi(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid doc id %q\n", fs.Arg(0))
+			return 2
+		}
+		docID = id
+	case fs.NArg() == 0 && *file != "" && *text == "":
+		var data []byte
+		var err error
+		if *file == "-" {
+			data, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			data, err = ioutil.ReadFile(*file)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		*text = string(data)
+	case fs.NArg() == 0 && *file == "" && *text != "":
+	default:
+		fmt.Fprintln(os.Stderr, "usage: searchctl similar [--limit n] [--json] <doc-id> | --file path | --text \"...\"")
+		return 2
+	}
+
+	report, err := similarityReport(docID, *text, *limit)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *asJSON {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		return 0
+	}
+
+	fmt.Printf("%d words, %d similar documents\n", report.SourceWords, len(report.Matches))
+	for _, match := range report.Matches {
+		fmt.Printf("\n#%d %s (%s)\n  %s\n  containment %.2f, similarity %.2f, %d shared shingles\n",
+			match.DocID, match.Title, match.Source, match.URL, match.Containment, match.Similarity, match.SharedShingles)
+		for _, passage := range match.Passages {
+			fmt.Printf("  [%d -> %d, %d words] %s\n", passage.SourceOffset, passage.DocOffset, passage.Words, passage.Text)
+		}
+	}
+	return 0
+}
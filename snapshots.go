@@ -0,0 +1,362 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Snapshot hasil untuk deteksi regresi antar build index. Query tetap dari
+// regression_queries.json dijalankan setiap generasi index baru aktif; top-k
+// hasilnya disimpan ke snapshots/latest.json dan dibandingkan dengan snapshot
+// generasi sebelumnya (snapshots/previous.json). Laporan diff (hasil yang
+// naik/turun, baru, hilang, selisih skor) ditulis ke snapshots/report.json.
+// Hasil dicocokkan lewat URL karena docID berubah antar build.
+//
+// Manual: searchctl snapshot take --out a.json, lalu searchctl snapshot diff a.json b.json
+const (
+	REGRESSION_QUERIES_FILE = "regression_queries.json"
+	SNAPSHOT_DIR            = "snapshots"
+	SNAPSHOT_TOP_K          = 20
+
+	JobRegressionSnapshot = "regression_snapshot"
+)
+
+type RegressionQuery struct {
+	Query  string `json:"query"`
+	Method string `json:"method,omitempty"` // default cosine
+}
+
+type SnapshotResult struct {
+	URL   string  `json:"url"`
+	Title string  `json:"title"`
+	Score float64 `json:"score"`
+}
+
+type QuerySnapshot struct {
+	RegressionQuery
+	Total   int              `json:"total"`
+	Results []SnapshotResult `json:"results"`
+}
+
+type ResultSnapshot struct {
+	Generation int             `json:"generation,omitempty"`
+	Time       time.Time       `json:"time"`
+	Queries    []QuerySnapshot `json:"queries"`
+}
+
+// Perubahan satu hasil antar snapshot; rank 1-based, 0 = tidak ada di top-k
+type ResultChange struct {
+	URL        string  `json:"url"`
+	Title      string  `json:"title"`
+	FromRank   int     `json:"from_rank"`
+	ToRank     int     `json:"to_rank"`
+	ScoreDelta float64 `json:"score_delta"`
+}
+
+type QueryDiff struct {
+	RegressionQuery
+	TotalBefore int            `json:"total_before"`
+	TotalAfter  int            `json:"total_after"`
+	Added       []ResultChange `json:"added,omitempty"`
+	Dropped     []ResultChange `json:"dropped,omitempty"`
+	Moved       []ResultChange `json:"moved,omitempty"`
+	// Selisih skor absolut terbesar di antara hasil yang ada di kedua snapshot
+	MaxScoreDelta float64 `json:"max_score_delta"`
+}
+
+type SnapshotReport struct {
+	Before  time.Time   `json:"before"`
+	After   time.Time   `json:"after"`
+	Queries int         `json:"queries"`
+	Changed int         `json:"changed"`
+	Diffs   []QueryDiff `json:"diffs"` // hanya query yang berubah
+}
+
+// Diaktifkan startBackgroundJobs, supaya perintah searchctl yang membangun
+// index tidak ikut menulis snapshot
+var snapshotOnReindex bool
+
+// Generasi yang berganti cepat tidak boleh merotasi snapshot bersamaan
+var snapshotMu sync.Mutex
+
+func loadRegressionQueries(path string) ([]RegressionQuery, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var queries []RegressionQuery
+	if err := json.Unmarshal(data, &queries); err != nil {
+		return nil, err
+	}
+	for i := range queries {
+		if queries[i].Method == "" {
+			queries[i].Method = "cosine"
+		}
+	}
+	return queries, nil
+}
+
+func takeSnapshot(queries []RegressionQuery, topK int) *ResultSnapshot {
+	snapshot := &ResultSnapshot{Time: time.Now()}
+	for _, query := range queries {
+		results, _ := searchWithPlan(query.Query, query.Method, SearchOptions{Sort: SortRelevance})
+		qs := QuerySnapshot{RegressionQuery: query, Total: len(results), Results: []SnapshotResult{}}
+		for i, result := range results {
+			if i >= topK {
+				break
+			}
+			qs.Results = append(qs.Results, SnapshotResult{URL: result.URL, Title: result.Title, Score: result.Score})
+		}
+		snapshot.Queries = append(snapshot.Queries, qs)
+	}
+	return snapshot
+}
+
+func diffSnapshots(before, after *ResultSnapshot) SnapshotReport {
+	report := SnapshotReport{Before: before.Time, After: after.Time, Diffs: []QueryDiff{}}
+
+	previous := make(map[RegressionQuery]QuerySnapshot, len(before.Queries))
+	for _, qs := range before.Queries {
+		previous[qs.RegressionQuery] = qs
+	}
+
+	for _, current := range after.Queries {
+		report.Queries++
+		old, exists := previous[current.RegressionQuery]
+		if !exists {
+			// Query baru di regression_queries.json, belum ada pembanding
+			continue
+		}
+		diff := diffQuerySnapshots(old, current)
+		if diff.TotalBefore != diff.TotalAfter || len(diff.Added) > 0 || len(diff.Dropped) > 0 || len(diff.Moved) > 0 {
+			report.Changed++
+			report.Diffs = append(report.Diffs, diff)
+		}
+	}
+	return report
+}
+
+func diffQuerySnapshots(before, after QuerySnapshot) QueryDiff {
+	diff := QueryDiff{RegressionQuery: after.RegressionQuery, TotalBefore: before.Total, TotalAfter: after.Total}
+
+	beforeRank := make(map[string]int, len(before.Results))
+	for rank, result := range before.Results {
+		beforeRank[result.URL] = rank
+	}
+	seen := make(map[string]bool, len(after.Results))
+
+	for rank, result := range after.Results {
+		seen[result.URL] = true
+		oldRank, exists := beforeRank[result.URL]
+		if !exists {
+			diff.Added = append(diff.Added, ResultChange{URL: result.URL, Title: result.Title, ToRank: rank + 1, ScoreDelta: result.Score})
+			continue
+		}
+		delta := result.Score - before.Results[oldRank].Score
+		if delta < 0 && -delta > diff.MaxScoreDelta {
+			diff.MaxScoreDelta = -delta
+		} else if delta > diff.MaxScoreDelta {
+			diff.MaxScoreDelta = delta
+		}
+		if oldRank != rank {
+			diff.Moved = append(diff.Moved, ResultChange{URL: result.URL, Title: result.Title, FromRank: oldRank + 1, ToRank: rank + 1, ScoreDelta: delta})
+		}
+	}
+	for rank, result := range before.Results {
+		if !seen[result.URL] {
+			diff.Dropped = append(diff.Dropped, ResultChange{URL: result.URL, Title: result.Title, FromRank: rank + 1, ScoreDelta: -result.Score})
+		}
+	}
+	return diff
+}
+
+func readSnapshot(path string) (*ResultSnapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot ResultSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Job regression_snapshot, dijadwalkan noteIndexGeneration dengan param generation
+func regressionSnapshotJob(ctx context.Context, job jobRun) error {
+	generation, err := strconv.Atoi(job.Params()["generation"])
+	if err != nil {
+		return fmt.Errorf("invalid generation: %v", err)
+	}
+	return runRegressionSnapshot(generation)
+}
+
+func runRegressionSnapshot(generation int) error {
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+
+	queries, err := loadRegressionQueries(REGRESSION_QUERIES_FILE)
+	if err != nil {
+		return fmt.Errorf("loading %s: %v", REGRESSION_QUERIES_FILE, err)
+	}
+	if len(queries) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(SNAPSHOT_DIR, 0755); err != nil {
+		return err
+	}
+
+	snapshot := takeSnapshot(queries, SNAPSHOT_TOP_K)
+	snapshot.Generation = generation
+
+	latest := filepath.Join(SNAPSHOT_DIR, "latest.json")
+	previous, err := readSnapshot(latest)
+	if err == nil {
+		if err := os.Rename(latest, filepath.Join(SNAPSHOT_DIR, "previous.json")); err != nil {
+			log.Printf("Error rotating snapshot: %v", err)
+		}
+	} else if !os.IsNotExist(err) {
+		log.Printf("Error reading snapshot: %v", err)
+	}
+	if err := writeJSONFile(latest, snapshot); err != nil {
+		return err
+	}
+	if previous == nil {
+		return nil
+	}
+
+	report := diffSnapshots(previous, snapshot)
+	if err := writeJSONFile(filepath.Join(SNAPSHOT_DIR, "report.json"), report); err != nil {
+		log.Printf("Error writing snapshot report: %v", err)
+	}
+	if report.Changed > 0 {
+		log.Printf("Regression snapshot (generation %d): %d of %d queries changed, see %s/report.json", generation, report.Changed, report.Queries, SNAPSHOT_DIR)
+	}
+	return nil
+}
+
+// GET /api/admin/snapshots/report: laporan diff terakhir
+func snapshotReportHandler(c *gin.Context) {
+	data, err := ioutil.ReadFile(filepath.Join(SNAPSHOT_DIR, "report.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no snapshot report yet"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// searchctl snapshot take [--queries file] [--out file]
+func snapshotTakeCommand(args []string) int {
+	fs := flag.NewFlagSet("snapshot take", flag.ExitOnError)
+	queriesPath := fs.String("queries", REGRESSION_QUERIES_FILE, "daftar query tetap")
+	out := fs.String("out", "", "file output (default stdout)")
+	topK := fs.Int("top", SNAPSHOT_TOP_K, "jumlah hasil per query")
+	fs.Parse(args)
+
+	queries, err := loadRegressionQueries(*queriesPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(queries) == 0 {
+		fmt.Fprintf(os.Stderr, "no queries in %s\n", *queriesPath)
+		return 1
+	}
+
+	snapshot := takeSnapshot(queries, *topK)
+	if *out == "" {
+		data, _ := json.MarshalIndent(snapshot, "", "  ")
+		fmt.Println(string(data))
+		return 0
+	}
+	if err := writeJSONFile(*out, snapshot); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Printf("wrote %d queries to %s\n", len(snapshot.Queries), *out)
+	return 0
+}
+
+// searchctl snapshot diff <before.json> <after.json>; exit 1 kalau ada perubahan
+func snapshotDiffCommand(args []string) int {
+	fs := flag.NewFlagSet("snapshot diff", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "cetak laporan sebagai JSON")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: searchctl snapshot diff [--json] <before.json> <after.json>")
+		return 2
+	}
+
+	before, err := readSnapshot(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	after, err := readSnapshot(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	report := diffSnapshots(before, after)
+	if *asJSON {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		printSnapshotReport(report)
+	}
+	if report.Changed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func printSnapshotReport(report SnapshotReport) {
+	sort.Slice(report.Diffs, func(i, j int) bool { return report.Diffs[i].Query < report.Diffs[j].Query })
+	for _, diff := range report.Diffs {
+		fmt.Printf("%q (%s): %d -> %d results, max score delta %.4f\n", diff.Query, diff.Method, diff.TotalBefore, diff.TotalAfter, diff.MaxScoreDelta)
+		for _, change := range diff.Added {
+			fmt.Printf("  + #%d %s\n", change.ToRank, change.URL)
+		}
+		for _, change := range diff.Dropped {
+			fmt.Printf("  - #%d %s\n", change.FromRank, change.URL)
+		}
+		for _, change := range diff.Moved {
+			fmt.Printf("  ~ #%d -> #%d %s (score %+.4f)\n", change.FromRank, change.ToRank, change.URL, change.ScoreDelta)
+		}
+	}
+	fmt.Printf("%d queries, %d changed\n", report.Queries, report.Changed)
+}
+
+func init() {
+	registerJobKind(JobRegressionSnapshot, "", maintenanceRetry, regressionSnapshotJob)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Jarak karakter sebelum match pertama di snippet
+const SNIPPET_LEAD = 60
+
+// Buat snippet dan highlight langsung dari byte offset di posting list,
+// tanpa menganalisis ulang dokumen. Offset relatif terhadap title + " " + content.
+func offsetSnippet(article Article, idx *InvertedIndex, docID int, queryVector map[string]float64, maxLength int) (string, string) {
+	content := article.Content
+	base := len(article.Title) + 1
+
+	var matches []Offset
+	for term := range queryVector {
+		postingList, exists := idx.Index[term]
+		if !exists {
+			continue
+		}
+		posting, exists := postingList.Postings[docID]
+		if !exists {
+			continue
+		}
+		for _, off := range posting.Offsets {
+			// Match di judul atau field schema tambahan tidak dipakai untuk snippet konten
+			if off.Start < base || off.Start >= base+len(content) {
+				continue
+			}
+			matches = append(matches, Offset{Start: off.Start - base, End: off.End - base})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+
+	first := Offset{Start: -1, End: -1}
+	if len(matches) > 0 {
+		first = matches[0]
+	}
+	start, end, cutStart, cutEnd := snippetBounds(content, first, maxLength)
+
+	window := content[start:end]
+
+	// Bangun highlight dengan escape HTML di luar match
+	var b strings.Builder
+	cursor := start
+	for _, m := range matches {
+		if m.Start < cursor || m.End > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(content[cursor:m.Start]))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(content[m.Start:m.End]))
+		b.WriteString("</em>")
+		cursor = m.End
+	}
+	b.WriteString(html.EscapeString(content[cursor:end]))
+
+	preview := strings.Join(strings.Fields(window), " ")
+	highlighted := strings.Join(strings.Fields(b.String()), " ")
+	if cutStart {
+		preview = "..." + preview
+		highlighted = "..." + highlighted
+	}
+	if cutEnd {
+		preview += "..."
+		highlighted += "..."
+	}
+
+	return preview, highlighted
+}
+
+// Batas snippet di text untuk match pertama (Start -1 kalau tidak ada).
+// Snippet mulai di awal kalimat yang memuat match dan berhenti di akhir
+// kalimat terakhir yang masih muat, jadi terbaca utuh. Kalimat yang terlalu
+// panjang dipotong di batas kata; cutStart/cutEnd true kalau batasnya di
+// tengah kalimat dan perlu "...".
+func snippetBounds(text string, match Offset, maxLength int) (start, end int, cutStart, cutEnd bool) {
+	if match.Start >= 0 {
+		start = sentenceStart(text, match.Start)
+		// Match harus tetap di paruh pertama snippet
+		if match.Start-start > maxLength/2 {
+			start = snapToWordStart(text, match.Start-SNIPPET_LEAD)
+			if start > match.Start {
+				start = match.Start
+			}
+			cutStart = true
+		}
+	}
+
+	limit := start + maxLength
+	if limit >= len(text) {
+		return start, len(text), cutStart, false
+	}
+	end = sentenceEnd(text, start, limit, match.End)
+	// Kalimat terakhir yang muat terlalu pendek untuk snippet
+	if end-start < maxLength/2 {
+		end = snapToWordEnd(text, limit)
+		cutEnd = true
+	}
+	return start, end, cutStart, cutEnd
+}
+
+// Awal kalimat yang memuat pos: setelah akhir kalimat atau baris sebelumnya
+func sentenceStart(text string, pos int) int {
+	i := pos - 1
+	for i >= 0 && text[i] != '\n' && !endsSentence(text, i) {
+		i--
+	}
+	i++
+	for i < pos && isSpaceByte(text[i]) {
+		i++
+	}
+	return i
+}
+
+// Akhir kalimat terakhir di text[start:limit] yang tidak memotong match
+// (berakhir di minEnd atau sesudahnya); start kalau tidak ada
+func sentenceEnd(text string, start, limit, minEnd int) int {
+	end := start
+	for i := start; i < limit; i++ {
+		switch {
+		case text[i] == '\n' && i >= minEnd:
+			end = i
+		case i+1 >= minEnd && endsSentence(text, i):
+			end = i + 1
+		}
+	}
+	return end
+}
+
+// Geser posisi ke awal kata berikutnya supaya snippet tidak mulai di tengah kata
+func snapToWordStart(text string, pos int) int {
+	if pos <= 0 {
+		return 0
+	}
+	if pos >= len(text) {
+		return len(text)
+	}
+	for pos < len(text) && !isSpaceByte(text[pos-1]) {
+		pos++
+	}
+	for pos < len(text) && !utf8.RuneStart(text[pos]) {
+		pos++
+	}
+	return pos
+}
+
+// Mundurkan posisi ke akhir kata sebelumnya
+func snapToWordEnd(text string, pos int) int {
+	orig := pos
+	for pos > 0 && !isSpaceByte(text[pos]) {
+		pos--
+	}
+	if pos == 0 {
+		pos = orig
+		for pos > 0 && !utf8.RuneStart(text[pos]) {
+			pos--
+		}
+	}
+	return pos
+}
+
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\t' || c == '\r'
+}
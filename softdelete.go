@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Soft delete: record delete dari ingest tidak langsung membuang artikel,
+// tapi menandainya DeletedAt. Artikel itu tetap di articles.json dengan
+// docID yang sama, tidak ikut diindex, dan bisa dikembalikan lewat
+// POST /api/documents/:id/restore selama soft_delete_days hari. Setelah
+// itu purger membuangnya permanen (record purge di WAL). soft_delete_days 0
+// berarti delete langsung permanen seperti dulu.
+//
+// Pengarsipan memakai purge, karena artikelnya sudah disalin ke archive.json.
+const (
+	SOFT_DELETE_DAYS           = 7
+	SOFT_DELETE_PURGE_INTERVAL = time.Hour
+
+	JobPurgeDeleted = "purge_deleted"
+)
+
+func (a Article) Deleted() bool {
+	return a.DeletedAt != nil
+}
+
+// Artikel yang di-soft delete
+type DeletedDocument struct {
+	DocID     int       `json:"doc_id"`
+	URL       string    `json:"url"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+func softDeleteWindow(days int) time.Duration {
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func deletedDocuments(articles []Article, window time.Duration) []DeletedDocument {
+	deleted := []DeletedDocument{}
+	for docID, article := range articles {
+		if !article.Deleted() {
+			continue
+		}
+		deleted = append(deleted, DeletedDocument{
+			DocID:     docID,
+			URL:       article.URL,
+			Title:     article.Title,
+			DeletedAt: *article.DeletedAt,
+			PurgeAt:   article.DeletedAt.Add(window),
+		})
+	}
+	sort.SliceStable(deleted, func(i, j int) bool { return deleted[i].DeletedAt.After(deleted[j].DeletedAt) })
+	return deleted
+}
+
+// Record purge untuk artikel yang masa undelete-nya sudah lewat
+func expiredDeletions(articles []Article, window time.Duration, now time.Time) []WalRecord {
+	var records []WalRecord
+	for _, article := range articles {
+		if article.Deleted() && !now.Before(article.DeletedAt.Add(window)) {
+			records = append(records, WalRecord{Op: WalPurge, URL: article.URL})
+		}
+	}
+	return records
+}
+
+func purgeDeleted(now time.Time) (int, error) {
+	articles, err := loadArticles()
+	if err != nil {
+		return 0, err
+	}
+	cfg := currentConfig()
+	records := expiredDeletions(applyWalRecords(articles, documentLog.Pending(), cfg), softDeleteWindow(cfg.SoftDeleteDays), now)
+	if len(records) == 0 {
+		return 0, nil
+	}
+	if _, err := documentLog.Append(records...); err != nil {
+		return 0, err
+	}
+	log.Printf("Purged %d soft-deleted articles", len(records))
+	return len(records), nil
+}
+
+func startSoftDeletePurger(interval time.Duration) {
+	adminJobs.Every(JobPurgeDeleted, interval)
+}
+
+func purgeDeletedJob(ctx context.Context, job jobRun) error {
+	purged, err := purgeDeleted(time.Now())
+	job.Advance(purged, purged)
+	return err
+}
+
+// GET /api/documents/deleted
+func deletedDocumentsHandler(c *gin.Context) {
+	articles, _, err := loadIndex()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	days := requestConfig(c).SoftDeleteDays
+	c.JSON(http.StatusOK, gin.H{
+		"soft_delete_days": days,
+		"documents":        deletedDocuments(articles, softDeleteWindow(days)),
+	})
+}
+
+// POST /api/documents/:id/restore
+func restoreDocumentHandler(c *gin.Context) {
+	docID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid document id"})
+		return
+	}
+	articles, _, err := loadIndex()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if docID < 0 || docID >= len(articles) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "document not found"})
+		return
+	}
+	article := articles[docID]
+	if !article.Deleted() {
+		c.JSON(http.StatusConflict, gin.H{"error": "document is not deleted"})
+		return
+	}
+
+	accepted, err := auditActor(c).appendDocuments(WalRecord{Op: WalRestore, URL: article.URL})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("Restored soft-deleted document %d (%s)", docID, article.URL)
+	c.JSON(http.StatusAccepted, gin.H{"doc_id": docID, "url": article.URL, "seq": accepted[0].Seq})
+}
+
+func init() {
+	registerJobKind(JobPurgeDeleted, "", maintenanceRetry, purgeDeletedJob)
+}
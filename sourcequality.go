@@ -0,0 +1,183 @@
+package main
+
+import (
+	"hash/fnv"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Skor kualitas per source dari sinyal corpus (dihitung ulang setiap
+// generasi index) dan crawler:
+//   - duplicate rate: salinan sindikasi non-kanonik dan konten kembar di source yang sama
+//   - boilerplate ratio: kata di paragraf yang berulang di banyak artikel source itu
+//   - panjang artikel rata-rata, dibandingkan QUALITY_TARGET_WORDS
+//   - dead-link rate: request crawl terakhir yang gagal (lihat crawlprogress.go)
+//
+// Skor 0-1 dipakai sebagai authority boost saat ranking (authority_weight di
+// config.json) dan ditampilkan di dashboard /admin/crawl.
+const (
+	BOILERPLATE_MIN_DOCS  = 3   // paragraf di minimal sekian artikel...
+	BOILERPLATE_MIN_SHARE = 0.1 // ...dan minimal fraksi artikel source ini
+	QUALITY_TARGET_WORDS  = 600 // panjang yang dianggap lengkap
+	AUTHORITY_WEIGHT      = 0.2 // skor 0 -> x0.9, skor 1 -> x1.1
+)
+
+// Bobot tiap sinyal di skor akhir
+var sourceQualityWeights = struct {
+	Duplicates, Boilerplate, Length, DeadLinks float64
+}{0.3, 0.25, 0.25, 0.2}
+
+type SourceQuality struct {
+	Source           string  `json:"source"`
+	Articles         int     `json:"articles"`
+	DuplicateRate    float64 `json:"duplicate_rate"`
+	BoilerplateRatio float64 `json:"boilerplate_ratio"`
+	AvgWords         float64 `json:"avg_words"`
+	DeadLinkRate     float64 `json:"dead_link_rate"`
+	Score            float64 `json:"score"`
+}
+
+// Sinyal corpus per source dari generasi terakhir (tanpa dead-link rate)
+var sourceQualitySignals struct {
+	mu      sync.RWMutex
+	sources map[string]SourceQuality
+}
+
+// Dipanggil noteIndexGeneration setelah updateSyndication
+func updateSourceQuality(articles []Article) {
+	bySource := make(map[string][]Article)
+	for _, article := range articles {
+		source := sourceOf(article.URL)
+		bySource[source] = append(bySource[source], article)
+	}
+
+	sources := make(map[string]SourceQuality, len(bySource))
+	for source, docs := range bySource {
+		sources[source] = corpusQuality(source, docs)
+	}
+
+	sourceQualitySignals.mu.Lock()
+	sourceQualitySignals.sources = sources
+	sourceQualitySignals.mu.Unlock()
+}
+
+func corpusQuality(source string, docs []Article) SourceQuality {
+	quality := SourceQuality{Source: source, Articles: len(docs)}
+	if len(docs) == 0 {
+		return quality
+	}
+
+	// Paragraf dihitung sekali per artikel
+	paragraphDocs := make(map[uint64]int)
+	seenContent := make(map[uint64]bool)
+	duplicates, totalWords := 0, 0
+	for _, doc := range docs {
+		h := fnv.New64a()
+		h.Write([]byte(doc.Content))
+		contentHash := h.Sum64()
+		if seenContent[contentHash] {
+			duplicates++
+		} else if group := syndicationGroup(doc.URL); group != nil && group.Canonical != doc.URL {
+			duplicates++
+		}
+		seenContent[contentHash] = true
+
+		totalWords += countWords(doc.Content)
+		for hash := range paragraphHashes(doc.Content) {
+			paragraphDocs[hash]++
+		}
+	}
+
+	minDocs := int(math.Ceil(BOILERPLATE_MIN_SHARE * float64(len(docs))))
+	if minDocs < BOILERPLATE_MIN_DOCS {
+		minDocs = BOILERPLATE_MIN_DOCS
+	}
+	boilerplateWords := 0
+	for _, doc := range docs {
+		for hash, words := range paragraphHashes(doc.Content) {
+			if paragraphDocs[hash] >= minDocs {
+				boilerplateWords += words
+			}
+		}
+	}
+
+	quality.DuplicateRate = float64(duplicates) / float64(len(docs))
+	quality.AvgWords = float64(totalWords) / float64(len(docs))
+	if totalWords > 0 {
+		quality.BoilerplateRatio = float64(boilerplateWords) / float64(totalWords)
+	}
+	return quality
+}
+
+// Hash paragraf unik -> jumlah kata. Crawler menggabungkan <p> dengan "\n".
+func paragraphHashes(content string) map[uint64]int {
+	paragraphs := make(map[uint64]int)
+	for _, paragraph := range strings.Split(content, "\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		h := fnv.New64a()
+		h.Write([]byte(paragraph))
+		paragraphs[h.Sum64()] = countWords(paragraph)
+	}
+	return paragraphs
+}
+
+// Skor semua source: sinyal corpus ditambah dead-link rate crawl terakhir
+func sourceQualities() []SourceQuality {
+	sourceQualitySignals.mu.RLock()
+	list := make([]SourceQuality, 0, len(sourceQualitySignals.sources))
+	for _, quality := range sourceQualitySignals.sources {
+		list = append(list, quality)
+	}
+	sourceQualitySignals.mu.RUnlock()
+
+	crawls := make(map[string]CrawlProgress)
+	for _, progress := range crawlMonitor.List() {
+		crawls[progress.Source] = progress
+	}
+	for i := range list {
+		if progress, exists := crawls[list[i].Source]; exists && progress.Responses+progress.Errors > 0 {
+			list[i].DeadLinkRate = 1 - progress.SuccessRate
+		}
+		list[i].Score = qualityScore(list[i])
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Source < list[j].Source })
+	return list
+}
+
+func qualityScore(q SourceQuality) float64 {
+	w := sourceQualityWeights
+	length := math.Min(q.AvgWords/QUALITY_TARGET_WORDS, 1)
+	return w.Duplicates*(1-q.DuplicateRate) +
+		w.Boilerplate*(1-q.BoilerplateRatio) +
+		w.Length*length +
+		w.DeadLinks*(1-q.DeadLinkRate)
+}
+
+// Pengali skor ranking per source: 1 + authority_weight * (skor - 0.5).
+// Source tanpa data (mis. hasil instance remote) tidak disentuh.
+func authorityBoosts(weight float64) map[string]float64 {
+	boosts := make(map[string]float64)
+	if weight <= 0 {
+		return boosts
+	}
+	for _, quality := range sourceQualities() {
+		boosts[quality.Source] = 1 + weight*(quality.Score-0.5)
+	}
+	return boosts
+}
+
+// GET /api/admin/source-quality
+func sourceQualityHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"authority_weight": requestConfig(c).AuthorityWeight,
+		"sources":          sourceQualities(),
+	})
+}
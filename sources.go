@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sumber crawl tambahan hasil onboarding (selain sumber bawaan di crawlers.json)
+const SOURCES_FILE = "sources.json"
+
+type SourceStore struct {
+	mu      sync.RWMutex
+	path    string
+	sources map[string]CrawlSource
+}
+
+var savedSources = &SourceStore{path: SOURCES_FILE, sources: make(map[string]CrawlSource)}
+
+func loadSourceStore(path string) (*SourceStore, error) {
+	store := &SourceStore{path: path, sources: make(map[string]CrawlSource)}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, err
+	}
+
+	var sources []CrawlSource
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil, err
+	}
+	for _, source := range sources {
+		store.sources[source.Name] = source
+	}
+	return store, nil
+}
+
+func (s *SourceStore) List() []CrawlSource {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]CrawlSource, 0, len(s.sources))
+	for _, source := range s.sources {
+		list = append(list, source)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func (s *SourceStore) Get(name string) (CrawlSource, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	source, exists := s.sources[name]
+	return source, exists
+}
+
+// Simpan (atau timpa) sumber lalu tulis ulang sources.json
+func (s *SourceStore) Save(source CrawlSource) error {
+	if err := validateCrawlSource(source); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sources[source.Name] = source
+	list := make([]CrawlSource, 0, len(s.sources))
+	for _, saved := range s.sources {
+		list = append(list, saved)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.path, data, 0644)
+}
+
+func validateCrawlSource(source CrawlSource) error {
+	if source.Name == "" || slugify(source.Name) != source.Name {
+		return errors.New("name must be a lowercase slug (a-z, 0-9, -)")
+	}
+	if _, builtin := crawlSources[source.Name]; builtin {
+		return fmt.Errorf("%s is a built-in source, change it in %s instead", source.Name, CRAWLERS_FILE)
+	}
+	if u, err := url.Parse(source.StartURL); err != nil || u.Host == "" {
+		return errors.New("start_url must be an absolute URL")
+	}
+	if source.Domain == "" || source.Title == "" || source.Content == "" {
+		return errors.New("domain, title and content selectors are required")
+	}
+	if _, err := dateLocation(source.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", source.Timezone)
+	}
+	if delay, err := source.crawlDelay(); err != nil || delay < 0 {
+		return fmt.Errorf("invalid delay %q", source.Delay)
+	}
+	if source.Parallelism < 0 {
+		return errors.New("parallelism must not be negative")
+	}
+	return nil
+}
+
+// Sumber bawaan dulu, lalu sumber hasil onboarding
+func findCrawlSource(name string) (CrawlSource, bool) {
+	if source, exists := crawlSources[name]; exists {
+		return source, true
+	}
+	return savedSources.Get(name)
+}
+
+// GET /api/admin/sources: sumber bawaan dan hasil onboarding
+func listSourcesHandler(c *gin.Context) {
+	builtin := make([]CrawlSource, 0, len(crawlSources))
+	for _, source := range crawlSources {
+		builtin = append(builtin, source)
+	}
+	sort.Slice(builtin, func(i, j int) bool { return builtin[i].Name < builtin[j].Name })
+
+	c.JSON(http.StatusOK, gin.H{"builtin": builtin, "saved": savedSources.List()})
+}
+
+// POST /api/admin/sources/propose {"url": "<contoh artikel>"}: draft CrawlSource
+// beserta kandidat selector untuk ditinjau admin
+func proposeSourceHandler(c *gin.Context) {
+	var req struct {
+		URL string `json:"url"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.URL) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url is required"})
+		return
+	}
+
+	proposal, err := proposeSource(req.URL)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, proposal)
+}
+
+// POST /api/admin/sources: simpan draft yang sudah disesuaikan admin
+func saveSourceHandler(c *gin.Context) {
+	var source CrawlSource
+	if err := c.ShouldBindJSON(&source); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var before json.RawMessage
+	if current, exists := savedSources.Get(source.Name); exists {
+		before = auditSnapshot(current)
+	}
+	if err := savedSources.Save(source); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	auditActor(c).Record(AuditSourceSave, source.Name, before, source)
+	c.JSON(http.StatusOK, source)
+}
+
+func init() {
+	store, err := loadSourceStore(SOURCES_FILE)
+	if err != nil {
+		log.Printf("Error loading %s: %v", SOURCES_FILE, err)
+		return
+	}
+	savedSources = store
+}
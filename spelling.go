@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Levenshtein distance antara dua kata (dalam rune)
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j] + 1
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+			if prev[j-1]+cost < curr[j] {
+				curr[j] = prev[j-1] + cost
+			}
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// Cari term terdekat di vocabulary index, prioritas jarak terkecil lalu
+// document frequency terbesar
+func closestTerm(idx *InvertedIndex, term string, maxDistance int) (string, bool) {
+	best := ""
+	bestDist := maxDistance + 1
+	bestDF := 0
+
+	for candidate, postingList := range idx.Index {
+		diff := len(candidate) - len(term)
+		if diff > maxDistance || -diff > maxDistance {
+			continue
+		}
+		dist := levenshtein(term, candidate)
+		if dist < bestDist || (dist == bestDist && postingList.DocFrequency > bestDF) {
+			best = candidate
+			bestDist = dist
+			bestDF = postingList.DocFrequency
+		}
+	}
+
+	return best, best != "" && bestDist <= maxDistance
+}
+
+// Koreksi ejaan query ("Maksud Anda"). Kata query yang tidak punya posting
+// di index diganti kata corpus terdekat (Levenshtein, dicari lewat BK-tree
+// kosakata query) dengan frekuensi terbesar. spelling_correction di
+// config.json:
+//
+//   - suggest (default): tampilkan link ke query yang dikoreksi
+//   - rewrite: kalau query asli tanpa hasil, query yang dikoreksi langsung
+//     dicari, dengan link untuk mencari query asli (spell=false)
+//   - off
+const (
+	SpellingSuggest = "suggest"
+	SpellingRewrite = "rewrite"
+	SpellingOff     = "off"
+
+	// Kata yang lebih pendek tidak dikoreksi
+	SPELLING_MIN_LENGTH = 3
+	// Kata yang muncul lebih jarang di corpus tidak jadi koreksi (salah
+	// ketik di artikel sendiri)
+	SPELLING_MIN_COUNT = 2
+
+	RewriteSpelling = "spelling"
+)
+
+// BK-tree: anak setiap node dikelompokkan per jarak Levenshtein ke node itu,
+// jadi pencarian dalam jarak d hanya menelusuri anak berjarak dist-d..dist+d
+type BKTree struct {
+	root *bkNode
+}
+
+type bkNode struct {
+	word     string
+	count    int
+	children map[int]*bkNode
+}
+
+func (t *BKTree) Add(word string, count int) {
+	if t.root == nil {
+		t.root = &bkNode{word: word, count: count}
+		return
+	}
+	node := t.root
+	for {
+		dist := levenshtein(word, node.word)
+		if dist == 0 {
+			return
+		}
+		child, exists := node.children[dist]
+		if !exists {
+			if node.children == nil {
+				node.children = make(map[int]*bkNode)
+			}
+			node.children[dist] = &bkNode{word: word, count: count}
+			return
+		}
+		node = child
+	}
+}
+
+// Kata terdekat dalam maxDistance: jarak terkecil, lalu frekuensi terbesar
+func (t *BKTree) Closest(word string, maxDistance int) (string, bool) {
+	best, bestDist, bestCount := "", maxDistance+1, 0
+	if t.root == nil {
+		return best, false
+	}
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		dist := levenshtein(word, node.word)
+		if dist < bestDist || (dist == bestDist && (node.count > bestCount || (node.count == bestCount && node.word < best))) {
+			best, bestDist, bestCount = node.word, dist, node.count
+		}
+		for childDist, child := range node.children {
+			if childDist >= dist-maxDistance && childDist <= dist+maxDistance {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return best, best != "" && bestDist <= maxDistance
+}
+
+// BK-tree kata corpus, dibangun sekali per generasi kosakata
+func (qv *QueryVocabulary) spellingTree() *BKTree {
+	qv.mu.Lock()
+	defer qv.mu.Unlock()
+
+	if qv.spelling == nil {
+		qv.spelling = &BKTree{}
+		for word, count := range qv.counts {
+			if count >= SPELLING_MIN_COUNT && len(word) >= SPELLING_MIN_LENGTH && isPlainWord(word) {
+				qv.spelling.Add(word, count)
+			}
+		}
+	}
+	return qv.spelling
+}
+
+// Ada term hasil analisis kata ini yang punya posting; stopword (tanpa term)
+// dianggap ada
+func hasPostings(idx *InvertedIndex, word string) bool {
+	terms := buildQueryVector(word)
+	if len(terms) == 0 {
+		return true
+	}
+	for term := range terms {
+		if postingList, exists := idx.Index[term]; exists && postingList.DocFrequency > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Query dengan kata tanpa posting diganti kata corpus terdekat, nil kalau
+// tidak ada yang bisa dikoreksi
+func correctSpelling(query string, idx *InvertedIndex, opts SearchOptions) *QueryRewrite {
+	if opts.withConfig().Config.SpellingCorrection == SpellingOff || opts.NoSpell {
+		return nil
+	}
+
+	words := strings.Fields(strings.ToLower(query))
+	changed := false
+	for i, word := range words {
+		if len(word) < SPELLING_MIN_LENGTH || !isPlainWord(word) || strings.IndexFunc(word, unicode.IsDigit) >= 0 || hasPostings(idx, word) {
+			continue
+		}
+		maxDistance := 2
+		if len(word) <= 4 {
+			maxDistance = 1
+		}
+		if corrected, ok := queryVocabulary.spellingTree().Closest(word, maxDistance); ok && corrected != word {
+			words[i] = corrected
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return &QueryRewrite{Kind: RewriteSpelling, Original: query, Text: strings.Join(words, " ")}
+}
+
+func validateSpellingCorrection(mode string) error {
+	switch mode {
+	case SpellingSuggest, SpellingRewrite, SpellingOff:
+		return nil
+	}
+	return fmt.Errorf("must be %s, %s or %s", SpellingSuggest, SpellingRewrite, SpellingOff)
+}
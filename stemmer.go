@@ -0,0 +1,427 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Stemmer bahasa Indonesia gaya Nazief–Adriani / Sastrawi (Enhanced Confix
+// Stripping). Partikel (-lah, -kah, -tah, -pun), kata ganti milik (-ku, -mu,
+// -nya) dan akhiran turunan (-i, -kan, -an) dibuang dulu, lalu sampai tiga
+// awalan dengan aturan recoding (memakai -> pakai, menulis -> tulis,
+// pengembangan -> kembang). Setelah setiap langkah hasilnya dicek ke kamus
+// kata dasar; kalau belum ketemu, akhiran yang sudah dibuang dikembalikan satu
+// per satu dan awalan dicoba lagi. Kata berawalan be-...-lah/-an dan
+// di-/me-/pe-/te-...-i dicoba awalan dulu (bertepatan, mengakhiri).
+//
+// Kamus ada di rootwords.go, ditambah root_words.txt (satu kata per baris)
+// untuk istilah yang belum ada. Kata yang kata dasarnya tidak ketemu hanya
+// dibuang -nya, supaya nama dan istilah asing tidak terpotong.
+const (
+	ROOT_WORDS_FILE = "root_words.txt"
+	STEM_CACHE_SIZE = 100000
+)
+
+// Diisi saat inisialisasi variabel paket, sebelum init() lain yang mungkin
+// sudah menganalisis teks
+var (
+	rootDictionary       = loadRootDictionary(ROOT_WORDS_FILE)
+	rootDictionaryDigest = dictionaryDigest(rootDictionary) // untuk fingerprint analyzer
+
+	stemCache     sync.Map
+	stemCacheSize int64
+)
+
+var (
+	inflectionalParticles = []string{"lah", "kah", "tah", "pun"}
+	possessivePronouns    = []string{"nya", "ku", "mu"}
+	derivationalSuffixes  = []string{"isasi", "isme", "kan", "an", "i"}
+
+	// Pasangan awalan pertama dan akhiran yang tidak dipakai bahasa Indonesia
+	// (Nazief–Adriani); kerusakan bukan ke-rusa-kan. ke-...-i tidak termasuk
+	// karena ketahui.
+	disallowedConfixes = map[string]bool{
+		"be-i": true, "ke-kan": true, "me-an": true, "se-i": true,
+		"se-kan": true, "te-an": true,
+	}
+)
+
+func isVowel(c byte) bool {
+	return c == 'a' || c == 'i' || c == 'u' || c == 'e' || c == 'o'
+}
+
+func isConsonant(c byte) bool {
+	return c >= 'a' && c <= 'z' && !isVowel(c)
+}
+
+// Affix yang sudah dibuang: word sebelum, result sesudahnya
+type stemRemoval struct {
+	word   string
+	result string
+	affix  string
+	prefix bool
+}
+
+type stemContext struct {
+	current  string
+	removals []stemRemoval
+}
+
+func (s *stemContext) found() bool {
+	return rootDictionary[s.current]
+}
+
+func (s *stemContext) remove(result, affix string, prefix bool) {
+	s.removals = append(s.removals, stemRemoval{word: s.current, result: result, affix: affix, prefix: prefix})
+	s.current = result
+}
+
+// Akhiran turunan yang sudah dibuang, kosong kalau belum ada
+func (s *stemContext) suffix() string {
+	for _, removal := range s.removals {
+		if !removal.prefix && removal.affix != "" {
+			for _, suffix := range derivationalSuffixes {
+				if removal.affix == suffix {
+					return suffix
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// Buang satu akhiran dari daftar kalau ada
+func (s *stemContext) removeSuffix(suffixes []string) {
+	for _, suffix := range suffixes {
+		if len(s.current) > len(suffix) && strings.HasSuffix(s.current, suffix) {
+			s.remove(s.current[:len(s.current)-len(suffix)], suffix, false)
+			return
+		}
+	}
+}
+
+// Langkah 2-3: partikel, kata ganti milik, akhiran turunan; berhenti begitu
+// kata dasar ketemu
+func (s *stemContext) removeSuffixes() {
+	for _, suffixes := range [][]string{inflectionalParticles, possessivePronouns, derivationalSuffixes} {
+		s.removeSuffix(suffixes)
+		if s.found() {
+			return
+		}
+	}
+}
+
+// Langkah 4-5: sampai tiga awalan, awalan yang sama tidak dibuang dua kali
+// berturut-turut
+func (s *stemContext) removePrefixes() {
+	prefix, _ := prefixCandidates(s.current)
+	if root, _ := s.stripPrefixes(s.current, "", 3); root != s.current {
+		s.remove(root, prefix, true)
+	}
+}
+
+// Semua kandidat recoding dicoba; jalur yang sampai ke kata dasar di kamus
+// yang dipakai (memerhatikan -> perhati -> hati), kalau tidak ada jalur
+// kandidat pertama
+func (s *stemContext) stripPrefixes(word, last string, depth int) (string, bool) {
+	if depth == 0 {
+		return word, false
+	}
+	prefix, candidates := prefixCandidates(word)
+	if len(candidates) == 0 || prefix == last {
+		return word, false
+	}
+	if last == "" && disallowedConfixes[prefix+"-"+s.suffix()] {
+		return word, false
+	}
+	for _, candidate := range candidates {
+		if rootDictionary[candidate] {
+			return candidate, true
+		}
+	}
+	for _, candidate := range candidates {
+		if root, found := s.stripPrefixes(candidate, prefix, depth-1); found {
+			return root, true
+		}
+	}
+	root, _ := s.stripPrefixes(candidates[0], prefix, depth-1)
+	return root, false
+}
+
+// Kembalikan kata ke sebelum awalan pertama dibuang
+func (s *stemContext) restorePrefixes() {
+	kept := s.removals[:0]
+	restored := false
+	for _, removal := range s.removals {
+		if removal.prefix {
+			if !restored {
+				s.current = removal.word
+				restored = true
+			}
+			continue
+		}
+		kept = append(kept, removal)
+	}
+	s.removals = kept
+}
+
+// ECS: akhiran yang sudah dibuang dikembalikan satu per satu (yang terakhir
+// dulu) lalu awalan dicoba lagi; "-kan" juga dicoba sebagai "-k" (menaikkan)
+func (s *stemContext) restoreSuffixes() {
+	s.restorePrefixes()
+	removals := append([]stemRemoval(nil), s.removals...)
+	current := s.current
+
+	for i := len(removals) - 1; i >= 0; i-- {
+		removal := removals[i]
+		s.removals = append(s.removals[:0], removals[:i]...)
+		if removal.affix == "kan" {
+			s.current = removal.result + "k"
+			s.removePrefixes()
+			if s.found() {
+				return
+			}
+			s.current = removal.result + "kan"
+			s.removals = append(s.removals[:0], removals[:i]...)
+		} else {
+			s.current = removal.word
+		}
+		s.removePrefixes()
+		if s.found() {
+			return
+		}
+		s.removals = append(s.removals[:0], removals...)
+		s.current = current
+	}
+}
+
+// be-...-lah/-an dan di-/me-/pe-/te-...-i: awalan dibuang lebih dulu
+func prefixFirst(word string) bool {
+	// -kan tidak termasuk: berikan -> beri, bukan ikan
+	if strings.HasPrefix(word, "be") && (strings.HasSuffix(word, "lah") || (strings.HasSuffix(word, "an") && !strings.HasSuffix(word, "kan"))) {
+		return true
+	}
+	if !strings.HasSuffix(word, "i") {
+		return false
+	}
+	return strings.HasPrefix(word, "di") || strings.HasPrefix(word, "me") || strings.HasPrefix(word, "pe") || strings.HasPrefix(word, "te")
+}
+
+func stemIndonesian(word string) string {
+	if len(word) <= 3 || rootDictionary[word] {
+		return word
+	}
+	root := confixStrip(word, false)
+	// Kalau dua urutan sama-sama ketemu, kata dasar yang lebih panjang
+	// dipakai (disetujui -> setuju, bukan tuju)
+	if prefixFirst(word) {
+		if alt := confixStrip(word, true); len(alt) > len(root) {
+			root = alt
+		}
+	}
+	if root != "" {
+		return root
+	}
+
+	// Kata dasar tidak ketemu: hanya -nya yang dibuang (rumah123nya)
+	if len(word)-3 >= 4 && strings.HasSuffix(word, "nya") {
+		return word[:len(word)-3]
+	}
+	return word
+}
+
+// Kata dasar word, kosong kalau tidak ketemu di kamus
+func confixStrip(word string, prefixFirst bool) string {
+	s := &stemContext{current: word}
+	if prefixFirst {
+		s.removePrefixes()
+		if !s.found() {
+			s.removeSuffixes()
+		}
+	} else {
+		s.removeSuffixes()
+		if !s.found() {
+			s.removePrefixes()
+		}
+		if !s.found() {
+			s.restoreSuffixes()
+		}
+	}
+	if s.found() {
+		return s.current
+	}
+	return ""
+}
+
+// Awalan di depan word dan kandidat kata sesudah awalan dibuang, urut aturan
+// Sastrawi; kandidat pertama yang ada di kamus yang dipakai
+func prefixCandidates(word string) (string, []string) {
+	n := len(word)
+	at := func(i int) byte {
+		if i < n {
+			return word[i]
+		}
+		return 0
+	}
+	// Sisa kata mulai i tidak diawali "er"
+	notEr := func(i int) bool {
+		return i >= n || !strings.HasPrefix(word[i:], "er")
+	}
+
+	switch {
+	case n < 4:
+		return "", nil
+
+	// Awalan polos
+	case strings.HasPrefix(word, "di"), strings.HasPrefix(word, "ke"), strings.HasPrefix(word, "se"):
+		return word[:2], []string{word[2:]}
+	case strings.HasPrefix(word, "kau"):
+		return "kau", []string{word[3:]}
+	case strings.HasPrefix(word, "ku"):
+		return "ku", []string{word[2:]}
+
+	// be-
+	case word == "belajar":
+		return "be", []string{"ajar"}
+	case strings.HasPrefix(word, "ber") && isVowel(at(3)):
+		return "be", []string{word[3:], "r" + word[3:]}
+	case strings.HasPrefix(word, "ber") && isConsonant(at(3)) && at(3) != 'r' && n > 5 && strings.HasPrefix(word[5:], "er") && isVowel(at(7)):
+		return "be", []string{word[3:]}
+	case strings.HasPrefix(word, "ber") && isConsonant(at(3)) && at(3) != 'r' && notEr(5):
+		return "be", []string{word[3:]}
+	case strings.HasPrefix(word, "be") && isConsonant(at(2)) && at(2) != 'r' && at(2) != 'l' && strings.HasPrefix(word[3:], "er") && isConsonant(at(5)):
+		return "be", []string{word[2:]}
+
+	// te-
+	case strings.HasPrefix(word, "ter") && isVowel(at(3)):
+		return "te", []string{word[3:], "r" + word[3:]}
+	case strings.HasPrefix(word, "ter") && isConsonant(at(3)) && at(3) != 'r' && strings.HasPrefix(word[4:], "er") && isVowel(at(6)):
+		return "te", []string{word[3:]}
+	case strings.HasPrefix(word, "ter") && isConsonant(at(3)) && at(3) != 'r' && notEr(4):
+		return "te", []string{word[3:]}
+	case strings.HasPrefix(word, "ter") && isConsonant(at(3)) && at(3) != 'r' && strings.HasPrefix(word[4:], "er") && isConsonant(at(6)):
+		return "te", []string{word[3:]}
+	case strings.HasPrefix(word, "te") && isConsonant(at(2)) && at(2) != 'r' && strings.HasPrefix(word[3:], "er") && isConsonant(at(5)):
+		return "te", []string{word[2:]}
+
+	// me-
+	case strings.HasPrefix(word, "me") && strings.IndexByte("lrwy", at(2)) >= 0 && isVowel(at(3)):
+		return "me", []string{word[2:]}
+	case strings.HasPrefix(word, "mem") && strings.IndexByte("bfv", at(3)) >= 0:
+		return "me", []string{word[3:]}
+	case strings.HasPrefix(word, "mempe"):
+		return "me", []string{word[3:]}
+	case strings.HasPrefix(word, "mem") && (isVowel(at(3)) || (at(3) == 'r' && isVowel(at(4)))):
+		return "me", []string{"m" + word[3:], "p" + word[3:]}
+	case strings.HasPrefix(word, "memp") && at(4) != 'e':
+		return "me", []string{word[3:]}
+	case strings.HasPrefix(word, "men") && strings.IndexByte("cdjstz", at(3)) >= 0:
+		return "me", []string{word[3:]}
+	case strings.HasPrefix(word, "men") && isVowel(at(3)):
+		return "me", []string{"n" + word[3:], "t" + word[3:]}
+	case strings.HasPrefix(word, "meng") && strings.IndexByte("ghqk", at(4)) >= 0:
+		return "me", []string{word[4:]}
+	case strings.HasPrefix(word, "menge") && isConsonant(at(5)):
+		return "me", []string{"k" + word[4:], word[5:], word[4:], "ng" + word[4:]}
+	case strings.HasPrefix(word, "meng") && isVowel(at(4)):
+		return "me", []string{word[4:], "k" + word[4:], "ng" + word[4:]}
+	case strings.HasPrefix(word, "meny") && isVowel(at(4)):
+		return "me", []string{"s" + word[4:], "ny" + word[4:]}
+
+	// pe-
+	case word == "pelajar":
+		return "pe", []string{"ajar"}
+	case strings.HasPrefix(word, "pe") && strings.IndexByte("wy", at(2)) >= 0 && isVowel(at(3)):
+		return "pe", []string{word[2:]}
+	case strings.HasPrefix(word, "per") && isVowel(at(3)):
+		return "pe", []string{word[3:], "r" + word[3:]}
+	case strings.HasPrefix(word, "per") && isConsonant(at(3)) && at(3) != 'r' && n > 5 && strings.HasPrefix(word[5:], "er") && isVowel(at(7)):
+		return "pe", []string{word[3:]}
+	case strings.HasPrefix(word, "per") && isConsonant(at(3)) && at(3) != 'r' && notEr(5):
+		return "pe", []string{word[3:]}
+	case strings.HasPrefix(word, "pem") && strings.IndexByte("bfv", at(3)) >= 0:
+		return "pe", []string{word[3:]}
+	case strings.HasPrefix(word, "pem") && (isVowel(at(3)) || (at(3) == 'r' && isVowel(at(4)))):
+		return "pe", []string{"m" + word[3:], "p" + word[3:]}
+	case strings.HasPrefix(word, "pen") && strings.IndexByte("cdjz", at(3)) >= 0:
+		return "pe", []string{word[3:]}
+	case strings.HasPrefix(word, "pen") && isVowel(at(3)):
+		return "pe", []string{"n" + word[3:], "t" + word[3:]}
+	case strings.HasPrefix(word, "penge") && isConsonant(at(5)):
+		return "pe", []string{"k" + word[4:], word[5:], word[4:]}
+	case strings.HasPrefix(word, "peng") && isConsonant(at(4)):
+		return "pe", []string{word[4:]}
+	case strings.HasPrefix(word, "peng") && isVowel(at(4)):
+		return "pe", []string{word[4:], "k" + word[4:]}
+	case strings.HasPrefix(word, "peny") && isVowel(at(4)):
+		return "pe", []string{"s" + word[4:], "ny" + word[4:]}
+	case strings.HasPrefix(word, "pel") && isVowel(at(3)):
+		return "pe", []string{word[2:]}
+	case strings.HasPrefix(word, "pe") && isConsonant(at(2)) && strings.IndexByte("rwylmn", at(2)) < 0 && strings.HasPrefix(word[3:], "er"):
+		return "pe", []string{word[2:]}
+	case strings.HasPrefix(word, "pe") && isConsonant(at(2)) && strings.IndexByte("rwylmn", at(2)) < 0:
+		return "pe", []string{word[2:]}
+	}
+	return "", nil
+}
+
+// Stem dengan cache; kosakata terbatas, jadi cache berhenti tumbuh di
+// STEM_CACHE_SIZE kata supaya query acak tidak menghabiskan memori
+func cachedStemIndonesian(word string) string {
+	if stem, exists := stemCache.Load(word); exists {
+		return stem.(string)
+	}
+	stem := stemIndonesian(word)
+	if atomic.LoadInt64(&stemCacheSize) < STEM_CACHE_SIZE {
+		if _, loaded := stemCache.LoadOrStore(word, stem); !loaded {
+			atomic.AddInt64(&stemCacheSize, 1)
+		}
+	}
+	return stem
+}
+
+// Kamus bawaan ditambah root_words.txt kalau ada
+func loadRootDictionary(path string) map[string]bool {
+	dictionary := make(map[string]bool, 4096)
+	for _, word := range strings.Fields(rootWords) {
+		dictionary[word] = true
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Error loading %s: %v", path, err)
+		}
+		return dictionary
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if word != "" && !strings.HasPrefix(word, "#") {
+			dictionary[word] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("Error loading %s: %v", path, err)
+	}
+	return dictionary
+}
+
+func dictionaryDigest(dictionary map[string]bool) string {
+	words := make([]string, 0, len(dictionary))
+	for word := range dictionary {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	sum := sha256.Sum256([]byte(strings.Join(words, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
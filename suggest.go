@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Saran query untuk autocomplete (GET /api/suggest?q=rumah+s). Kandidat
+// datang dari dua sumber: query populer di query log dan kata/bigram judul
+// artikel di corpus. Popularitas query meluruh eksponensial (half-life
+// suggest_half_life_days, default 7 hari), jadi query yang sedang ramai
+// (mis. program subsidi baru) naik cepat dan turun lagi setelah sepi, tidak
+// seperti frekuensi kata corpus yang hampir statis. Skor akhir:
+//
+//	weight * popularitas/popularitas_max + (1 - weight) * df/df_max
+//
+// dengan weight = suggest_popularity_weight (default 0.7).
+const (
+	SUGGEST_LIMIT             = 8
+	SUGGEST_MAX_LIMIT         = 20
+	SUGGEST_HALF_LIFE_DAYS    = 7
+	SUGGEST_POPULARITY_WEIGHT = 0.7
+	SUGGEST_WINDOW            = 90 * 24 * time.Hour
+	SUGGEST_REFRESH_INTERVAL  = 10 * time.Minute
+	JobSuggestRefresh         = "suggest_refresh"
+	// Query yang dicari kurang dari ini tidak disarankan, supaya query
+	// pribadi yang hanya sekali diketik tidak bocor ke pengguna lain
+	SUGGEST_MIN_QUERY_COUNT  = 2
+	SUGGEST_MAX_QUERY_LENGTH = 80
+	SUGGEST_MIN_WORD_LENGTH  = 3
+)
+
+// Sumber saran
+const (
+	SuggestFromQueries = "queries"
+	SuggestFromCorpus  = "corpus"
+)
+
+type Suggestion struct {
+	Text    string   `json:"text"`
+	Score   float64  `json:"score"`
+	Sources []string `json:"sources"`
+}
+
+type suggestTerm struct {
+	Text string
+	DF   int
+}
+
+// Kata dan bigram judul (terurut per teks untuk pencarian prefix) dan
+// popularitas query
+type SuggestIndex struct {
+	mu          sync.RWMutex
+	words       []suggestTerm
+	bigrams     []suggestTerm
+	maxWordDF   int
+	maxBigramDF int
+	queryScores map[string]float64
+	maxQuery    float64
+}
+
+var suggestIndex = &SuggestIndex{}
+
+// Dipanggil noteIndexGeneration: kosakata judul dari generasi corpus terbaru
+func updateSuggestVocabulary(articles []Article) {
+	words := make(map[string]int)
+	bigrams := make(map[string]int)
+	for _, article := range articles {
+		// Judul dokumen internal tidak boleh bocor lewat saran
+		if article.Deleted() || article.visibility() != VisibilityPublic {
+			continue
+		}
+		seen := make(map[string]bool)
+		var prev string
+		for _, word := range suggestWords(article.Title) {
+			if prev != "" && !seen[prev+" "+word] {
+				seen[prev+" "+word] = true
+				bigrams[prev+" "+word]++
+			}
+			if !seen[word] {
+				seen[word] = true
+				words[word]++
+			}
+			prev = word
+		}
+	}
+
+	wordTerms, maxWordDF := sortedSuggestTerms(words)
+	bigramTerms, maxBigramDF := sortedSuggestTerms(bigrams)
+
+	suggestIndex.mu.Lock()
+	suggestIndex.words, suggestIndex.maxWordDF = wordTerms, maxWordDF
+	suggestIndex.bigrams, suggestIndex.maxBigramDF = bigramTerms, maxBigramDF
+	suggestIndex.mu.Unlock()
+}
+
+// Kata judul yang layak disarankan: huruf kecil, bukan stopword, bukan angka
+func suggestWords(title string) []string {
+	var words []string
+	for _, tok := range textProcessor.splitWords(nil, strings.ToLower(title)) {
+		if len(tok.Term) < SUGGEST_MIN_WORD_LENGTH || textProcessor.stopWords[tok.Term] || englishTextProcessor.stopWords[tok.Term] {
+			continue
+		}
+		words = append(words, tok.Term)
+	}
+	return words
+}
+
+func sortedSuggestTerms(counts map[string]int) ([]suggestTerm, int) {
+	terms := make([]suggestTerm, 0, len(counts))
+	maxDF := 0
+	for text, df := range counts {
+		terms = append(terms, suggestTerm{text, df})
+		if df > maxDF {
+			maxDF = df
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool { return terms[i].Text < terms[j].Text })
+	return terms, maxDF
+}
+
+// Term dengan prefix tertentu
+func prefixTerms(terms []suggestTerm, prefix string) []suggestTerm {
+	start := sort.Search(len(terms), func(i int) bool { return terms[i].Text >= prefix })
+	end := start
+	for end < len(terms) && strings.HasPrefix(terms[end].Text, prefix) {
+		end++
+	}
+	return terms[start:end]
+}
+
+// Popularitas query dengan peluruhan eksponensial: setiap pencarian bernilai
+// 0.5^(umur/halfLife). Query yang terakhir kali tanpa hasil, terlalu panjang
+// atau dicari kurang dari SUGGEST_MIN_QUERY_COUNT kali dibuang.
+func queryPopularity(entries []QueryLogEntry, now time.Time, halfLife time.Duration) map[string]float64 {
+	scores := make(map[string]float64)
+	counts := make(map[string]int)
+	lastResults := make(map[string]int)
+	for _, entry := range entries {
+		q := strings.ToLower(normalizeQuery(entry.Query))
+		if q == "" || len(q) > SUGGEST_MAX_QUERY_LENGTH {
+			continue
+		}
+		lastResults[q] = entry.Results
+		if entry.Results == 0 {
+			continue
+		}
+		age := now.Sub(entry.Time)
+		if age < 0 {
+			age = 0
+		}
+		scores[q] += math.Pow(0.5, float64(age)/float64(halfLife))
+		counts[q]++
+	}
+	for q := range scores {
+		if counts[q] < SUGGEST_MIN_QUERY_COUNT || lastResults[q] == 0 {
+			delete(scores, q)
+		}
+	}
+	return scores
+}
+
+func suggestHalfLife(days float64) time.Duration {
+	if days <= 0 {
+		days = SUGGEST_HALF_LIFE_DAYS
+	}
+	return time.Duration(days * float64(24*time.Hour))
+}
+
+// Hitung ulang popularitas dari query log
+func refreshQueryPopularity() error {
+	entries, err := readQueryLog(QUERY_LOG_FILE, time.Now().Add(-SUGGEST_WINDOW))
+	if err != nil {
+		return err
+	}
+	scores := queryPopularity(entries, time.Now(), suggestHalfLife(currentConfig().SuggestHalfLifeDays))
+	maxScore := 0.0
+	for _, score := range scores {
+		maxScore = math.Max(maxScore, score)
+	}
+
+	suggestIndex.mu.Lock()
+	suggestIndex.queryScores, suggestIndex.maxQuery = scores, maxScore
+	suggestIndex.mu.Unlock()
+	return nil
+}
+
+// Refresh popularitas berkala di background
+func startSuggestRefresher(interval time.Duration) {
+	adminJobs.Every(JobSuggestRefresh, interval)
+}
+
+func suggestRefreshJob(ctx context.Context, job jobRun) error {
+	return refreshQueryPopularity()
+}
+
+// Saran untuk teks yang sedang diketik. Kata terakhir dianggap belum selesai
+// kecuali input diakhiri spasi; kata sebelumnya dipakai sebagai konteks
+// bigram judul. weight = suggest_popularity_weight.
+func suggest(input string, limit int, weight float64) []Suggestion {
+	// Normalisasi yang sama dengan pencarian dan key cache (query.go);
+	// vocabulary saran huruf kecil
+	query := strings.ToLower(normalizeQuery(input))
+	if query == "" {
+		return []Suggestion{}
+	}
+	words := strings.Fields(query)
+	partial := words[len(words)-1]
+	head := words[:len(words)-1]
+	if strings.HasSuffix(input, " ") {
+		partial, head = "", words
+	}
+
+	if weight < 0 || weight > 1 {
+		weight = SUGGEST_POPULARITY_WEIGHT
+	}
+
+	v := suggestIndex
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	candidates := make(map[string]*Suggestion)
+	add := func(text string, score float64, source string) {
+		s, exists := candidates[text]
+		if !exists {
+			s = &Suggestion{Text: text}
+			candidates[text] = s
+		}
+		s.Score += score
+		s.Sources = append(s.Sources, source)
+	}
+
+	if v.maxQuery > 0 {
+		prefix := query
+		if strings.HasSuffix(input, " ") {
+			prefix += " "
+		}
+		for q, score := range v.queryScores {
+			if strings.HasPrefix(q, prefix) {
+				add(q, weight*score/v.maxQuery, SuggestFromQueries)
+			}
+		}
+	}
+
+	// Lengkapi kata terakhir: bigram dengan kata sebelumnya kalau ada,
+	// kalau tidak kata judul dengan prefix itu
+	if len(head) > 0 && v.maxBigramDF > 0 {
+		prev := head[len(head)-1]
+		for _, term := range prefixTerms(v.bigrams, prev+" "+partial) {
+			text := strings.Join(append(append([]string(nil), head...), strings.TrimPrefix(term.Text, prev+" ")), " ")
+			add(text, (1-weight)*float64(term.DF)/float64(v.maxBigramDF), SuggestFromCorpus)
+		}
+	} else if partial != "" && v.maxWordDF > 0 {
+		for _, term := range prefixTerms(v.words, partial) {
+			add(term.Text, (1-weight)*float64(term.DF)/float64(v.maxWordDF), SuggestFromCorpus)
+		}
+	}
+
+	suggestions := make([]Suggestion, 0, len(candidates))
+	for _, s := range candidates {
+		suggestions = append(suggestions, *s)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Text < suggestions[j].Text
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// GET /api/suggest?q=rumah+s&limit=8
+func suggestHandler(c *gin.Context) {
+	input := c.Query("q")
+	if strings.TrimSpace(input) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(SUGGEST_LIMIT)))
+	if err != nil || limit < 1 || limit > SUGGEST_MAX_LIMIT {
+		limit = SUGGEST_LIMIT
+	}
+	c.JSON(http.StatusOK, gin.H{"query": input, "suggestions": suggest(input, limit, requestConfig(c).SuggestPopularityWeight)})
+}
+
+func init() {
+	registerJobKind(JobSuggestRefresh, "", maintenanceRetry, suggestRefreshJob)
+}
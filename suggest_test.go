@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+type suggestTestResponse struct {
+	Query       string       `json:"query"`
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+func suggestionTexts(suggestions []Suggestion) []string {
+	texts := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		texts[i] = s.Text
+	}
+	return texts
+}
+
+func TestSuggestCompletesTitleWords(t *testing.T) {
+	var res suggestTestResponse
+	getJSON(t, "/api/suggest?q=Apar", &res)
+	if res.Query != "Apar" {
+		t.Errorf("query %q", res.Query)
+	}
+	if len(res.Suggestions) != 1 || res.Suggestions[0].Text != "apartemen" {
+		t.Fatalf("suggestions %v", suggestionTexts(res.Suggestions))
+	}
+	if sources := res.Suggestions[0].Sources; len(sources) != 1 || sources[0] != SuggestFromCorpus {
+		t.Errorf("sources %v", sources)
+	}
+}
+
+func TestSuggestCompletesBigrams(t *testing.T) {
+	var res suggestTestResponse
+	getJSON(t, "/api/suggest?q=rumah+sub", &res)
+	if got := suggestionTexts(res.Suggestions); len(got) != 1 || got[0] != "rumah subsidi" {
+		t.Errorf("suggestions %v", got)
+	}
+
+	// Semua kota sama seringnya di judul: urut per teks, dipotong di limit
+	getJSON(t, "/api/suggest?q=subsidi+&limit=3", &res)
+	if got := suggestionTexts(res.Suggestions); len(got) != 3 || got[0] != "subsidi bekasi" || got[1] != "subsidi bogor" || got[2] != "subsidi cianjur" {
+		t.Errorf("suggestions %v", got)
+	}
+}
+
+func TestSuggestRequiresQuery(t *testing.T) {
+	if w := get(t, "/api/suggest?q=+"); w.Code != http.StatusBadRequest {
+		t.Errorf("status %d", w.Code)
+	}
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Artikel sindikasi: banyak artikel properti dimuat ulang di source lain
+// dengan sedikit perubahan. Setiap artikel di-fingerprint sebagai himpunan
+// shingle (SHINGLE_SIZE kata berurutan) dan signature MinHash; kandidat
+// dicari lewat LSH (banding signature) lalu diverifikasi dengan Jaccard
+// shingle sebenarnya. Salinan dikelompokkan, yang terbit paling awal (lalu
+// yang paling lengkap) jadi kanonik. Di hasil pencarian setiap grup hanya
+// muncul sekali, dan kartu hasil menampilkan "juga dimuat di".
+const (
+	SHINGLE_SIZE          = 5
+	MINHASH_SIZE          = 64
+	MINHASH_BANDS         = 16 // MINHASH_SIZE / MINHASH_BANDS baris per band
+	SYNDICATION_THRESHOLD = 0.6
+	SYNDICATION_MIN_WORDS = 50 // artikel lebih pendek tidak di-fingerprint
+)
+
+type SyndicatedCopy struct {
+	URL    string    `json:"url"`
+	Source string    `json:"source"`
+	Title  string    `json:"title"`
+	Date   time.Time `json:"date,omitempty"`
+}
+
+type SyndicationGroup struct {
+	Canonical string           `json:"canonical"`
+	Copies    []SyndicatedCopy `json:"copies"` // termasuk kanonik, kanonik pertama
+}
+
+// Grup per URL anggota, dihitung ulang setiap generasi index
+var syndication struct {
+	mu     sync.RWMutex
+	byURL  map[string]*SyndicationGroup
+	groups []*SyndicationGroup
+}
+
+type Fingerprint struct {
+	Shingles  map[uint64]bool
+	Signature []uint64
+}
+
+// Kata huruf kecil tanpa tanda baca, supaya perbedaan format tidak mengubah shingle
+func fingerprintWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Hash shingle yang dimulai di setiap posisi kata
+func shingleHashes(words []string) []uint64 {
+	var hashes []uint64
+	for i := 0; i+SHINGLE_SIZE <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+SHINGLE_SIZE], " ")))
+		hashes = append(hashes, h.Sum64())
+	}
+	return hashes
+}
+
+func shingleSet(words []string) map[uint64]bool {
+	shingles := make(map[uint64]bool)
+	for _, shingle := range shingleHashes(words) {
+		shingles[shingle] = true
+	}
+	return shingles
+}
+
+// Fungsi hash MinHash: h(x) = a*x + b dengan a ganjil, konstanta dari splitmix64
+var minhashSeeds = func() [][2]uint64 {
+	seeds := make([][2]uint64, MINHASH_SIZE)
+	state := uint64(0x9e3779b97f4a7c15)
+	next := func() uint64 {
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		return z ^ (z >> 31)
+	}
+	for i := range seeds {
+		seeds[i] = [2]uint64{next() | 1, next()}
+	}
+	return seeds
+}()
+
+// Fingerprint konten artikel; nil kalau terlalu pendek
+func fingerprint(content string) *Fingerprint {
+	words := fingerprintWords(content)
+	if len(words) < SYNDICATION_MIN_WORDS {
+		return nil
+	}
+	fp := &Fingerprint{Shingles: shingleSet(words), Signature: make([]uint64, MINHASH_SIZE)}
+	for i := range fp.Signature {
+		fp.Signature[i] = ^uint64(0)
+	}
+	for shingle := range fp.Shingles {
+		for i, seed := range minhashSeeds {
+			if h := shingle*seed[0] + seed[1]; h < fp.Signature[i] {
+				fp.Signature[i] = h
+			}
+		}
+	}
+	return fp
+}
+
+func jaccard(a, b map[uint64]bool) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	shared := 0
+	for shingle := range a {
+		if b[shingle] {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// Pasangan dokumen yang signature-nya sama di minimal satu band
+func lshCandidates(fingerprints []*Fingerprint) map[[2]int]bool {
+	rows := MINHASH_SIZE / MINHASH_BANDS
+	pairs := make(map[[2]int]bool)
+	for band := 0; band < MINHASH_BANDS; band++ {
+		buckets := make(map[uint64][]int)
+		for docID, fp := range fingerprints {
+			if fp == nil {
+				continue
+			}
+			h := fnv.New64a()
+			for _, value := range fp.Signature[band*rows : (band+1)*rows] {
+				var buf [8]byte
+				for i := range buf {
+					buf[i] = byte(value >> (8 * i))
+				}
+				h.Write(buf[:])
+			}
+			key := h.Sum64()
+			for _, other := range buckets[key] {
+				pairs[[2]int{other, docID}] = true
+			}
+			buckets[key] = append(buckets[key], docID)
+		}
+	}
+	return pairs
+}
+
+// Kelompokkan artikel yang saling sindikasi (antar source berbeda)
+func findSyndicated(articles []Article) []*SyndicationGroup {
+	fingerprints := make([]*Fingerprint, len(articles))
+	for docID, article := range articles {
+		fingerprints[docID] = fingerprint(article.Content)
+	}
+
+	parent := make([]int, len(articles))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for pair := range lshCandidates(fingerprints) {
+		a, b := pair[0], pair[1]
+		if articles[a].URL == articles[b].URL || sourceOf(articles[a].URL) == sourceOf(articles[b].URL) {
+			continue
+		}
+		if jaccard(fingerprints[a].Shingles, fingerprints[b].Shingles) >= SYNDICATION_THRESHOLD {
+			parent[find(a)] = find(b)
+		}
+	}
+
+	members := make(map[int][]int)
+	for docID := range articles {
+		if fingerprints[docID] != nil {
+			root := find(docID)
+			members[root] = append(members[root], docID)
+		}
+	}
+
+	var groups []*SyndicationGroup
+	for _, docIDs := range members {
+		if len(docIDs) < 2 {
+			continue
+		}
+		sort.Slice(docIDs, func(i, j int) bool {
+			return moreCanonical(articles[docIDs[i]], articles[docIDs[j]])
+		})
+		group := &SyndicationGroup{Canonical: articles[docIDs[0]].URL}
+		for _, docID := range docIDs {
+			article := articles[docID]
+			group.Copies = append(group.Copies, SyndicatedCopy{
+				URL:    article.URL,
+				Source: sourceOf(article.URL),
+				Title:  article.Title,
+				Date:   article.Date,
+			})
+		}
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Canonical < groups[j].Canonical })
+	return groups
+}
+
+// Kanonik: terbit paling awal (tanggal kosong paling akhir), lalu konten
+// terpanjang, lalu URL
+func moreCanonical(a, b Article) bool {
+	if !a.Date.Equal(b.Date) {
+		if a.Date.IsZero() || b.Date.IsZero() {
+			return !a.Date.IsZero()
+		}
+		return a.Date.Before(b.Date)
+	}
+	if wordsA, wordsB := countWords(a.Content), countWords(b.Content); wordsA != wordsB {
+		return wordsA > wordsB
+	}
+	return a.URL < b.URL
+}
+
+// Dipanggil noteIndexGeneration, sebelum konten dilepas ke document store
+func updateSyndication(articles []Article) {
+	groups := findSyndicated(articles)
+	byURL := make(map[string]*SyndicationGroup)
+	for _, group := range groups {
+		for _, member := range group.Copies {
+			byURL[member.URL] = group
+		}
+	}
+
+	syndication.mu.Lock()
+	syndication.groups, syndication.byURL = groups, byURL
+	syndication.mu.Unlock()
+}
+
+func syndicationGroup(url string) *SyndicationGroup {
+	syndication.mu.RLock()
+	defer syndication.mu.RUnlock()
+	return syndication.byURL[url]
+}
+
+// Satu hasil per grup sindikasi: versi kanonik kalau ikut muncul, kalau
+// tidak salinan dengan peringkat tertinggi. AlsoPublished diisi salinan lain
+// dan Collapsed diisi hasil yang dibuang. Dengan collapse false (collapse=false)
+// semua hasil tetap ada dan salinan ditandai DuplicateOf.
+func collapseSyndicated(results []SearchResult, collapse bool) []SearchResult {
+	chosen := make(map[*SyndicationGroup]int)
+	for i, result := range results {
+		group := syndicationGroup(result.URL)
+		if group == nil {
+			continue
+		}
+		if _, exists := chosen[group]; !exists || result.URL == group.Canonical {
+			chosen[group] = i
+		}
+	}
+
+	collapsed := make(map[*SyndicationGroup][]CollapsedResult)
+	for i, result := range results {
+		if group := syndicationGroup(result.URL); group != nil && chosen[group] != i {
+			collapsed[group] = append(collapsed[group], collapsedFrom(result, CollapseSyndicated))
+			if !collapse {
+				duplicateOf := collapsedFrom(results[chosen[group]], CollapseSyndicated)
+				results[i].DuplicateOf = &duplicateOf
+			}
+		}
+	}
+
+	kept := results[:0]
+	for i, result := range results {
+		group := syndicationGroup(result.URL)
+		if group == nil {
+			kept = append(kept, result)
+			continue
+		}
+		if chosen[group] != i {
+			if !collapse {
+				kept = append(kept, result)
+			}
+			continue
+		}
+		for _, member := range group.Copies {
+			if member.URL != result.URL {
+				result.AlsoPublished = append(result.AlsoPublished, member)
+			}
+		}
+		if collapse {
+			result.Collapsed = append(result.Collapsed, collapsed[group]...)
+		}
+		kept = append(kept, result)
+	}
+	return kept
+}
+
+// GET /api/admin/syndication
+func syndicationHandler(c *gin.Context) {
+	syndication.mu.RLock()
+	groups := syndication.groups
+	syndication.mu.RUnlock()
+
+	copies := 0
+	for _, group := range groups {
+		copies += len(group.Copies) - 1
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"groups":     len(groups),
+		"duplicates": copies,
+		"syndicated": groups,
+	})
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const SYNONYMS_FILE = "synonyms.json"
+
+// Bobot term sinonim di query vector, lebih kecil dari term asli
+const SYNONYM_WEIGHT = 0.5
+
+// SynonymCandidate adalah usulan sinonim (mis. hasil mining zero-result query)
+// yang menunggu persetujuan admin. Term dan Synonym disimpan dalam bentuk
+// hasil ProcessText supaya langsung cocok dengan term di index.
+type SynonymCandidate struct {
+	Term      string    `json:"term"`
+	Synonym   string    `json:"synonym"`
+	Source    string    `json:"source"`
+	Count     int       `json:"count"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SynonymStore struct {
+	mu         sync.RWMutex
+	path       string
+	Approved   map[string][]string `json:"approved"`
+	Candidates []*SynonymCandidate `json:"candidates"`
+	// Fingerprint analyzer yang menghasilkan term di atas, lihat analyzercheck.go
+	Analyzer string `json:"analyzer,omitempty"`
+}
+
+var synonyms *SynonymStore
+
+func loadSynonyms(path string) (*SynonymStore, error) {
+	store := &SynonymStore{
+		path:     path,
+		Approved: make(map[string][]string),
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, err
+	}
+	if store.Approved == nil {
+		store.Approved = make(map[string][]string)
+	}
+
+	return store, nil
+}
+
+// File lama tanpa fingerprint dianggap cocok dan ditandai saat disimpan.
+// Fingerprint yang beda tidak ditimpa; hanya Reanalyze yang memperbaruinya.
+func (s *SynonymStore) save() error {
+	if s.Analyzer == "" {
+		s.Analyzer = analyzerFingerprint(currentConfig())
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.path, data, 0644)
+}
+
+// Normalisasi term/sinonim lewat text processor yang sama dengan index
+func analyzeTerm(text string) string {
+	return strings.Join(textProcessor.ProcessText(text), " ")
+}
+
+// Tambah kandidat baru, kandidat yang sudah ada hanya di-update count-nya
+func (s *SynonymStore) AddCandidate(term, synonym, source string, count int) {
+	term, synonym = analyzeTerm(term), analyzeTerm(synonym)
+	if term == "" || synonym == "" || term == synonym {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.Candidates {
+		if c.Term == term && c.Synonym == synonym {
+			if count > c.Count {
+				c.Count = count
+			}
+			return
+		}
+	}
+
+	s.Candidates = append(s.Candidates, &SynonymCandidate{
+		Term:      term,
+		Synonym:   synonym,
+		Source:    source,
+		Count:     count,
+		Status:    ReviewPending,
+		CreatedAt: time.Now(),
+	})
+	if err := s.save(); err != nil {
+		log.Printf("Error saving synonyms: %v", err)
+	}
+}
+
+// Salinan kandidat, nil kalau tidak ada
+func (s *SynonymStore) Candidate(term, synonym string) *SynonymCandidate {
+	term, synonym = analyzeTerm(term), analyzeTerm(synonym)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, c := range s.Candidates {
+		if c.Term == term && c.Synonym == synonym {
+			copied := *c
+			return &copied
+		}
+	}
+	return nil
+}
+
+// Approve/reject kandidat; approve memasukkan sinonim ke daftar aktif.
+// Yang dikembalikan salinan, bukan pointer ke kandidat di store.
+func (s *SynonymStore) Decide(term, synonym, status string) (*SynonymCandidate, error) {
+	term, synonym = analyzeTerm(term), analyzeTerm(synonym)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var found *SynonymCandidate
+	for _, c := range s.Candidates {
+		if c.Term == term && c.Synonym == synonym {
+			found = c
+			break
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no synonym candidate %q -> %q", term, synonym)
+	}
+
+	found.Status = status
+	if status == ReviewApproved {
+		s.Approved[term] = appendUniqueStrings(s.Approved[term], synonym)
+	}
+
+	copied := *found
+	return &copied, s.save()
+}
+
+// Salinan kandidat dengan status tertentu ("" = semua); di-encode handler
+// setelah lock dilepas
+func (s *SynonymStore) List(status string) []*SynonymCandidate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*SynonymCandidate, 0)
+	for _, c := range s.Candidates {
+		if status == "" || c.Status == status {
+			copied := *c
+			result = append(result, &copied)
+		}
+	}
+	return result
+}
+
+func (s *SynonymStore) analyzer() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Analyzer
+}
+
+// Analisis ulang semua term dan sinonim dengan analyzer sekarang setelah
+// analyzer berubah. Hasilnya best effort karena bentuk asli kata tidak
+// disimpan: stem di-stem lagi. Pasangan yang jadi kosong atau sama dibuang.
+func (s *SynonymStore) Reanalyze() (changed, dropped int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	approved := make(map[string][]string, len(s.Approved))
+	for term, list := range s.Approved {
+		analyzedTerm := analyzeTerm(term)
+		for _, synonym := range list {
+			analyzedSynonym := analyzeTerm(synonym)
+			if analyzedTerm == "" || analyzedSynonym == "" || analyzedTerm == analyzedSynonym {
+				dropped++
+				continue
+			}
+			if analyzedTerm != term || analyzedSynonym != synonym {
+				changed++
+			}
+			approved[analyzedTerm] = appendUniqueStrings(approved[analyzedTerm], analyzedSynonym)
+		}
+	}
+
+	candidates := make([]*SynonymCandidate, 0, len(s.Candidates))
+	for _, c := range s.Candidates {
+		term, synonym := analyzeTerm(c.Term), analyzeTerm(c.Synonym)
+		if term == "" || synonym == "" || term == synonym {
+			dropped++
+			continue
+		}
+		if term != c.Term || synonym != c.Synonym {
+			changed++
+		}
+		c.Term, c.Synonym = term, synonym
+		candidates = append(candidates, c)
+	}
+
+	s.Approved, s.Candidates = approved, candidates
+	s.Analyzer = analyzerFingerprint(currentConfig())
+	return changed, dropped, s.save()
+}
+
+// Sinonim yang di-approve untuk satu term hasil analisis
+func (s *SynonymStore) Lookup(term string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.Approved[term]...)
+}
+
+// Tambahkan sinonim yang sudah di-approve ke query vector
+func (s *SynonymStore) Expand(queryVector map[string]float64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	expandSynonyms(s.Approved, queryVector)
+}
+
+// Salinan daftar sinonim aktif, disimpan bersama generasi index
+func (s *SynonymStore) Snapshot() map[string][]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	approved := make(map[string][]string, len(s.Approved))
+	for term, list := range s.Approved {
+		approved[term] = append([]string(nil), list...)
+	}
+	return approved
+}
+
+func expandSynonyms(approved map[string][]string, queryVector map[string]float64) {
+	terms := make([]string, 0, len(queryVector))
+	for term := range queryVector {
+		terms = append(terms, term)
+	}
+
+	for _, term := range terms {
+		for _, synonym := range approved[term] {
+			for _, token := range strings.Fields(synonym) {
+				if _, exists := queryVector[token]; !exists {
+					queryVector[token] = SYNONYM_WEIGHT
+				}
+			}
+		}
+	}
+}
+
+func init() {
+	store, err := loadSynonyms(SYNONYMS_FILE)
+	if err != nil {
+		log.Printf("Error loading %s: %v", SYNONYMS_FILE, err)
+		store = &SynonymStore{path: SYNONYMS_FILE, Approved: make(map[string][]string)}
+	}
+	synonyms = store
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Tabel di halaman artikel (perbandingan harga, cicilan KPR, spesifikasi
+// unit). Ekstraksi konten hanya mengambil paragraf, jadi isi tabel dulu
+// hilang. Tabel disimpan per baris di dokumen (Article.Tables), teksnya ikut
+// diindex sebagai bagian dari field content (lihat IndexSchema.documentText),
+// dan tabel utama ditampilkan di halaman dokumen (/document/:id).
+//
+// Struktur yang sama ada di crawler/tables.go dan harus tetap sama.
+const (
+	TABLE_MAX_ROWS       = 200
+	TABLE_MAX_CELL_BYTES = 500
+	// Tabel yang ditampilkan di halaman dokumen
+	DOCUMENT_PAGE_TABLES = 3
+)
+
+type ArticleTable struct {
+	Caption string     `json:"caption,omitempty"`
+	Header  []string   `json:"header,omitempty"`
+	Rows    [][]string `json:"rows"`
+}
+
+// Tabel data di container artikel. Tabel layout (berisi tabel lain) dan
+// tabel satu kolom dilewati.
+func extractTables(container *goquery.Selection) []ArticleTable {
+	var tables []ArticleTable
+	container.Find("table").Each(func(_ int, s *goquery.Selection) {
+		if table, ok := tableOf(s); ok {
+			tables = append(tables, table)
+		}
+	})
+	return tables
+}
+
+func tableOf(s *goquery.Selection) (ArticleTable, bool) {
+	if s.Find("table").Length() > 0 {
+		return ArticleTable{}, false
+	}
+	table := ArticleTable{Caption: cellText(s.ChildrenFiltered("caption"))}
+	columns := 0
+	s.Find("tr").EachWithBreak(func(i int, tr *goquery.Selection) bool {
+		var cells []string
+		headerRow := true
+		tr.ChildrenFiltered("th, td").Each(func(_ int, cell *goquery.Selection) {
+			cells = append(cells, cellText(cell))
+			headerRow = headerRow && goquery.NodeName(cell) == "th"
+		})
+		if strings.Join(cells, "") == "" {
+			return true
+		}
+		if len(cells) > columns {
+			columns = len(cells)
+		}
+		// Baris th pertama (atau baris di thead) jadi header
+		if table.Header == nil && len(table.Rows) == 0 && (headerRow || tr.ParentsFiltered("thead").Length() > 0) {
+			table.Header = cells
+			return true
+		}
+		table.Rows = append(table.Rows, cells)
+		return len(table.Rows) < TABLE_MAX_ROWS
+	})
+	return table, columns >= 2 && len(table.Rows) > 0
+}
+
+// Teks sel dengan whitespace dirapikan
+func cellText(s *goquery.Selection) string {
+	text := strings.Join(strings.Fields(s.Text()), " ")
+	if len(text) <= TABLE_MAX_CELL_BYTES {
+		return text
+	}
+	end := TABLE_MAX_CELL_BYTES
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end]
+}
+
+// Teks tabel untuk diindex: caption, header lalu satu baris per row
+func tablesText(tables []ArticleTable) string {
+	var b strings.Builder
+	for _, table := range tables {
+		lines := make([]string, 0, len(table.Rows)+2)
+		if table.Caption != "" {
+			lines = append(lines, table.Caption)
+		}
+		if len(table.Header) > 0 {
+			lines = append(lines, strings.Join(table.Header, " | "))
+		}
+		for _, row := range table.Rows {
+			lines = append(lines, strings.Join(row, " | "))
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(strings.Join(lines, "\n"))
+	}
+	return b.String()
+}
+
+// Tabel yang ditampilkan di halaman dokumen: yang punya header dulu, lalu
+// yang barisnya paling banyak
+func keyTables(tables []ArticleTable) []ArticleTable {
+	ranked := make([]ArticleTable, len(tables))
+	copy(ranked, tables)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if (len(ranked[i].Header) > 0) != (len(ranked[j].Header) > 0) {
+			return len(ranked[i].Header) > 0
+		}
+		return len(ranked[i].Rows) > len(ranked[j].Rows)
+	})
+	if len(ranked) > DOCUMENT_PAGE_TABLES {
+		ranked = ranked[:DOCUMENT_PAGE_TABLES]
+	}
+	return ranked
+}
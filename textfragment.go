@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Link hasil pencarian langsung ke paragraf yang cocok di situs sumber lewat
+// text fragment (https://example.com/artikel#:~:text=awal,akhir). Fragment
+// disusun dari baris konten mentah (satu <p> di halaman sumber) yang memuat
+// term query terbanyak. Halaman hasil hanya menempelkannya kalau browser
+// mendukung (document.fragmentDirective).
+
+// Jumlah kata di awal dan akhir baris untuk text=awal,akhir
+const TEXT_FRAGMENT_WORDS = 4
+
+// Text directive ("text=...") untuk baris terbaik di content, kosong kalau
+// tidak ada baris yang memuat term query
+func textFragment(content, language string, queryVector map[string]float64) string {
+	analyzer := analyzerFor(language)
+	best, bestMatches := "", 0
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			continue
+		}
+		matches := 0
+		for _, term := range uniqueTerms(analyzer.ProcessText(line)) {
+			if queryVector[term] > 0 {
+				matches++
+			}
+		}
+		if matches > bestMatches {
+			best, bestMatches = line, matches
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fragmentDirective(best)
+}
+
+// Text directive untuk satu potongan teks: utuh kalau pendek, kalau tidak
+// text=awal,akhir
+func fragmentDirective(text string) string {
+	words := strings.Fields(text)
+	if len(words) <= 2*TEXT_FRAGMENT_WORDS {
+		return "text=" + encodeFragmentText(strings.Join(words, " "))
+	}
+	start := strings.Join(words[:TEXT_FRAGMENT_WORDS], " ")
+	end := strings.Join(words[len(words)-TEXT_FRAGMENT_WORDS:], " ")
+	return "text=" + encodeFragmentText(start) + "," + encodeFragmentText(end)
+}
+
+// Selain percent-encoding biasa, "-", "," dan "&" punya arti khusus di text
+// directive sehingga harus di-escape
+func encodeFragmentText(text string) string {
+	return strings.NewReplacer("-", "%2D", ",", "%2C", "&", "%26").Replace(url.PathEscape(text))
+}
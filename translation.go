@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Pencarian lintas bahasa: query diterjemahkan (Inggris -> Indonesia atau
+// sebaliknya) lalu terjemahannya ikut dicari di index utama sebagai tier
+// "translated". Pengguna ekspatriat yang mencari "subsidized house bekasi"
+// tetap menemukan artikel berbahasa Indonesia.
+//
+//	"translation": {"enabled": true, "provider": "libretranslate", "url": "http://localhost:5000/translate"}
+//
+// Hasil yang ditemukan lewat terjemahan digabung dengan hasil query asli per
+// URL (skor tertinggi menang), dengan skor dikali weight karena terjemahan
+// bisa meleset. Lama terjemahan dibatasi tier_timeouts_ms["translated"];
+// kalau terlambat, hasil query asli tetap dikembalikan. Arsip dan instance
+// remote hanya dicari dengan query asli. Provider lain cukup mengimplementasi
+// Translator dan didaftarkan di translationProviders.
+const (
+	TIER_TRANSLATED = "translated"
+
+	TRANSLATION_PROVIDER      = "libretranslate"
+	TRANSLATION_URL           = "http://localhost:5000/translate"
+	TRANSLATION_WEIGHT        = 0.8
+	TRANSLATION_TIMEOUT       = 1500 * time.Millisecond
+	TRANSLATION_CACHE_SIZE    = 1024
+	TRANSLATION_MAX_QUERY_LEN = 200
+)
+
+type TranslationConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Provider string `json:"provider"`
+	URL      string `json:"url"`
+	APIKey   string `json:"api_key"`
+	// Pengali skor hasil yang hanya/lebih cocok lewat terjemahan
+	Weight float64 `json:"weight"`
+}
+
+func defaultTranslationConfig() TranslationConfig {
+	return TranslationConfig{
+		Provider: TRANSLATION_PROVIDER,
+		URL:      TRANSLATION_URL,
+		Weight:   TRANSLATION_WEIGHT,
+	}
+}
+
+// Terjemahan query yang ikut dicari, ditampilkan di response
+type QueryTranslation struct {
+	Text     string `json:"text"`
+	Source   string `json:"source"` // bahasa query asli
+	Target   string `json:"target"`
+	Provider string `json:"provider"`
+}
+
+// Provider terjemahan. source kosong berarti deteksi otomatis; bahasa yang
+// terdeteksi dikembalikan di QueryTranslation.Source.
+type Translator interface {
+	Name() string
+	Translate(ctx context.Context, text, source, target string) (QueryTranslation, error)
+}
+
+var translationProviders = map[string]func(TranslationConfig) Translator{
+	TRANSLATION_PROVIDER: newLibreTranslate,
+}
+
+func translator(cfg TranslationConfig) (Translator, error) {
+	newProvider, exists := translationProviders[cfg.Provider]
+	if !exists {
+		return nil, fmt.Errorf("unknown translation provider %q", cfg.Provider)
+	}
+	return newProvider(cfg), nil
+}
+
+type libreTranslate struct {
+	url    string
+	apiKey string
+}
+
+func newLibreTranslate(cfg TranslationConfig) Translator {
+	return &libreTranslate{url: cfg.URL, apiKey: cfg.APIKey}
+}
+
+func (l *libreTranslate) Name() string { return TRANSLATION_PROVIDER }
+
+// POST {"q", "source", "target"} ke LibreTranslate (bisa di-host sendiri)
+func (l *libreTranslate) Translate(ctx context.Context, text, source, target string) (QueryTranslation, error) {
+	if source == "" {
+		source = "auto"
+	}
+	payload, err := json.Marshal(map[string]string{
+		"q":       text,
+		"source":  source,
+		"target":  target,
+		"format":  "text",
+		"api_key": l.apiKey,
+	})
+	if err != nil {
+		return QueryTranslation{}, err
+	}
+	req, err := http.NewRequest(http.MethodPost, l.url, bytes.NewReader(payload))
+	if err != nil {
+		return QueryTranslation{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return QueryTranslation{}, err
+	}
+	defer res.Body.Close()
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return QueryTranslation{}, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return QueryTranslation{}, fmt.Errorf("libretranslate returned %s: %s", res.Status, strings.TrimSpace(string(data)))
+	}
+
+	var result struct {
+		TranslatedText   string `json:"translatedText"`
+		DetectedLanguage struct {
+			Language string `json:"language"`
+		} `json:"detectedLanguage"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return QueryTranslation{}, fmt.Errorf("invalid libretranslate response: %v", err)
+	}
+	if source == "auto" {
+		source = result.DetectedLanguage.Language
+	}
+	return QueryTranslation{Text: result.TranslatedText, Source: source, Target: target, Provider: l.Name()}, nil
+}
+
+// Cache terjemahan per query; dikosongkan kalau penuh
+type TranslationCache struct {
+	mu      sync.Mutex
+	entries map[string]*QueryTranslation
+}
+
+var translationCache = &TranslationCache{entries: make(map[string]*QueryTranslation)}
+
+func (tc *TranslationCache) Get(query string) (*QueryTranslation, bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	translation, exists := tc.entries[query]
+	return translation, exists
+}
+
+func (tc *TranslationCache) Put(query string, translation *QueryTranslation) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if len(tc.entries) >= TRANSLATION_CACHE_SIZE {
+		tc.entries = make(map[string]*QueryTranslation)
+	}
+	tc.entries[query] = translation
+}
+
+// Arah terjemahan dari stopword query. Query pendek tanpa stopword
+// ("green building jakarta") dideteksi provider dan diterjemahkan ke
+// Indonesia, bahasa sebagian besar corpus.
+func translationDirection(query string) (source, target string) {
+	idHits, enHits := languageHits(query)
+	switch {
+	case enHits > idHits:
+		return LangEnglish, LangIndonesian
+	case idHits > enHits:
+		return LangIndonesian, LangEnglish
+	}
+	return "", LangIndonesian
+}
+
+// Terjemahan query, nil kalau terjemahannya sama dengan query asli (mis.
+// query Indonesia yang dideteksi otomatis). Hasil, termasuk nil, di-cache.
+func translateQuery(ctx context.Context, query string, cfg TranslationConfig) (*QueryTranslation, error) {
+	if cached, exists := translationCache.Get(query); exists {
+		return cached, nil
+	}
+	provider, err := translator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	source, target := translationDirection(query)
+	translation, err := provider.Translate(ctx, query, source, target)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *QueryTranslation
+	translation.Text = normalizeQuery(translation.Text)
+	if translation.Text != "" && translation.Source != target && !strings.EqualFold(translation.Text, query) {
+		result = &translation
+	}
+	translationCache.Put(query, result)
+	return result, nil
+}
+
+// Tier yang mencari terjemahan query di index yang sama dengan query asli. Terjemahan yang
+// dipakai dicatat di *translation setelah tier selesai.
+func translatedTier(pinned *GenerationIndex, query, method string, opts SearchOptions, translation **QueryTranslation) SearchTier {
+	opts = opts.withConfig()
+	timeout := tierTimeout(opts.Config, TIER_TRANSLATED, TRANSLATION_TIMEOUT)
+	return SearchTier{
+		Name:    TIER_TRANSLATED,
+		Timeout: timeout,
+		Search: func() ([]SearchResult, *QueryPlan, error) {
+			ctx := context.Background()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			translated, err := translateQuery(ctx, query, opts.Config.Translation)
+			if err != nil || translated == nil {
+				return nil, nil, err
+			}
+			*translation = translated
+
+			queryVector := buildQueryVector(translated.Text)
+			pinned.expand(queryVector)
+			results, _ := searchIndex(pinned.Articles, pinned.Index, translated.Text, queryVector, method, opts, false)
+			return results, nil, nil
+		},
+	}
+}
+
+// Terjemahan query ikut dicari kecuali translate=false atau query terlalu panjang
+func shouldTranslate(query string, opts SearchOptions) bool {
+	return opts.withConfig().Config.Translation.Enabled && !opts.NoTranslate && len(query) <= TRANSLATION_MAX_QUERY_LEN
+}
+
+// Gabungkan hasil terjemahan ke hasil query asli dari index yang sama. Skor
+// terjemahan dikali weight; hasil yang lebih cocok lewat terjemahan memakai
+// snippet dan highlight terjemahan dan ditandai Translated.
+func mergeTranslated(results, translated []SearchResult, weight float64) []SearchResult {
+	if weight <= 0 || weight > 1 {
+		weight = TRANSLATION_WEIGHT
+	}
+	position := make(map[string]int, len(results))
+	for i, result := range results {
+		position[result.URL] = i
+	}
+	for _, result := range translated {
+		result.Score *= weight
+		result.Translated = true
+		if i, exists := position[result.URL]; exists {
+			if result.Score > results[i].Score {
+				results[i] = result
+			}
+			continue
+		}
+		position[result.URL] = len(results)
+		results = append(results, result)
+	}
+	return results
+}
+
+func (p *QueryPlan) translation() *QueryTranslation {
+	if p == nil {
+		return nil
+	}
+	return p.Translation
+}
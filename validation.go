@@ -0,0 +1,298 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validasi dokumen yang masuk lewat ingestion (POST /api/admin/ingest dan
+// unggahan dokumen). Setiap record dicek sendiri; record yang tidak valid
+// ditolak dengan alasan per field, record lain dalam batch yang sama tetap
+// diterima. Aturan di config.json:
+//
+//	"validation": {
+//	  "required_fields": ["url", "title", "content"],
+//	  "max_content_bytes": 1048576,
+//	  "max_field_length": {"title": 300, "author": 200, "url": 2048},
+//	  "allowed_doctypes": ["article", "pdf", "docx"],
+//	  "max_upload_bytes": 33554432,
+//	  "max_batch_records": 1000
+//	}
+const (
+	MAX_CONTENT_BYTES = 1 << 20
+	MAX_UPLOAD_BYTES  = 32 << 20
+	MAX_BATCH_RECORDS = 1000
+)
+
+// Nama rule di FieldError
+const (
+	RuleRequired  = "required"
+	RuleMaxLength = "max_length"
+	RuleURL       = "url"
+	RuleDocType   = "doctype"
+	RuleOp        = "op"
+	// Lihat visibility.go
+	RuleVisibility = "visibility"
+)
+
+type ValidationRules struct {
+	RequiredFields  []string       `json:"required_fields"`
+	MaxContentBytes int            `json:"max_content_bytes"`
+	MaxFieldLength  map[string]int `json:"max_field_length"` // dalam karakter
+	AllowedDocTypes []string       `json:"allowed_doctypes"`
+	MaxUploadBytes  int64          `json:"max_upload_bytes"`
+	MaxBatchRecords int            `json:"max_batch_records"`
+}
+
+func defaultValidationRules() ValidationRules {
+	return ValidationRules{
+		RequiredFields:  []string{"url", "title", "content"},
+		MaxContentBytes: MAX_CONTENT_BYTES,
+		MaxFieldLength:  map[string]int{"title": 300, "author": 200, "url": 2048},
+		AllowedDocTypes: []string{DOCTYPE_ARTICLE, DOCTYPE_PDF, DOCTYPE_DOCX},
+		MaxUploadBytes:  MAX_UPLOAD_BYTES,
+		MaxBatchRecords: MAX_BATCH_RECORDS,
+	}
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Record yang ditolak; Index adalah posisinya di batch
+type RecordError struct {
+	Index  int          `json:"index"`
+	Op     string       `json:"op,omitempty"`
+	URL    string       `json:"url,omitempty"`
+	Errors []FieldError `json:"errors"`
+}
+
+func (e RecordError) Error() string {
+	return fmt.Sprintf("record %d: %s", e.Index, fieldErrorsString(e.Errors))
+}
+
+// "title: is required; url: must be ..."
+func fieldErrorsString(errs []FieldError) string {
+	messages := make([]string, len(errs))
+	for i, fieldErr := range errs {
+		messages[i] = fieldErr.Field + ": " + fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Nilai field artikel untuk required_fields dan max_field_length
+func articleField(article *Article, field string) (string, bool) {
+	switch field {
+	case "url":
+		return article.URL, true
+	case "title":
+		return article.Title, true
+	case "content":
+		return article.Content, true
+	case "author":
+		return article.Author, true
+	case "language":
+		return article.Language, true
+	case "breadcrumb":
+		return strings.Join(article.Breadcrumb, " / "), true
+	case "date":
+		if article.Date.IsZero() {
+			return "", true
+		}
+		return article.Date.Format("2006-01-02"), true
+	}
+	return "", false
+}
+
+// URL dokumen harus absolut dengan skema http(s) dan host
+func validateURL(rawURL string) *FieldError {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &FieldError{Field: "url", Rule: RuleURL, Message: "must be an absolute http(s) URL"}
+	}
+	return nil
+}
+
+// Semua pelanggaran aturan untuk satu artikel
+func (rules ValidationRules) validateArticle(article *Article, schema IndexSchema) []FieldError {
+	var errs []FieldError
+	for _, field := range rules.RequiredFields {
+		if value, known := articleField(article, field); known && strings.TrimSpace(value) == "" {
+			errs = append(errs, FieldError{Field: field, Rule: RuleRequired, Message: "is required"})
+		}
+	}
+	if article.URL != "" {
+		if err := validateURL(article.URL); err != nil {
+			errs = append(errs, *err)
+		}
+	}
+	if rules.MaxContentBytes > 0 && len(article.Content) > rules.MaxContentBytes {
+		errs = append(errs, FieldError{Field: "content", Rule: RuleMaxLength,
+			Message: fmt.Sprintf("is %d bytes, limit is %d", len(article.Content), rules.MaxContentBytes)})
+	}
+	// Urut per nama field supaya laporan stabil
+	fields := make([]string, 0, len(rules.MaxFieldLength))
+	for field := range rules.MaxFieldLength {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		limit := rules.MaxFieldLength[field]
+		value, known := articleField(article, field)
+		if !known || limit <= 0 {
+			continue
+		}
+		if length := utf8.RuneCountInString(value); length > limit {
+			errs = append(errs, FieldError{Field: field, Rule: RuleMaxLength,
+				Message: fmt.Sprintf("is %d characters, limit is %d", length, limit)})
+		}
+	}
+	if err := validateVisibility(article); err != nil {
+		errs = append(errs, *err)
+	}
+	if len(rules.AllowedDocTypes) > 0 && !containsString(rules.AllowedDocTypes, docTypeOf(*article)) {
+		errs = append(errs, FieldError{Field: "doctype", Rule: RuleDocType,
+			Message: fmt.Sprintf("%q is not allowed (allowed: %s)", docTypeOf(*article), strings.Join(rules.AllowedDocTypes, ", "))})
+	}
+	// Field wajib di schema yang sudah ada di required_fields tidak dilaporkan dua kali
+	for _, schemaErr := range schema.validateArticle(article) {
+		if schemaErr.Rule == RuleRequired && containsString(rules.RequiredFields, schemaErr.Field) {
+			continue
+		}
+		errs = append(errs, schemaErr)
+	}
+	return errs
+}
+
+// Validasi satu record WAL: struktur (op, url) lalu aturan artikel
+func (rules ValidationRules) validateRecord(index int, record WalRecord, schema IndexSchema) *RecordError {
+	recordErr := &RecordError{Index: index, Op: record.Op, URL: record.URL}
+	switch record.Op {
+	case WalAdd, WalUpdate:
+		if record.Article == nil {
+			recordErr.Errors = []FieldError{{Field: "article", Rule: RuleRequired, Message: fmt.Sprintf("%s requires an article", record.Op)}}
+			return recordErr
+		}
+		recordErr.URL = record.Article.URL
+		recordErr.Errors = rules.validateArticle(record.Article, schema)
+	case WalDelete:
+		if record.URL == "" {
+			recordErr.Errors = []FieldError{{Field: "url", Rule: RuleRequired, Message: "is required"}}
+		}
+	default:
+		recordErr.Errors = []FieldError{{Field: "op", Rule: RuleOp, Message: fmt.Sprintf("unknown op %q", record.Op)}}
+	}
+	if len(recordErr.Errors) == 0 {
+		return nil
+	}
+	return recordErr
+}
+
+// Pisahkan batch jadi record valid dan record yang ditolak
+func (rules ValidationRules) validateBatch(records []WalRecord, schema IndexSchema) ([]WalRecord, []RecordError) {
+	var valid []WalRecord
+	rejected := []RecordError{}
+	dynamic := make(map[string]string) // tipe field yang akan dipetakan otomatis
+	for i, record := range records {
+		err := rules.validateRecord(i, record, schema)
+		if err == nil && record.Article != nil {
+			if errs := schema.checkDynamic(record.Article, dynamic); len(errs) > 0 {
+				err = &RecordError{Index: i, Op: record.Op, URL: record.Article.URL, Errors: errs}
+			}
+		}
+		if err != nil {
+			rejected = append(rejected, *err)
+			continue
+		}
+		valid = append(valid, record)
+	}
+	return valid, rejected
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// searchctl validate <file>: cek file bulk import (array record ingest atau
+// array artikel) dengan aturan validasi config.json tanpa mengirimnya
+func validateCommand(args []string) int {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "cetak record yang ditolak sebagai JSON")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: searchctl validate [--json] <file.json>")
+		return 2
+	}
+
+	data, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	records, err := decodeImport(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", fs.Arg(0), err)
+		return 1
+	}
+
+	cfg := currentConfig()
+	valid, rejected := cfg.Validation.validateBatch(records, cfg.Schema)
+	if *asJSON {
+		out, _ := json.MarshalIndent(rejected, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		for _, recordErr := range rejected {
+			fmt.Printf("#%d %s\n", recordErr.Index, recordErr.URL)
+			for _, fieldErr := range recordErr.Errors {
+				fmt.Printf("  %s (%s): %s\n", fieldErr.Field, fieldErr.Rule, fieldErr.Message)
+			}
+		}
+		fmt.Printf("%d records, %d valid, %d rejected\n", len(records), len(valid), len(rejected))
+	}
+	if len(rejected) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// Array record ingest ({"op", "url", "article"}) atau array artikel biasa
+// (dianggap update)
+func decodeImport(data []byte) ([]WalRecord, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	records := make([]WalRecord, len(raw))
+	for i, item := range raw {
+		var probe struct {
+			Op string `json:"op"`
+		}
+		json.Unmarshal(item, &probe)
+		if probe.Op != "" {
+			if err := json.Unmarshal(item, &records[i]); err != nil {
+				return nil, fmt.Errorf("record %d: %v", i, err)
+			}
+			continue
+		}
+		var article Article
+		if err := json.Unmarshal(item, &article); err != nil {
+			return nil, fmt.Errorf("record %d: %v", i, err)
+		}
+		records[i] = WalRecord{Op: WalUpdate, URL: article.URL, Article: &article}
+	}
+	return records, nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Visibility dokumen: "public" (default) atau "internal". Dokumen internal,
+// misalnya laporan pasar internal yang di-ingest, hanya muncul untuk klien
+// API yang mengirim API key dengan scope "internal" di header X-API-Key:
+//
+//	"api_keys": [{"name": "crm", "key": "...", "scopes": ["internal"]}]
+//
+// Visibility dicek sebagai filter wajib di setiap pencarian (searchIndex),
+// termasuk arsip dan terjemahan query. Scope hanya berasal dari API key,
+// tidak pernah dari parameter URL. Halaman web dan instance remote tanpa API
+// key hanya melihat dokumen public.
+const (
+	VisibilityPublic   = "public"
+	VisibilityInternal = "internal"
+
+	API_KEY_HEADER = "X-API-Key"
+
+	// Semua visibility, untuk job admin yang harus melihat semua dokumen
+	ScopeAll = "*"
+
+	apiKeyContextKey = "api_key"
+)
+
+var visibilities = []string{VisibilityPublic, VisibilityInternal}
+
+type APIKey struct {
+	Name   string   `json:"name"`
+	Key    string   `json:"key"`
+	Scopes []string `json:"scopes"`
+}
+
+func validateAPIKeys(keys []APIKey) error {
+	for i, key := range keys {
+		if key.Name == "" || key.Key == "" {
+			return fmt.Errorf("key %d needs a name and a key", i)
+		}
+		for _, scope := range key.Scopes {
+			if scope != ScopeAll && !containsString(visibilities, scope) {
+				return fmt.Errorf("key %q: unknown scope %q", key.Name, scope)
+			}
+		}
+	}
+	return nil
+}
+
+// Visibility artikel, kosong berarti public
+func (a Article) visibility() string {
+	if a.Visibility == "" {
+		return VisibilityPublic
+	}
+	return a.Visibility
+}
+
+func visibleTo(visibility string, scopes []string) bool {
+	return visibility == VisibilityPublic || containsString(scopes, visibility) || containsString(scopes, ScopeAll)
+}
+
+func (a Article) VisibleTo(scopes []string) bool {
+	return visibleTo(a.visibility(), scopes)
+}
+
+func validateVisibility(article *Article) *FieldError {
+	if article.Visibility == "" || containsString(visibilities, article.Visibility) {
+		return nil
+	}
+	return &FieldError{Field: "visibility", Rule: RuleVisibility,
+		Message: fmt.Sprintf("must be %s", strings.Join(visibilities, " or "))}
+}
+
+// Cocokkan X-API-Key dengan api_keys. Request tanpa key tetap lanjut sebagai
+// klien anonim; key yang tidak dikenal ditolak supaya klien tidak diam-diam
+// kehilangan dokumen internal.
+func apiKeyAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		given := c.GetHeader(API_KEY_HEADER)
+		if given == "" {
+			c.Next()
+			return
+		}
+		for _, key := range requestConfig(c).APIKeys {
+			if subtle.ConstantTimeCompare([]byte(given), []byte(key.Key)) == 1 {
+				c.Set(apiKeyContextKey, key)
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid API key"})
+	}
+}
+
+// Scope visibility klien dari API key-nya, nil untuk klien anonim
+func clientScopes(c *gin.Context) []string {
+	value, exists := c.Get(apiKeyContextKey)
+	if !exists {
+		return nil
+	}
+	scopes := append([]string(nil), value.(APIKey).Scopes...)
+	sort.Strings(scopes)
+	return scopes
+}
+
+// Filter wajib: dokumen yang tidak boleh dilihat scope ini dibuang dari
+// bitmap filter. nil tetap berarti tanpa filter kalau semua dokumen public.
+func restrictVisibility(filterBitmap *Bitmap, dv *DocValues, scopes []string) *Bitmap {
+	if !dv.Restricted || containsString(scopes, ScopeAll) {
+		return filterBitmap
+	}
+	visible := filterCache.clause(dv, filterClause{"visibility=" + strings.Join(scopes, ","), func(dv *DocValues, docID int) bool {
+		return visibleTo(dv.Visibilities[docID], scopes)
+	}})
+	if filterBitmap == nil {
+		return visible
+	}
+	return filterBitmap.And(visible)
+}
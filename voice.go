@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Pencarian suara (POST /api/voice-search, multipart "audio"). Audio dikirim
+// ke provider speech-to-text, lalu teks hasilnya dicari seperti q di
+// /api/search; parameter sort, filter dan paging diambil dari query string.
+//
+//	"voice": {"enabled": true, "provider": "whisper", "url": "http://localhost:8178/inference", "language": "id"}
+//
+// Provider "whisper" memakai server HTTP whisper (whisper.cpp server, atau
+// server yang kompatibel dengan /v1/audio/transcriptions OpenAI), jadi audio
+// tidak keluar dari jaringan sendiri. Provider lain cukup mengimplementasi
+// SpeechToText dan didaftarkan di speechProviders.
+const (
+	VOICE_PROVIDER        = "whisper"
+	VOICE_WHISPER_URL     = "http://localhost:8178/inference"
+	VOICE_LANGUAGE        = "id"
+	VOICE_MAX_AUDIO_BYTES = 10 << 20
+	VOICE_TIMEOUT         = 30 * time.Second
+)
+
+type VoiceConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Provider string `json:"provider"`
+	URL      string `json:"url"`
+	// Model untuk server kompatibel OpenAI; whisper.cpp mengabaikannya
+	Model         string `json:"model"`
+	Language      string `json:"language"` // kosong = deteksi otomatis oleh provider
+	MaxAudioBytes int64  `json:"max_audio_bytes"`
+	TimeoutSec    int    `json:"timeout_sec"`
+}
+
+func defaultVoiceConfig() VoiceConfig {
+	return VoiceConfig{
+		Provider:      VOICE_PROVIDER,
+		URL:           VOICE_WHISPER_URL,
+		Language:      VOICE_LANGUAGE,
+		MaxAudioBytes: VOICE_MAX_AUDIO_BYTES,
+		TimeoutSec:    int(VOICE_TIMEOUT / time.Second),
+	}
+}
+
+type Transcription struct {
+	Text     string  `json:"text"`
+	Language string  `json:"language,omitempty"`
+	Duration float64 `json:"duration,omitempty"` // detik audio
+	Provider string  `json:"provider"`
+}
+
+// Provider speech-to-text. language kosong berarti deteksi otomatis.
+type SpeechToText interface {
+	Name() string
+	Transcribe(ctx context.Context, audio []byte, contentType, language string) (Transcription, error)
+}
+
+var speechProviders = map[string]func(VoiceConfig) SpeechToText{
+	VOICE_PROVIDER: newWhisperServer,
+}
+
+// Provider sesuai config
+func speechToText(cfg VoiceConfig) (SpeechToText, error) {
+	newProvider, exists := speechProviders[cfg.Provider]
+	if !exists {
+		return nil, fmt.Errorf("unknown speech-to-text provider %q", cfg.Provider)
+	}
+	return newProvider(cfg), nil
+}
+
+type whisperServer struct {
+	url   string
+	model string
+}
+
+func newWhisperServer(cfg VoiceConfig) SpeechToText {
+	return &whisperServer{url: cfg.URL, model: cfg.Model}
+}
+
+func (w *whisperServer) Name() string { return VOICE_PROVIDER }
+
+// POST multipart "file" dengan response_format=verbose_json; whisper.cpp dan
+// server kompatibel OpenAI sama-sama menjawab {"text", "language", "duration"}
+func (w *whisperServer) Transcribe(ctx context.Context, audio []byte, contentType, language string) (Transcription, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="file"; filename="audio"`)
+	header.Set("Content-Type", contentType)
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return Transcription{}, err
+	}
+	if _, err := part.Write(audio); err != nil {
+		return Transcription{}, err
+	}
+	form.WriteField("response_format", "verbose_json")
+	form.WriteField("temperature", "0")
+	if language != "" {
+		form.WriteField("language", language)
+	}
+	if w.model != "" {
+		form.WriteField("model", w.model)
+	}
+	if err := form.Close(); err != nil {
+		return Transcription{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.url, &body)
+	if err != nil {
+		return Transcription{}, err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return Transcription{}, err
+	}
+	defer res.Body.Close()
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return Transcription{}, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return Transcription{}, fmt.Errorf("whisper server returned %s: %s", res.Status, strings.TrimSpace(string(data)))
+	}
+
+	var result struct {
+		Text     string  `json:"text"`
+		Language string  `json:"language"`
+		Duration float64 `json:"duration"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return Transcription{}, fmt.Errorf("invalid whisper response: %v", err)
+	}
+	return Transcription{
+		Text:     result.Text,
+		Language: result.Language,
+		Duration: result.Duration,
+		Provider: w.Name(),
+	}, nil
+}
+
+// Anotasi non-ucapan dari whisper, mis. "[BLANK_AUDIO]", "(musik)" atau "♪"
+var transcriptNoisePattern = regexp.MustCompile(`\[[^\]]*\]|\([^)]*\)|♪`)
+
+// Teks transkripsi jadi query: buang anotasi dan tanda baca kalimat
+func transcriptQuery(text string) string {
+	text = transcriptNoisePattern.ReplaceAllString(text, " ")
+	text = strings.Map(func(r rune) rune {
+		switch r {
+		case '.', ',', '?', '!', ';', ':', '"':
+			return ' '
+		}
+		return r
+	}, text)
+	return normalizeQuery(text)
+}
+
+type VoiceSearchResponse struct {
+	Transcription Transcription `json:"transcription"`
+	SearchAPIResponse
+}
+
+// POST /api/voice-search?per_page=5&sort=... (multipart): audio, opsional
+// language untuk menimpa voice.language di config
+func voiceSearchHandler(c *gin.Context) {
+	cfg := requestConfig(c).Voice
+	if !cfg.Enabled {
+		c.JSON(http.StatusNotFound, gin.H{"error": "voice search is disabled"})
+		return
+	}
+	provider, err := speechToText(cfg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	header, err := c.FormFile("audio")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "audio is required"})
+		return
+	}
+	if cfg.MaxAudioBytes > 0 && header.Size > cfg.MaxAudioBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("audio is %d bytes, limit is %d", header.Size, cfg.MaxAudioBytes)})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	audio, err := ioutil.ReadAll(file)
+	file.Close()
+	if err != nil || len(audio) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "audio is empty"})
+		return
+	}
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(audio)
+	}
+	language := c.PostForm("language")
+	if language == "" {
+		language = cfg.Language
+	}
+
+	timeout := time.Duration(cfg.TimeoutSec) * time.Second
+	if timeout <= 0 {
+		timeout = VOICE_TIMEOUT
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+	transcription, err := provider.Transcribe(ctx, audio, contentType, language)
+	if err != nil {
+		log.Printf("Error transcribing voice query: %v", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "speech-to-text failed: " + err.Error()})
+		return
+	}
+
+	query := transcriptQuery(transcription.Text)
+	if query == "" {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "no speech recognized", "transcription": transcription})
+		return
+	}
+	response, status, err := runSearchAPI(c, query)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error(), "transcription": transcription})
+		return
+	}
+	c.JSON(http.StatusOK, VoiceSearchResponse{Transcription: transcription, SearchAPIResponse: *response})
+}
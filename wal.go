@@ -0,0 +1,462 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Write-ahead log untuk perubahan dokumen. Setiap perubahan ditulis dan
+// di-fsync ke WAL sebelum diterima, lalu secara berkala di-flush ke
+// articles.json. Kalau proses mati sebelum flush, record di WAL diputar ulang
+// saat startup sehingga tidak ada perubahan yang hilang.
+//
+// Format: satu record per baris, "<crc32 hex> <json>". Baris terakhir yang
+// terpotong (crash saat menulis) diabaikan karena belum pernah di-acknowledge.
+const (
+	WAL_FILE           = "articles.wal"
+	WAL_FLUSH_RECORDS  = 100 // default, bisa diubah lewat config
+	WAL_FLUSH_INTERVAL = time.Minute
+)
+
+const (
+	WalAdd    = "add"
+	WalUpdate = "update"
+	WalDelete = "delete"
+	// Soft delete, lihat softdelete.go
+	WalRestore = "restore"
+	WalPurge   = "purge"
+)
+
+type WalRecord struct {
+	Seq     uint64    `json:"seq"`
+	Op      string    `json:"op"`
+	URL     string    `json:"url"`
+	Article *Article  `json:"article,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+type WriteAheadLog struct {
+	mu          sync.Mutex
+	path        string
+	file        *os.File
+	seq         uint64
+	pending     []WalRecord
+	loggedBytes int64 // ukuran record tertunda di WAL
+	stats       MergeStats
+}
+
+// Metrik merge WAL ke articles.json
+type MergeStats struct {
+	Merges          int     `json:"merges"`
+	RecordsMerged   int     `json:"records_merged"`
+	LastDurationMs  float64 `json:"last_duration_ms"`
+	TotalDurationMs float64 `json:"total_duration_ms"`
+	LastMergeAt     string  `json:"last_merge_at,omitempty"`
+	BytesLogged     int64   `json:"bytes_logged"`    // total byte record yang ditulis ke WAL
+	BytesRewritten  int64   `json:"bytes_rewritten"` // total byte articles.json yang ditulis ulang saat merge
+	// (WAL + articles.json) / WAL: berapa kali lipat data yang ditulis dibanding data yang masuk
+	WriteAmplification float64 `json:"write_amplification"`
+	PendingRecords     int     `json:"pending_records"`
+}
+
+var documentLog *WriteAheadLog
+
+// Buka WAL dan baca record yang belum di-flush
+func openWAL(path string) (*WriteAheadLog, error) {
+	wal := &WriteAheadLog{path: path}
+
+	records, validSize, err := readWAL(path)
+	if err != nil {
+		return nil, err
+	}
+	wal.pending = records
+	wal.loggedBytes = validSize
+	if len(records) > 0 {
+		wal.seq = records[len(records)-1].Seq
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	// Buang ekor yang terpotong supaya append berikutnya mulai di baris baru
+	if err := f.Truncate(validSize); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(validSize, 0); err != nil {
+		f.Close()
+		return nil, err
+	}
+	wal.file = f
+
+	return wal, nil
+}
+
+func readWAL(path string) ([]WalRecord, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var records []WalRecord
+	var validSize int64
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// Baris tanpa newline berarti tulisan terakhir tidak selesai
+			break
+		}
+		record, ok := decodeWalLine(strings.TrimSuffix(line, "\n"))
+		if !ok {
+			log.Printf("WAL %s: corrupt record after seq %d, ignoring the rest", path, lastSeq(records))
+			break
+		}
+		records = append(records, record)
+		validSize += int64(len(line))
+	}
+
+	return records, validSize, nil
+}
+
+func decodeWalLine(line string) (WalRecord, bool) {
+	var record WalRecord
+	parts := strings.SplitN(line, " ", 2)
+	if len(parts) != 2 {
+		return record, false
+	}
+	checksum, err := strconv.ParseUint(parts[0], 16, 32)
+	if err != nil || crc32.ChecksumIEEE([]byte(parts[1])) != uint32(checksum) {
+		return record, false
+	}
+	if err := json.Unmarshal([]byte(parts[1]), &record); err != nil {
+		return record, false
+	}
+	return record, true
+}
+
+func lastSeq(records []WalRecord) uint64 {
+	if len(records) == 0 {
+		return 0
+	}
+	return records[len(records)-1].Seq
+}
+
+func validateWalRecord(record WalRecord) error {
+	switch record.Op {
+	case WalAdd, WalUpdate:
+		if record.Article == nil || record.Article.URL == "" {
+			return fmt.Errorf("%s requires an article with a url", record.Op)
+		}
+	case WalDelete, WalRestore, WalPurge:
+		if record.URL == "" {
+			return fmt.Errorf("%s requires a url", record.Op)
+		}
+	default:
+		return fmt.Errorf("unknown op %q", record.Op)
+	}
+	return nil
+}
+
+// Tulis record ke WAL dan fsync; setelah return tanpa error perubahan dijamin tidak hilang
+func (wal *WriteAheadLog) Append(records ...WalRecord) ([]WalRecord, error) {
+	for _, record := range records {
+		if err := validateWalRecord(record); err != nil {
+			return nil, err
+		}
+	}
+
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+
+	var buf strings.Builder
+	seq := wal.seq
+	accepted := make([]WalRecord, len(records))
+	for i, record := range records {
+		seq++
+		record.Seq = seq
+		record.Time = time.Now()
+		if record.Article != nil {
+			record.URL = record.Article.URL
+		}
+
+		data, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, "%08x %s\n", crc32.ChecksumIEEE(data), data)
+		accepted[i] = record
+	}
+
+	if _, err := wal.file.WriteString(buf.String()); err != nil {
+		return nil, err
+	}
+	if err := wal.file.Sync(); err != nil {
+		return nil, err
+	}
+
+	wal.seq = seq
+	wal.pending = append(wal.pending, accepted...)
+	wal.loggedBytes += int64(buf.Len())
+	wal.stats.BytesLogged += int64(buf.Len())
+
+	if limit := currentConfig().WalFlushRecords; limit > 0 && len(wal.pending) >= limit {
+		if err := wal.flush(); err != nil {
+			log.Printf("Error flushing WAL: %v", err)
+		}
+	}
+
+	return accepted, nil
+}
+
+// Record yang sudah diterima tapi belum masuk articles.json
+func (wal *WriteAheadLog) Pending() []WalRecord {
+	if wal == nil {
+		return nil
+	}
+
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+
+	return append([]WalRecord(nil), wal.pending...)
+}
+
+// Terapkan record ke articles.json lalu kosongkan WAL
+func (wal *WriteAheadLog) Flush() error {
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+
+	return wal.flush()
+}
+
+func (wal *WriteAheadLog) flush() error {
+	if len(wal.pending) == 0 {
+		return nil
+	}
+	start := time.Now()
+	cfg := currentConfig()
+
+	articles, err := loadArticles()
+	if err != nil {
+		return err
+	}
+	articles = applyWalRecords(articles, wal.pending, cfg)
+	written, err := saveArticles(ARTICLES_FILE, articles)
+	if err != nil {
+		return err
+	}
+
+	// articles.json sudah aman di disk, baru WAL boleh dikosongkan
+	if err := wal.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := wal.file.Seek(0, 0); err != nil {
+		return err
+	}
+	if err := wal.file.Sync(); err != nil {
+		return err
+	}
+	duration := float64(time.Since(start).Microseconds()) / 1000
+	wal.stats.Merges++
+	wal.stats.RecordsMerged += len(wal.pending)
+	wal.stats.LastDurationMs = duration
+	wal.stats.TotalDurationMs += duration
+	wal.stats.LastMergeAt = time.Now().Format(time.RFC3339)
+	wal.stats.BytesRewritten += written
+	log.Printf("Merged %d WAL records into %s in %.1fms (%d bytes logged, %d bytes written)",
+		len(wal.pending), ARTICLES_FILE, duration, wal.loggedBytes, written)
+
+	wal.pending = nil
+	wal.loggedBytes = 0
+
+	corpusFlushed(cfg.DocStorePath)
+	return nil
+}
+
+func (wal *WriteAheadLog) Stats() MergeStats {
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+
+	stats := wal.stats
+	stats.PendingRecords = len(wal.pending)
+	if stats.BytesLogged > 0 {
+		stats.WriteAmplification = float64(stats.BytesLogged+stats.BytesRewritten) / float64(stats.BytesLogged)
+	}
+	return stats
+}
+
+func (wal *WriteAheadLog) Close() error {
+	return wal.file.Close()
+}
+
+// Terapkan perubahan ke salinan corpus, dokumen dikenali dari URL,
+// dengan aturan soft delete dari cfg
+func applyWalRecords(articles []Article, records []WalRecord, cfg *Config) []Article {
+	if len(records) == 0 {
+		return articles
+	}
+
+	result := make([]Article, len(articles))
+	copy(result, articles)
+
+	position := make(map[string]int, len(result))
+	for i, article := range result {
+		position[article.URL] = i
+	}
+
+	deleted := make(map[string]bool)
+	for _, record := range records {
+		switch record.Op {
+		case WalAdd, WalUpdate:
+			delete(deleted, record.URL)
+			if i, exists := position[record.URL]; exists {
+				result[i] = *record.Article
+			} else {
+				position[record.URL] = len(result)
+				result = append(result, *record.Article)
+			}
+		case WalDelete:
+			i, exists := position[record.URL]
+			if !exists {
+				continue
+			}
+			// Soft delete: tetap di corpus sampai di-purge
+			if cfg.SoftDeleteDays > 0 {
+				if !result[i].Deleted() {
+					deletedAt := record.Time
+					result[i].DeletedAt = &deletedAt
+				}
+				continue
+			}
+			deleted[record.URL] = true
+		case WalPurge:
+			if _, exists := position[record.URL]; exists {
+				deleted[record.URL] = true
+			}
+		case WalRestore:
+			if i, exists := position[record.URL]; exists && !deleted[record.URL] {
+				result[i].DeletedAt = nil
+			}
+		}
+	}
+
+	if len(deleted) == 0 {
+		return result
+	}
+	kept := result[:0]
+	for _, article := range result {
+		if !deleted[article.URL] {
+			kept = append(kept, article)
+		}
+	}
+	return kept
+}
+
+// Tulis corpus secara atomik (file sementara + fsync + rename + fsync
+// direktori), return jumlah byte yang ditulis
+func saveArticles(path string, articles []Article) (int64, error) {
+	data, err := json.MarshalIndent(articles, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmpPath)
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return 0, err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, err
+	}
+	// Rename baru tahan crash setelah entri direktorinya ikut di-fsync
+	return int64(len(data)), syncDir(filepath.Dir(path))
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// Data turunan corpus (document store) dibangun ulang setelah flush
+func corpusFlushed(docStorePath string) {
+	if !hasDocStore() {
+		return
+	}
+	if err := reopenDocStore(docStorePath, false); err != nil {
+		log.Printf("Error rebuilding document store after flush: %v", err)
+	}
+}
+
+// Buka ulang document store, force = bangun ulang walaupun tidak lebih lama dari corpus
+func reopenDocStore(path string, force bool) error {
+	if force {
+		if err := rebuildDocStore(path); err != nil {
+			return err
+		}
+	}
+	ds, err := ensureDocStore(path)
+	if err != nil {
+		return err
+	}
+	// Store lama ditutup setelah query terakhir yang membacanya selesai
+	setDocStore(ds)
+	return nil
+}
+
+// Paksa merge: flush WAL lalu bangun ulang (compact) document store
+func forceMerge(wal *WriteAheadLog) error {
+	if err := wal.Flush(); err != nil {
+		return err
+	}
+	if !hasDocStore() {
+		return nil
+	}
+	return reopenDocStore(currentConfig().DocStorePath, true)
+}
+
+// Flush berkala supaya WAL tidak tumbuh tanpa batas
+func startWALFlusher(wal *WriteAheadLog) {
+	go func() {
+		for {
+			interval := time.Duration(currentConfig().WalFlushIntervalSeconds) * time.Second
+			if interval <= 0 {
+				interval = WAL_FLUSH_INTERVAL
+			}
+			time.Sleep(interval)
+			if err := wal.Flush(); err != nil {
+				log.Printf("Error flushing WAL: %v", err)
+			}
+		}
+	}()
+}
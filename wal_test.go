@@ -0,0 +1,161 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Jalankan test dari direktori sementara kosong; working directory
+// dikembalikan setelah test selesai
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(cwd) })
+	return dir
+}
+
+func fixtureRecord(op string, article Article) WalRecord {
+	return WalRecord{Op: op, URL: article.URL, Article: &article}
+}
+
+func TestWALReplaysAfterTornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), WAL_FILE)
+	wal, err := openWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	added := fixtureArticles[0]
+	added.URL = "https://artikel.rumah123.com/apartemen-baru"
+	if _, err := wal.Append(fixtureRecord(WalAdd, added), WalRecord{Op: WalDelete, URL: fixtureArticles[1].URL}); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Proses mati di tengah menulis record ketiga: baris tanpa newline
+	if _, err := wal.file.WriteString(`1234abcd {"seq":3,"op":"add"`); err != nil {
+		t.Fatal(err)
+	}
+	wal.Close()
+
+	wal, err = openWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wal.Close()
+	pending := wal.Pending()
+	if len(pending) != 2 || pending[0].Seq != 1 || pending[0].URL != added.URL || pending[1].Seq != 2 || pending[1].Op != WalDelete {
+		t.Fatalf("replayed %+v", pending)
+	}
+	if after, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if after.Size() != info.Size() {
+		t.Fatalf("torn tail not truncated: %d bytes, want %d", after.Size(), info.Size())
+	}
+
+	// Record berikutnya melanjutkan seq dan terbaca lagi setelah restart
+	if records, err := wal.Append(WalRecord{Op: WalRestore, URL: fixtureArticles[1].URL}); err != nil || records[0].Seq != 3 {
+		t.Fatalf("append after replay: %+v, %v", records, err)
+	}
+	if records, _, err := readWAL(path); err != nil || len(records) != 3 {
+		t.Fatalf("read back %d records, %v", len(records), err)
+	}
+}
+
+func TestWALStopsAtCorruptRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), WAL_FILE)
+	wal, err := openWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wal.Append(fixtureRecord(WalAdd, fixtureArticles[0])); err != nil {
+		t.Fatal(err)
+	}
+	// Checksum tidak cocok: record ini dan semua sesudahnya tidak dipercaya
+	if _, err := wal.file.WriteString("00000000 {\"seq\":2,\"op\":\"delete\",\"url\":\"x\"}\n"); err != nil {
+		t.Fatal(err)
+	}
+	wal.Close()
+
+	records, _, err := readWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].URL != fixtureArticles[0].URL {
+		t.Errorf("records %+v", records)
+	}
+}
+
+func TestWALFlushMergesIntoCorpus(t *testing.T) {
+	dir := chdirTemp(t)
+	if err := writeJSONFile(ARTICLES_FILE, fixtureArticles); err != nil {
+		t.Fatal(err)
+	}
+	wal, err := openWAL(WAL_FILE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wal.Close()
+
+	updated := fixtureArticles[0]
+	updated.Title = "Apartemen Murah di Jakarta Selatan dan Depok"
+	added := fixtureCorpus()[2]
+	records := []WalRecord{
+		fixtureRecord(WalUpdate, updated),
+		fixtureRecord(WalAdd, added),
+		{Op: WalDelete, URL: fixtureArticles[1].URL},
+	}
+	if _, err := wal.Append(records...); err != nil {
+		t.Fatal(err)
+	}
+	if err := wal.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if pending := wal.Pending(); len(pending) != 0 {
+		t.Errorf("%d records pending after flush", len(pending))
+	}
+	if info, err := os.Stat(filepath.Join(dir, WAL_FILE)); err != nil || info.Size() != 0 {
+		t.Errorf("WAL not emptied after flush (%v)", err)
+	}
+	if _, err := os.Stat(ARTICLES_FILE + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary corpus file left behind (%v)", err)
+	}
+
+	articles, err := loadArticles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 3 {
+		t.Fatalf("%d articles after flush, want 3", len(articles))
+	}
+	if articles[0].Title != updated.Title {
+		t.Errorf("update not merged: %q", articles[0].Title)
+	}
+	if articles[2].URL != added.URL {
+		t.Errorf("add not merged: %q", articles[2].URL)
+	}
+	// soft_delete_days default: dokumen ditandai, bukan dibuang
+	if !articles[1].Deleted() {
+		t.Error("delete not merged")
+	}
+
+	// Restart setelah flush tidak memutar ulang record yang sudah masuk corpus
+	reopened, err := openWAL(WAL_FILE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	if pending := reopened.Pending(); len(pending) != 0 {
+		t.Errorf("%d records replayed after flush", len(pending))
+	}
+}
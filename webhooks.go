@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Webhook dipanggil saat crawl selesai (dikirim oleh crawler, lihat
+// */webhook.go) dan saat index generasi baru mulai dipakai. Konfigurasi
+// dibagi dengan crawler lewat file yang sama:
+//
+//	{"webhooks": [{"url": "https://...", "events": ["index.swapped"], "secret": "..."}]}
+//
+// Body di-sign HMAC-SHA256 dengan secret di header X-Webhook-Signature.
+const (
+	WEBHOOKS_FILE     = "webhooks.json"
+	WEBHOOK_ATTEMPTS  = 3
+	WEBHOOK_TIMEOUT   = 10 * time.Second
+	WEBHOOK_RETRY_GAP = 2 * time.Second
+)
+
+const (
+	EventCrawlCompleted = "crawl.completed"
+	EventIndexSwapped   = "index.swapped"
+)
+
+type Webhook struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"` // kosong = semua event
+	Secret string   `json:"secret,omitempty"`
+}
+
+type WebhookPayload struct {
+	Event string      `json:"event"`
+	Time  time.Time   `json:"time"`
+	Data  interface{} `json:"data"`
+}
+
+type Webhooks struct {
+	Webhooks []Webhook `json:"webhooks"`
+
+	client *http.Client
+}
+
+var webhooks = &Webhooks{}
+
+func loadWebhooks(path string) (*Webhooks, error) {
+	wh := &Webhooks{client: &http.Client{Timeout: WEBHOOK_TIMEOUT}}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return wh, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, wh); err != nil {
+		return nil, err
+	}
+	return wh, nil
+}
+
+func (w Webhook) subscribes(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Kirim event ke semua webhook yang berlangganan, di background
+func (wh *Webhooks) Fire(event string, data interface{}) {
+	if wh == nil || len(wh.Webhooks) == 0 || benchMode {
+		return
+	}
+
+	body, err := json.Marshal(WebhookPayload{Event: event, Time: time.Now(), Data: data})
+	if err != nil {
+		log.Printf("Error encoding webhook %s: %v", event, err)
+		return
+	}
+
+	for _, hook := range wh.Webhooks {
+		if hook.subscribes(event) {
+			go wh.deliver(hook, event, body)
+		}
+	}
+}
+
+func (wh *Webhooks) deliver(hook Webhook, event string, body []byte) {
+	var err error
+	for attempt := 1; attempt <= WEBHOOK_ATTEMPTS; attempt++ {
+		if err = wh.post(hook, event, body); err == nil {
+			return
+		}
+		time.Sleep(time.Duration(attempt) * WEBHOOK_RETRY_GAP)
+	}
+	log.Printf("Error delivering webhook %s to %s: %v", event, hook.URL, err)
+}
+
+func (wh *Webhooks) post(hook Webhook, event string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", event)
+	if hook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(hook.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := wh.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Generasi index: berubah setiap kali corpus yang diindex berubah
+// (articles.json diganti/di-merge atau ada record baru di WAL)
+var indexGeneration struct {
+	mu      sync.Mutex
+	key     string
+	counter int
+}
+
+func corpusGenerationKey(pending []WalRecord) string {
+	var modTime time.Time
+	var size int64
+	if info, err := os.Stat(ARTICLES_FILE); err == nil {
+		modTime, size = info.ModTime(), info.Size()
+	}
+	return fmt.Sprintf("%d|%d|%d", modTime.UnixNano(), size, lastSeq(pending))
+}
+
+// Dipanggil setiap index dibangun; event index.swapped dikirim sekali per generasi
+func noteIndexGeneration(key string, articles []Article, idx *InvertedIndex, buildDuration time.Duration, cfg *Config) {
+	indexGeneration.mu.Lock()
+	if key == indexGeneration.key {
+		indexGeneration.mu.Unlock()
+		return
+	}
+	indexGeneration.key = key
+	indexGeneration.counter++
+	generation := indexGeneration.counter
+	indexGeneration.mu.Unlock()
+
+	// Halaman hasil generasi lama tidak boleh disajikan lagi
+	purgeResultCaches()
+
+	writeBuildReport(newBuildReport(generation, articles, idx, buildDuration, cfg))
+	scheduleGenerationRetention(generation, articles, idx.Allowed, cfg)
+	updateSyndication(articles)
+	updateSourceQuality(articles)
+	updateLinkGraph(articles)
+	updateSuggestVocabulary(articles)
+	updateQueryVocabulary(articles)
+
+	if snapshotOnReindex {
+		if _, err := adminJobs.Enqueue(JobRegressionSnapshot, map[string]string{"generation": strconv.Itoa(generation)}); err != nil {
+			log.Printf("Error scheduling regression snapshot: %v", err)
+		}
+	}
+	queryMonitor.IndexSwapped(generation)
+
+	webhooks.Fire(EventIndexSwapped, map[string]interface{}{
+		"generation":   generation,
+		"docs":         len(idx.DocLengths),
+		"indexed_docs": idx.DocCount,
+		"terms":        len(idx.Index),
+	})
+}
+
+func init() {
+	wh, err := loadWebhooks(WEBHOOKS_FILE)
+	if err != nil {
+		log.Printf("Error loading %s: %v", WEBHOOKS_FILE, err)
+		return
+	}
+	webhooks = wh
+}
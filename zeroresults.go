@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ZERO_RESULTS_REPORT_FILE = "zero_results_report.json"
+	ZERO_RESULTS_INTERVAL    = time.Hour
+	ZERO_RESULTS_WINDOW      = 30 * 24 * time.Hour
+	ZERO_RESULTS_MIN_COUNT   = 2
+
+	JobZeroResults = "zero_results"
+)
+
+// Query tanpa hasil yang sering muncul di query log
+type ZeroResultQuery struct {
+	Query      string    `json:"query"`
+	Count      int       `json:"count"`
+	LastSeen   time.Time `json:"last_seen"`
+	Fix        string    `json:"fix,omitempty"`
+	FixType    string    `json:"fix_type,omitempty"` // "spelling" atau "synonym"
+	FixResults int       `json:"fix_results,omitempty"`
+}
+
+type ZeroResultReport struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Window      string            `json:"window"`
+	Queries     []ZeroResultQuery `json:"queries"`
+}
+
+var (
+	zeroResultMu     sync.RWMutex
+	zeroResultReport *ZeroResultReport
+)
+
+// Kelompokkan query yang hasilnya nol, abaikan query yang terakhir kali sudah ada hasilnya
+func collectZeroResultQueries(entries []QueryLogEntry, minCount int) []ZeroResultQuery {
+	stats := make(map[string]*ZeroResultQuery)
+	lastResults := make(map[string]int)
+
+	for _, entry := range entries {
+		// Variasi spasi/kapital dihitung sebagai query yang sama
+		q := strings.ToLower(normalizeQuery(entry.Query))
+		if q == "" {
+			continue
+		}
+		lastResults[q] = entry.Results
+		if entry.Results > 0 {
+			continue
+		}
+
+		zr, exists := stats[q]
+		if !exists {
+			zr = &ZeroResultQuery{Query: q}
+			stats[q] = zr
+		}
+		zr.Count++
+		if entry.Time.After(zr.LastSeen) {
+			zr.LastSeen = entry.Time
+		}
+	}
+
+	var queries []ZeroResultQuery
+	for q, zr := range stats {
+		if zr.Count >= minCount && lastResults[q] == 0 {
+			queries = append(queries, *zr)
+		}
+	}
+
+	sort.Slice(queries, func(i, j int) bool {
+		if queries[i].Count != queries[j].Count {
+			return queries[i].Count > queries[j].Count
+		}
+		return queries[i].Query < queries[j].Query
+	})
+
+	return queries
+}
+
+// Coba perbaiki query: koreksi ejaan ke vocabulary index, lalu sinonim yang sudah ada
+func suggestZeroResultFix(idx *InvertedIndex, zr *ZeroResultQuery) {
+	tokens := textProcessor.ProcessText(zr.Query)
+	if len(tokens) == 0 {
+		return
+	}
+
+	corrected := make([]string, len(tokens))
+	changed := false
+	for i, token := range tokens {
+		corrected[i] = token
+		if _, exists := idx.Index[token]; exists {
+			continue
+		}
+
+		maxDistance := 2
+		if len(token) <= 4 {
+			maxDistance = 1
+		}
+		if term, ok := closestTerm(idx, token, maxDistance); ok {
+			corrected[i] = term
+			changed = true
+		}
+	}
+
+	if changed {
+		zr.Fix = strings.Join(corrected, " ")
+		zr.FixType = "spelling"
+		for i, token := range tokens {
+			if corrected[i] != token {
+				synonyms.AddCandidate(token, corrected[i], "spelling", zr.Count)
+			}
+		}
+		return
+	}
+
+	vector := make(map[string]float64)
+	for _, token := range tokens {
+		vector[token]++
+	}
+	synonyms.Expand(vector)
+	if len(vector) > len(tokens) {
+		var expanded []string
+		for term := range vector {
+			expanded = append(expanded, term)
+		}
+		sort.Strings(expanded)
+		zr.Fix = strings.Join(expanded, " ")
+		zr.FixType = "synonym"
+	}
+}
+
+// Jalankan mining dari query log dan simpan report
+func mineZeroResultQueries() (*ZeroResultReport, error) {
+	entries, err := readQueryLog(QUERY_LOG_FILE, time.Now().Add(-ZERO_RESULTS_WINDOW))
+	if err != nil {
+		return nil, err
+	}
+
+	_, idx, err := loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	queries := collectZeroResultQueries(entries, ZERO_RESULTS_MIN_COUNT)
+	for i := range queries {
+		suggestZeroResultFix(idx, &queries[i])
+		if queries[i].Fix != "" {
+			queries[i].FixResults = len(searching(queries[i].Fix, "cosine", SearchOptions{}))
+		}
+	}
+
+	report := &ZeroResultReport{
+		GeneratedAt: time.Now(),
+		Window:      ZERO_RESULTS_WINDOW.String(),
+		Queries:     queries,
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(ZERO_RESULTS_REPORT_FILE, data, 0644); err != nil {
+		return nil, err
+	}
+
+	zeroResultMu.Lock()
+	zeroResultReport = report
+	zeroResultMu.Unlock()
+
+	return report, nil
+}
+
+func latestZeroResultReport() *ZeroResultReport {
+	zeroResultMu.RLock()
+	defer zeroResultMu.RUnlock()
+
+	return zeroResultReport
+}
+
+// Mining berkala di background
+func startZeroResultMiner(interval time.Duration) {
+	adminJobs.Every(JobZeroResults, interval)
+}
+
+func zeroResultsJob(ctx context.Context, job jobRun) error {
+	report, err := mineZeroResultQueries()
+	if report != nil {
+		job.Advance(len(report.Queries), 0)
+	}
+	return err
+}
+
+func init() {
+	registerJobKind(JobZeroResults, "", maintenanceRetry, zeroResultsJob)
+}